@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+	"workout-tracker/migrations"
+	"workout-tracker/pkg/db"
+	"workout-tracker/pkg/logger"
+	"workout-tracker/pkg/migrate"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `usage: migrate <command> [arg]
+
+commands:
+  up             apply all pending migrations
+  down [n]       revert the last n applied migrations (default 1)
+  status         list migrations and whether they are applied
+  force <ver>    mark migrations up to <ver> as applied without running them`
+
+func main() {
+	if err := godotenv.Load("config/.env"); err != nil {
+		log.Println("Error loading .env file")
+	}
+
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	logger.Init("dev")
+
+	conn, err := db.New(logger.L())
+	if err != nil {
+		log.Fatal("start db error: ", err)
+	}
+	defer conn.Pool.Close()
+
+	migrator, err := migrate.New(conn.Pool, logger.L(), migrations.FS)
+	if err != nil {
+		log.Fatal("load migrations error: ", err)
+	}
+
+	if err := run(context.Background(), migrator, os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, migrator *migrate.Migrator, args []string) error {
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+			steps = n
+		}
+		return migrator.Down(ctx, steps)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format(time.DateTime)
+			}
+			fmt.Printf("%03d_%-40s %s\n", s.Version, s.Name, state)
+		}
+		return nil
+	case "force":
+		if len(args) < 2 {
+			return fmt.Errorf("force requires a version\n%s", usage)
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		return migrator.Force(ctx, version)
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+}
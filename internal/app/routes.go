@@ -2,24 +2,60 @@ package app
 
 import (
 	"workout-tracker/internal/handler"
+	"workout-tracker/internal/handler/account"
 	"workout-tracker/internal/handler/admin"
 	"workout-tracker/internal/handler/auth"
+	"workout-tracker/internal/handler/category"
+	"workout-tracker/internal/handler/exercise"
+	"workout-tracker/internal/handler/measurement"
+	"workout-tracker/internal/handler/program"
+	"workout-tracker/internal/handler/progression"
+	"workout-tracker/internal/handler/record"
+	"workout-tracker/internal/handler/schedule"
+	"workout-tracker/internal/handler/session"
+	"workout-tracker/internal/handler/statistics"
 	"workout-tracker/internal/handler/workout"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(r *gin.Engine, h *auth.AuthHandler, a *admin.AdminHandler, w *workout.WorkoutHandler, m *handler.Middleware) {
+func SetupRoutes(
+	r *gin.Engine,
+	h *auth.AuthHandler,
+	a *admin.AdminHandler,
+	w *workout.WorkoutHandler,
+	s *session.SessionHandler,
+	st *statistics.StatisticsHandler,
+	rec *record.RecordHandler,
+	ex *exercise.ExerciseHandler,
+	cat *category.CategoryHandler,
+	prog *program.ProgramHandler,
+	pr *progression.ProgressionHandler,
+	sc *schedule.ScheduleHandler,
+	ms *measurement.MeasurementHandler,
+	acc *account.AccountHandler,
+	m *handler.Middleware,
+) {
+	r.Use(handler.ErrorHandler(m.Log))
+
+	r.GET("/.well-known/jwks.json", h.JWKS)
+
 	auth := r.Group("/auth")
 	auth.POST("/register", h.Register)
 	auth.POST("/login", h.Login)
 	auth.POST("/refresh", h.RefreshToken)
+	auth.POST("/logout", h.Logout)
 
 	admin := r.Group("/admin").Use(m.AuthMiddleware()).Use(m.AdminMiddleware())
 	admin.POST("/exercises", a.CreateExercise)
 	admin.PUT("/exercises/:id", a.UpdateExercise)
 	admin.GET("/exercises", a.GetAllExercises)
 	admin.DELETE("/exercises/:id", a.DeleteExercise)
+	admin.GET("/categories", cat.GetAll)
+	admin.POST("/categories", cat.Create)
+	admin.PUT("/categories/order", cat.Reorder)
+	admin.PUT("/categories/:id", cat.Update)
+	admin.DELETE("/categories/:id", cat.Delete)
 
 	workout := r.Group("/workouts").Use(m.AuthMiddleware())
 	workout.POST("", w.Create)
@@ -29,7 +65,65 @@ func SetupRoutes(r *gin.Engine, h *auth.AuthHandler, a *admin.AdminHandler, w *w
 	workout.DELETE("/:id", w.Delete)
 	workout.POST("/:id/photo", w.UpdatePhoto)
 	workout.GET("/:id/photo", w.GetPhoto)
+	workout.GET("/:id/next", pr.Next)
+	workout.POST("/:id/sessions", s.Start)
+	workout.GET("/:id/sessions", s.GetAll)
+	workout.GET("/:id/sessions/:session_id", s.Get)
+	workout.POST("/:id/sessions/:session_id/sets", s.LogSet)
+	workout.POST("/:id/sessions/:session_id/finish", s.Finish)
 
 	allExercises := r.Group("/exercises").Use(m.AuthMiddleware())
-	allExercises.GET("", a.GetAllExercises)
+	allExercises.GET("", ex.GetAll)
+	allExercises.POST("", ex.Create)
+	allExercises.PUT("/:id", ex.Update)
+	allExercises.DELETE("/:id", ex.Delete)
+	allExercises.GET("/:id/records", rec.ByExercise)
+
+	categories := r.Group("/categories").Use(m.AuthMiddleware())
+	categories.GET("", cat.GetAll)
+
+	programs := r.Group("/programs").Use(m.AuthMiddleware())
+	programs.POST("", prog.Create)
+	programs.GET("", prog.GetAll)
+	programs.GET("/:id", prog.Get)
+	programs.DELETE("/:id", prog.Delete)
+	programs.POST("/:id/enroll", prog.Enroll)
+
+	plan := r.Group("/schedule").Use(m.AuthMiddleware())
+	plan.POST("", sc.Create)
+	plan.GET("", sc.GetAll)
+	plan.DELETE("/:id", sc.Delete)
+	plan.PUT("/:id/occurrences/:date", sc.Mark)
+
+	calendar := r.Group("/calendar").Use(m.AuthMiddleware())
+	calendar.GET("", sc.Calendar)
+	// The feed is fetched by calendar apps and authenticates with its own token.
+	r.GET("/calendar.ics", sc.Feed)
+
+	stats := r.Group("/stats").Use(m.AuthMiddleware())
+	stats.GET("/categories", st.ByCategory)
+	stats.GET("/exercises", st.ByExercise)
+	stats.GET("/timeline", st.ByPeriod)
+
+	me := r.Group("/me").Use(m.AuthMiddleware())
+	me.GET("", acc.Get)
+	me.PUT("", acc.Update)
+	me.DELETE("", acc.Delete)
+	me.PUT("/password", acc.ChangePassword)
+	me.GET("/sessions", acc.GetSessions)
+	me.DELETE("/sessions", acc.RevokeOtherSessions)
+	me.DELETE("/sessions/:id", acc.RevokeSession)
+	me.GET("/records", rec.Mine)
+	me.GET("/program/today", prog.Today)
+	me.DELETE("/program", prog.Unenroll)
+	me.GET("/preferences", pr.GetPreferences)
+	me.PUT("/preferences", pr.UpdatePreferences)
+	me.POST("/calendar-token", sc.CreateToken)
+	me.DELETE("/calendar-token", sc.RevokeToken)
+	me.GET("/metrics", ms.GetMetrics)
+	me.POST("/metrics", ms.CreateMetric)
+	me.DELETE("/metrics/:slug", ms.DeleteMetric)
+	me.POST("/measurements", ms.Add)
+	me.GET("/measurements/:metric", ms.Series)
+	me.DELETE("/measurements/:metric/:id", ms.Delete)
 }
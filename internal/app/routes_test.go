@@ -17,12 +17,24 @@ import (
 	exerciseDTO "workout-tracker/internal/dto/exercise"
 	workoutDTO "workout-tracker/internal/dto/workout"
 	"workout-tracker/internal/handler"
+	"workout-tracker/internal/handler/account"
 	"workout-tracker/internal/handler/admin"
 	"workout-tracker/internal/handler/auth"
+	"workout-tracker/internal/handler/category"
+	exerciseHandler "workout-tracker/internal/handler/exercise"
+	"workout-tracker/internal/handler/measurement"
+	"workout-tracker/internal/handler/program"
+	"workout-tracker/internal/handler/progression"
+	"workout-tracker/internal/handler/record"
+	"workout-tracker/internal/handler/schedule"
+	"workout-tracker/internal/handler/session"
+	"workout-tracker/internal/handler/statistics"
 	"workout-tracker/internal/handler/workout"
 	"workout-tracker/internal/model/exercise"
 	"workout-tracker/internal/model/user"
+	jwtModel "workout-tracker/internal/model/user/jwt"
 	"workout-tracker/internal/model/workoutexercisejoin"
+	"workout-tracker/pkg/jwtkeys"
 )
 
 type mockAuthService struct{}
@@ -39,18 +51,27 @@ func (m *mockAuthService) GetUserByUsername(ctx context.Context, username string
 func (m *mockAuthService) CheckPassword(hashed, password string) error {
 	return nil
 }
-func (m *mockAuthService) GenerateAccessToken(u *user.User) (string, error) {
+func (m *mockAuthService) GenerateAccessToken(u *user.User, sessionID string) (string, error) {
 	return "access", nil
 }
-func (m *mockAuthService) GenerateAndStoreRefreshToken(ctx context.Context, userID int) (string, error) {
-	return "refresh", nil
+func (m *mockAuthService) GenerateAndStoreRefreshToken(ctx context.Context, userID int, device jwtModel.Device) (string, string, error) {
+	return "refresh", "session", nil
 }
-func (m *mockAuthService) UpdateRefreshToken(ctx context.Context, refreshToken string) (string, string, error) {
+func (m *mockAuthService) UpdateRefreshToken(ctx context.Context, refreshToken string, device jwtModel.Device) (string, string, error) {
 	return "newAccess", "newRefresh", nil
 }
+func (m *mockAuthService) Logout(ctx context.Context, refreshToken string) error {
+	return nil
+}
+func (m *mockAuthService) JWKS() jwtkeys.JWKS {
+	return jwtkeys.JWKS{}
+}
 func (m *mockAuthService) GetUserByUserID(ctx context.Context, id int) (*user.User, error) {
 	return &user.User{ID: id, TokenVersion: 1, Role: user.UserRole}, nil
 }
+func (m *mockAuthService) SessionActive(ctx context.Context, userID int, sessionID string) (bool, error) {
+	return true, nil
+}
 
 type mockAdminService struct{}
 
@@ -60,8 +81,8 @@ func (m *mockAdminService) CreateExercise(ctx context.Context, req exerciseDTO.C
 func (m *mockAdminService) UpdateExercise(ctx context.Context, id int, req exerciseDTO.CreateExerciseRequest) error {
 	return nil
 }
-func (m *mockAdminService) GetAllExercises(ctx context.Context) ([]exercise.Exercise, error) {
-	return []exercise.Exercise{}, nil
+func (m *mockAdminService) GetAllExercises(ctx context.Context, filter exerciseDTO.ExerciseFilter) (*exerciseDTO.ExercisePage, error) {
+	return &exerciseDTO.ExercisePage{Items: []exercise.Exercise{}}, nil
 }
 func (m *mockAdminService) DeleteExercise(ctx context.Context, id int) error {
 	return nil
@@ -69,7 +90,7 @@ func (m *mockAdminService) DeleteExercise(ctx context.Context, id int) error {
 
 type mockWorkoutService struct{}
 
-func (m *mockWorkoutService) UpdateWorkoutPhoto(ctx context.Context, workoutID int, path string) error {
+func (m *mockWorkoutService) UpdateWorkoutPhoto(ctx context.Context, userID, workoutID int, path string) error {
 	return nil
 }
 
@@ -79,7 +100,7 @@ func (m *mockWorkoutService) CreateWorkout(
 	name,
 	title,
 	category string,
-	exercises []workoutexercisejoin.WorkoutExercise) error {
+	blocks []workoutexercisejoin.Block) error {
 	return nil
 }
 func (m *mockWorkoutService) UpdateWorkout(
@@ -89,14 +110,15 @@ func (m *mockWorkoutService) UpdateWorkout(
 	name,
 	title,
 	category string,
-	exercises []workoutexercisejoin.WorkoutExercise) error {
+	blocks []workoutexercisejoin.Block) error {
 	return nil
 }
 func (m *mockWorkoutService) DeleteWorkout(ctx context.Context, userID, workoutID int) error {
 	return nil
 }
-func (m *mockWorkoutService) GetAllWorkoutsWithExercises(ctx context.Context, userID int) ([]workoutDTO.WorkoutWithExercises, error) {
-	return []workoutDTO.WorkoutWithExercises{}, nil
+func (m *mockWorkoutService) GetAllWorkoutsWithExercises(
+	ctx context.Context, userID int, filter workoutDTO.WorkoutFilter) (*workoutDTO.WorkoutPage, error) {
+	return &workoutDTO.WorkoutPage{Items: []workoutDTO.WorkoutWithExercises{}}, nil
 }
 func (m *mockWorkoutService) GetWorkoutByID(ctx context.Context, userID, workoutID int) (*workoutDTO.WorkoutWithExercises, error) {
 	return &workoutDTO.WorkoutWithExercises{}, nil
@@ -129,12 +151,63 @@ func TestSetupRoutes(t *testing.T) {
 		Logger:  logger,
 	})
 
+	sessionHandler := session.NewSessionHandler(session.SessionHandlerParams{
+		Service: &session.FakeService{},
+		Logger:  logger,
+	})
+
+	statisticsHandler := statistics.NewStatisticsHandler(statistics.StatisticsHandlerParams{
+		Service: &statistics.FakeService{},
+		Logger:  logger,
+	})
+
+	recordHandler := record.NewRecordHandler(record.RecordHandlerParams{
+		Service: &record.FakeService{},
+		Logger:  logger,
+	})
+
+	exHandler := exerciseHandler.NewExerciseHandler(exerciseHandler.ExerciseHandlerParams{
+		Service: &exerciseHandler.FakeService{},
+		Logger:  logger,
+	})
+
+	categoryHandler := category.NewCategoryHandler(category.CategoryHandlerParams{
+		Service: &category.FakeService{},
+		Logger:  logger,
+	})
+
+	programHandler := program.NewProgramHandler(program.ProgramHandlerParams{
+		Service: &program.FakeService{},
+		Logger:  logger,
+	})
+
+	progressionHandler := progression.NewProgressionHandler(progression.ProgressionHandlerParams{
+		Service: &progression.FakeService{},
+		Logger:  logger,
+	})
+
+	scheduleHandler := schedule.NewScheduleHandler(schedule.ScheduleHandlerParams{
+		Service: &schedule.FakeService{},
+		Logger:  logger,
+	})
+
+	measurementHandler := measurement.NewMeasurementHandler(measurement.MeasurementHandlerParams{
+		Service: &measurement.FakeService{},
+		Logger:  logger,
+	})
+
+	accountHandler := account.NewAccountHandler(account.AccountHandlerParams{
+		Service: &account.FakeService{},
+		Logger:  logger,
+	})
+
 	mw := handler.NewMiddleware(handler.MiddlewareParams{
 		Log:     logger,
 		Service: &mockAuthService{},
 	})
 
-	SetupRoutes(router, authHandler, adminHandler, workoutHandler, mw)
+	SetupRoutes(router, authHandler, adminHandler, workoutHandler, sessionHandler, statisticsHandler, recordHandler, exHandler, categoryHandler, programHandler,
+		progressionHandler, scheduleHandler, measurementHandler, accountHandler, mw)
 
 	req, _ := http.NewRequest(http.MethodGet, "/workouts", http.NoBody)
 
@@ -1,19 +1,53 @@
 package app
 
 import (
+	"context"
 	"log"
+	"time"
 	middleware "workout-tracker/internal/handler"
+	accountHandler "workout-tracker/internal/handler/account"
 	"workout-tracker/internal/handler/admin"
 	handler "workout-tracker/internal/handler/auth"
+	categoryHandler "workout-tracker/internal/handler/category"
+	exerciseHandler "workout-tracker/internal/handler/exercise"
+	measurementHandler "workout-tracker/internal/handler/measurement"
+	programHandler "workout-tracker/internal/handler/program"
+	progressionHandler "workout-tracker/internal/handler/progression"
+	"workout-tracker/internal/handler/record"
+	scheduleHandler "workout-tracker/internal/handler/schedule"
+	"workout-tracker/internal/handler/session"
+	"workout-tracker/internal/handler/statistics"
 	"workout-tracker/internal/handler/workout"
+	categoryRepo "workout-tracker/internal/repository/category"
 	"workout-tracker/internal/repository/exercise"
+	measurementRepo "workout-tracker/internal/repository/measurement"
+	preferenceRepo "workout-tracker/internal/repository/preference"
+	programRepo "workout-tracker/internal/repository/program"
+	recordRepo "workout-tracker/internal/repository/record"
+	scheduleRepo "workout-tracker/internal/repository/schedule"
+	sessionRepo "workout-tracker/internal/repository/session"
+	statisticsRepo "workout-tracker/internal/repository/statistics"
 	"workout-tracker/internal/repository/user"
 	workoutRepo "workout-tracker/internal/repository/workout"
+	accountService "workout-tracker/internal/service/account"
 	adminService "workout-tracker/internal/service/admin"
 	service "workout-tracker/internal/service/auth"
+	categoryService "workout-tracker/internal/service/category"
+	exerciseService "workout-tracker/internal/service/exercise"
+	measurementService "workout-tracker/internal/service/measurement"
+	programService "workout-tracker/internal/service/program"
+	progressionService "workout-tracker/internal/service/progression"
+	recordService "workout-tracker/internal/service/record"
+	scheduleService "workout-tracker/internal/service/schedule"
+	sessionService "workout-tracker/internal/service/session"
+	statisticsService "workout-tracker/internal/service/statistics"
 	workoutService "workout-tracker/internal/service/workout"
+	"workout-tracker/internal/validation"
+	"workout-tracker/migrations"
 	"workout-tracker/pkg/db"
+	"workout-tracker/pkg/jwtkeys"
 	"workout-tracker/pkg/logger"
+	"workout-tracker/pkg/migrate"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -21,6 +55,13 @@ import (
 	"go.uber.org/dig"
 )
 
+// The auth middleware looks users up on every request. Entries are dropped when a user's token
+// version changes, the TTL bounds staleness for writes that bypass this process.
+const (
+	userCacheSize = 10_000
+	userCacheTTL  = time.Minute
+)
+
 func StartServer() {
 	container := dig.New()
 
@@ -42,8 +83,38 @@ func StartServer() {
 		log.Println("failed to provide pgxpool.Pool: ", err)
 		return
 	}
-	err = container.Provide(func(params user.UserRepositoryParams) user.UserRepositoryInterface {
-		return user.NewRepository(params)
+	err = container.Provide(func(params db.TxManagerParams) db.UnitOfWork {
+		return db.NewTxManager(params)
+	})
+	if err != nil {
+		log.Println("start unit of work error: ", err)
+		return
+	}
+	err = container.Invoke(func(pool *pgxpool.Pool, l logger.SugaredLoggerInterface) error {
+		migrator, err := migrate.New(pool, l, migrations.FS)
+		if err != nil {
+			return err
+		}
+		return migrator.Up(context.Background())
+	})
+	if err != nil {
+		log.Println("apply migrations error: ", err)
+		return
+	}
+	err = container.Provide(jwtkeys.FromEnv)
+	if err != nil {
+		log.Println("provide jwt keys error: ", err)
+		return
+	}
+	err = container.Provide(func() user.UserCache {
+		return user.NewLRUCache(userCacheSize, userCacheTTL)
+	})
+	if err != nil {
+		log.Println("failed to provide user cache: ", err)
+		return
+	}
+	err = container.Provide(func(params user.UserRepositoryParams, cache user.UserCache) user.UserRepositoryInterface {
+		return user.NewCachedRepository(user.NewRepository(params), cache)
 	})
 	if err != nil {
 		log.Println("failed to provide user.Repository: ", err)
@@ -61,6 +132,18 @@ func StartServer() {
 		log.Println("bind ExerciseRepositoryInterface error:", err)
 		return
 	}
+	err = container.Provide(categoryRepo.NewCategoryRepository)
+	if err != nil {
+		log.Println("start category repo error: ", err)
+		return
+	}
+	err = container.Provide(func(repo *categoryRepo.CategoryRepository) categoryRepo.CategoryRepositoryInterface {
+		return repo
+	})
+	if err != nil {
+		log.Println("bind CategoryRepositoryInterface error:", err)
+		return
+	}
 	err = container.Provide(func(params service.AuthServiceParams) handler.AuthServiceInterface {
 		return service.NewAuthService(params)
 	})
@@ -132,6 +215,231 @@ func StartServer() {
 		log.Println("bind AdminServiceInterface error:", err)
 		return
 	}
+	err = container.Provide(func(params sessionRepo.SessionRepositoryParams) sessionRepo.SessionRepositoryInterface {
+		return sessionRepo.NewSessionRepository(params)
+	})
+	if err != nil {
+		log.Println("start session repo error: ", err)
+		return
+	}
+	err = container.Provide(func(params sessionService.SessionServiceParams) session.SessionServiceInterface {
+		return sessionService.NewSessionService(params)
+	})
+	if err != nil {
+		log.Println("start session service error: ", err)
+		return
+	}
+	err = container.Provide(session.NewSessionHandler)
+	if err != nil {
+		log.Println("start session handler error: ", err)
+		return
+	}
+	err = container.Provide(func(params statisticsRepo.StatisticsRepositoryParams) statisticsRepo.StatisticsRepositoryInterface {
+		return statisticsRepo.NewStatisticsRepository(params)
+	})
+	if err != nil {
+		log.Println("start statistics repo error: ", err)
+		return
+	}
+	err = container.Provide(func(params statisticsService.StatisticsServiceParams) statistics.StatisticsServiceInterface {
+		return statisticsService.NewStatisticsService(params)
+	})
+	if err != nil {
+		log.Println("start statistics service error: ", err)
+		return
+	}
+	err = container.Provide(statistics.NewStatisticsHandler)
+	if err != nil {
+		log.Println("start statistics handler error: ", err)
+		return
+	}
+	err = container.Provide(func(params recordRepo.RecordRepositoryParams) recordRepo.RecordRepositoryInterface {
+		return recordRepo.NewRecordRepository(params)
+	})
+	if err != nil {
+		log.Println("start record repo error: ", err)
+		return
+	}
+	err = container.Provide(recordService.NewRecordService)
+	if err != nil {
+		log.Println("start record service error: ", err)
+		return
+	}
+	err = container.Provide(func(s *recordService.RecordService) record.RecordServiceInterface {
+		return s
+	})
+	if err != nil {
+		log.Println("bind RecordServiceInterface error:", err)
+		return
+	}
+	err = container.Provide(func(s *recordService.RecordService) sessionService.RecordDetector {
+		return s
+	})
+	if err != nil {
+		log.Println("bind RecordDetector error:", err)
+		return
+	}
+	err = container.Provide(record.NewRecordHandler)
+	if err != nil {
+		log.Println("start record handler error: ", err)
+		return
+	}
+	err = container.Provide(func(params exerciseService.ExerciseServiceParams) exerciseHandler.ExerciseServiceInterface {
+		return exerciseService.NewExerciseService(params)
+	})
+	if err != nil {
+		log.Println("start exercise service error: ", err)
+		return
+	}
+	err = container.Provide(exerciseHandler.NewExerciseHandler)
+	if err != nil {
+		log.Println("start exercise handler error: ", err)
+		return
+	}
+	err = container.Provide(func(params categoryService.CategoryServiceParams) categoryHandler.CategoryServiceInterface {
+		return categoryService.NewCategoryService(params)
+	})
+	if err != nil {
+		log.Println("start category service error: ", err)
+		return
+	}
+	err = container.Provide(categoryHandler.NewCategoryHandler)
+	if err != nil {
+		log.Println("start category handler error: ", err)
+		return
+	}
+	err = container.Provide(func(pool *pgxpool.Pool) programRepo.DBPool {
+		return pool
+	})
+	if err != nil {
+		log.Println("start program-repo error: ", err)
+		return
+	}
+	err = container.Provide(programRepo.NewProgramRepository)
+	if err != nil {
+		log.Println("start program repo error: ", err)
+		return
+	}
+	err = container.Provide(func(params programService.ProgramServiceParams) programHandler.ProgramServiceInterface {
+		return programService.NewProgramService(params)
+	})
+	if err != nil {
+		log.Println("start program service error: ", err)
+		return
+	}
+	err = container.Provide(programHandler.NewProgramHandler)
+	if err != nil {
+		log.Println("start program handler error: ", err)
+		return
+	}
+	err = container.Provide(func(pool *pgxpool.Pool) preferenceRepo.DBPool {
+		return pool
+	})
+	if err != nil {
+		log.Println("start preference-repo error: ", err)
+		return
+	}
+	err = container.Provide(preferenceRepo.NewPreferenceRepository)
+	if err != nil {
+		log.Println("start preference repo error: ", err)
+		return
+	}
+	err = container.Provide(func(params progressionService.ProgressionServiceParams) progressionHandler.ProgressionServiceInterface {
+		return progressionService.NewProgressionService(params)
+	})
+	if err != nil {
+		log.Println("start progression service error: ", err)
+		return
+	}
+	err = container.Provide(progressionHandler.NewProgressionHandler)
+	if err != nil {
+		log.Println("start progression handler error: ", err)
+		return
+	}
+	err = container.Provide(func(pool *pgxpool.Pool) scheduleRepo.DBPool {
+		return pool
+	})
+	if err != nil {
+		log.Println("start schedule-repo error: ", err)
+		return
+	}
+	err = container.Provide(scheduleRepo.NewScheduleRepository)
+	if err != nil {
+		log.Println("start schedule repo error: ", err)
+		return
+	}
+	err = container.Provide(func(params scheduleService.ScheduleServiceParams) scheduleHandler.ScheduleServiceInterface {
+		return scheduleService.NewScheduleService(params)
+	})
+	if err != nil {
+		log.Println("start schedule service error: ", err)
+		return
+	}
+	err = container.Provide(scheduleHandler.NewScheduleHandler)
+	if err != nil {
+		log.Println("start schedule handler error: ", err)
+		return
+	}
+	err = container.Provide(func(pool *pgxpool.Pool) measurementRepo.DBPool {
+		return pool
+	})
+	if err != nil {
+		log.Println("start measurement-repo error: ", err)
+		return
+	}
+	err = container.Provide(measurementRepo.NewMeasurementRepository)
+	if err != nil {
+		log.Println("start measurement repo error: ", err)
+		return
+	}
+	err = container.Provide(measurementService.NewMeasurementService)
+	if err != nil {
+		log.Println("start measurement service error: ", err)
+		return
+	}
+	err = container.Provide(func(s *measurementService.MeasurementService) measurementHandler.MeasurementServiceInterface {
+		return s
+	})
+	if err != nil {
+		log.Println("bind MeasurementServiceInterface error:", err)
+		return
+	}
+	err = container.Provide(func(s *measurementService.MeasurementService) statisticsService.Bodyweights {
+		return s
+	})
+	if err != nil {
+		log.Println("bind Bodyweights error:", err)
+		return
+	}
+	err = container.Provide(measurementHandler.NewMeasurementHandler)
+	if err != nil {
+		log.Println("start measurement handler error: ", err)
+		return
+	}
+	err = container.Provide(func(params service.AuthServiceParams) accountService.Passwords {
+		return service.NewAuthService(params)
+	})
+	if err != nil {
+		log.Println("start auth service error: ", err)
+		return
+	}
+	err = container.Provide(func(params accountService.AccountServiceParams) accountHandler.AccountServiceInterface {
+		return accountService.NewAccountService(params)
+	})
+	if err != nil {
+		log.Println("start account service error: ", err)
+		return
+	}
+	err = container.Provide(accountHandler.NewAccountHandler)
+	if err != nil {
+		log.Println("start account handler error: ", err)
+		return
+	}
+	err = validation.Register()
+	if err != nil {
+		log.Println("register validators error: ", err)
+		return
+	}
 	err = container.Provide(gin.Default)
 	if err != nil {
 		log.Println("start gin error: ", err)
@@ -143,8 +451,19 @@ func StartServer() {
 		authHandler *handler.AuthHandler,
 		adminHandler *admin.AdminHandler,
 		workoutHandler *workout.WorkoutHandler,
+		sessionHandler *session.SessionHandler,
+		statisticsHandler *statistics.StatisticsHandler,
+		recordHandler *record.RecordHandler,
+		exHandler *exerciseHandler.ExerciseHandler,
+		catHandler *categoryHandler.CategoryHandler,
+		progHandler *programHandler.ProgramHandler,
+		suggestionHandler *progressionHandler.ProgressionHandler,
+		schedHandler *scheduleHandler.ScheduleHandler,
+		measHandler *measurementHandler.MeasurementHandler,
+		accHandler *accountHandler.AccountHandler,
 		middleware *middleware.Middleware) {
-		SetupRoutes(router, authHandler, adminHandler, workoutHandler, middleware)
+		SetupRoutes(router, authHandler, adminHandler, workoutHandler, sessionHandler, statisticsHandler, recordHandler, exHandler,
+			catHandler, progHandler, suggestionHandler, schedHandler, measHandler, accHandler, middleware)
 		err := router.Run(":8080")
 		if err != nil {
 			return
@@ -0,0 +1,14 @@
+package category
+
+type CategoryRequest struct {
+	Slug     string `json:"slug" binding:"omitempty,max=64,slug"`
+	Name     string `json:"name" binding:"required,notblank,max=64"`
+	Icon     string `json:"icon" binding:"max=64"`
+	Color    string `json:"color" binding:"omitempty,hexcolor,len=7"`
+	Position int    `json:"position" binding:"gte=0"`
+}
+
+// ReorderRequest lists category ids in their new display order.
+type ReorderRequest struct {
+	IDs []int `json:"ids" binding:"required,min=1,unique,dive,gt=0"`
+}
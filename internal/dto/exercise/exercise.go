@@ -1,6 +1,31 @@
 package exercise
 
+import model "workout-tracker/internal/model/exercise"
+
 type CreateExerciseRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
+	Name             string                `json:"name" binding:"required,notblank,max=100"`
+	Description      string                `json:"description" binding:"max=1000"`
+	MovementType     model.MovementType    `json:"movement_type" binding:"omitempty,oneof=compound isolation"`
+	MeasurementKind  model.MeasurementKind `json:"measurement_kind" binding:"omitempty,oneof=weight_reps time distance"`
+	PrimaryMuscles   []string              `json:"primary_muscles" binding:"max=10,dive,notblank,max=50"`
+	SecondaryMuscles []string              `json:"secondary_muscles" binding:"max=10,dive,notblank,max=50"`
+	Equipment        []string              `json:"equipment" binding:"max=10,dive,notblank,max=50"`
+}
+
+type ExerciseFilter struct {
+	UserID          int
+	Search          string
+	Muscle          string
+	Equipment       string
+	MovementType    model.MovementType
+	MeasurementKind model.MeasurementKind
+	Limit           int
+	Offset          int
+}
+
+type ExercisePage struct {
+	Items  []model.Exercise `json:"items"`
+	Total  int              `json:"total"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
 }
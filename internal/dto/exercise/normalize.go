@@ -0,0 +1,71 @@
+package exercise
+
+import (
+	"strings"
+	"workout-tracker/internal/erorrs"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Normalize validates the enum filters, canonicalises the tag filters and applies the paging limits.
+func (filter ExerciseFilter) Normalize() (ExerciseFilter, error) {
+	if filter.MovementType != "" && !filter.MovementType.IsValid() {
+		return filter, erorrs.ErrInvalidMovementType
+	}
+	if filter.MeasurementKind != "" && !filter.MeasurementKind.IsValid() {
+		return filter, erorrs.ErrInvalidMeasurementKind
+	}
+
+	filter.Search = strings.TrimSpace(filter.Search)
+	filter.Muscle = normalizeTag(filter.Muscle)
+	filter.Equipment = normalizeTag(filter.Equipment)
+
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = defaultPageSize
+	case filter.Limit > maxPageSize:
+		filter.Limit = maxPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return filter, nil
+}
+
+// Normalize lower-cases and de-duplicates the metadata lists so that filters match exactly.
+// Nil lists stay nil, which leaves the stored values untouched on update.
+func (input CreateExerciseRequest) Normalize() CreateExerciseRequest {
+	input.PrimaryMuscles = normalizeTags(input.PrimaryMuscles)
+	input.SecondaryMuscles = normalizeTags(input.SecondaryMuscles)
+	input.Equipment = normalizeTags(input.Equipment)
+	return input
+}
+
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		result = append(result, tag)
+	}
+	return result
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
@@ -0,0 +1,35 @@
+package measurement
+
+import (
+	"time"
+	"workout-tracker/internal/model/units"
+)
+
+// MetricRequest defines a metric of the user's own. The slug is derived from the name when left out.
+type MetricRequest struct {
+	Slug string `json:"slug" binding:"omitempty,max=64,slug"`
+	Name string `json:"name" binding:"required,notblank,max=64"`
+	Kind string `json:"kind" binding:"required,oneof=mass length percent"`
+}
+
+// MeasurementRequest records a value of the metric. Unit defaults to the metric's unit in the
+// user's UnitSystem and measured_at to now.
+type MeasurementRequest struct {
+	MeasuredAt *time.Time `json:"measured_at"`
+	Value      *float64   `json:"value" binding:"required,gte=0,lt=100000"`
+	Metric     string     `json:"metric" binding:"required,max=64,slug"`
+	Unit       string     `json:"unit" binding:"omitempty,oneof=kg lb cm in %"`
+	Note       string     `json:"note" binding:"max=500"`
+
+	UnitSystem units.System `json:"-"`
+}
+
+// SeriesQuery selects the measurements of a series from From up to, not including, To. Nil bounds
+// are open; Unit defaults to the metric's unit in UnitSystem.
+type SeriesQuery struct {
+	From       *time.Time
+	To         *time.Time
+	Unit       string
+	UnitSystem units.System
+	WindowDays int
+}
@@ -0,0 +1,102 @@
+package program
+
+import (
+	workoutDTO "workout-tracker/internal/dto/workout"
+	model "workout-tracker/internal/model/program"
+	"workout-tracker/internal/model/units"
+)
+
+// ProgramRequest describes a whole program. Weeks are numbered by their position, days within a
+// week by their day field.
+type ProgramRequest struct {
+	Name        string                   `json:"name" binding:"required,notblank,max=100"`
+	Description string                   `json:"description" binding:"max=2000"`
+	Weeks       []WeekRequest            `json:"weeks" binding:"required,min=1,max=52,dive"`
+	Progression []ProgressionRuleRequest `json:"progression" binding:"omitempty,max=20,dive"`
+}
+
+type WeekRequest struct {
+	Days []DayRequest `json:"days" binding:"omitempty,max=7,unique=Day,dive"`
+}
+
+type DayRequest struct {
+	Day       int `json:"day" binding:"required,gte=1,lte=7"`
+	WorkoutID int `json:"workout_id" binding:"required,gt=0"`
+}
+
+type ProgressionRuleRequest struct {
+	ExerciseID *int    `json:"exercise_id" binding:"omitempty,gt=0"`
+	Type       string  `json:"type" binding:"required,oneof=weight percent_1rm reps"`
+	Amount     float64 `json:"amount" binding:"required,gte=-100,lte=100"`
+	EveryWeeks int     `json:"every_weeks" binding:"gte=0,lte=52"`
+}
+
+// Days flattens the weeks into the days that have a workout.
+func (r ProgramRequest) Days() []model.Day {
+	var days []model.Day
+	for i, w := range r.Weeks {
+		for _, d := range w.Days {
+			days = append(days, model.Day{Week: i + 1, Day: d.Day, WorkoutID: d.WorkoutID})
+		}
+	}
+	return days
+}
+
+// InKilograms returns a copy of the request with the amounts of weight rules, given in the system's
+// mass unit, converted to kilograms.
+func (r ProgramRequest) InKilograms(system units.System) ProgramRequest {
+	rules := make([]ProgressionRuleRequest, len(r.Progression))
+	for i, p := range r.Progression {
+		if p.Type == string(model.ProgressWeight) {
+			p.Amount = system.ToKilograms(p.Amount)
+		}
+		rules[i] = p
+	}
+	r.Progression = rules
+	return r
+}
+
+// Rules converts the progression rules, defaulting them to apply every week.
+func (r ProgramRequest) Rules() []model.ProgressionRule {
+	rules := make([]model.ProgressionRule, 0, len(r.Progression))
+	for _, p := range r.Progression {
+		every := p.EveryWeeks
+		if every == 0 {
+			every = 1
+		}
+		rules = append(rules, model.ProgressionRule{
+			ExerciseID: p.ExerciseID,
+			Type:       model.ProgressionType(p.Type),
+			Amount:     p.Amount,
+			EveryWeeks: every,
+		})
+	}
+	return rules
+}
+
+// EnrollRequest starts the program on StartDate, or today when it is left out.
+type EnrollRequest struct {
+	StartDate string `json:"start_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// TodayResponse tells an enrolled user what their program plans for a date. Workout is the day's
+// template with the progression of its week applied and is only set when Status is "workout".
+type TodayResponse struct {
+	Date        string                           `json:"date"`
+	Status      model.TodayStatus                `json:"status"`
+	ProgramName string                           `json:"program_name"`
+	Workout     *workoutDTO.WorkoutWithExercises `json:"workout,omitempty"`
+	ProgramID   int                              `json:"program_id"`
+	Week        int                              `json:"week,omitempty"`
+	Day         int                              `json:"day,omitempty"`
+}
+
+// InUnits returns a copy of the response with the weights of its workout converted to the system's
+// mass unit.
+func (t TodayResponse) InUnits(system units.System) TodayResponse {
+	if t.Workout != nil {
+		workout := t.Workout.InUnits(system)
+		t.Workout = &workout
+	}
+	return t
+}
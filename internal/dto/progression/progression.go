@@ -0,0 +1,37 @@
+package progression
+
+import (
+	"workout-tracker/internal/model/progression"
+	"workout-tracker/internal/model/units"
+	"workout-tracker/internal/model/workout"
+	join "workout-tracker/internal/model/workoutexercisejoin"
+)
+
+// PreferencesRequest changes the user's preferences. Preferences left out keep their saved value.
+type PreferencesRequest struct {
+	ProgressionStrategy string `json:"progression_strategy" binding:"omitempty,oneof=linear double rpe"`
+	UnitSystem          string `json:"unit_system" binding:"omitempty,oneof=metric imperial"`
+}
+
+// NextSessionResponse is a workout with a suggestion for every exercise, worked out with Strategy.
+// Weights are given in the mass unit of UnitSystem.
+type NextSessionResponse struct {
+	Strategy   progression.Strategy `json:"strategy"`
+	UnitSystem units.System         `json:"unit_system"`
+	Exercises  []SuggestedExercise  `json:"exercises"`
+	Workout    workout.Workout      `json:"workout"`
+}
+
+type SuggestedExercise struct {
+	join.WorkoutExercise
+	Suggestion progression.Suggestion `json:"suggestion"`
+}
+
+// InUnits returns a copy of the exercise with its weights converted from kilograms to the system's
+// mass unit. The suggested weight is rounded, only here, to what can be loaded with the system's
+// plates.
+func (e SuggestedExercise) InUnits(system units.System) SuggestedExercise {
+	e.WorkoutExercise = e.WorkoutExercise.InUnits(system)
+	e.Suggestion.Weight = system.RoundToPlates(e.Suggestion.Weight)
+	return e
+}
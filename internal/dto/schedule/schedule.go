@@ -0,0 +1,26 @@
+package schedule
+
+import "time"
+
+// ScheduleRequest plans a workout once at starts_at or, with repeat, every week on the given days
+// from starts_at on until the optional until date. Days and times repeat in timezone, which
+// defaults to the one of the user's profile.
+type ScheduleRequest struct {
+	StartsAt        time.Time `json:"starts_at" binding:"required"`
+	WorkoutID       int       `json:"workout_id" binding:"required,gt=0"`
+	DurationMinutes *int      `json:"duration_minutes" binding:"omitempty,gte=0,lte=1440"`
+	Repeat          []string  `json:"repeat" binding:"omitempty,max=7,unique,dive,oneof=mon tue wed thu fri sat sun"`
+	Until           string    `json:"until" binding:"omitempty,datetime=2006-01-02"`
+	Timezone        string    `json:"timezone" binding:"omitempty,timezone,max=64"`
+}
+
+// MarkRequest sets the status of one occurrence. Setting it back to planned removes the mark.
+type MarkRequest struct {
+	Status    string `json:"status" binding:"required,oneof=planned completed skipped"`
+	SessionID *int   `json:"session_id" binding:"omitempty,gt=0"`
+}
+
+type CalendarTokenResponse struct {
+	Token   string `json:"token"`
+	FeedURL string `json:"feed_url"`
+}
@@ -0,0 +1,42 @@
+package session
+
+import (
+	"time"
+	model "workout-tracker/internal/model/session"
+	"workout-tracker/internal/model/units"
+)
+
+type StartSessionRequest struct {
+	Notes string `json:"notes"`
+}
+
+type LogSetRequest struct {
+	CompletedAt *time.Time `json:"completed_at"`
+	RPE         *float64   `json:"rpe" binding:"omitempty,min=1,max=10"`
+	RestSeconds *int       `json:"rest_seconds" binding:"omitempty,min=0"`
+	Weight      float64    `json:"weight" binding:"min=0"`
+	ExerciseID  int        `json:"exercise_id" binding:"required"`
+	SetNumber   int        `json:"set_number" binding:"required,min=1"`
+	Reps        int        `json:"reps" binding:"min=0"`
+}
+
+// ToSet converts the request into a set, with the weight given in the system's mass unit stored in
+// kilograms.
+func (r LogSetRequest) ToSet(system units.System) model.SessionSet {
+	set := model.SessionSet{
+		ExerciseID:  r.ExerciseID,
+		SetNumber:   r.SetNumber,
+		Reps:        r.Reps,
+		Weight:      system.ToKilograms(r.Weight),
+		RPE:         r.RPE,
+		RestSeconds: r.RestSeconds,
+	}
+	if r.CompletedAt != nil {
+		set.CompletedAt = *r.CompletedAt
+	}
+	return set
+}
+
+type FinishSessionRequest struct {
+	Notes *string `json:"notes"`
+}
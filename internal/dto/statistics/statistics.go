@@ -0,0 +1,9 @@
+package statistics
+
+import "time"
+
+// StatisticsFilter restricts aggregation to sets completed within [From, To]. Nil bounds are open.
+type StatisticsFilter struct {
+	From *time.Time
+	To   *time.Time
+}
@@ -1,6 +1,13 @@
 package user
 
-import "workout-tracker/internal/model/user"
+import (
+	"time"
+	"workout-tracker/internal/model/units"
+	"workout-tracker/internal/model/user"
+)
+
+// DateLayout is the format of birth dates.
+const DateLayout = "2006-01-02"
 
 type RegisterRequest struct {
 	Username string `json:"username" binding:"required"`
@@ -13,9 +20,11 @@ type RegisterResponse struct {
 	ID       int       `json:"id"`
 }
 
+// LoginRequest signs in on a device. The device name is shown in the list of sessions.
 type LoginRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Username   string `json:"username" binding:"required"`
+	Password   string `json:"password" binding:"required"`
+	DeviceName string `json:"device_name" binding:"max=64"`
 }
 
 type LoginResponse struct {
@@ -25,3 +34,69 @@ type LoginResponse struct {
 	Role         user.Role `json:"role"`
 	UserID       int       `json:"user_id"`
 }
+
+// ProfileRequest replaces the user's profile; fields left out are cleared. The height is given in
+// the length unit of unit_system, which defaults to the user's current one.
+type ProfileRequest struct {
+	DisplayName string   `json:"display_name" binding:"max=64"`
+	Email       string   `json:"email" binding:"omitempty,email,max=255"`
+	BirthDate   string   `json:"birth_date" binding:"omitempty,datetime=2006-01-02"`
+	Height      *float64 `json:"height" binding:"omitempty,gt=0,lt=300"`
+	Sex         string   `json:"sex" binding:"omitempty,oneof=female male other"`
+	Timezone    string   `json:"timezone" binding:"omitempty,timezone,max=64"`
+	UnitSystem  string   `json:"unit_system" binding:"omitempty,oneof=metric imperial"`
+	AvatarURL   string   `json:"avatar_url" binding:"omitempty,http_url,max=2048"`
+}
+
+// ProfileResponse shows the profile with the height in the user's unit system.
+type ProfileResponse struct {
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	Height      *float64     `json:"height"`
+	Username    string       `json:"username"`
+	DisplayName string       `json:"display_name"`
+	Email       string       `json:"email"`
+	BirthDate   string       `json:"birth_date"`
+	HeightUnit  string       `json:"height_unit"`
+	Sex         user.Sex     `json:"sex"`
+	Timezone    string       `json:"timezone"`
+	UnitSystem  units.System `json:"unit_system"`
+	AvatarURL   string       `json:"avatar_url"`
+	ID          int          `json:"id"`
+}
+
+func NewProfileResponse(p user.Profile) ProfileResponse {
+	resp := ProfileResponse{
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+		Username:    p.Username,
+		DisplayName: p.DisplayName,
+		Email:       p.Email,
+		HeightUnit:  p.UnitSystem.LengthUnit(),
+		Sex:         p.Sex,
+		Timezone:    p.Timezone,
+		UnitSystem:  p.UnitSystem,
+		AvatarURL:   p.AvatarURL,
+		ID:          p.ID,
+	}
+	if p.BirthDate != nil {
+		resp.BirthDate = p.BirthDate.Format(DateLayout)
+	}
+	if p.HeightCm != nil {
+		height := p.UnitSystem.FromCentimetres(*p.HeightCm)
+		resp.Height = &height
+	}
+	return resp
+}
+
+// ChangePasswordRequest sets a new password. bcrypt ignores everything past 72 bytes, so longer
+// passwords are refused rather than silently truncated.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8,max=72"`
+}
+
+// DeleteAccountRequest confirms the deletion with the user's password.
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
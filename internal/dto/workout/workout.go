@@ -1,19 +1,105 @@
 package workout
 
 import (
+	"time"
+	"workout-tracker/internal/model/units"
 	"workout-tracker/internal/model/workout"
 	"workout-tracker/internal/model/workoutexercisejoin"
 )
 
+// CreateWorkoutWithExercisesRequest describes a workout either as a plain list of exercises or as
+// blocks of exercises, never both.
 type CreateWorkoutWithExercisesRequest struct {
-	Name      string `json:"name"`
-	Title     string `json:"title"`
-	Category  string `json:"category"`
-	Exercises []struct {
-		ExerciseID int `json:"exercise_id"`
-		Sets       int `json:"sets"`
-		Reps       int `json:"reps"`
-	} `json:"exercises"`
+	Name      string                   `json:"name" binding:"required,notblank,max=100"`
+	Title     string                   `json:"title" binding:"omitempty,max=200"`
+	Category  string                   `json:"category" binding:"omitempty,max=64"`
+	Exercises []WorkoutExerciseRequest `json:"exercises" binding:"required_without=Blocks,excluded_with=Blocks,omitempty,min=1,max=50,unique=ExerciseID,dive"`
+	Blocks    []WorkoutBlockRequest    `json:"blocks" binding:"omitempty,min=1,max=20,dive"`
+}
+
+type WorkoutBlockRequest struct {
+	Type        string                   `json:"type" binding:"omitempty,oneof=straight superset circuit emom amrap"`
+	Rounds      int                      `json:"rounds" binding:"gte=0,lte=100"`
+	RestSeconds int                      `json:"rest_seconds" binding:"gte=0,lte=3600"`
+	Exercises   []WorkoutExerciseRequest `json:"exercises" binding:"required,min=1,max=20,unique=ExerciseID,dive"`
+}
+
+// WorkoutExerciseRequest prescribes an exercise either as sets×reps or set by set. With a
+// prescription, sets and reps may be left out and are derived from it.
+type WorkoutExerciseRequest struct {
+	ExerciseID   int                      `json:"exercise_id" binding:"required,gt=0"`
+	Sets         int                      `json:"sets" binding:"required_without=Prescription,omitempty,gte=1,lte=100"`
+	Reps         int                      `json:"reps" binding:"required_without=Prescription,omitempty,gte=1,lte=1000"`
+	Prescription []SetPrescriptionRequest `json:"prescription" binding:"omitempty,max=100,dive"`
+}
+
+type SetPrescriptionRequest struct {
+	Type         string   `json:"type" binding:"omitempty,oneof=warmup working drop failure"`
+	Reps         *int     `json:"reps" binding:"omitempty,gte=1,lte=1000"`
+	RepsMax      *int     `json:"reps_max" binding:"omitempty,gte=1,lte=1000"`
+	Weight       *float64 `json:"weight" binding:"omitempty,gte=0,lte=2000"`
+	PercentOneRM *float64 `json:"percent_1rm" binding:"omitempty,gt=0,lte=150"`
+	RPE          *float64 `json:"rpe" binding:"omitempty,gte=1,lte=10"`
+	RestSeconds  *int     `json:"rest_seconds" binding:"omitempty,gte=0,lte=3600"`
+}
+
+// ToBlocks converts the request into blocks, with weights given in the system's mass unit stored
+// in kilograms. A plain exercise list becomes a single block of straight sets.
+func (r CreateWorkoutWithExercisesRequest) ToBlocks(system units.System) []workoutexercisejoin.Block {
+	if len(r.Blocks) == 0 {
+		return []workoutexercisejoin.Block{{
+			Type:      workoutexercisejoin.BlockStraight,
+			Exercises: toExercises(r.Exercises, system),
+			Implicit:  true,
+		}}
+	}
+
+	blocks := make([]workoutexercisejoin.Block, 0, len(r.Blocks))
+	for _, b := range r.Blocks {
+		blocks = append(blocks, workoutexercisejoin.Block{
+			Type:        workoutexercisejoin.BlockType(b.Type),
+			Rounds:      b.Rounds,
+			RestSeconds: b.RestSeconds,
+			Exercises:   toExercises(b.Exercises, system),
+		})
+	}
+	return blocks
+}
+
+func toExercises(list []WorkoutExerciseRequest, system units.System) []workoutexercisejoin.WorkoutExercise {
+	exercises := make([]workoutexercisejoin.WorkoutExercise, 0, len(list))
+	for _, e := range list {
+		exercises = append(exercises, workoutexercisejoin.WorkoutExercise{
+			ExerciseID:   e.ExerciseID,
+			Sets:         e.Sets,
+			Reps:         e.Reps,
+			Prescription: toPrescription(e.Prescription, system),
+		})
+	}
+	return exercises
+}
+
+func toPrescription(list []SetPrescriptionRequest, system units.System) []workoutexercisejoin.SetPrescription {
+	if len(list) == 0 {
+		return nil
+	}
+	sets := make([]workoutexercisejoin.SetPrescription, 0, len(list))
+	for _, s := range list {
+		if s.Weight != nil {
+			kg := system.ToKilograms(*s.Weight)
+			s.Weight = &kg
+		}
+		sets = append(sets, workoutexercisejoin.SetPrescription{
+			Type:         workoutexercisejoin.SetType(s.Type),
+			Reps:         s.Reps,
+			RepsMax:      s.RepsMax,
+			Weight:       s.Weight,
+			PercentOneRM: s.PercentOneRM,
+			RPE:          s.RPE,
+			RestSeconds:  s.RestSeconds,
+		})
+	}
+	return sets
 }
 
 type CreateWorkoutWithExercisesResponse struct {
@@ -43,7 +129,62 @@ type UpdateWorkoutRequest struct {
 	ID       int    `json:"id"`
 }
 
+// WorkoutWithExercises carries the workout's exercises both in order and grouped by block.
 type WorkoutWithExercises struct {
 	workout.Workout
 	Exercises []workoutexercisejoin.WorkoutExercise `json:"exercises"`
+	Blocks    []workoutexercisejoin.Block           `json:"blocks"`
+}
+
+// InUnits returns a copy of the workout with its weights converted to the system's mass unit.
+func (w WorkoutWithExercises) InUnits(system units.System) WorkoutWithExercises {
+	exercises := make([]workoutexercisejoin.WorkoutExercise, len(w.Exercises))
+	for i, e := range w.Exercises {
+		exercises[i] = e.InUnits(system)
+	}
+	blocks := make([]workoutexercisejoin.Block, len(w.Blocks))
+	for i, b := range w.Blocks {
+		b.Exercises = make([]workoutexercisejoin.WorkoutExercise, len(w.Blocks[i].Exercises))
+		for j, e := range w.Blocks[i].Exercises {
+			b.Exercises[j] = e.InUnits(system)
+		}
+		blocks[i] = b
+	}
+	w.Exercises, w.Blocks = exercises, blocks
+	return w
+}
+
+type WorkoutFilter struct {
+	From     *time.Time
+	To       *time.Time
+	Category string
+	Search   string
+	Cursor   string
+	SortBy   workout.SortField
+	Order    workout.SortOrder
+	Limit    int
+}
+
+// WorkoutCursor points at the last workout of a page. Value holds that workout's sort column
+// so the next page can continue right after it.
+type WorkoutCursor struct {
+	SortBy workout.SortField `json:"s"`
+	Value  string            `json:"v"`
+	ID     int               `json:"id"`
+}
+
+type WorkoutPage struct {
+	NextCursor string                 `json:"next_cursor,omitempty"`
+	Items      []WorkoutWithExercises `json:"items"`
+}
+
+// InUnits returns a copy of the page with the weights of every workout converted to the system's
+// mass unit.
+func (p WorkoutPage) InUnits(system units.System) WorkoutPage {
+	items := make([]WorkoutWithExercises, len(p.Items))
+	for i, w := range p.Items {
+		items[i] = w.InUnits(system)
+	}
+	p.Items = items
+	return p
 }
@@ -0,0 +1,130 @@
+package erorrs
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Machine-readable error codes returned to clients in the "code" member of a problem response.
+const (
+	CodeInvalidRequest     = "invalid_request"
+	CodeValidationFailed   = "validation_failed"
+	CodeUnauthorized       = "unauthorized"
+	CodeInvalidCredentials = "invalid_credentials"
+	CodeInvalidToken       = "invalid_token"
+	CodeForbidden          = "forbidden"
+	CodeNotFound           = "not_found"
+	CodeConflict           = "conflict"
+	CodeUnknownExercise    = "unknown_exercise"
+	CodeInternal           = "internal_error"
+)
+
+// FieldError describes a single invalid field of a request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// AppError is an error that knows how it should be presented to the client. Message and Fields are
+// safe to show to users; the wrapped cause is only ever logged.
+type AppError struct {
+	Code    string
+	Status  int
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func New(status int, code, message string) *AppError {
+	return &AppError{Code: code, Status: status, Message: message}
+}
+
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// Wrap returns a copy of e with err recorded as its cause.
+func (e *AppError) Wrap(err error) *AppError {
+	wrapped := *e
+	wrapped.Err = err
+	return &wrapped
+}
+
+// WithFields returns a copy of e carrying the given field errors.
+func (e *AppError) WithFields(fields ...FieldError) *AppError {
+	withFields := *e
+	withFields.Fields = append(append([]FieldError(nil), e.Fields...), fields...)
+	return &withFields
+}
+
+func BadRequest(message string) *AppError {
+	return New(http.StatusBadRequest, CodeInvalidRequest, message)
+}
+
+// Validation reports a well-formed request whose content breaks one or more rules.
+func Validation(fields ...FieldError) *AppError {
+	return New(http.StatusUnprocessableEntity, CodeValidationFailed, "request validation failed").WithFields(fields...)
+}
+
+func NotFound(message string) *AppError {
+	return New(http.StatusNotFound, CodeNotFound, message)
+}
+
+func Internal(err error) *AppError {
+	return New(http.StatusInternalServerError, CodeInternal, "internal server error").Wrap(err)
+}
+
+// sentinels maps the package's sentinel errors to their HTTP representation. The sentinel texts are
+// written for users, so they double as the message.
+var sentinels = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{ErrNotFound, http.StatusNotFound, CodeNotFound},
+	{ErrUserNotFound, http.StatusNotFound, CodeNotFound},
+	{ErrNotEnrolled, http.StatusNotFound, CodeNotFound},
+	{ErrForbidden, http.StatusForbidden, CodeForbidden},
+	{ErrUsernameAlreadyExists, http.StatusConflict, CodeConflict},
+	{ErrEmailAlreadyExists, http.StatusConflict, CodeConflict},
+	{ErrExerciseAlreadyExists, http.StatusConflict, CodeConflict},
+	{ErrCategoryAlreadyExists, http.StatusConflict, CodeConflict},
+	{ErrMetricAlreadyExists, http.StatusConflict, CodeConflict},
+	{ErrSessionFinished, http.StatusConflict, CodeConflict},
+	{ErrSetAlreadyLogged, http.StatusConflict, CodeConflict},
+	{ErrInvalidToken, http.StatusUnauthorized, CodeInvalidToken},
+	{ErrTokenNotFound, http.StatusUnauthorized, CodeInvalidToken},
+	{ErrUnknownExercise, http.StatusUnprocessableEntity, CodeUnknownExercise},
+	{ErrInvalidBucket, http.StatusBadRequest, CodeInvalidRequest},
+	{ErrInvalidDateRange, http.StatusBadRequest, CodeInvalidRequest},
+	{ErrCalendarRangeTooLong, http.StatusBadRequest, CodeInvalidRequest},
+	{ErrInvalidSort, http.StatusBadRequest, CodeInvalidRequest},
+	{ErrInvalidOrder, http.StatusBadRequest, CodeInvalidRequest},
+	{ErrInvalidCursor, http.StatusBadRequest, CodeInvalidRequest},
+	{ErrInvalidMovementType, http.StatusBadRequest, CodeInvalidRequest},
+	{ErrInvalidMeasurementKind, http.StatusBadRequest, CodeInvalidRequest},
+}
+
+// FromError converts any error into an AppError. Known sentinels keep their meaning; everything else
+// becomes an internal error whose details are hidden from the client.
+func FromError(err error) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	for _, s := range sentinels {
+		if errors.Is(err, s.err) {
+			return New(s.status, s.code, s.err.Error()).Wrap(err)
+		}
+	}
+
+	return Internal(err)
+}
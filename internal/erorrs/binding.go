@@ -0,0 +1,69 @@
+package erorrs
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FromBinding converts an error returned by gin's binding into a client error. Validation failures
+// list every offending field; anything else means the body could not be decoded at all.
+func FromBinding(err error) *AppError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return BadRequest("malformed request body").Wrap(err)
+	}
+
+	fields := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fields = append(fields, FieldError{Field: fieldPath(fe), Message: describe(fe)})
+	}
+
+	return Validation(fields...).Wrap(err)
+}
+
+// fieldPath returns the path of the field below the request struct, e.g. "exercises[1].sets".
+func fieldPath(fe validator.FieldError) string {
+	if _, path, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return path
+	}
+	return fe.Field()
+}
+
+func describe(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_without":
+		return "is required"
+	case "notblank":
+		return "must not be blank"
+	case "unique":
+		return "must not contain duplicates"
+	case "slug":
+		return "must contain only lowercase letters, digits and single dashes"
+	case "hexcolor":
+		return "must be a hex color such as #1e90ff"
+	case "len":
+		return "must have a length of " + fe.Param()
+	case "email":
+		return "must be an email address"
+	case "http_url":
+		return "must be an http or https URL"
+	case "timezone":
+		return "must be an IANA timezone such as Europe/Berlin"
+	case "datetime":
+		return "must be a date such as " + fe.Param()
+	case "oneof":
+		return "must be one of " + fe.Param()
+	case "min", "gte":
+		return "must be at least " + fe.Param()
+	case "max", "lte":
+		return "must be at most " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	default:
+		return "is invalid (" + fe.Tag() + ")"
+	}
+}
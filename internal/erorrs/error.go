@@ -3,10 +3,26 @@ package erorrs
 import "errors"
 
 var ErrUsernameAlreadyExists = errors.New("username already exists")
+var ErrEmailAlreadyExists = errors.New("email already in use")
 var ErrUserNotFound = errors.New("user not found")
 var ErrTokenNotFound = errors.New("token not found")
 var ErrExerciseAlreadyExists = errors.New("exercise already exists")
 var ErrNotFound = errors.New("not found")
+var ErrForbidden = errors.New("forbidden")
+var ErrSessionFinished = errors.New("session already finished")
+var ErrSetAlreadyLogged = errors.New("set number already logged for this exercise")
+var ErrInvalidBucket = errors.New("bucket must be one of day, week, month")
+var ErrInvalidDateRange = errors.New("from must not be after to")
+var ErrCalendarRangeTooLong = errors.New("calendar range must not exceed 366 days")
+var ErrInvalidSort = errors.New("sort must be one of created_at, updated_at, name, title")
+var ErrInvalidOrder = errors.New("order must be asc or desc")
+var ErrInvalidCursor = errors.New("invalid cursor")
+var ErrInvalidMovementType = errors.New("movement_type must be compound or isolation")
+var ErrInvalidMeasurementKind = errors.New("measurement_kind must be one of weight_reps, time, distance")
+var ErrUnknownExercise = errors.New("exercise does not exist or is not accessible")
+var ErrCategoryAlreadyExists = errors.New("category already exists")
+var ErrNotEnrolled = errors.New("not enrolled in a program")
+var ErrMetricAlreadyExists = errors.New("metric already exists")
 var (
 	ErrInvalidToken = errors.New("invalid or expired refresh token")
 	ErrInternal     = errors.New("internal server error")
@@ -0,0 +1,134 @@
+package account
+
+import (
+	"net/http"
+	dto "workout-tracker/internal/dto/user"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/dig"
+)
+
+var errInvalidSessionID = erorrs.BadRequest("invalid session id")
+
+type AccountHandlerParams struct {
+	dig.In
+
+	Service AccountServiceInterface
+	Logger  logger.SugaredLoggerInterface
+}
+
+type AccountHandler struct {
+	Service AccountServiceInterface
+	Log     logger.SugaredLoggerInterface
+}
+
+func NewAccountHandler(params AccountHandlerParams) *AccountHandler {
+	return &AccountHandler{
+		Service: params.Service,
+		Log:     params.Logger,
+	}
+}
+
+func (h *AccountHandler) Get(c *gin.Context) {
+	profile, err := h.Service.GetProfile(c.Request.Context(), c.GetInt("userID"))
+	if err != nil {
+		h.Log.Errorw("error getting profile", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *AccountHandler) Update(c *gin.Context) {
+	var req dto.ProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	profile, err := h.Service.UpdateProfile(c.Request.Context(), c.GetInt("userID"), req)
+	if err != nil {
+		h.Log.Errorw("error updating profile", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// ChangePassword sets a new password. The access token used for the request stops working too, so
+// the client has to sign in again.
+func (h *AccountHandler) ChangePassword(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	if err := h.Service.ChangePassword(c.Request.Context(), c.GetInt("userID"), req); err != nil {
+		h.Log.Errorw("error changing password", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AccountHandler) Delete(c *gin.Context) {
+	var req dto.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	if err := h.Service.DeleteAccount(c.Request.Context(), c.GetInt("userID"), req); err != nil {
+		h.Log.Errorw("error deleting account", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetSessions lists the devices the user is signed in on.
+func (h *AccountHandler) GetSessions(c *gin.Context) {
+	sessions, err := h.Service.GetSessions(c.Request.Context(), c.GetInt("userID"), c.GetString("sessionID"))
+	if err != nil {
+		h.Log.Errorw("error getting sessions", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+func (h *AccountHandler) RevokeSession(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidSessionID.Wrap(err))
+		return
+	}
+
+	if err := h.Service.RevokeSession(c.Request.Context(), c.GetInt("userID"), sessionID.String()); err != nil {
+		h.Log.Errorw("error revoking session", "sessionID", sessionID, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeOtherSessions signs out everywhere else. The session of the request stays signed in.
+func (h *AccountHandler) RevokeOtherSessions(c *gin.Context) {
+	if err := h.Service.RevokeOtherSessions(c.Request.Context(), c.GetInt("userID"), c.GetString("sessionID")); err != nil {
+		h.Log.Errorw("error revoking other sessions", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
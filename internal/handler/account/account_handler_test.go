@@ -0,0 +1,155 @@
+package account
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	dto "workout-tracker/internal/dto/user"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+	"workout-tracker/internal/model/user/jwt"
+	"workout-tracker/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupRouter(fs *FakeService) *gin.Engine {
+	if err := validation.Register(); err != nil {
+		panic(err)
+	}
+	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
+	h := NewAccountHandler(AccountHandlerParams{
+		Service: fs,
+		Logger:  zap.NewNop().Sugar(),
+	})
+	authed := r.Group("").Use(func(c *gin.Context) {
+		c.Set("userID", 7)
+		c.Set("sessionID", "current")
+		c.Next()
+	})
+	authed.GET("/me", h.Get)
+	authed.PUT("/me", h.Update)
+	authed.PUT("/me/password", h.ChangePassword)
+	authed.DELETE("/me", h.Delete)
+	authed.GET("/me/sessions", h.GetSessions)
+	authed.DELETE("/me/sessions", h.RevokeOtherSessions)
+	authed.DELETE("/me/sessions/:id", h.RevokeSession)
+	return r
+}
+
+func send(r *gin.Engine, method, path, payload string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGet_Success(t *testing.T) {
+	fs := &FakeService{Profile: &dto.ProfileResponse{ID: 7, Username: "ann", Timezone: "UTC"}}
+	r := setupRouter(fs)
+	w := send(r, http.MethodGet, "/me", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 7, fs.LastUserID)
+
+	var resp dto.ProfileResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "ann", resp.Username)
+}
+
+func TestUpdate_Success(t *testing.T) {
+	fs := &FakeService{Profile: &dto.ProfileResponse{ID: 7}}
+	r := setupRouter(fs)
+	w := send(r, http.MethodPut, "/me",
+		`{"display_name":"Ann","email":"ann@example.com","birth_date":"1990-05-17","height":70,"timezone":"Europe/Berlin","unit_system":"imperial"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Europe/Berlin", fs.LastProfile.Timezone)
+	assert.Equal(t, 70.0, *fs.LastProfile.Height)
+}
+
+func TestUpdate_Invalid(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodPut, "/me",
+		`{"email":"ann","birth_date":"17.05.1990","timezone":"Mars/Olympus","sex":"x","avatar_url":"ftp://example.com/a.png"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	fields := make([]string, 0, len(problem.Errors))
+	for _, fe := range problem.Errors {
+		fields = append(fields, fe.Field)
+	}
+	assert.ElementsMatch(t, []string{"email", "birth_date", "timezone", "sex", "avatar_url"}, fields)
+}
+
+func TestUpdate_EmailTaken(t *testing.T) {
+	r := setupRouter(&FakeService{UpdateErr: erorrs.ErrEmailAlreadyExists})
+	w := send(r, http.MethodPut, "/me", `{"email":"ann@example.com"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestChangePassword(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	w := send(r, http.MethodPut, "/me/password", `{"current_password":"secret","new_password":"much-longer"}`)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "much-longer", fs.LastPassword.NewPassword)
+
+	w = send(r, http.MethodPut, "/me/password", `{"current_password":"secret","new_password":"short"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestDelete(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	w := send(r, http.MethodDelete, "/me", `{"password":"secret"}`)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "secret", fs.LastDelete.Password)
+
+	w = send(r, http.MethodDelete, "/me", `{}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestGetSessions(t *testing.T) {
+	fs := &FakeService{Sessions: []jwt.Session{{ID: "current", Current: true, Device: jwt.Device{Name: "Pixel"}}}}
+	r := setupRouter(fs)
+	w := send(r, http.MethodGet, "/me/sessions", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "current", fs.LastSession)
+
+	var resp []map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp, 1)
+	assert.Equal(t, "Pixel", resp[0]["device_name"])
+	assert.Equal(t, true, resp[0]["current"])
+}
+
+func TestRevokeSession(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	id := "0b0a4e3c-5f61-4a9e-9d57-0f3d8a2b1c11"
+	w := send(r, http.MethodDelete, "/me/sessions/"+id, "")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, id, fs.LastSession)
+
+	w = send(r, http.MethodDelete, "/me/sessions/42", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	r = setupRouter(&FakeService{RevokeErr: erorrs.ErrNotFound})
+	w = send(r, http.MethodDelete, "/me/sessions/"+id, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRevokeOtherSessions(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	w := send(r, http.MethodDelete, "/me/sessions", "")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "current", fs.LastSession, "the current session is kept")
+}
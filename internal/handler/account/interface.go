@@ -0,0 +1,20 @@
+package account
+
+import (
+	"context"
+	dto "workout-tracker/internal/dto/user"
+	"workout-tracker/internal/model/user/jwt"
+	"workout-tracker/internal/service/account"
+)
+
+type AccountServiceInterface interface {
+	GetProfile(ctx context.Context, userID int) (*dto.ProfileResponse, error)
+	UpdateProfile(ctx context.Context, userID int, input dto.ProfileRequest) (*dto.ProfileResponse, error)
+	ChangePassword(ctx context.Context, userID int, input dto.ChangePasswordRequest) error
+	DeleteAccount(ctx context.Context, userID int, input dto.DeleteAccountRequest) error
+	GetSessions(ctx context.Context, userID int, currentSessionID string) ([]jwt.Session, error)
+	RevokeSession(ctx context.Context, userID int, sessionID string) error
+	RevokeOtherSessions(ctx context.Context, userID int, currentSessionID string) error
+}
+
+var _ AccountServiceInterface = (*account.AccountService)(nil)
@@ -0,0 +1,63 @@
+package account
+
+import (
+	"context"
+	dto "workout-tracker/internal/dto/user"
+	"workout-tracker/internal/model/user/jwt"
+)
+
+type FakeService struct {
+	Profile      *dto.ProfileResponse
+	GetErr       error
+	UpdateErr    error
+	PasswordErr  error
+	DeleteErr    error
+	LastProfile  dto.ProfileRequest
+	LastPassword dto.ChangePasswordRequest
+	LastDelete   dto.DeleteAccountRequest
+	Sessions     []jwt.Session
+	RevokeErr    error
+	LastSession  string
+	LastUserID   int
+}
+
+func (f *FakeService) GetProfile(ctx context.Context, userID int) (*dto.ProfileResponse, error) {
+	f.LastUserID = userID
+	return f.Profile, f.GetErr
+}
+
+func (f *FakeService) UpdateProfile(ctx context.Context, userID int, input dto.ProfileRequest) (*dto.ProfileResponse, error) {
+	f.LastUserID = userID
+	f.LastProfile = input
+	return f.Profile, f.UpdateErr
+}
+
+func (f *FakeService) ChangePassword(ctx context.Context, userID int, input dto.ChangePasswordRequest) error {
+	f.LastUserID = userID
+	f.LastPassword = input
+	return f.PasswordErr
+}
+
+func (f *FakeService) DeleteAccount(ctx context.Context, userID int, input dto.DeleteAccountRequest) error {
+	f.LastUserID = userID
+	f.LastDelete = input
+	return f.DeleteErr
+}
+
+func (f *FakeService) GetSessions(ctx context.Context, userID int, currentSessionID string) ([]jwt.Session, error) {
+	f.LastUserID = userID
+	f.LastSession = currentSessionID
+	return f.Sessions, nil
+}
+
+func (f *FakeService) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	f.LastUserID = userID
+	f.LastSession = sessionID
+	return f.RevokeErr
+}
+
+func (f *FakeService) RevokeOtherSessions(ctx context.Context, userID int, currentSessionID string) error {
+	f.LastUserID = userID
+	f.LastSession = currentSessionID
+	return f.RevokeErr
+}
@@ -1,10 +1,12 @@
 package admin
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	dto "workout-tracker/internal/dto/exercise"
 	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/exercise"
 	"workout-tracker/pkg/logger"
 
 	"github.com/gin-gonic/gin"
@@ -22,6 +24,8 @@ type AdminHandler struct {
 	Logger  logger.SugaredLoggerInterface
 }
 
+var errInvalidID = erorrs.BadRequest("invalid exercise id")
+
 func NewAdminHandler(p AdminHandlerParams) *AdminHandler {
 	return &AdminHandler{
 		Service: p.Service,
@@ -33,14 +37,14 @@ func (h *AdminHandler) CreateExercise(c *gin.Context) {
 	var ex dto.CreateExerciseRequest
 	if err := c.ShouldBindJSON(&ex); err != nil {
 		h.Logger.Errorw("Invalid exercise data", erorrs.ErrorKey, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		_ = c.Error(erorrs.FromBinding(err))
 		return
 	}
 
 	created, err := h.Service.CreateExercise(c.Request.Context(), ex)
 	if err != nil {
 		h.Logger.Errorw("Failed to create exercise", erorrs.ErrorKey, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create exercise"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -53,36 +57,56 @@ func (h *AdminHandler) UpdateExercise(c *gin.Context) {
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		h.Logger.Errorw("Invalid exercise ID", "id", idStr, erorrs.ErrorKey, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exercise ID"})
+		_ = c.Error(errInvalidID.Wrap(err))
 		return
 	}
 
 	var ex dto.CreateExerciseRequest
 	if err := c.ShouldBindJSON(&ex); err != nil {
 		h.Logger.Errorw("Invalid exercise data", erorrs.ErrorKey, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		_ = c.Error(erorrs.FromBinding(err))
 		return
 	}
 
 	err = h.Service.UpdateExercise(c.Request.Context(), id, ex)
 	if err != nil {
 		h.Logger.Errorw("Failed to update exercise", "id", id, erorrs.ErrorKey, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update exercise"})
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"answer": "exercise successfully updated"})
 }
 
+// GetAllExercises lists the catalogue. Supported query parameters are q (full-text search), muscle,
+// equipment, movement_type, measurement_kind, limit and offset.
 func (h *AdminHandler) GetAllExercises(c *gin.Context) {
-	exercises, err := h.Service.GetAllExercises(c.Request.Context())
+	filter := dto.ExerciseFilter{
+		Search:          c.Query("q"),
+		Muscle:          c.Query("muscle"),
+		Equipment:       c.Query("equipment"),
+		MovementType:    model.MovementType(c.Query("movement_type")),
+		MeasurementKind: model.MeasurementKind(c.Query("measurement_kind")),
+	}
+
+	var err error
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		_ = c.Error(erorrs.BadRequest("invalid limit").Wrap(err))
+		return
+	}
+	if filter.Offset, err = queryInt(c, "offset"); err != nil {
+		_ = c.Error(erorrs.BadRequest("invalid offset").Wrap(err))
+		return
+	}
+
+	page, err := h.Service.GetAllExercises(c.Request.Context(), filter)
 	if err != nil {
 		h.Logger.Errorw("Failed to retrieve exercises", erorrs.ErrorKey, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve exercises"})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, exercises)
+	c.JSON(http.StatusOK, page)
 }
 
 func (h *AdminHandler) DeleteExercise(c *gin.Context) {
@@ -90,16 +114,28 @@ func (h *AdminHandler) DeleteExercise(c *gin.Context) {
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		h.Logger.Errorw("Invalid exercise ID", "id", idStr, erorrs.ErrorKey, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exercise ID"})
+		_ = c.Error(errInvalidID.Wrap(err))
 		return
 	}
 
 	err = h.Service.DeleteExercise(c.Request.Context(), id)
 	if err != nil {
 		h.Logger.Errorw("Failed to delete exercise", erorrs.ErrorKey, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete exercise"})
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusNoContent, gin.H{"answer": "exercise successfully deleted"})
 }
+
+func queryInt(c *gin.Context, key string) (int, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, raw)
+	}
+	return value, nil
+}
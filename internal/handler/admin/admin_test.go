@@ -8,7 +8,10 @@ import (
 	"net/http/httptest"
 	"testing"
 	"workout-tracker/internal/dto/exercise"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
 	exerciseRepsonse "workout-tracker/internal/model/exercise"
+	"workout-tracker/internal/validation"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -17,15 +20,19 @@ import (
 )
 
 func setupRouter(svc *FakeAdminService) *gin.Engine {
+	if err := validation.Register(); err != nil {
+		panic(err)
+	}
 	r := gin.Default()
-	handler := NewAdminHandler(AdminHandlerParams{
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
+	h := NewAdminHandler(AdminHandlerParams{
 		Service: svc,
 		Logger:  zap.NewNop().Sugar(),
 	})
-	r.POST("/admin/exercises", handler.CreateExercise)
-	r.PUT("/admin/exercises/:id", handler.UpdateExercise)
-	r.GET("/admin/exercises", handler.GetAllExercises)
-	r.DELETE("/admin/exercises/:id", handler.DeleteExercise)
+	r.POST("/admin/exercises", h.CreateExercise)
+	r.PUT("/admin/exercises/:id", h.UpdateExercise)
+	r.GET("/admin/exercises", h.GetAllExercises)
+	r.DELETE("/admin/exercises/:id", h.DeleteExercise)
 	return r
 }
 
@@ -69,6 +76,16 @@ func TestCreateExercise_Success(t *testing.T) {
 	assert.Equal(t, 42, resp["exercise_id"])
 }
 
+func TestCreateExercise_InvalidMovementType(t *testing.T) {
+	r := setupRouter(&FakeAdminService{CreateID: 42})
+	payload := `{"name":"Squat","movement_type":"explosive","primary_muscles":["quads"]}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/admin/exercises", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
 func TestUpdateExercise_InvalidID(t *testing.T) {
 	r := setupRouter(&FakeAdminService{})
 	w := httptest.NewRecorder()
@@ -115,9 +132,12 @@ func TestUpdateExercise_Success(t *testing.T) {
 
 func TestGetAllExercises_Success(t *testing.T) {
 	r := setupRouter(&FakeAdminService{
-		GetAllResult: []exerciseRepsonse.Exercise{
-			{ID: 1, Name: "Squat", Description: "Leg"},
-			{ID: 2, Name: "Push-up", Description: "Upper body"},
+		GetAllResult: &exercise.ExercisePage{
+			Items: []exerciseRepsonse.Exercise{
+				{ID: 1, Name: "Squat", Description: "Leg"},
+				{ID: 2, Name: "Push-up", Description: "Upper body"},
+			},
+			Total: 2,
 		},
 	})
 	w := httptest.NewRecorder()
@@ -125,9 +145,46 @@ func TestGetAllExercises_Success(t *testing.T) {
 	r.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var resp []exerciseRepsonse.Exercise
+	var resp exercise.ExercisePage
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Len(t, resp, 2)
+	assert.Len(t, resp.Items, 2)
+	assert.Equal(t, 2, resp.Total)
+}
+
+func TestGetAllExercises_ParsesFilter(t *testing.T) {
+	fs := &FakeAdminService{GetAllResult: &exercise.ExercisePage{}}
+	r := setupRouter(fs)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet,
+		"/admin/exercises?q=bench&muscle=chest&equipment=barbell&movement_type=compound&measurement_kind=weight_reps&limit=5&offset=10",
+		http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, exercise.ExerciseFilter{
+		Search:          "bench",
+		Muscle:          "chest",
+		Equipment:       "barbell",
+		MovementType:    exerciseRepsonse.Compound,
+		MeasurementKind: exerciseRepsonse.WeightReps,
+		Limit:           5,
+		Offset:          10,
+	}, fs.LastFilter)
+}
+
+func TestGetAllExercises_InvalidOffset(t *testing.T) {
+	r := setupRouter(&FakeAdminService{})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/admin/exercises?offset=-1", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetAllExercises_InvalidMovementType(t *testing.T) {
+	r := setupRouter(&FakeAdminService{GetAllErr: erorrs.ErrInvalidMovementType})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/admin/exercises?movement_type=explosive", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
 func TestGetAllExercises_Error(t *testing.T) {
@@ -138,6 +195,29 @@ func TestGetAllExercises_Error(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+func TestUpdateExercise_NotFound(t *testing.T) {
+	r := setupRouter(&FakeAdminService{UpdateErr: erorrs.ErrNotFound})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/admin/exercises/9", bytes.NewBufferString(`{"name":"Bench"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+}
+
+func TestUpdateExercise_Duplicate(t *testing.T) {
+	r := setupRouter(&FakeAdminService{UpdateErr: erorrs.ErrExerciseAlreadyExists})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/admin/exercises/9", bytes.NewBufferString(`{"name":"Bench"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, erorrs.CodeConflict, problem.Code)
+}
+
 func TestDeleteExercise_InvalidID(t *testing.T) {
 	r := setupRouter(&FakeAdminService{})
 	w := httptest.NewRecorder()
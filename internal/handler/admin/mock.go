@@ -4,14 +4,14 @@ import (
 	"context"
 
 	"workout-tracker/internal/dto/exercise"
-	exerciseRepsonse "workout-tracker/internal/model/exercise"
 )
 
 type FakeAdminService struct {
 	CreateID     int
 	CreateErr    error
 	UpdateErr    error
-	GetAllResult []exerciseRepsonse.Exercise
+	GetAllResult *exercise.ExercisePage
+	LastFilter   exercise.ExerciseFilter
 	GetAllErr    error
 	DeleteErr    error
 }
@@ -24,7 +24,8 @@ func (f *FakeAdminService) UpdateExercise(ctx context.Context, id int, req exerc
 	return f.UpdateErr
 }
 
-func (f *FakeAdminService) GetAllExercises(ctx context.Context) ([]exerciseRepsonse.Exercise, error) {
+func (f *FakeAdminService) GetAllExercises(ctx context.Context, filter exercise.ExerciseFilter) (*exercise.ExercisePage, error) {
+	f.LastFilter = filter
 	return f.GetAllResult, f.GetAllErr
 }
 
@@ -3,9 +3,11 @@ package auth
 import (
 	"errors"
 	"net/http"
+	"strings"
 	dto "workout-tracker/internal/dto/user"
 	"workout-tracker/internal/erorrs"
 	model "workout-tracker/internal/model/user"
+	"workout-tracker/internal/model/user/jwt"
 	"workout-tracker/pkg/logger"
 
 	"github.com/gin-gonic/gin"
@@ -24,6 +26,12 @@ type AuthHandler struct {
 	Logger  logger.SugaredLoggerInterface
 }
 
+const maxUserAgentLength = 512
+
+// errInvalidCredentials is returned for both unknown users and wrong passwords so that logins
+// cannot be used to probe for usernames.
+var errInvalidCredentials = erorrs.New(http.StatusUnauthorized, erorrs.CodeInvalidCredentials, "invalid credentials")
+
 func NewAuthHandler(p AuthHandlerParams) *AuthHandler {
 	return &AuthHandler{
 		Service: p.Service,
@@ -34,16 +42,16 @@ func NewAuthHandler(p AuthHandlerParams) *AuthHandler {
 func (h *AuthHandler) Register(c *gin.Context) {
 	var request dto.RegisterRequest
 
-	if err := c.Bind(&request); err != nil {
-		h.Logger.Errorw("Invalid params: %s", erorrs.ErrorKey, err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := c.ShouldBind(&request); err != nil {
+		h.Logger.Errorw("Invalid params", erorrs.ErrorKey, err.Error())
+		_ = c.Error(erorrs.FromBinding(err))
 		return
 	}
 
 	hashed, err := h.Service.HashPassword(request.Password)
 	if err != nil {
 		h.Logger.Errorw("Error hashing password", erorrs.ErrorKey, err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -55,7 +63,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	id, err := h.Service.CreateUser(c.Request.Context(), user)
 	if err != nil {
 		h.Logger.Errorw("Error creating user", erorrs.ErrorKey, err.Error())
-		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -71,36 +79,41 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var request dto.LoginRequest
 
-	if err := c.Bind(&request); err != nil {
-		h.Logger.Errorw("Invalid params: %s", erorrs.ErrorKey, err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := c.ShouldBind(&request); err != nil {
+		h.Logger.Errorw("Invalid params", erorrs.ErrorKey, err.Error())
+		_ = c.Error(erorrs.FromBinding(err))
 		return
 	}
 
 	user, err := h.Service.GetUserByUsername(c.Request.Context(), request.Username)
 	if err != nil {
 		h.Logger.Errorw("Error getting user", erorrs.ErrorKey, err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if errors.Is(err, erorrs.ErrUserNotFound) {
+			_ = c.Error(errInvalidCredentials.Wrap(err))
+			return
+		}
+		_ = c.Error(err)
 		return
 	}
 
 	if err := h.Service.CheckPassword(user.Password, request.Password); err != nil {
 		h.Logger.Errorw("Error checking password", erorrs.ErrorKey, err.Error())
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+		_ = c.Error(errInvalidCredentials.Wrap(err))
 		return
 	}
 
-	accessToken, err := h.Service.GenerateAccessToken(user)
+	refreshToken, sessionID, err := h.Service.GenerateAndStoreRefreshToken(c.Request.Context(), user.ID,
+		device(c, request.DeviceName))
 	if err != nil {
-		h.Logger.Errorw("Error generating access token", erorrs.ErrorKey, err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.Logger.Errorw("Error generating refresh token", erorrs.ErrorKey, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
-	refreshToken, err := h.Service.GenerateAndStoreRefreshToken(c.Request.Context(), user.ID)
+	accessToken, err := h.Service.GenerateAccessToken(user, sessionID)
 	if err != nil {
-		h.Logger.Errorw("Error generating refresh token", erorrs.ErrorKey, err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "refresh token generation failed"})
+		h.Logger.Errorw("Error generating access token", erorrs.ErrorKey, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -115,22 +128,24 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	var req struct {
-		RefreshToken string `json:"refresh_token" binding:"required"`
-	}
+	var req refreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(erorrs.FromBinding(err))
 		return
 	}
 
-	accessToken, newRefreshToken, err := h.Service.UpdateRefreshToken(c, req.RefreshToken)
+	accessToken, newRefreshToken, err := h.Service.UpdateRefreshToken(c, req.RefreshToken, device(c, ""))
 	if err != nil {
-		status := http.StatusUnauthorized
 		if errors.Is(err, erorrs.ErrInternal) {
-			status = http.StatusInternalServerError
+			_ = c.Error(err)
+			return
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		_ = c.Error(erorrs.New(http.StatusUnauthorized, erorrs.CodeInvalidToken, erorrs.ErrInvalidToken.Error()).Wrap(err))
 		return
 	}
 
@@ -139,3 +154,40 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		"refresh_token": newRefreshToken,
 	})
 }
+
+// Logout ends the session of the refresh token, along with the access tokens issued to it.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	if err := h.Service.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		h.Logger.Errorw("Error logging out", erorrs.ErrorKey, err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// device describes the client of the request. User agents are made valid UTF-8 and cut to the
+// number of characters stored, never inside a character.
+func device(c *gin.Context, name string) jwt.Device {
+	userAgent := strings.ToValidUTF8(c.Request.UserAgent(), "")
+	if runes := []rune(userAgent); len(runes) > maxUserAgentLength {
+		userAgent = string(runes[:maxUserAgentLength])
+	}
+	return jwt.Device{
+		Name:      strings.TrimSpace(name),
+		UserAgent: userAgent,
+		IP:        c.ClientIP(),
+	}
+}
+
+// JWKS publishes the public keys access tokens are verified with.
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.Service.JWKS())
+}
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -14,16 +16,22 @@ import (
 	"go.uber.org/zap"
 
 	dto "workout-tracker/internal/dto/user"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
 	model "workout-tracker/internal/model/user"
+	"workout-tracker/pkg/jwtkeys"
 )
 
 func setupRouter(fs AuthServiceInterface) *gin.Engine {
 	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
 	h := NewAuthHandler(AuthHandlerParams{Service: fs, Logger: zap.NewNop().Sugar()})
 
 	r.POST("/register", h.Register)
 	r.POST("/login", h.Login)
 	r.POST("/refresh", h.RefreshToken)
+	r.POST("/logout", h.Logout)
+	r.GET("/.well-known/jwks.json", h.JWKS)
 	return r
 }
 
@@ -32,7 +40,7 @@ func TestRegister_BadBind(t *testing.T) {
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBufferString("notjson"))
 	r.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 }
 
 func TestRegister_HashError(t *testing.T) {
@@ -42,7 +50,7 @@ func TestRegister_HashError(t *testing.T) {
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBufferString(payload))
 	r.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 }
 
 func TestRegister_CreateError(t *testing.T) {
@@ -52,7 +60,7 @@ func TestRegister_CreateError(t *testing.T) {
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBufferString(payload))
 	r.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 }
 
 func TestRegister_Success(t *testing.T) {
@@ -76,7 +84,7 @@ func TestLogin_BadBind(t *testing.T) {
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString("x"))
 	r.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 }
 
 func TestLogin_GetUserError(t *testing.T) {
@@ -86,7 +94,7 @@ func TestLogin_GetUserError(t *testing.T) {
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(payload))
 	r.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 }
 
 func TestLogin_CheckPasswordError(t *testing.T) {
@@ -96,7 +104,7 @@ func TestLogin_CheckPasswordError(t *testing.T) {
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(payload))
 	r.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 }
 
 func TestLogin_TokenError(t *testing.T) {
@@ -106,7 +114,7 @@ func TestLogin_TokenError(t *testing.T) {
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(payload))
 	r.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 }
 
 func TestLogin_Success(t *testing.T) {
@@ -116,7 +124,7 @@ func TestLogin_Success(t *testing.T) {
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(payload))
 	r.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 	var resp dto.LoginResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
 	assert.Equal(t, "", resp.AccessToken)
@@ -127,7 +135,7 @@ func TestRefresh_BadJSON(t *testing.T) {
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/refresh", bytes.NewBufferString("{}"))
 	r.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 }
 
 func TestRefresh_Error(t *testing.T) {
@@ -173,7 +181,7 @@ func TestRegister_BadContentType(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/register",
 		bytes.NewBufferString(`{"username":"u","password":"p"}`))
 	r.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 }
 
 func TestRefresh_MissingField(t *testing.T) {
@@ -182,5 +190,128 @@ func TestRefresh_MissingField(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/refresh", bytes.NewBufferString(`{"token":"rt"}`))
 	req.Header.Set("Content-Type", "application/json")
 	r.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func postJSON(r *gin.Engine, path, payload string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) handler.Problem {
+	t.Helper()
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	return problem
+}
+
+func TestRegister_UsernameTaken(t *testing.T) {
+	r := setupRouter(&FakeService{CreateErr: erorrs.ErrUsernameAlreadyExists})
+	w := postJSON(r, "/register", `{"username":"u","password":"p"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, erorrs.CodeConflict, decodeProblem(t, w).Code)
+}
+
+func TestRegister_HashErrorIsNotLeaked(t *testing.T) {
+	r := setupRouter(&FakeService{HashErr: errors.New("bcrypt: password length exceeds 72 bytes")})
+	w := postJSON(r, "/register", `{"username":"u","password":"p"}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "bcrypt")
+	assert.Equal(t, erorrs.CodeInternal, decodeProblem(t, w).Code)
+}
+
+func TestRegister_MissingFields(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := postJSON(r, "/register", `{}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	problem := decodeProblem(t, w)
+	assert.Equal(t, erorrs.CodeValidationFailed, problem.Code)
+	assert.Len(t, problem.Errors, 2)
+}
+
+func TestLogin_UnknownUser(t *testing.T) {
+	r := setupRouter(&FakeService{FindErr: erorrs.ErrUserNotFound})
+	w := postJSON(r, "/login", `{"username":"u","password":"p"}`)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, erorrs.CodeInvalidCredentials, decodeProblem(t, w).Code)
+}
+
+func TestLogin_WrongPassword(t *testing.T) {
+	fs := &FakeService{FoundUser: &model.User{Username: "u", Password: "h"}, PasswordCheckErr: errors.New("mismatch")}
+	r := setupRouter(fs)
+	w := postJSON(r, "/login", `{"username":"u","password":"p"}`)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, erorrs.CodeInvalidCredentials, decodeProblem(t, w).Code)
+}
+
+func TestRefresh_InvalidToken(t *testing.T) {
+	r := setupRouter(&FakeService{UpdateErr: erorrs.ErrInvalidToken})
+	w := postJSON(r, "/refresh", `{"refresh_token":"rt"}`)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, erorrs.CodeInvalidToken, decodeProblem(t, w).Code)
+}
+
+func TestLogin_RecordsDevice(t *testing.T) {
+	fs := &FakeService{FoundUser: &model.User{ID: 2, Username: "u", Password: "h", Role: model.UserRole}, AccessToken: "at", RefreshToken: "rt"}
+	r := setupRouter(fs)
+	payload := `{"username":"u","password":"p","device_name":" Pixel 8 "}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "okhttp/4.12")
+	req.RemoteAddr = "10.0.0.1:5555"
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Pixel 8", fs.LastDevice.Name)
+	assert.Equal(t, "okhttp/4.12", fs.LastDevice.UserAgent)
+	assert.Equal(t, "10.0.0.1", fs.LastDevice.IP)
+	assert.Equal(t, "session", fs.LastSessionID, "the access token belongs to the new session")
+}
+
+func TestLogin_CutsLongUserAgentsOnCharacters(t *testing.T) {
+	fs := &FakeService{FoundUser: &model.User{ID: 2, Username: "u", Password: "h", Role: model.UserRole}, AccessToken: "at", RefreshToken: "rt"}
+	r := setupRouter(fs)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"username":"u","password":"p"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "a\xff"+strings.Repeat("ж", 600))
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	userAgent := fs.LastDevice.UserAgent
+	assert.True(t, utf8.ValidString(userAgent))
+	assert.Equal(t, 512, utf8.RuneCountInString(userAgent))
+	assert.Equal(t, "a"+strings.Repeat("ж", 511), userAgent)
+}
+
+func TestLogout(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/logout", bytes.NewBufferString(`{"refresh_token":"rt"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "rt", fs.LastLogout)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/logout", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestJWKS(t *testing.T) {
+	fs := &FakeService{Keys: jwtkeys.JWKS{Keys: []jwtkeys.JWK{{Kty: "OKP", Kid: "k1", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: "abc"}}}}
+	r := setupRouter(fs)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+	assert.JSONEq(t, `{"keys":[{"kty":"OKP","kid":"k1","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"abc"}]}`, w.Body.String())
 }
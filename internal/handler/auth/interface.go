@@ -3,7 +3,9 @@ package auth
 import (
 	"context"
 	model "workout-tracker/internal/model/user"
+	"workout-tracker/internal/model/user/jwt"
 	"workout-tracker/internal/service/auth"
+	"workout-tracker/pkg/jwtkeys"
 )
 
 type AuthServiceInterface interface {
@@ -11,9 +13,11 @@ type AuthServiceInterface interface {
 	CreateUser(ctx context.Context, user model.User) (int, error)
 	GetUserByUsername(ctx context.Context, username string) (*model.User, error)
 	CheckPassword(hashed, password string) error
-	GenerateAccessToken(user *model.User) (string, error)
-	GenerateAndStoreRefreshToken(ctx context.Context, userID int) (string, error)
-	UpdateRefreshToken(ctx context.Context, refreshToken string) (string, string, error)
+	GenerateAccessToken(user *model.User, sessionID string) (string, error)
+	GenerateAndStoreRefreshToken(ctx context.Context, userID int, device jwt.Device) (string, string, error)
+	UpdateRefreshToken(ctx context.Context, refreshToken string, device jwt.Device) (string, string, error)
+	Logout(ctx context.Context, refreshToken string) error
+	JWKS() jwtkeys.JWKS
 }
 
 var _ AuthServiceInterface = (*auth.AuthService)(nil)
@@ -3,6 +3,8 @@ package auth
 import (
 	"context"
 	model "workout-tracker/internal/model/user"
+	"workout-tracker/internal/model/user/jwt"
+	"workout-tracker/pkg/jwtkeys"
 )
 
 type FakeService struct {
@@ -17,6 +19,11 @@ type FakeService struct {
 	RefreshToken     string
 	RefreshErr       error
 	UpdateErr        error
+	LogoutErr        error
+	LastDevice       jwt.Device
+	LastSessionID    string
+	LastLogout       string
+	Keys             jwtkeys.JWKS
 }
 
 func (f *FakeService) HashPassword(password string) (string, error) {
@@ -31,12 +38,22 @@ func (f *FakeService) GetUserByUsername(ctx context.Context, username string) (*
 func (f *FakeService) CheckPassword(hashed, password string) error {
 	return f.PasswordCheckErr
 }
-func (f *FakeService) GenerateAccessToken(user *model.User) (string, error) {
+func (f *FakeService) GenerateAccessToken(user *model.User, sessionID string) (string, error) {
+	f.LastSessionID = sessionID
 	return f.AccessToken, f.AccessErr
 }
-func (f *FakeService) GenerateAndStoreRefreshToken(ctx context.Context, userID int) (string, error) {
-	return f.RefreshToken, f.RefreshErr
+func (f *FakeService) GenerateAndStoreRefreshToken(ctx context.Context, userID int, device jwt.Device) (string, string, error) {
+	f.LastDevice = device
+	return f.RefreshToken, "session", f.RefreshErr
 }
-func (f *FakeService) UpdateRefreshToken(ctx context.Context, refreshToken string) (string, string, error) {
+func (f *FakeService) UpdateRefreshToken(ctx context.Context, refreshToken string, device jwt.Device) (string, string, error) {
+	f.LastDevice = device
 	return "newAccess", "newRefresh", f.UpdateErr
 }
+func (f *FakeService) Logout(ctx context.Context, refreshToken string) error {
+	f.LastLogout = refreshToken
+	return f.LogoutErr
+}
+func (f *FakeService) JWKS() jwtkeys.JWKS {
+	return f.Keys
+}
@@ -0,0 +1,117 @@
+package category
+
+import (
+	"net/http"
+	"strconv"
+	dto "workout-tracker/internal/dto/category"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/dig"
+)
+
+var errInvalidID = erorrs.BadRequest("invalid category id")
+
+type CategoryHandlerParams struct {
+	dig.In
+
+	Service CategoryServiceInterface
+	Logger  logger.SugaredLoggerInterface
+}
+
+type CategoryHandler struct {
+	Service CategoryServiceInterface
+	Log     logger.SugaredLoggerInterface
+}
+
+func NewCategoryHandler(params CategoryHandlerParams) *CategoryHandler {
+	return &CategoryHandler{
+		Service: params.Service,
+		Log:     params.Logger,
+	}
+}
+
+// GetAll lists the categories in display order. It serves both the public and the admin listing.
+func (h *CategoryHandler) GetAll(c *gin.Context) {
+	categories, err := h.Service.GetCategories(c.Request.Context())
+	if err != nil {
+		h.Log.Errorw("error getting categories", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+func (h *CategoryHandler) Create(c *gin.Context) {
+	var req dto.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	id, err := h.Service.CreateCategory(c.Request.Context(), req)
+	if err != nil {
+		h.Log.Errorw("error creating category", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"category_id": id})
+}
+
+func (h *CategoryHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidID.Wrap(err))
+		return
+	}
+
+	var req dto.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	if err := h.Service.UpdateCategory(c.Request.Context(), id, req); err != nil {
+		h.Log.Errorw("error updating category", "id", id, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "category updated"})
+}
+
+func (h *CategoryHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidID.Wrap(err))
+		return
+	}
+
+	if err := h.Service.DeleteCategory(c.Request.Context(), id); err != nil {
+		h.Log.Errorw("error deleting category", "id", id, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Reorder takes the category ids in their new display order.
+func (h *CategoryHandler) Reorder(c *gin.Context) {
+	var req dto.ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	if err := h.Service.ReorderCategories(c.Request.Context(), req.IDs); err != nil {
+		h.Log.Errorw("error reordering categories", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "categories reordered"})
+}
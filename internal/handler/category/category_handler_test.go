@@ -0,0 +1,127 @@
+package category
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	dto "workout-tracker/internal/dto/category"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+	model "workout-tracker/internal/model/category"
+	"workout-tracker/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupRouter(fs *FakeService) *gin.Engine {
+	if err := validation.Register(); err != nil {
+		panic(err)
+	}
+	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
+	h := NewCategoryHandler(CategoryHandlerParams{
+		Service: fs,
+		Logger:  zap.NewNop().Sugar(),
+	})
+
+	r.GET("/categories", h.GetAll)
+	r.POST("/admin/categories", h.Create)
+	r.PUT("/admin/categories/order", h.Reorder)
+	r.PUT("/admin/categories/:id", h.Update)
+	r.DELETE("/admin/categories/:id", h.Delete)
+	return r
+}
+
+func send(r *gin.Engine, method, path, payload string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetAll_Success(t *testing.T) {
+	r := setupRouter(&FakeService{Categories: []model.Category{{ID: 1, Slug: "strength", Name: "Strength"}}})
+	w := send(r, http.MethodGet, "/categories", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp []model.Category
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "strength", resp[0].Slug)
+}
+
+func TestGetAll_Error(t *testing.T) {
+	r := setupRouter(&FakeService{GetErr: errors.New("db down")})
+	w := send(r, http.MethodGet, "/categories", "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestCreate_Success(t *testing.T) {
+	fs := &FakeService{CreateID: 3}
+	r := setupRouter(fs)
+	w := send(r, http.MethodPost, "/admin/categories", `{"name":"Leg day","icon":"legs","color":"#1e90ff","position":2}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, dto.CategoryRequest{Name: "Leg day", Icon: "legs", Color: "#1e90ff", Position: 2}, fs.LastInput)
+
+	var resp map[string]int
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 3, resp["category_id"])
+}
+
+func TestCreate_Invalid(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodPost, "/admin/categories", `{"name":"Legs","slug":"Leg Day","color":"blue","position":-1}`)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Len(t, problem.Errors, 3)
+}
+
+func TestCreate_Duplicate(t *testing.T) {
+	r := setupRouter(&FakeService{CreateErr: erorrs.ErrCategoryAlreadyExists})
+	w := send(r, http.MethodPost, "/admin/categories", `{"name":"Cardio"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestUpdate_NotFound(t *testing.T) {
+	fs := &FakeService{UpdateErr: erorrs.ErrNotFound}
+	r := setupRouter(fs)
+	w := send(r, http.MethodPut, "/admin/categories/8", `{"name":"Cardio"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, 8, fs.LastID)
+}
+
+func TestUpdate_InvalidID(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodPut, "/admin/categories/abc", `{"name":"Cardio"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDelete_Success(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	w := send(r, http.MethodDelete, "/admin/categories/5", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, 5, fs.LastID)
+}
+
+func TestReorder_Success(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	w := send(r, http.MethodPut, "/admin/categories/order", `{"ids":[3,1,2]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []int{3, 1, 2}, fs.LastOrder)
+}
+
+func TestReorder_DuplicateIDs(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodPut, "/admin/categories/order", `{"ids":[3,3]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
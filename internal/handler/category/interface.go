@@ -0,0 +1,18 @@
+package category
+
+import (
+	"context"
+	dto "workout-tracker/internal/dto/category"
+	model "workout-tracker/internal/model/category"
+	"workout-tracker/internal/service/category"
+)
+
+type CategoryServiceInterface interface {
+	GetCategories(ctx context.Context) ([]model.Category, error)
+	CreateCategory(ctx context.Context, input dto.CategoryRequest) (int, error)
+	UpdateCategory(ctx context.Context, id int, input dto.CategoryRequest) error
+	DeleteCategory(ctx context.Context, id int) error
+	ReorderCategories(ctx context.Context, ids []int) error
+}
+
+var _ CategoryServiceInterface = (*category.CategoryService)(nil)
@@ -0,0 +1,45 @@
+package category
+
+import (
+	"context"
+	dto "workout-tracker/internal/dto/category"
+	model "workout-tracker/internal/model/category"
+)
+
+type FakeService struct {
+	Categories []model.Category
+	GetErr     error
+	CreateID   int
+	CreateErr  error
+	UpdateErr  error
+	DeleteErr  error
+	ReorderErr error
+	LastInput  dto.CategoryRequest
+	LastID     int
+	LastOrder  []int
+}
+
+func (f *FakeService) GetCategories(ctx context.Context) ([]model.Category, error) {
+	return f.Categories, f.GetErr
+}
+
+func (f *FakeService) CreateCategory(ctx context.Context, input dto.CategoryRequest) (int, error) {
+	f.LastInput = input
+	return f.CreateID, f.CreateErr
+}
+
+func (f *FakeService) UpdateCategory(ctx context.Context, id int, input dto.CategoryRequest) error {
+	f.LastID = id
+	f.LastInput = input
+	return f.UpdateErr
+}
+
+func (f *FakeService) DeleteCategory(ctx context.Context, id int) error {
+	f.LastID = id
+	return f.DeleteErr
+}
+
+func (f *FakeService) ReorderCategories(ctx context.Context, ids []int) error {
+	f.LastOrder = ids
+	return f.ReorderErr
+}
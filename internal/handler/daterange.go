@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"time"
+	"workout-tracker/internal/erorrs"
+
+	"github.com/gin-gonic/gin"
+)
+
+const endOfDay = 24*time.Hour - time.Nanosecond
+
+// DateRange reads the optional from/to query parameters. Both accept RFC 3339 timestamps or plain
+// dates; a plain "to" date covers the whole day.
+func DateRange(c *gin.Context) (from, to *time.Time, err error) {
+	if raw := c.Query("from"); raw != "" {
+		t, _, err := parseTime(raw)
+		if err != nil {
+			return nil, nil, erorrs.BadRequest("invalid from date").Wrap(err)
+		}
+		from = &t
+	}
+
+	if raw := c.Query("to"); raw != "" {
+		t, dateOnly, err := parseTime(raw)
+		if err != nil {
+			return nil, nil, erorrs.BadRequest("invalid to date").Wrap(err)
+		}
+		if dateOnly {
+			t = t.Add(endOfDay)
+		}
+		to = &t
+	}
+
+	return from, to, nil
+}
+
+// parseTime accepts RFC 3339 timestamps or plain dates and reports which form was used.
+func parseTime(raw string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		return t, true, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, false, nil
+}
@@ -0,0 +1,48 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+)
+
+func dateRange(t *testing.T, query string) (*time.Time, *time.Time, error) {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/things?"+query, http.NoBody)
+	return handler.DateRange(c)
+}
+
+func TestDateRange_PlainDates(t *testing.T) {
+	from, to, err := dateRange(t, "from=2025-01-01&to=2025-01-31")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), *from)
+	assert.Equal(t, time.Date(2025, 1, 31, 23, 59, 59, 999999999, time.UTC), *to, "a plain to date covers the whole day")
+}
+
+func TestDateRange_Timestamps(t *testing.T) {
+	from, to, err := dateRange(t, "to=2025-01-31T10:00:00Z")
+	require.NoError(t, err)
+	assert.Nil(t, from)
+	assert.Equal(t, time.Date(2025, 1, 31, 10, 0, 0, 0, time.UTC), *to)
+}
+
+func TestDateRange_Invalid(t *testing.T) {
+	_, _, err := dateRange(t, "from=yesterday")
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Status)
+	assert.Equal(t, "invalid from date", appErr.Message)
+
+	_, _, err = dateRange(t, "to=2025-13-01")
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "invalid to date", appErr.Message)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object extended with a machine-readable code and
+// field-level validation errors.
+type Problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Code     string              `json:"code"`
+	Errors   []erorrs.FieldError `json:"errors,omitempty"`
+}
+
+// ErrorHandler renders the last error a handler attached with c.Error as a problem response.
+// Server errors are logged with their cause, which is never sent to the client.
+func ErrorHandler(log logger.SugaredLoggerInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		appErr := erorrs.FromError(c.Errors.Last().Err)
+		if appErr.Status >= http.StatusInternalServerError {
+			log.Errorw("request failed", "method", c.Request.Method, "path", c.FullPath(), erorrs.ErrorKey, appErr.Err)
+		}
+
+		c.Header("Content-Type", problemContentType)
+		c.JSON(appErr.Status, Problem{
+			Type:     "about:blank",
+			Title:    http.StatusText(appErr.Status),
+			Status:   appErr.Status,
+			Detail:   appErr.Message,
+			Instance: c.Request.URL.Path,
+			Code:     appErr.Code,
+			Errors:   appErr.Fields,
+		})
+	}
+}
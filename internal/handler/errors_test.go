@@ -0,0 +1,83 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+)
+
+func serveError(t *testing.T, err error) (*httptest.ResponseRecorder, handler.Problem) {
+	t.Helper()
+	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
+	r.GET("/things/:id", func(c *gin.Context) {
+		_ = c.Error(err)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/things/5", http.NoBody))
+
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	return w, problem
+}
+
+func TestErrorHandler_AppError(t *testing.T) {
+	appErr := erorrs.New(http.StatusUnprocessableEntity, erorrs.CodeValidationFailed, "request validation failed").
+		WithFields(erorrs.FieldError{Field: "name", Message: "is required"})
+
+	w, problem := serveError(t, appErr)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Equal(t, handler.Problem{
+		Type:     "about:blank",
+		Title:    "Unprocessable Entity",
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   "request validation failed",
+		Instance: "/things/5",
+		Code:     erorrs.CodeValidationFailed,
+		Errors:   []erorrs.FieldError{{Field: "name", Message: "is required"}},
+	}, problem)
+}
+
+func TestErrorHandler_Sentinel(t *testing.T) {
+	w, problem := serveError(t, fmt.Errorf("get workout: %w", erorrs.ErrForbidden))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, erorrs.CodeForbidden, problem.Code)
+}
+
+func TestErrorHandler_HidesInternalErrors(t *testing.T) {
+	w, problem := serveError(t, errors.New(`pq: relation "workouts" does not exist`))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, erorrs.CodeInternal, problem.Code)
+	assert.Equal(t, "internal server error", problem.Detail)
+	assert.NotContains(t, w.Body.String(), "relation")
+}
+
+func TestErrorHandler_LeavesWrittenResponses(t *testing.T) {
+	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
+	r.GET("/", func(c *gin.Context) {
+		_ = c.Error(errors.New("already handled"))
+		c.JSON(http.StatusTeapot, gin.H{"ok": true})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.JSONEq(t, `{"ok":true}`, w.Body.String())
+}
@@ -0,0 +1,133 @@
+package exercise
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	dto "workout-tracker/internal/dto/exercise"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/exercise"
+	"workout-tracker/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/dig"
+)
+
+var errInvalidID = erorrs.BadRequest("invalid exercise id")
+
+type ExerciseHandlerParams struct {
+	dig.In
+
+	Service ExerciseServiceInterface
+	Logger  logger.SugaredLoggerInterface
+}
+
+type ExerciseHandler struct {
+	Service ExerciseServiceInterface
+	Log     logger.SugaredLoggerInterface
+}
+
+func NewExerciseHandler(params ExerciseHandlerParams) *ExerciseHandler {
+	return &ExerciseHandler{
+		Service: params.Service,
+		Log:     params.Logger,
+	}
+}
+
+// GetAll lists the global catalogue together with the user's own exercises. It accepts the same
+// query parameters as the admin listing.
+func (h *ExerciseHandler) GetAll(c *gin.Context) {
+	filter := dto.ExerciseFilter{
+		Search:          c.Query("q"),
+		Muscle:          c.Query("muscle"),
+		Equipment:       c.Query("equipment"),
+		MovementType:    model.MovementType(c.Query("movement_type")),
+		MeasurementKind: model.MeasurementKind(c.Query("measurement_kind")),
+	}
+
+	var err error
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		_ = c.Error(erorrs.BadRequest("invalid limit").Wrap(err))
+		return
+	}
+	if filter.Offset, err = queryInt(c, "offset"); err != nil {
+		_ = c.Error(erorrs.BadRequest("invalid offset").Wrap(err))
+		return
+	}
+
+	page, err := h.Service.GetExercises(c.Request.Context(), c.GetInt("userID"), filter)
+	if err != nil {
+		h.Log.Errorw("error getting exercises", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (h *ExerciseHandler) Create(c *gin.Context) {
+	var req dto.CreateExerciseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	id, err := h.Service.CreateExercise(c.Request.Context(), c.GetInt("userID"), req)
+	if err != nil {
+		h.Log.Errorw("error creating exercise", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"exercise_id": id})
+}
+
+func (h *ExerciseHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidID.Wrap(err))
+		return
+	}
+
+	var req dto.CreateExerciseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	if err := h.Service.UpdateExercise(c.Request.Context(), c.GetInt("userID"), id, req); err != nil {
+		h.Log.Errorw("error updating exercise", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "exercise updated"})
+}
+
+func (h *ExerciseHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidID.Wrap(err))
+		return
+	}
+
+	if err := h.Service.DeleteExercise(c.Request.Context(), c.GetInt("userID"), id); err != nil {
+		h.Log.Errorw("error deleting exercise", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func queryInt(c *gin.Context, key string) (int, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, raw)
+	}
+	return value, nil
+}
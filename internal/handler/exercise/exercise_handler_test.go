@@ -0,0 +1,135 @@
+package exercise
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	dto "workout-tracker/internal/dto/exercise"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+	model "workout-tracker/internal/model/exercise"
+	"workout-tracker/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupRouter(fs *FakeService) *gin.Engine {
+	if err := validation.Register(); err != nil {
+		panic(err)
+	}
+	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", 7)
+		c.Next()
+	})
+	h := NewExerciseHandler(ExerciseHandlerParams{
+		Service: fs,
+		Logger:  zap.NewNop().Sugar(),
+	})
+
+	r.GET("/exercises", h.GetAll)
+	r.POST("/exercises", h.Create)
+	r.PUT("/exercises/:id", h.Update)
+	r.DELETE("/exercises/:id", h.Delete)
+	return r
+}
+
+func TestGetAll_Success(t *testing.T) {
+	owner := 7
+	fs := &FakeService{Page: &dto.ExercisePage{
+		Items: []model.Exercise{{ID: 1, Name: "Squat"}, {ID: 2, Name: "Sled push", OwnerUserID: &owner}},
+		Total: 2,
+	}}
+	r := setupRouter(fs)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/exercises?q=push&limit=10", http.NoBody)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 7, fs.LastUserID)
+	assert.Equal(t, dto.ExerciseFilter{Search: "push", Limit: 10}, fs.LastFilter)
+
+	var resp dto.ExercisePage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Items, 2)
+}
+
+func TestGetAll_InvalidLimit(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/exercises?limit=abc", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreate_Success(t *testing.T) {
+	fs := &FakeService{CreateID: 12}
+	r := setupRouter(fs)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/exercises", bytes.NewBufferString(`{"name":"Sled push"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 7, fs.LastUserID)
+	var resp map[string]int
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 12, resp["exercise_id"])
+}
+
+func TestCreate_Duplicate(t *testing.T) {
+	r := setupRouter(&FakeService{CreateErr: erorrs.ErrExerciseAlreadyExists})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/exercises", bytes.NewBufferString(`{"name":"Sled push"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestCreate_Invalid(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/exercises",
+		bytes.NewBufferString(`{"name":" ","primary_muscles":["chest",""]}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	require.Len(t, problem.Errors, 2)
+	assert.Equal(t, "name", problem.Errors[0].Field)
+	assert.Equal(t, "primary_muscles[1]", problem.Errors[1].Field)
+}
+
+func TestUpdate_NotOwned(t *testing.T) {
+	r := setupRouter(&FakeService{UpdateErr: erorrs.ErrNotFound})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/exercises/3", bytes.NewBufferString(`{"name":"Squat"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDelete_Success(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/exercises/3", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestDelete_Error(t *testing.T) {
+	r := setupRouter(&FakeService{DeleteErr: errors.New("db")})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/exercises/3", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
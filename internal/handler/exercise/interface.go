@@ -0,0 +1,16 @@
+package exercise
+
+import (
+	"context"
+	dto "workout-tracker/internal/dto/exercise"
+	"workout-tracker/internal/service/exercise"
+)
+
+type ExerciseServiceInterface interface {
+	GetExercises(ctx context.Context, userID int, filter dto.ExerciseFilter) (*dto.ExercisePage, error)
+	CreateExercise(ctx context.Context, userID int, input dto.CreateExerciseRequest) (int, error)
+	UpdateExercise(ctx context.Context, userID, id int, input dto.CreateExerciseRequest) error
+	DeleteExercise(ctx context.Context, userID, id int) error
+}
+
+var _ ExerciseServiceInterface = (*exercise.ExerciseService)(nil)
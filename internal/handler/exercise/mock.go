@@ -0,0 +1,38 @@
+package exercise
+
+import (
+	"context"
+	dto "workout-tracker/internal/dto/exercise"
+)
+
+type FakeService struct {
+	Page       *dto.ExercisePage
+	GetErr     error
+	CreateID   int
+	CreateErr  error
+	UpdateErr  error
+	DeleteErr  error
+	LastUserID int
+	LastFilter dto.ExerciseFilter
+}
+
+func (f *FakeService) GetExercises(ctx context.Context, userID int, filter dto.ExerciseFilter) (*dto.ExercisePage, error) {
+	f.LastUserID = userID
+	f.LastFilter = filter
+	return f.Page, f.GetErr
+}
+
+func (f *FakeService) CreateExercise(ctx context.Context, userID int, input dto.CreateExerciseRequest) (int, error) {
+	f.LastUserID = userID
+	return f.CreateID, f.CreateErr
+}
+
+func (f *FakeService) UpdateExercise(ctx context.Context, userID, id int, input dto.CreateExerciseRequest) error {
+	f.LastUserID = userID
+	return f.UpdateErr
+}
+
+func (f *FakeService) DeleteExercise(ctx context.Context, userID, id int) error {
+	f.LastUserID = userID
+	return f.DeleteErr
+}
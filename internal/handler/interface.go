@@ -8,6 +8,7 @@ import (
 
 type AuthService interface {
 	GetUserByUserID(ctx context.Context, id int) (*user.User, error)
+	SessionActive(ctx context.Context, userID int, sessionID string) (bool, error)
 }
 
 var _ AuthService = (*auth.AuthService)(nil)
@@ -0,0 +1,19 @@
+package measurement
+
+import (
+	"context"
+	dto "workout-tracker/internal/dto/measurement"
+	model "workout-tracker/internal/model/measurement"
+	"workout-tracker/internal/service/measurement"
+)
+
+type MeasurementServiceInterface interface {
+	GetMetrics(ctx context.Context, userID int) ([]model.Metric, error)
+	CreateMetric(ctx context.Context, userID int, input dto.MetricRequest) (*model.Metric, error)
+	DeleteMetric(ctx context.Context, userID int, slug string) error
+	AddMeasurement(ctx context.Context, userID int, input dto.MeasurementRequest) (int, error)
+	GetSeries(ctx context.Context, userID int, slug string, query dto.SeriesQuery) (*model.Series, error)
+	DeleteMeasurement(ctx context.Context, userID int, slug string, id int) error
+}
+
+var _ MeasurementServiceInterface = (*measurement.MeasurementService)(nil)
@@ -0,0 +1,158 @@
+package measurement
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	dto "workout-tracker/internal/dto/measurement"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+	"workout-tracker/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/dig"
+)
+
+const dateLayout = "2006-01-02"
+
+var (
+	errInvalidID     = erorrs.BadRequest("invalid measurement id")
+	errInvalidFrom   = erorrs.BadRequest("from must be formatted as YYYY-MM-DD")
+	errInvalidTo     = erorrs.BadRequest("to must be formatted as YYYY-MM-DD")
+	errInvalidWindow = erorrs.BadRequest("window must be a number of days")
+)
+
+type MeasurementHandlerParams struct {
+	dig.In
+
+	Service MeasurementServiceInterface
+	Logger  logger.SugaredLoggerInterface
+}
+
+type MeasurementHandler struct {
+	Service MeasurementServiceInterface
+	Log     logger.SugaredLoggerInterface
+}
+
+func NewMeasurementHandler(params MeasurementHandlerParams) *MeasurementHandler {
+	return &MeasurementHandler{
+		Service: params.Service,
+		Log:     params.Logger,
+	}
+}
+
+// GetMetrics lists the built-in metrics and the user's own.
+func (h *MeasurementHandler) GetMetrics(c *gin.Context) {
+	metrics, err := h.Service.GetMetrics(c.Request.Context(), c.GetInt("userID"))
+	if err != nil {
+		h.Log.Errorw("error getting metrics", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
+func (h *MeasurementHandler) CreateMetric(c *gin.Context) {
+	var req dto.MetricRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	metric, err := h.Service.CreateMetric(c.Request.Context(), c.GetInt("userID"), req)
+	if err != nil {
+		h.Log.Errorw("error creating metric", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, metric)
+}
+
+func (h *MeasurementHandler) DeleteMetric(c *gin.Context) {
+	slug := c.Param("slug")
+	if err := h.Service.DeleteMetric(c.Request.Context(), c.GetInt("userID"), slug); err != nil {
+		h.Log.Errorw("error deleting metric", "slug", slug, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *MeasurementHandler) Add(c *gin.Context) {
+	var req dto.MeasurementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	req.UnitSystem = handler.UnitSystem(c)
+	id, err := h.Service.AddMeasurement(c.Request.Context(), c.GetInt("userID"), req)
+	if err != nil {
+		h.Log.Errorw("error adding measurement", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"measurement_id": id})
+}
+
+// Series returns the measurements of the :metric from ?from= through ?to=, both optional dates, in
+// the ?unit= asked for with moving averages over ?window= days.
+func (h *MeasurementHandler) Series(c *gin.Context) {
+	var query dto.SeriesQuery
+	if raw := c.Query("from"); raw != "" {
+		from, err := time.Parse(dateLayout, raw)
+		if err != nil {
+			_ = c.Error(errInvalidFrom.Wrap(err))
+			return
+		}
+		query.From = &from
+	}
+	if raw := c.Query("to"); raw != "" {
+		to, err := time.Parse(dateLayout, raw)
+		if err != nil {
+			_ = c.Error(errInvalidTo.Wrap(err))
+			return
+		}
+		end := to.AddDate(0, 0, 1)
+		query.To = &end
+	}
+	if raw := c.Query("window"); raw != "" {
+		window, err := strconv.Atoi(raw)
+		if err != nil {
+			_ = c.Error(errInvalidWindow.Wrap(err))
+			return
+		}
+		query.WindowDays = window
+	}
+	query.Unit = c.Query("unit")
+	query.UnitSystem = handler.UnitSystem(c)
+
+	series, err := h.Service.GetSeries(c.Request.Context(), c.GetInt("userID"), c.Param("metric"), query)
+	if err != nil {
+		h.Log.Errorw("error getting measurements", "metric", c.Param("metric"), "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+func (h *MeasurementHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidID.Wrap(err))
+		return
+	}
+
+	if err := h.Service.DeleteMeasurement(c.Request.Context(), c.GetInt("userID"), c.Param("metric"), id); err != nil {
+		h.Log.Errorw("error deleting measurement", "id", id, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,134 @@
+package measurement
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+	model "workout-tracker/internal/model/measurement"
+	"workout-tracker/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupRouter(fs *FakeService) *gin.Engine {
+	if err := validation.Register(); err != nil {
+		panic(err)
+	}
+	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
+	h := NewMeasurementHandler(MeasurementHandlerParams{
+		Service: fs,
+		Logger:  zap.NewNop().Sugar(),
+	})
+	authed := r.Group("").Use(func(c *gin.Context) {
+		c.Set("userID", 7)
+		c.Next()
+	})
+	authed.GET("/me/metrics", h.GetMetrics)
+	authed.POST("/me/metrics", h.CreateMetric)
+	authed.DELETE("/me/metrics/:slug", h.DeleteMetric)
+	authed.POST("/me/measurements", h.Add)
+	authed.GET("/me/measurements/:metric", h.Series)
+	authed.DELETE("/me/measurements/:metric/:id", h.Delete)
+	return r
+}
+
+func send(r *gin.Engine, method, path, payload string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateMetric_Success(t *testing.T) {
+	fs := &FakeService{Metric: &model.Metric{ID: 10, Slug: "calves", Kind: model.KindLength}}
+	r := setupRouter(fs)
+	w := send(r, http.MethodPost, "/me/metrics", `{"name":"Calves","kind":"length"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 7, fs.LastUserID)
+	assert.Equal(t, "Calves", fs.LastMetric.Name)
+
+	var resp model.Metric
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "calves", resp.Slug)
+}
+
+func TestCreateMetric_Invalid(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodPost, "/me/metrics", `{"name":"Calves","kind":"volume"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "kind", problem.Errors[0].Field)
+}
+
+func TestCreateMetric_Conflict(t *testing.T) {
+	r := setupRouter(&FakeService{CreateErr: erorrs.ErrMetricAlreadyExists})
+	w := send(r, http.MethodPost, "/me/metrics", `{"name":"Calves","kind":"length"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestAdd_Success(t *testing.T) {
+	fs := &FakeService{AddID: 20}
+	r := setupRouter(fs)
+	w := send(r, http.MethodPost, "/me/measurements", `{"metric":"bodyweight","value":0,"unit":"lb"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NotNil(t, fs.LastInput.Value)
+	assert.Equal(t, 0.0, *fs.LastInput.Value)
+
+	var resp map[string]int
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 20, resp["measurement_id"])
+}
+
+func TestAdd_Invalid(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodPost, "/me/measurements", `{"metric":"bodyweight","unit":"stone"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Len(t, problem.Errors, 2)
+}
+
+func TestSeries_Query(t *testing.T) {
+	fs := &FakeService{Series: &model.Series{Unit: model.UnitPound}}
+	r := setupRouter(fs)
+	w := send(r, http.MethodGet, "/me/measurements/bodyweight?from=2026-03-01&to=2026-03-31&window=14&unit=lb", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "bodyweight", fs.LastSlug)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), *fs.LastQuery.From)
+	assert.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), *fs.LastQuery.To, "the to date is included")
+	assert.Equal(t, 14, fs.LastQuery.WindowDays)
+	assert.Equal(t, "lb", fs.LastQuery.Unit)
+}
+
+func TestSeries_Errors(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodGet, "/me/measurements/bodyweight?window=week", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	r = setupRouter(&FakeService{SeriesErr: erorrs.ErrNotFound})
+	w = send(r, http.MethodGet, "/me/measurements/calves", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDelete_Success(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	w := send(r, http.MethodDelete, "/me/measurements/waist/4", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "waist", fs.LastSlug)
+	assert.Equal(t, 4, fs.LastID)
+}
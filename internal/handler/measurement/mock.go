@@ -0,0 +1,61 @@
+package measurement
+
+import (
+	"context"
+	dto "workout-tracker/internal/dto/measurement"
+	model "workout-tracker/internal/model/measurement"
+)
+
+type FakeService struct {
+	Metrics    []model.Metric
+	Metric     *model.Metric
+	CreateErr  error
+	DeleteErr  error
+	AddID      int
+	AddErr     error
+	Series     *model.Series
+	SeriesErr  error
+	LastMetric dto.MetricRequest
+	LastInput  dto.MeasurementRequest
+	LastQuery  dto.SeriesQuery
+	LastSlug   string
+	LastID     int
+	LastUserID int
+}
+
+func (f *FakeService) GetMetrics(ctx context.Context, userID int) ([]model.Metric, error) {
+	f.LastUserID = userID
+	return f.Metrics, nil
+}
+
+func (f *FakeService) CreateMetric(ctx context.Context, userID int, input dto.MetricRequest) (*model.Metric, error) {
+	f.LastUserID = userID
+	f.LastMetric = input
+	return f.Metric, f.CreateErr
+}
+
+func (f *FakeService) DeleteMetric(ctx context.Context, userID int, slug string) error {
+	f.LastUserID = userID
+	f.LastSlug = slug
+	return f.DeleteErr
+}
+
+func (f *FakeService) AddMeasurement(ctx context.Context, userID int, input dto.MeasurementRequest) (int, error) {
+	f.LastUserID = userID
+	f.LastInput = input
+	return f.AddID, f.AddErr
+}
+
+func (f *FakeService) GetSeries(ctx context.Context, userID int, slug string, query dto.SeriesQuery) (*model.Series, error) {
+	f.LastUserID = userID
+	f.LastSlug = slug
+	f.LastQuery = query
+	return f.Series, f.SeriesErr
+}
+
+func (f *FakeService) DeleteMeasurement(ctx context.Context, userID int, slug string, id int) error {
+	f.LastUserID = userID
+	f.LastSlug = slug
+	f.LastID = id
+	return f.DeleteErr
+}
@@ -3,10 +3,11 @@ package handler
 import (
 	"log"
 	"net/http"
-	"os"
 	"strings"
 	"workout-tracker/internal/erorrs"
 	"workout-tracker/internal/model/user"
+	jwtModel "workout-tracker/internal/model/user/jwt"
+	"workout-tracker/pkg/jwtkeys"
 	"workout-tracker/pkg/logger"
 
 	"go.uber.org/dig"
@@ -15,28 +16,47 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Authentication failures, rendered by ErrorHandler. A missing or malformed header is reported as
+// unauthorized, a token that was presented but is not accepted as an invalid token.
+var (
+	errMissingToken     = erorrs.New(http.StatusUnauthorized, erorrs.CodeUnauthorized, "missing or invalid token")
+	errInvalidToken     = erorrs.New(http.StatusUnauthorized, erorrs.CodeInvalidToken, "invalid token")
+	errInvalidUserID    = erorrs.New(http.StatusUnauthorized, erorrs.CodeInvalidToken, "invalid user id in token")
+	errInvalidTokenRole = erorrs.New(http.StatusUnauthorized, erorrs.CodeInvalidToken, "invalid role in token")
+	errUnknownUser      = erorrs.New(http.StatusUnauthorized, erorrs.CodeInvalidToken, "user not found")
+	errTokenInvalidated = erorrs.New(http.StatusUnauthorized, erorrs.CodeInvalidToken, "token has been invalidated")
+	errSessionRevoked   = erorrs.New(http.StatusUnauthorized, erorrs.CodeInvalidToken, "session has been revoked")
+	errMissingRole      = erorrs.New(http.StatusUnauthorized, erorrs.CodeUnauthorized, "invalid role")
+	errNotAdmin         = erorrs.New(http.StatusForbidden, erorrs.CodeForbidden, "access denied")
+)
+
 type MiddlewareParams struct {
 	dig.In
 
 	Log     logger.SugaredLoggerInterface
 	Service AuthService
+	// Keys falls back to the key set described by the environment.
+	Keys *jwtkeys.KeySet `optional:"true"`
 }
 
 type Middleware struct {
 	Log     logger.SugaredLoggerInterface
 	Service AuthService
-	Secret  string
+	Keys    *jwtkeys.KeySet
 }
 
 func NewMiddleware(params MiddlewareParams) *Middleware {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		log.Fatal("JWT_SECRET environment variable not set")
+	keys := params.Keys
+	if keys == nil {
+		var err error
+		if keys, err = jwtkeys.FromEnv(); err != nil {
+			log.Fatal(err)
+		}
 	}
 	return &Middleware{
 		Log:     params.Log,
 		Service: params.Service,
-		Secret:  secret,
+		Keys:    keys,
 	}
 }
 
@@ -45,75 +65,69 @@ func (m *Middleware) AuthMiddleware() gin.HandlerFunc {
 		auth := c.GetHeader("Authorization")
 		if auth == "" || !strings.HasPrefix(auth, "Bearer ") {
 			m.Log.Errorw("missing or invalid token ", "header", auth)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{erorrs.ErrorKey: "missing or invalid token"})
+			abort(c, errMissingToken)
 			return
 		}
 
 		tokenStr := strings.TrimPrefix(auth, "Bearer ")
 
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				m.Log.Errorw("Unexpected signing method: %v", token.Header["alg"])
-				return nil, jwt.ErrTokenSignatureInvalid
-			}
-
-			return []byte(m.Secret), nil
-		})
-
-		if err != nil {
-			m.Log.Errorw("Unauthorized", "header", auth)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{erorrs.ErrorKey: "invalid token"})
+		var claims jwtModel.AccessClaims
+		token, err := jwt.ParseWithClaims(tokenStr, &claims, m.Keys.Keyfunc,
+			jwt.WithValidMethods(m.Keys.ValidMethods()), jwt.WithExpirationRequired())
+		if err != nil || !token.Valid {
+			m.Log.Errorw("Unauthorized", "header", auth, "error", err)
+			abort(c, errInvalidToken)
 			return
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok || !token.Valid {
+		userID := claims.UserID
+		if userID <= 0 {
 			m.Log.Errorw("Unauthorized", "header", auth)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{erorrs.ErrorKey: "invalid claims"})
+			abort(c, errInvalidUserID)
 			return
 		}
 
-		userIDFloat, ok := claims["user_id"].(float64)
-		if !ok {
+		role := claims.Role
+		if role == "" {
 			m.Log.Errorw("Unauthorized", "header", auth)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{erorrs.ErrorKey: "invalid user id in token"})
+			abort(c, errInvalidTokenRole)
 			return
 		}
 
-		userID := int(userIDFloat)
-
-		roleStr, ok := claims["role"].(string)
-		if !ok {
-			m.Log.Errorw("Unauthorized", "header", auth)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{erorrs.ErrorKey: "invalid role in token"})
-			return
-		}
-
-		role := user.Role(roleStr)
-
-		versionFloat, ok := claims["version"].(float64)
-		if !ok {
-			m.Log.Errorw("missing version in token")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{erorrs.ErrorKey: "invalid token version"})
-			return
-		}
-
-		tokenVersion := int(versionFloat)
+		tokenVersion := claims.Version
 
+		// Served from the user cache, which drops a user whenever their token version changes.
 		u, err := m.Service.GetUserByUserID(c.Request.Context(), userID)
 		if err != nil {
 			m.Log.Errorw("failed to fetch user", "userID", userID, "error", err)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{erorrs.ErrorKey: "user not found"})
+			abort(c, errUnknownUser)
 			return
 		}
 		if u.TokenVersion != tokenVersion {
 			m.Log.Info("token version mismatch", "tokenVersion", tokenVersion, "dbVersion", u.TokenVersion)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{erorrs.ErrorKey: "token has been invalidated"})
+			abort(c, errTokenInvalidated)
 			return
 		}
 
+		// Tokens issued before sessions were tracked carry no session id and expire on their own.
+		if claims.SessionID != "" {
+			active, err := m.Service.SessionActive(c.Request.Context(), userID, claims.SessionID)
+			if err != nil {
+				m.Log.Errorw("failed to check session", "userID", userID, "error", err)
+				abort(c, errInvalidToken)
+				return
+			}
+			if !active {
+				m.Log.Info("session revoked", "userID", userID, "sessionID", claims.SessionID)
+				abort(c, errSessionRevoked)
+				return
+			}
+			c.Set("sessionID", claims.SessionID)
+		}
+
 		c.Set("userID", userID)
 		c.Set("role", role)
+		SetUnitSystem(c, u.UnitSystem)
 
 		c.Next()
 	}
@@ -125,16 +139,22 @@ func (m *Middleware) AdminMiddleware() gin.HandlerFunc {
 		role, ok := roleValue.(user.Role)
 		if !exists || !ok {
 			m.Log.Errorw("Unauthorized", "reason", "missing or invalid role in context")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{erorrs.ErrorKey: "invalid role"})
+			abort(c, errMissingRole)
 			return
 		}
 
 		if role != user.AdminRole {
 			m.Log.Errorw("Unauthorized", "reason", "user is not admin")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{erorrs.ErrorKey: "access denied"})
+			abort(c, errNotAdmin)
 			return
 		}
 
 		c.Next()
 	}
 }
+
+// abort stops the chain with err, which ErrorHandler turns into the response.
+func abort(c *gin.Context, err *erorrs.AppError) {
+	_ = c.Error(err)
+	c.Abort()
+}
@@ -2,11 +2,16 @@ package handler_test
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -18,18 +23,27 @@ import (
 
 	"workout-tracker/internal/erorrs"
 	"workout-tracker/internal/handler"
+	"workout-tracker/internal/model/units"
 	modeluser "workout-tracker/internal/model/user"
+	"workout-tracker/pkg/jwtkeys"
 )
 
 type FakeAuthService struct {
-	User *modeluser.User
-	Err  error
+	User            *modeluser.User
+	Err             error
+	RevokedSessions map[string]bool
+	LastSessionID   string
 }
 
 func (f *FakeAuthService) GetUserByUserID(ctx context.Context, id int) (*modeluser.User, error) {
 	return f.User, f.Err
 }
 
+func (f *FakeAuthService) SessionActive(ctx context.Context, userID int, sessionID string) (bool, error) {
+	f.LastSessionID = sessionID
+	return !f.RevokedSessions[sessionID], nil
+}
+
 func setupRouter(fakeSvc *FakeAuthService) *gin.Engine {
 	const secret = "secret"
 
@@ -53,12 +67,14 @@ func setupRouter(fakeSvc *FakeAuthService) *gin.Engine {
 	})
 
 	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
 	r.Use(m.AuthMiddleware())
 	r.GET("/ok", func(c *gin.Context) {
 		roleVal, _ := c.Get("role")
 		c.JSON(http.StatusOK, gin.H{
 			"userID": c.GetInt("userID"),
 			"role":   roleVal,
+			"units":  handler.UnitSystem(c),
 		})
 	})
 	return r
@@ -71,9 +87,10 @@ func TestAuthMiddleware_MissingHeader(t *testing.T) {
 	r.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 
-	var body map[string]string
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
-	assert.Equal(t, "missing or invalid token", body[erorrs.ErrorKey])
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, erorrs.CodeUnauthorized, problem.Code)
+	assert.Equal(t, "missing or invalid token", problem.Detail)
 }
 
 func TestAuthMiddleware_InvalidPrefix(t *testing.T) {
@@ -121,6 +138,21 @@ func TestAuthMiddleware_InvalidClaimTypes(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
+func TestAuthMiddleware_MissingExpiry(t *testing.T) {
+	r := setupRouter(&FakeAuthService{User: &modeluser.User{ID: 1, Role: modeluser.UserRole}})
+	tok := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": float64(1),
+		"role":    string(modeluser.UserRole),
+		"version": float64(0),
+	})
+	tokStr, _ := tok.SignedString([]byte("secret"))
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/ok", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+tokStr)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
 func TestAuthMiddleware_UserNotFound(t *testing.T) {
 	r := setupRouter(&FakeAuthService{User: nil, Err: erorrs.ErrUserNotFound})
 	tok := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
@@ -155,7 +187,7 @@ func TestAuthMiddleware_VersionMismatch(t *testing.T) {
 }
 
 func TestAuthMiddleware_Success(t *testing.T) {
-	userObj := &modeluser.User{ID: 42, Role: modeluser.AdminRole, TokenVersion: 1}
+	userObj := &modeluser.User{ID: 42, Role: modeluser.AdminRole, TokenVersion: 1, UnitSystem: units.Imperial}
 	r := setupRouter(&FakeAuthService{User: userObj, Err: nil})
 	tok := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id": float64(42),
@@ -173,6 +205,71 @@ func TestAuthMiddleware_Success(t *testing.T) {
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
 	assert.Equal(t, float64(42), body["userID"])
 	assert.Equal(t, string(modeluser.AdminRole), body["role"])
+	assert.Equal(t, string(units.Imperial), body["units"])
+}
+
+func TestAuthMiddleware_AsymmetricKeys(t *testing.T) {
+	dir := t.TempDir()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "k1.pem"), pemBytes, 0o600))
+	keys, err := jwtkeys.Load(jwtkeys.AlgorithmEdDSA, dir, "k1")
+	require.NoError(t, err)
+
+	m := handler.NewMiddleware(handler.MiddlewareParams{
+		Log:     zap.NewNop().Sugar(),
+		Service: &FakeAuthService{User: &modeluser.User{ID: 42, Role: modeluser.UserRole}},
+		Keys:    keys,
+	})
+	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
+	r.Use(m.AuthMiddleware())
+	r.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	claims := jwt.MapClaims{
+		"user_id": float64(42),
+		"role":    string(modeluser.UserRole),
+		"version": float64(0),
+		"exp":     jwt.NewNumericDate(time.Now().Add(time.Hour)).Unix(),
+	}
+	signed, err := keys.Sign(claims)
+	require.NoError(t, err)
+	hmacSigned, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	for token, want := range map[string]int{signed: http.StatusOK, hmacSigned: http.StatusUnauthorized} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ok", http.NoBody)
+		req.Header.Set("Authorization", "Bearer "+token)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, want, w.Code)
+	}
+}
+
+func TestAuthMiddleware_RevokedSession(t *testing.T) {
+	userObj := &modeluser.User{ID: 42, Role: modeluser.UserRole, TokenVersion: 1}
+	fs := &FakeAuthService{User: userObj, RevokedSessions: map[string]bool{"revoked": true}}
+	r := setupRouter(fs)
+
+	for sessionID, want := range map[string]int{"live": http.StatusOK, "revoked": http.StatusUnauthorized} {
+		tok := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"user_id": float64(42),
+			"role":    string(modeluser.UserRole),
+			"version": float64(1),
+			"sid":     sessionID,
+			"exp":     jwt.NewNumericDate(time.Now().Add(time.Hour)).Unix(),
+		})
+		tokStr, _ := tok.SignedString([]byte("secret"))
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ok", http.NoBody)
+		req.Header.Set("Authorization", "Bearer "+tokStr)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, want, w.Code, sessionID)
+		assert.Equal(t, sessionID, fs.LastSessionID)
+	}
 }
 
 func TestAdminMiddleware_MissingRole(t *testing.T) {
@@ -181,6 +278,7 @@ func TestAdminMiddleware_MissingRole(t *testing.T) {
 	t.Setenv("JWT_SECRET", "secret")
 
 	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
 	r.Use(handler.NewMiddleware(handler.MiddlewareParams{Log: zap.NewNop().Sugar(), Service: &FakeAuthService{}}).AdminMiddleware())
 	r.GET("/admin", func(c *gin.Context) { c.Status(http.StatusOK) })
 
@@ -196,6 +294,7 @@ func TestAdminMiddleware_InvalidRoleType(t *testing.T) {
 	t.Setenv("JWT_SECRET", "secret")
 
 	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
 	r.Use(func(c *gin.Context) { c.Set("role", 123) })
 	r.Use(handler.NewMiddleware(handler.MiddlewareParams{Log: zap.NewNop().Sugar(), Service: &FakeAuthService{}}).AdminMiddleware())
 	r.GET("/admin", func(c *gin.Context) { c.Status(http.StatusOK) })
@@ -212,6 +311,7 @@ func TestAdminMiddleware_NotAdmin(t *testing.T) {
 	t.Setenv("JWT_SECRET", "secret")
 
 	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
 	r.Use(func(c *gin.Context) { c.Set("role", modeluser.UserRole) })
 	r.Use(handler.NewMiddleware(handler.MiddlewareParams{Log: zap.NewNop().Sugar(), Service: &FakeAuthService{}}).AdminMiddleware())
 	r.GET("/admin", func(c *gin.Context) { c.Status(http.StatusOK) })
@@ -219,5 +319,9 @@ func TestAdminMiddleware_NotAdmin(t *testing.T) {
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/admin", http.NoBody)
 	r.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, erorrs.CodeForbidden, problem.Code)
 }
@@ -0,0 +1,21 @@
+package program
+
+import (
+	"context"
+	"time"
+	dto "workout-tracker/internal/dto/program"
+	model "workout-tracker/internal/model/program"
+	"workout-tracker/internal/service/program"
+)
+
+type ProgramServiceInterface interface {
+	CreateProgram(ctx context.Context, userID int, input dto.ProgramRequest) (int, error)
+	GetPrograms(ctx context.Context) ([]model.Program, error)
+	GetProgram(ctx context.Context, id int) (*model.Program, error)
+	DeleteProgram(ctx context.Context, userID, id int) error
+	Enroll(ctx context.Context, userID, programID int, startDate time.Time) (*model.Enrollment, error)
+	Unenroll(ctx context.Context, userID int) error
+	GetToday(ctx context.Context, userID int, date time.Time) (*dto.TodayResponse, error)
+}
+
+var _ ProgramServiceInterface = (*program.ProgramService)(nil)
@@ -0,0 +1,69 @@
+package program
+
+import (
+	"context"
+	"time"
+	dto "workout-tracker/internal/dto/program"
+	model "workout-tracker/internal/model/program"
+)
+
+type FakeService struct {
+	CreateID      int
+	CreateErr     error
+	LastInput     dto.ProgramRequest
+	AllResponse   []model.Program
+	AllErr        error
+	GetResponse   *model.Program
+	GetErr        error
+	DeleteErr     error
+	EnrollErr     error
+	LastStartDate time.Time
+	UnenrollErr   error
+	TodayResponse *dto.TodayResponse
+	TodayErr      error
+	LastTodayDate time.Time
+	LastProgramID int
+	LastUserID    int
+}
+
+func (f *FakeService) CreateProgram(ctx context.Context, userID int, input dto.ProgramRequest) (int, error) {
+	f.LastUserID = userID
+	f.LastInput = input
+	return f.CreateID, f.CreateErr
+}
+
+func (f *FakeService) GetPrograms(ctx context.Context) ([]model.Program, error) {
+	return f.AllResponse, f.AllErr
+}
+
+func (f *FakeService) GetProgram(ctx context.Context, id int) (*model.Program, error) {
+	f.LastProgramID = id
+	return f.GetResponse, f.GetErr
+}
+
+func (f *FakeService) DeleteProgram(ctx context.Context, userID, id int) error {
+	f.LastUserID = userID
+	f.LastProgramID = id
+	return f.DeleteErr
+}
+
+func (f *FakeService) Enroll(ctx context.Context, userID, programID int, startDate time.Time) (*model.Enrollment, error) {
+	f.LastUserID = userID
+	f.LastProgramID = programID
+	f.LastStartDate = startDate
+	if f.EnrollErr != nil {
+		return nil, f.EnrollErr
+	}
+	return &model.Enrollment{UserID: userID, ProgramID: programID, StartDate: startDate}, nil
+}
+
+func (f *FakeService) Unenroll(ctx context.Context, userID int) error {
+	f.LastUserID = userID
+	return f.UnenrollErr
+}
+
+func (f *FakeService) GetToday(ctx context.Context, userID int, date time.Time) (*dto.TodayResponse, error) {
+	f.LastUserID = userID
+	f.LastTodayDate = date
+	return f.TodayResponse, f.TodayErr
+}
@@ -0,0 +1,166 @@
+package program
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	dto "workout-tracker/internal/dto/program"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+	"workout-tracker/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/dig"
+)
+
+const dateLayout = "2006-01-02"
+
+var (
+	errInvalidID   = erorrs.BadRequest("invalid program id")
+	errInvalidDate = erorrs.BadRequest("date must be formatted as YYYY-MM-DD")
+)
+
+type ProgramHandlerParams struct {
+	dig.In
+
+	Service ProgramServiceInterface
+	Logger  logger.SugaredLoggerInterface
+}
+
+type ProgramHandler struct {
+	Service ProgramServiceInterface
+	Log     logger.SugaredLoggerInterface
+}
+
+func NewProgramHandler(params ProgramHandlerParams) *ProgramHandler {
+	return &ProgramHandler{
+		Service: params.Service,
+		Log:     params.Logger,
+	}
+}
+
+func (h *ProgramHandler) Create(c *gin.Context) {
+	var req dto.ProgramRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	id, err := h.Service.CreateProgram(c.Request.Context(), c.GetInt("userID"), req.InKilograms(handler.UnitSystem(c)))
+	if err != nil {
+		h.Log.Errorw("error creating program", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"program_id": id})
+}
+
+func (h *ProgramHandler) GetAll(c *gin.Context) {
+	programs, err := h.Service.GetPrograms(c.Request.Context())
+	if err != nil {
+		h.Log.Errorw("error getting programs", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, programs)
+}
+
+func (h *ProgramHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidID.Wrap(err))
+		return
+	}
+
+	program, err := h.Service.GetProgram(c.Request.Context(), id)
+	if err != nil {
+		h.Log.Errorw("error getting program", "id", id, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, program.InUnits(handler.UnitSystem(c)))
+}
+
+func (h *ProgramHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidID.Wrap(err))
+		return
+	}
+
+	if err := h.Service.DeleteProgram(c.Request.Context(), c.GetInt("userID"), id); err != nil {
+		h.Log.Errorw("error deleting program", "id", id, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Enroll starts the program for the current user on the requested date, today by default. The body
+// is optional.
+func (h *ProgramHandler) Enroll(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidID.Wrap(err))
+		return
+	}
+
+	var req dto.EnrollRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			_ = c.Error(erorrs.FromBinding(err))
+			return
+		}
+	}
+
+	start := time.Now()
+	if req.StartDate != "" {
+		// The binding has already checked the format.
+		start, _ = time.Parse(dateLayout, req.StartDate)
+	}
+
+	enrollment, err := h.Service.Enroll(c.Request.Context(), c.GetInt("userID"), id, start)
+	if err != nil {
+		h.Log.Errorw("error enrolling in program", "id", id, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+func (h *ProgramHandler) Unenroll(c *gin.Context) {
+	if err := h.Service.Unenroll(c.Request.Context(), c.GetInt("userID")); err != nil {
+		h.Log.Errorw("error leaving program", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Today returns what the current user's program plans for today, or for the day given as ?date=.
+func (h *ProgramHandler) Today(c *gin.Context) {
+	date := time.Now()
+	if raw := c.Query("date"); raw != "" {
+		parsed, err := time.Parse(dateLayout, raw)
+		if err != nil {
+			_ = c.Error(errInvalidDate.Wrap(err))
+			return
+		}
+		date = parsed
+	}
+
+	today, err := h.Service.GetToday(c.Request.Context(), c.GetInt("userID"), date)
+	if err != nil {
+		h.Log.Errorw("error getting today's workout", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, today.InUnits(handler.UnitSystem(c)))
+}
@@ -0,0 +1,188 @@
+package program
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	dto "workout-tracker/internal/dto/program"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+	model "workout-tracker/internal/model/program"
+	"workout-tracker/internal/model/units"
+	"workout-tracker/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupRouter(fs *FakeService) *gin.Engine {
+	return setupRouterWithUnits(fs, units.Metric)
+}
+
+func setupRouterWithUnits(fs *FakeService, system units.System) *gin.Engine {
+	if err := validation.Register(); err != nil {
+		panic(err)
+	}
+	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", 7)
+		handler.SetUnitSystem(c, system)
+		c.Next()
+	})
+	h := NewProgramHandler(ProgramHandlerParams{
+		Service: fs,
+		Logger:  zap.NewNop().Sugar(),
+	})
+
+	r.POST("/programs", h.Create)
+	r.GET("/programs", h.GetAll)
+	r.GET("/programs/:id", h.Get)
+	r.DELETE("/programs/:id", h.Delete)
+	r.POST("/programs/:id/enroll", h.Enroll)
+	r.GET("/me/program/today", h.Today)
+	r.DELETE("/me/program", h.Unenroll)
+	return r
+}
+
+func send(r *gin.Engine, method, path, payload string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreate_Success(t *testing.T) {
+	fs := &FakeService{CreateID: 4}
+	r := setupRouter(fs)
+	w := send(r, http.MethodPost, "/programs", `{
+		"name": "Linear",
+		"weeks": [{"days": [{"day": 1, "workout_id": 2}]}, {"days": []}],
+		"progression": [{"type": "weight", "amount": 2.5}]
+	}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 7, fs.LastUserID)
+	assert.Len(t, fs.LastInput.Weeks, 2)
+	assert.Equal(t, []model.Day{{Week: 1, Day: 1, WorkoutID: 2}}, fs.LastInput.Days())
+
+	var resp map[string]int
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 4, resp["program_id"])
+}
+
+func TestCreate_WeightRulesInPounds(t *testing.T) {
+	fs := &FakeService{CreateID: 4}
+	r := setupRouterWithUnits(fs, units.Imperial)
+	w := send(r, http.MethodPost, "/programs", `{
+		"name": "Linear",
+		"weeks": [{"days": [{"day": 1, "workout_id": 2}]}],
+		"progression": [{"type": "weight", "amount": 5}, {"type": "reps", "amount": 1}]
+	}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 2.27, fs.LastInput.Progression[0].Amount)
+	assert.Equal(t, 1.0, fs.LastInput.Progression[1].Amount)
+}
+
+func TestCreate_Invalid(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodPost, "/programs", `{
+		"name": "Linear",
+		"weeks": [{"days": [{"day": 8, "workout_id": 2}]}],
+		"progression": [{"type": "tempo", "amount": 1}]
+	}`)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	fields := map[string]bool{}
+	for _, f := range problem.Errors {
+		fields[f.Field] = true
+	}
+	assert.True(t, fields["weeks[0].days[0].day"])
+	assert.True(t, fields["progression[0].type"])
+}
+
+func TestGet_InvalidID(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodGet, "/programs/abc", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGet_Success(t *testing.T) {
+	fs := &FakeService{GetResponse: &model.Program{ID: 3, Name: "Linear", WeekCount: 1}}
+	r := setupRouter(fs)
+	w := send(r, http.MethodGet, "/programs/3", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3, fs.LastProgramID)
+}
+
+func TestDelete_Forbidden(t *testing.T) {
+	r := setupRouter(&FakeService{DeleteErr: erorrs.ErrForbidden})
+	w := send(r, http.MethodDelete, "/programs/3", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestEnroll_WithStartDate(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	w := send(r, http.MethodPost, "/programs/3/enroll", `{"start_date":"2026-03-02"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3, fs.LastProgramID)
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), fs.LastStartDate)
+}
+
+func TestEnroll_WithoutBody(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	w := send(r, http.MethodPost, "/programs/3/enroll", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.WithinDuration(t, time.Now(), fs.LastStartDate, time.Minute)
+}
+
+func TestEnroll_InvalidDate(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodPost, "/programs/3/enroll", `{"start_date":"02.03.2026"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestEnroll_UnknownProgram(t *testing.T) {
+	r := setupRouter(&FakeService{EnrollErr: erorrs.ErrNotFound})
+	w := send(r, http.MethodPost, "/programs/3/enroll", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUnenroll_NotEnrolled(t *testing.T) {
+	r := setupRouter(&FakeService{UnenrollErr: erorrs.ErrNotEnrolled})
+	w := send(r, http.MethodDelete, "/me/program", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestToday_WithDate(t *testing.T) {
+	fs := &FakeService{TodayResponse: &dto.TodayResponse{Date: "2026-03-04", Status: model.TodayRest, Week: 1, Day: 3}}
+	r := setupRouter(fs)
+	w := send(r, http.MethodGet, "/me/program/today?date=2026-03-04", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC), fs.LastTodayDate)
+
+	var resp dto.TodayResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, model.TodayRest, resp.Status)
+}
+
+func TestToday_InvalidDate(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodGet, "/me/program/today?date=tomorrow", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestToday_NotEnrolled(t *testing.T) {
+	r := setupRouter(&FakeService{TodayErr: erorrs.ErrNotEnrolled})
+	w := send(r, http.MethodGet, "/me/program/today", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,16 @@
+package progression
+
+import (
+	"context"
+	dto "workout-tracker/internal/dto/progression"
+	model "workout-tracker/internal/model/preference"
+	"workout-tracker/internal/service/progression"
+)
+
+type ProgressionServiceInterface interface {
+	NextSession(ctx context.Context, userID, workoutID int) (*dto.NextSessionResponse, error)
+	GetPreferences(ctx context.Context, userID int) (*model.Preferences, error)
+	UpdatePreferences(ctx context.Context, userID int, input dto.PreferencesRequest) (*model.Preferences, error)
+}
+
+var _ ProgressionServiceInterface = (*progression.ProgressionService)(nil)
@@ -0,0 +1,42 @@
+package progression
+
+import (
+	"context"
+	dto "workout-tracker/internal/dto/progression"
+	model "workout-tracker/internal/model/preference"
+	"workout-tracker/internal/model/progression"
+)
+
+type FakeService struct {
+	NextResponse  *dto.NextSessionResponse
+	NextErr       error
+	GetErr        error
+	UpdateErr     error
+	LastInput     dto.PreferencesRequest
+	LastWorkoutID int
+	LastUserID    int
+}
+
+func (f *FakeService) NextSession(ctx context.Context, userID, workoutID int) (*dto.NextSessionResponse, error) {
+	f.LastUserID = userID
+	f.LastWorkoutID = workoutID
+	return f.NextResponse, f.NextErr
+}
+
+func (f *FakeService) GetPreferences(ctx context.Context, userID int) (*model.Preferences, error) {
+	f.LastUserID = userID
+	if f.GetErr != nil {
+		return nil, f.GetErr
+	}
+	prefs := model.Default(userID)
+	return &prefs, nil
+}
+
+func (f *FakeService) UpdatePreferences(ctx context.Context, userID int, input dto.PreferencesRequest) (*model.Preferences, error) {
+	f.LastUserID = userID
+	f.LastInput = input
+	if f.UpdateErr != nil {
+		return nil, f.UpdateErr
+	}
+	return &model.Preferences{UserID: userID, ProgressionStrategy: progression.Strategy(input.ProgressionStrategy)}, nil
+}
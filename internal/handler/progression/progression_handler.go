@@ -0,0 +1,79 @@
+package progression
+
+import (
+	"net/http"
+	"strconv"
+	dto "workout-tracker/internal/dto/progression"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/dig"
+)
+
+var errInvalidID = erorrs.BadRequest("invalid workout id")
+
+type ProgressionHandlerParams struct {
+	dig.In
+
+	Service ProgressionServiceInterface
+	Logger  logger.SugaredLoggerInterface
+}
+
+type ProgressionHandler struct {
+	Service ProgressionServiceInterface
+	Log     logger.SugaredLoggerInterface
+}
+
+func NewProgressionHandler(params ProgressionHandlerParams) *ProgressionHandler {
+	return &ProgressionHandler{
+		Service: params.Service,
+		Log:     params.Logger,
+	}
+}
+
+// Next returns the workout's exercises with suggested targets for the next session.
+func (h *ProgressionHandler) Next(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidID.Wrap(err))
+		return
+	}
+
+	next, err := h.Service.NextSession(c.Request.Context(), c.GetInt("userID"), id)
+	if err != nil {
+		h.Log.Errorw("error suggesting next session", "workoutID", id, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, next)
+}
+
+func (h *ProgressionHandler) GetPreferences(c *gin.Context) {
+	prefs, err := h.Service.GetPreferences(c.Request.Context(), c.GetInt("userID"))
+	if err != nil {
+		h.Log.Errorw("error getting preferences", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+func (h *ProgressionHandler) UpdatePreferences(c *gin.Context) {
+	var req dto.PreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	prefs, err := h.Service.UpdatePreferences(c.Request.Context(), c.GetInt("userID"), req)
+	if err != nil {
+		h.Log.Errorw("error saving preferences", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
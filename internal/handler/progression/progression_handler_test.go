@@ -0,0 +1,114 @@
+package progression
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	dto "workout-tracker/internal/dto/progression"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+	model "workout-tracker/internal/model/progression"
+	"workout-tracker/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupRouter(fs *FakeService) *gin.Engine {
+	if err := validation.Register(); err != nil {
+		panic(err)
+	}
+	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", 7)
+		c.Next()
+	})
+	h := NewProgressionHandler(ProgressionHandlerParams{
+		Service: fs,
+		Logger:  zap.NewNop().Sugar(),
+	})
+
+	r.GET("/workouts/:id/next", h.Next)
+	r.GET("/me/preferences", h.GetPreferences)
+	r.PUT("/me/preferences", h.UpdatePreferences)
+	return r
+}
+
+func send(r *gin.Engine, method, path, payload string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestNext_Success(t *testing.T) {
+	fs := &FakeService{NextResponse: &dto.NextSessionResponse{
+		Strategy:  model.Double,
+		Exercises: []dto.SuggestedExercise{{Suggestion: model.Suggestion{Weight: 42.5, Sets: 3, Reps: 8}}},
+	}}
+	r := setupRouter(fs)
+	w := send(r, http.MethodGet, "/workouts/10/next", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 7, fs.LastUserID)
+	assert.Equal(t, 10, fs.LastWorkoutID)
+
+	var resp dto.NextSessionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, model.Double, resp.Strategy)
+	assert.Equal(t, 42.5, resp.Exercises[0].Suggestion.Weight)
+}
+
+func TestNext_InvalidID(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodGet, "/workouts/abc/next", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestNext_NotFound(t *testing.T) {
+	r := setupRouter(&FakeService{NextErr: erorrs.ErrNotFound})
+	w := send(r, http.MethodGet, "/workouts/10/next", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetPreferences_Default(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodGet, "/me/preferences", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"progression_strategy":"linear","unit_system":"metric","updated_at":"0001-01-01T00:00:00Z"}`, w.Body.String())
+}
+
+func TestUpdatePreferences_Success(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	w := send(r, http.MethodPut, "/me/preferences", `{"progression_strategy":"rpe"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "rpe", fs.LastInput.ProgressionStrategy)
+}
+
+func TestUpdatePreferences_UnitSystem(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	w := send(r, http.MethodPut, "/me/preferences", `{"unit_system":"imperial"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "imperial", fs.LastInput.UnitSystem)
+
+	w = send(r, http.MethodPut, "/me/preferences", `{"unit_system":"stones"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestUpdatePreferences_InvalidStrategy(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodPut, "/me/preferences", `{"progression_strategy":"random"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "progression_strategy", problem.Errors[0].Field)
+}
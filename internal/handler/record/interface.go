@@ -0,0 +1,13 @@
+package record
+
+import (
+	"context"
+	model "workout-tracker/internal/model/record"
+	"workout-tracker/internal/service/record"
+)
+
+type RecordServiceInterface interface {
+	GetRecords(ctx context.Context, userID int, exerciseID *int) (*model.Records, error)
+}
+
+var _ RecordServiceInterface = (*record.RecordService)(nil)
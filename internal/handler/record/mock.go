@@ -0,0 +1,17 @@
+package record
+
+import (
+	"context"
+	model "workout-tracker/internal/model/record"
+)
+
+type FakeService struct {
+	Response       *model.Records
+	Err            error
+	LastExerciseID *int
+}
+
+func (f *FakeService) GetRecords(ctx context.Context, userID int, exerciseID *int) (*model.Records, error) {
+	f.LastExerciseID = exerciseID
+	return f.Response, f.Err
+}
@@ -0,0 +1,61 @@
+package record
+
+import (
+	"net/http"
+	"strconv"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+	"workout-tracker/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/dig"
+)
+
+var errInvalidExerciseID = erorrs.BadRequest("invalid exercise id")
+
+type RecordHandlerParams struct {
+	dig.In
+
+	Service RecordServiceInterface
+	Logger  logger.SugaredLoggerInterface
+}
+
+type RecordHandler struct {
+	Service RecordServiceInterface
+	Log     logger.SugaredLoggerInterface
+}
+
+func NewRecordHandler(params RecordHandlerParams) *RecordHandler {
+	return &RecordHandler{
+		Service: params.Service,
+		Log:     params.Logger,
+	}
+}
+
+func (h *RecordHandler) ByExercise(c *gin.Context) {
+	exerciseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidExerciseID.Wrap(err))
+		return
+	}
+
+	records, err := h.Service.GetRecords(c.Request.Context(), c.GetInt("userID"), &exerciseID)
+	if err != nil {
+		h.Log.Errorw("error getting exercise records", "exerciseID", exerciseID, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, records.InUnits(handler.UnitSystem(c)))
+}
+
+func (h *RecordHandler) Mine(c *gin.Context) {
+	records, err := h.Service.GetRecords(c.Request.Context(), c.GetInt("userID"), nil)
+	if err != nil {
+		h.Log.Errorw("error getting records", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, records.InUnits(handler.UnitSystem(c)))
+}
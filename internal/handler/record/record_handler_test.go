@@ -0,0 +1,79 @@
+package record
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"workout-tracker/internal/handler"
+	model "workout-tracker/internal/model/record"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupRouter(fs *FakeService) *gin.Engine {
+	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", 7)
+		c.Next()
+	})
+	h := NewRecordHandler(RecordHandlerParams{
+		Service: fs,
+		Logger:  zap.NewNop().Sugar(),
+	})
+
+	r.GET("/exercises/:id/records", h.ByExercise)
+	r.GET("/me/records", h.Mine)
+	return r
+}
+
+func TestByExercise_Success(t *testing.T) {
+	fs := &FakeService{Response: &model.Records{
+		Current: []model.PersonalRecord{{ExerciseID: 3, RecordType: model.MaxWeight, Value: 140}},
+		History: []model.PersonalRecord{{ExerciseID: 3, RecordType: model.MaxWeight, Value: 140}},
+	}}
+	r := setupRouter(fs)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/exercises/3/records", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	require.NotNil(t, fs.LastExerciseID)
+	assert.Equal(t, 3, *fs.LastExerciseID)
+
+	var resp model.Records
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Current, 1)
+	assert.InDelta(t, 140.0, resp.Current[0].Value, 0.001)
+}
+
+func TestByExercise_InvalidID(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/exercises/abc/records", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestMine_AllExercises(t *testing.T) {
+	fs := &FakeService{Response: &model.Records{}}
+	r := setupRouter(fs)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/me/records", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, fs.LastExerciseID)
+}
+
+func TestMine_Error(t *testing.T) {
+	r := setupRouter(&FakeService{Err: errors.New("fail")})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/me/records", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
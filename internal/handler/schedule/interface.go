@@ -0,0 +1,22 @@
+package schedule
+
+import (
+	"context"
+	"time"
+	dto "workout-tracker/internal/dto/schedule"
+	model "workout-tracker/internal/model/schedule"
+	"workout-tracker/internal/service/schedule"
+)
+
+type ScheduleServiceInterface interface {
+	CreateSchedule(ctx context.Context, userID int, input dto.ScheduleRequest) (int, error)
+	GetSchedules(ctx context.Context, userID int) ([]model.Schedule, error)
+	DeleteSchedule(ctx context.Context, userID, id int) error
+	MarkOccurrence(ctx context.Context, userID, scheduleID int, date time.Time, input dto.MarkRequest) (*model.Mark, error)
+	GetCalendar(ctx context.Context, userID int, from, to time.Time) ([]model.Entry, error)
+	CreateCalendarToken(ctx context.Context, userID int) (string, error)
+	RevokeCalendarToken(ctx context.Context, userID int) error
+	Feed(ctx context.Context, token string, now time.Time) (string, error)
+}
+
+var _ ScheduleServiceInterface = (*schedule.ScheduleService)(nil)
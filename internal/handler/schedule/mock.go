@@ -0,0 +1,79 @@
+package schedule
+
+import (
+	"context"
+	"time"
+	dto "workout-tracker/internal/dto/schedule"
+	model "workout-tracker/internal/model/schedule"
+)
+
+type FakeService struct {
+	CreateID    int
+	CreateErr   error
+	LastInput   dto.ScheduleRequest
+	Schedules   []model.Schedule
+	DeleteErr   error
+	MarkErr     error
+	LastMark    dto.MarkRequest
+	LastDate    time.Time
+	Entries     []model.Entry
+	CalendarErr error
+	LastFrom    time.Time
+	LastTo      time.Time
+	Token       string
+	RevokeErr   error
+	FeedBody    string
+	FeedErr     error
+	LastToken   string
+	LastID      int
+	LastUserID  int
+}
+
+func (f *FakeService) CreateSchedule(ctx context.Context, userID int, input dto.ScheduleRequest) (int, error) {
+	f.LastUserID = userID
+	f.LastInput = input
+	return f.CreateID, f.CreateErr
+}
+
+func (f *FakeService) GetSchedules(ctx context.Context, userID int) ([]model.Schedule, error) {
+	f.LastUserID = userID
+	return f.Schedules, nil
+}
+
+func (f *FakeService) DeleteSchedule(ctx context.Context, userID, id int) error {
+	f.LastUserID = userID
+	f.LastID = id
+	return f.DeleteErr
+}
+
+func (f *FakeService) MarkOccurrence(ctx context.Context, userID, scheduleID int, date time.Time, input dto.MarkRequest) (*model.Mark, error) {
+	f.LastUserID = userID
+	f.LastID = scheduleID
+	f.LastDate = date
+	f.LastMark = input
+	if f.MarkErr != nil {
+		return nil, f.MarkErr
+	}
+	return &model.Mark{ScheduleID: scheduleID, Date: date, Status: model.Status(input.Status), SessionID: input.SessionID}, nil
+}
+
+func (f *FakeService) GetCalendar(ctx context.Context, userID int, from, to time.Time) ([]model.Entry, error) {
+	f.LastUserID = userID
+	f.LastFrom, f.LastTo = from, to
+	return f.Entries, f.CalendarErr
+}
+
+func (f *FakeService) CreateCalendarToken(ctx context.Context, userID int) (string, error) {
+	f.LastUserID = userID
+	return f.Token, nil
+}
+
+func (f *FakeService) RevokeCalendarToken(ctx context.Context, userID int) error {
+	f.LastUserID = userID
+	return f.RevokeErr
+}
+
+func (f *FakeService) Feed(ctx context.Context, token string, now time.Time) (string, error) {
+	f.LastToken = token
+	return f.FeedBody, f.FeedErr
+}
@@ -0,0 +1,196 @@
+package schedule
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	dto "workout-tracker/internal/dto/schedule"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/dig"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	// defaultCalendarDays is how far GET /calendar looks ahead when no to date is given.
+	defaultCalendarDays = 28
+	feedPath            = "/calendar.ics"
+)
+
+var (
+	errInvalidID   = erorrs.BadRequest("invalid schedule id")
+	errInvalidDate = erorrs.BadRequest("date must be formatted as YYYY-MM-DD")
+	errInvalidFrom = erorrs.BadRequest("from must be formatted as YYYY-MM-DD")
+	errInvalidTo   = erorrs.BadRequest("to must be formatted as YYYY-MM-DD")
+)
+
+type ScheduleHandlerParams struct {
+	dig.In
+
+	Service ScheduleServiceInterface
+	Logger  logger.SugaredLoggerInterface
+}
+
+type ScheduleHandler struct {
+	Service ScheduleServiceInterface
+	Log     logger.SugaredLoggerInterface
+}
+
+func NewScheduleHandler(params ScheduleHandlerParams) *ScheduleHandler {
+	return &ScheduleHandler{
+		Service: params.Service,
+		Log:     params.Logger,
+	}
+}
+
+func (h *ScheduleHandler) Create(c *gin.Context) {
+	var req dto.ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	id, err := h.Service.CreateSchedule(c.Request.Context(), c.GetInt("userID"), req)
+	if err != nil {
+		h.Log.Errorw("error creating schedule", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"schedule_id": id})
+}
+
+func (h *ScheduleHandler) GetAll(c *gin.Context) {
+	schedules, err := h.Service.GetSchedules(c.Request.Context(), c.GetInt("userID"))
+	if err != nil {
+		h.Log.Errorw("error getting schedules", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+func (h *ScheduleHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidID.Wrap(err))
+		return
+	}
+
+	if err := h.Service.DeleteSchedule(c.Request.Context(), c.GetInt("userID"), id); err != nil {
+		h.Log.Errorw("error deleting schedule", "id", id, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Mark sets the status of the schedule's occurrence on the :date of the path.
+func (h *ScheduleHandler) Mark(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidID.Wrap(err))
+		return
+	}
+	date, err := time.Parse(dateLayout, c.Param("date"))
+	if err != nil {
+		_ = c.Error(errInvalidDate.Wrap(err))
+		return
+	}
+
+	var req dto.MarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	mark, err := h.Service.MarkOccurrence(c.Request.Context(), c.GetInt("userID"), id, date, req)
+	if err != nil {
+		h.Log.Errorw("error marking occurrence", "id", id, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mark)
+}
+
+// Calendar lists the planned and completed workouts from ?from= through ?to=, both dates. They
+// default to today and the defaultCalendarDays days after from.
+func (h *ScheduleHandler) Calendar(c *gin.Context) {
+	from := time.Now().UTC()
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.Parse(dateLayout, raw)
+		if err != nil {
+			_ = c.Error(errInvalidFrom.Wrap(err))
+			return
+		}
+		from = parsed
+	}
+	to := from.AddDate(0, 0, defaultCalendarDays-1)
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.Parse(dateLayout, raw)
+		if err != nil {
+			_ = c.Error(errInvalidTo.Wrap(err))
+			return
+		}
+		to = parsed
+	}
+
+	entries, err := h.Service.GetCalendar(c.Request.Context(), c.GetInt("userID"), from, to)
+	if err != nil {
+		h.Log.Errorw("error getting calendar", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// CreateToken issues the secret the iCalendar feed is subscribed with and returns the feed's URL.
+func (h *ScheduleHandler) CreateToken(c *gin.Context) {
+	token, err := h.Service.CreateCalendarToken(c.Request.Context(), c.GetInt("userID"))
+	if err != nil {
+		h.Log.Errorw("error creating calendar token", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CalendarTokenResponse{Token: token, FeedURL: feedURL(c, token)})
+}
+
+func (h *ScheduleHandler) RevokeToken(c *gin.Context) {
+	if err := h.Service.RevokeCalendarToken(c.Request.Context(), c.GetInt("userID")); err != nil {
+		h.Log.Errorw("error revoking calendar token", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Feed serves the iCalendar feed. Calendar apps cannot send bearer tokens, so the feed is
+// authenticated by the ?token= it was subscribed with instead.
+func (h *ScheduleHandler) Feed(c *gin.Context) {
+	ics, err := h.Service.Feed(c.Request.Context(), c.Query("token"), time.Now())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=900")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(ics))
+}
+
+func feedURL(c *gin.Context, token string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	u := url.URL{Scheme: scheme, Host: c.Request.Host, Path: feedPath, RawQuery: url.Values{"token": {token}}.Encode()}
+	return u.String()
+}
@@ -0,0 +1,171 @@
+package schedule
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	dto "workout-tracker/internal/dto/schedule"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+	model "workout-tracker/internal/model/schedule"
+	"workout-tracker/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupRouter(fs *FakeService) *gin.Engine {
+	if err := validation.Register(); err != nil {
+		panic(err)
+	}
+	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
+	h := NewScheduleHandler(ScheduleHandlerParams{
+		Service: fs,
+		Logger:  zap.NewNop().Sugar(),
+	})
+	r.GET("/calendar.ics", h.Feed)
+
+	authed := r.Group("").Use(func(c *gin.Context) {
+		c.Set("userID", 7)
+		c.Next()
+	})
+	authed.POST("/schedule", h.Create)
+	authed.GET("/schedule", h.GetAll)
+	authed.DELETE("/schedule/:id", h.Delete)
+	authed.PUT("/schedule/:id/occurrences/:date", h.Mark)
+	authed.GET("/calendar", h.Calendar)
+	authed.POST("/me/calendar-token", h.CreateToken)
+	authed.DELETE("/me/calendar-token", h.RevokeToken)
+	return r
+}
+
+func send(r *gin.Engine, method, path, payload string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreate_Success(t *testing.T) {
+	fs := &FakeService{CreateID: 3}
+	r := setupRouter(fs)
+	w := send(r, http.MethodPost, "/schedule",
+		`{"workout_id":5,"starts_at":"2026-03-02T07:00:00Z","repeat":["mon","wed","fri"],"until":"2026-06-30"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 7, fs.LastUserID)
+	assert.Equal(t, []string{"mon", "wed", "fri"}, fs.LastInput.Repeat)
+	assert.Equal(t, time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC), fs.LastInput.StartsAt)
+
+	var resp map[string]int
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 3, resp["schedule_id"])
+}
+
+func TestCreate_Invalid(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodPost, "/schedule", `{"workout_id":5,"starts_at":"2026-03-02T07:00:00Z","repeat":["mon","monday"]}`)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "repeat[1]", problem.Errors[0].Field)
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	fs := &FakeService{DeleteErr: erorrs.ErrNotFound}
+	r := setupRouter(fs)
+	w := send(r, http.MethodDelete, "/schedule/4", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, 4, fs.LastID)
+}
+
+func TestMark_Success(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	w := send(r, http.MethodPut, "/schedule/4/occurrences/2026-03-09", `{"status":"completed","session_id":40}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), fs.LastDate)
+	assert.Equal(t, "completed", fs.LastMark.Status)
+}
+
+func TestMark_InvalidDate(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodPut, "/schedule/4/occurrences/monday", `{"status":"skipped"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestMark_InvalidStatus(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodPut, "/schedule/4/occurrences/2026-03-09", `{"status":"done"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestCalendar_Range(t *testing.T) {
+	fs := &FakeService{Entries: []model.Entry{{WorkoutName: "Legs", Status: model.StatusPlanned}}}
+	r := setupRouter(fs)
+	w := send(r, http.MethodGet, "/calendar?from=2026-03-01&to=2026-03-31", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), fs.LastFrom)
+	assert.Equal(t, time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), fs.LastTo)
+
+	var resp []model.Entry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Legs", resp[0].WorkoutName)
+}
+
+func TestCalendar_DefaultRange(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	w := send(r, http.MethodGet, "/calendar?from=2026-03-01", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, time.Date(2026, 3, 28, 0, 0, 0, 0, time.UTC), fs.LastTo)
+}
+
+func TestCalendar_Errors(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := send(r, http.MethodGet, "/calendar?to=soon", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	r = setupRouter(&FakeService{CalendarErr: erorrs.ErrCalendarRangeTooLong})
+	w = send(r, http.MethodGet, "/calendar?from=2026-01-01&to=2028-01-01", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateToken(t *testing.T) {
+	r := setupRouter(&FakeService{Token: "s3cret"})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/me/calendar-token", http.NoBody)
+	req.Host = "api.example.com"
+	req.Header.Set("X-Forwarded-Proto", "https")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var resp dto.CalendarTokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "s3cret", resp.Token)
+	assert.Equal(t, "https://api.example.com/calendar.ics?token=s3cret", resp.FeedURL)
+}
+
+func TestFeed_Success(t *testing.T) {
+	fs := &FakeService{FeedBody: "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"}
+	r := setupRouter(fs)
+	w := send(r, http.MethodGet, "/calendar.ics?token=s3cret", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "s3cret", fs.LastToken)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, fs.FeedBody, w.Body.String())
+}
+
+func TestFeed_InvalidToken(t *testing.T) {
+	r := setupRouter(&FakeService{FeedErr: erorrs.ErrTokenNotFound})
+	w := send(r, http.MethodGet, "/calendar.ics?token=wrong", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,17 @@
+package session
+
+import (
+	"context"
+	model "workout-tracker/internal/model/session"
+	"workout-tracker/internal/service/session"
+)
+
+type SessionServiceInterface interface {
+	StartSession(ctx context.Context, userID, workoutID int, notes string) (*model.Session, error)
+	GetSessions(ctx context.Context, userID, workoutID int) ([]model.Session, error)
+	GetSession(ctx context.Context, userID, workoutID, sessionID int) (*model.Session, error)
+	LogSet(ctx context.Context, userID, workoutID, sessionID int, set model.SessionSet) (*model.SessionSet, error)
+	FinishSession(ctx context.Context, userID, workoutID, sessionID int, notes *string) error
+}
+
+var _ SessionServiceInterface = (*session.SessionService)(nil)
@@ -0,0 +1,40 @@
+package session
+
+import (
+	"context"
+	model "workout-tracker/internal/model/session"
+)
+
+type FakeService struct {
+	StartErr      error
+	StartResponse *model.Session
+	AllErr        error
+	AllResponse   []model.Session
+	GetErr        error
+	GetResponse   *model.Session
+	LogErr        error
+	LogResponse   *model.SessionSet
+	LastSet       model.SessionSet
+	FinishErr     error
+}
+
+func (f *FakeService) StartSession(ctx context.Context, userID, workoutID int, notes string) (*model.Session, error) {
+	return f.StartResponse, f.StartErr
+}
+
+func (f *FakeService) GetSessions(ctx context.Context, userID, workoutID int) ([]model.Session, error) {
+	return f.AllResponse, f.AllErr
+}
+
+func (f *FakeService) GetSession(ctx context.Context, userID, workoutID, sessionID int) (*model.Session, error) {
+	return f.GetResponse, f.GetErr
+}
+
+func (f *FakeService) LogSet(ctx context.Context, userID, workoutID, sessionID int, set model.SessionSet) (*model.SessionSet, error) {
+	f.LastSet = set
+	return f.LogResponse, f.LogErr
+}
+
+func (f *FakeService) FinishSession(ctx context.Context, userID, workoutID, sessionID int, notes *string) error {
+	return f.FinishErr
+}
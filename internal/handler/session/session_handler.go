@@ -0,0 +1,162 @@
+package session
+
+import (
+	"net/http"
+	"strconv"
+	dto "workout-tracker/internal/dto/session"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+	"workout-tracker/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/dig"
+)
+
+var (
+	errInvalidWorkoutID = erorrs.BadRequest("invalid workout id")
+	errInvalidSessionID = erorrs.BadRequest("invalid session id")
+)
+
+type SessionHandlerParams struct {
+	dig.In
+
+	Service SessionServiceInterface
+	Logger  logger.SugaredLoggerInterface
+}
+
+type SessionHandler struct {
+	Service SessionServiceInterface
+	Log     logger.SugaredLoggerInterface
+}
+
+func NewSessionHandler(params SessionHandlerParams) *SessionHandler {
+	return &SessionHandler{
+		Service: params.Service,
+		Log:     params.Logger,
+	}
+}
+
+func (h *SessionHandler) Start(c *gin.Context) {
+	workoutID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidWorkoutID.Wrap(err))
+		return
+	}
+
+	var req dto.StartSessionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			_ = c.Error(erorrs.FromBinding(err))
+			return
+		}
+	}
+
+	session, err := h.Service.StartSession(c.Request.Context(), c.GetInt("userID"), workoutID, req.Notes)
+	if err != nil {
+		h.Log.Errorw("error starting session", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, session.InUnits(handler.UnitSystem(c)))
+}
+
+func (h *SessionHandler) GetAll(c *gin.Context) {
+	workoutID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidWorkoutID.Wrap(err))
+		return
+	}
+
+	sessions, err := h.Service.GetSessions(c.Request.Context(), c.GetInt("userID"), workoutID)
+	if err != nil {
+		h.Log.Errorw("error getting sessions", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	system := handler.UnitSystem(c)
+	for i := range sessions {
+		sessions[i] = sessions[i].InUnits(system)
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+func (h *SessionHandler) Get(c *gin.Context) {
+	workoutID, sessionID, err := parseIDs(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	session, err := h.Service.GetSession(c.Request.Context(), c.GetInt("userID"), workoutID, sessionID)
+	if err != nil {
+		h.Log.Errorw("error getting session", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session.InUnits(handler.UnitSystem(c)))
+}
+
+func (h *SessionHandler) LogSet(c *gin.Context) {
+	workoutID, sessionID, err := parseIDs(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.LogSetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(erorrs.FromBinding(err))
+		return
+	}
+
+	system := handler.UnitSystem(c)
+	created, err := h.Service.LogSet(c.Request.Context(), c.GetInt("userID"), workoutID, sessionID, req.ToSet(system))
+	if err != nil {
+		h.Log.Errorw("error logging set", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created.InUnits(system))
+}
+
+func (h *SessionHandler) Finish(c *gin.Context) {
+	workoutID, sessionID, err := parseIDs(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.FinishSessionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			_ = c.Error(erorrs.FromBinding(err))
+			return
+		}
+	}
+
+	if err := h.Service.FinishSession(c.Request.Context(), c.GetInt("userID"), workoutID, sessionID, req.Notes); err != nil {
+		h.Log.Errorw("error finishing session", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "session finished"})
+}
+
+func parseIDs(c *gin.Context) (int, int, error) {
+	workoutID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, 0, errInvalidWorkoutID.Wrap(err)
+	}
+
+	sessionID, err := strconv.Atoi(c.Param("session_id"))
+	if err != nil {
+		return 0, 0, errInvalidSessionID.Wrap(err)
+	}
+
+	return workoutID, sessionID, nil
+}
@@ -0,0 +1,145 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+	model "workout-tracker/internal/model/session"
+	"workout-tracker/internal/model/units"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupRouter(fs *FakeService) *gin.Engine {
+	return setupRouterWithUnits(fs, units.Metric)
+}
+
+func setupRouterWithUnits(fs *FakeService, system units.System) *gin.Engine {
+	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", 7)
+		handler.SetUnitSystem(c, system)
+		c.Next()
+	})
+	h := NewSessionHandler(SessionHandlerParams{
+		Service: fs,
+		Logger:  zap.NewNop().Sugar(),
+	})
+
+	r.POST("/workouts/:id/sessions", h.Start)
+	r.GET("/workouts/:id/sessions", h.GetAll)
+	r.GET("/workouts/:id/sessions/:session_id", h.Get)
+	r.POST("/workouts/:id/sessions/:session_id/sets", h.LogSet)
+	r.POST("/workouts/:id/sessions/:session_id/finish", h.Finish)
+	return r
+}
+
+func TestStart_Success(t *testing.T) {
+	r := setupRouter(&FakeService{StartResponse: &model.Session{ID: 3, WorkoutID: 5}})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts/5/sessions", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp model.Session
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 3, resp.ID)
+}
+
+func TestStart_InvalidID(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts/abc/sessions", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetAll_Error(t *testing.T) {
+	r := setupRouter(&FakeService{AllErr: errors.New("fail")})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/workouts/5/sessions", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestGet_NotFound(t *testing.T) {
+	r := setupRouter(&FakeService{GetErr: erorrs.ErrNotFound})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/workouts/5/sessions/1", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestLogSet_BadJSON(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts/5/sessions/1/sets", bytes.NewBufferString(`{"reps":5}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, erorrs.CodeValidationFailed, problem.Code)
+}
+
+func TestLogSet_Finished(t *testing.T) {
+	r := setupRouter(&FakeService{LogErr: erorrs.ErrSessionFinished})
+	payload := `{"exercise_id":1,"set_number":1,"reps":5,"weight":100,"rpe":8}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts/5/sessions/1/sets", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestLogSet_Success(t *testing.T) {
+	r := setupRouter(&FakeService{LogResponse: &model.SessionSet{ID: 9, ExerciseID: 1, Reps: 5, Weight: 100}})
+	payload := `{"exercise_id":1,"set_number":1,"reps":5,"weight":100}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts/5/sessions/1/sets", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestFinish_Success(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts/5/sessions/1/finish", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestFinish_InvalidSessionID(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts/5/sessions/x/finish", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLogSet_Imperial(t *testing.T) {
+	fs := &FakeService{LogResponse: &model.SessionSet{ID: 9, ExerciseID: 1, Reps: 5, Weight: 102.0582}}
+	r := setupRouterWithUnits(fs, units.Imperial)
+	payload := `{"exercise_id":1,"set_number":1,"reps":5,"weight":225}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts/5/sessions/1/sets", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.InDelta(t, 102.06, fs.LastSet.Weight, 0.01, "weights are stored in kilograms")
+
+	var resp model.SessionSet
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 225.0, resp.Weight)
+}
@@ -0,0 +1,35 @@
+package statistics
+
+import (
+	"context"
+	dto "workout-tracker/internal/dto/statistics"
+	model "workout-tracker/internal/model/statistics"
+)
+
+type FakeService struct {
+	CategoryResponse []model.WorkoutStatistics
+	CategoryErr      error
+	ExerciseResponse []model.ExerciseStatistics
+	ExerciseErr      error
+	PeriodResponse   []model.PeriodStatistics
+	PeriodErr        error
+	LastFilter       dto.StatisticsFilter
+	LastBucket       model.Bucket
+}
+
+func (f *FakeService) GetCategoryStatistics(ctx context.Context, userID int, filter dto.StatisticsFilter) ([]model.WorkoutStatistics, error) {
+	f.LastFilter = filter
+	return f.CategoryResponse, f.CategoryErr
+}
+
+func (f *FakeService) GetExerciseStatistics(ctx context.Context, userID int, filter dto.StatisticsFilter) ([]model.ExerciseStatistics, error) {
+	f.LastFilter = filter
+	return f.ExerciseResponse, f.ExerciseErr
+}
+
+func (f *FakeService) GetPeriodStatistics(
+	ctx context.Context, userID int, bucket model.Bucket, filter dto.StatisticsFilter) ([]model.PeriodStatistics, error) {
+	f.LastFilter = filter
+	f.LastBucket = bucket
+	return f.PeriodResponse, f.PeriodErr
+}
@@ -0,0 +1,107 @@
+package statistics
+
+import (
+	"net/http"
+	dto "workout-tracker/internal/dto/statistics"
+	"workout-tracker/internal/handler"
+	model "workout-tracker/internal/model/statistics"
+	"workout-tracker/internal/model/units"
+	"workout-tracker/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/dig"
+)
+
+type StatisticsHandlerParams struct {
+	dig.In
+
+	Service StatisticsServiceInterface
+	Logger  logger.SugaredLoggerInterface
+}
+
+type StatisticsHandler struct {
+	Service StatisticsServiceInterface
+	Log     logger.SugaredLoggerInterface
+}
+
+func NewStatisticsHandler(params StatisticsHandlerParams) *StatisticsHandler {
+	return &StatisticsHandler{
+		Service: params.Service,
+		Log:     params.Logger,
+	}
+}
+
+func (h *StatisticsHandler) ByCategory(c *gin.Context) {
+	filter, err := parseFilter(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	stats, err := h.Service.GetCategoryStatistics(c.Request.Context(), c.GetInt("userID"), filter)
+	if err != nil {
+		h.Log.Errorw("error getting category statistics", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, inUnits(stats, handler.UnitSystem(c)))
+}
+
+func (h *StatisticsHandler) ByExercise(c *gin.Context) {
+	filter, err := parseFilter(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	stats, err := h.Service.GetExerciseStatistics(c.Request.Context(), c.GetInt("userID"), filter)
+	if err != nil {
+		h.Log.Errorw("error getting exercise statistics", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, inUnits(stats, handler.UnitSystem(c)))
+}
+
+func (h *StatisticsHandler) ByPeriod(c *gin.Context) {
+	filter, err := parseFilter(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	bucket := model.Bucket(c.DefaultQuery("bucket", string(model.BucketWeek)))
+
+	stats, err := h.Service.GetPeriodStatistics(c.Request.Context(), c.GetInt("userID"), bucket, filter)
+	if err != nil {
+		h.Log.Errorw("error getting period statistics", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, inUnits(stats, handler.UnitSystem(c)))
+}
+
+// inUnits converts the weights of every row to the system's mass unit.
+func inUnits[T interface{ InUnits(units.System) T }](stats []T, system units.System) []T {
+	if stats == nil {
+		return nil
+	}
+	converted := make([]T, len(stats))
+	for i, s := range stats {
+		converted[i] = s.InUnits(system)
+	}
+	return converted
+}
+
+// parseFilter reads the from/to query parameters, see handler.DateRange.
+func parseFilter(c *gin.Context) (dto.StatisticsFilter, error) {
+	from, to, err := handler.DateRange(c)
+	if err != nil {
+		return dto.StatisticsFilter{}, err
+	}
+
+	return dto.StatisticsFilter{From: from, To: to}, nil
+}
@@ -0,0 +1,127 @@
+package statistics
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+	model "workout-tracker/internal/model/statistics"
+	"workout-tracker/internal/model/units"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupRouter(fs *FakeService) *gin.Engine {
+	return setupRouterWithUnits(fs, units.Metric)
+}
+
+func setupRouterWithUnits(fs *FakeService, system units.System) *gin.Engine {
+	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", 7)
+		handler.SetUnitSystem(c, system)
+		c.Next()
+	})
+	h := NewStatisticsHandler(StatisticsHandlerParams{
+		Service: fs,
+		Logger:  zap.NewNop().Sugar(),
+	})
+
+	r.GET("/stats/categories", h.ByCategory)
+	r.GET("/stats/exercises", h.ByExercise)
+	r.GET("/stats/timeline", h.ByPeriod)
+	return r
+}
+
+func TestByCategory_Success(t *testing.T) {
+	fs := &FakeService{CategoryResponse: []model.WorkoutStatistics{{Category: "legs", TotalSets: 3}}}
+	r := setupRouter(fs)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/stats/categories?from=2025-01-01&to=2025-01-31", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp []model.WorkoutStatistics
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp, 1)
+
+	require.NotNil(t, fs.LastFilter.From)
+	require.NotNil(t, fs.LastFilter.To)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), *fs.LastFilter.From)
+	assert.Equal(t, time.Date(2025, 1, 31, 23, 59, 59, 999999999, time.UTC), *fs.LastFilter.To)
+}
+
+func TestByCategory_InvalidFrom(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/stats/categories?from=yesterday", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestByCategory_InvalidRange(t *testing.T) {
+	r := setupRouter(&FakeService{CategoryErr: erorrs.ErrInvalidDateRange})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/stats/categories?from=2025-02-01&to=2025-01-01", http.NoBody)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, erorrs.CodeInvalidRequest, problem.Code)
+	assert.Equal(t, erorrs.ErrInvalidDateRange.Error(), problem.Detail)
+}
+
+func TestByExercise_Imperial(t *testing.T) {
+	strength := 1.5
+	fs := &FakeService{ExerciseResponse: []model.ExerciseStatistics{
+		{ExerciseID: 1, TotalWeight: 1020.6, MaxWeight: 102.06, RelativeStrength: &strength},
+	}}
+	r := setupRouterWithUnits(fs, units.Imperial)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/stats/exercises", http.NoBody)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp []model.ExerciseStatistics
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp, 1)
+	assert.Equal(t, 2250.04, resp[0].TotalWeight)
+	assert.Equal(t, 225.0, resp[0].MaxWeight)
+	assert.Equal(t, 1.5, *resp[0].RelativeStrength)
+	assert.Equal(t, 102.06, fs.ExerciseResponse[0].MaxWeight, "the service result is not modified")
+}
+
+func TestByExercise_Error(t *testing.T) {
+	r := setupRouter(&FakeService{ExerciseErr: errors.New("db")})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/stats/exercises", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestByPeriod_DefaultBucket(t *testing.T) {
+	fs := &FakeService{PeriodResponse: []model.PeriodStatistics{}}
+	r := setupRouter(fs)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/stats/timeline?from=2025-01-01T10:00:00Z", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, model.BucketWeek, fs.LastBucket)
+}
+
+func TestByPeriod_InvalidBucket(t *testing.T) {
+	r := setupRouter(&FakeService{PeriodErr: erorrs.ErrInvalidBucket})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/stats/timeline?bucket=year", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
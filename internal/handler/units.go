@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"workout-tracker/internal/model/units"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unitSystemKey is the context key AuthMiddleware stores the user's unit system under.
+const unitSystemKey = "unitSystem"
+
+// UnitSystem returns the unit system the authenticated user enters and reads weights in. Requests
+// that did not pass AuthMiddleware get the default.
+func UnitSystem(c *gin.Context) units.System {
+	if system, ok := c.Value(unitSystemKey).(units.System); ok && system.IsValid() {
+		return system
+	}
+	return units.DefaultSystem
+}
+
+func SetUnitSystem(c *gin.Context, system units.System) {
+	c.Set(unitSystemKey, system)
+}
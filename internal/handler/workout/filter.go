@@ -0,0 +1,37 @@
+package workout
+
+import (
+	"strconv"
+	dto "workout-tracker/internal/dto/workout"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+	model "workout-tracker/internal/model/workout"
+
+	"github.com/gin-gonic/gin"
+)
+
+func parseFilter(c *gin.Context) (dto.WorkoutFilter, error) {
+	filter := dto.WorkoutFilter{
+		Category: c.Query("category"),
+		Search:   c.Query("q"),
+		Cursor:   c.Query("cursor"),
+		SortBy:   model.SortField(c.Query("sort")),
+		Order:    model.SortOrder(c.Query("order")),
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return filter, erorrs.BadRequest("invalid limit")
+		}
+		filter.Limit = limit
+	}
+
+	from, to, err := handler.DateRange(c)
+	if err != nil {
+		return filter, err
+	}
+	filter.From, filter.To = from, to
+
+	return filter, nil
+}
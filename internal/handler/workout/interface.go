@@ -8,12 +8,12 @@ import (
 )
 
 type WorkoutServiceInterface interface {
-	CreateWorkout(ctx context.Context, userID int, name, title, category string, exercises []workoutexercisejoin.WorkoutExercise) error
-	UpdateWorkout(ctx context.Context, userID, workoutID int, name, title, category string, exercises []workoutexercisejoin.WorkoutExercise) error
+	CreateWorkout(ctx context.Context, userID int, name, title, category string, blocks []workoutexercisejoin.Block) error
+	UpdateWorkout(ctx context.Context, userID, workoutID int, name, title, category string, blocks []workoutexercisejoin.Block) error
 	DeleteWorkout(ctx context.Context, userID, workoutID int) error
-	GetAllWorkoutsWithExercises(ctx context.Context, userID int) ([]dto.WorkoutWithExercises, error)
+	GetAllWorkoutsWithExercises(ctx context.Context, userID int, filter dto.WorkoutFilter) (*dto.WorkoutPage, error)
 	GetWorkoutByID(ctx context.Context, userID, workoutID int) (*dto.WorkoutWithExercises, error)
-	UpdateWorkoutPhoto(ctx context.Context, workoutID int, path string) error
+	UpdateWorkoutPhoto(ctx context.Context, userID, workoutID int, path string) error
 }
 
 var _ WorkoutServiceInterface = (*workout.WorkoutService)(nil)
@@ -11,28 +11,33 @@ type FakeService struct {
 	UpdateErr      error
 	DeleteErr      error
 	AllErr         error
-	AllResponse    []dto.WorkoutWithExercises
+	AllResponse    *dto.WorkoutPage
+	LastFilter     dto.WorkoutFilter
+	LastBlocks     []join.Block
 	GetErr         error
 	GetResponse    *dto.WorkoutWithExercises
 	UpdatePhotoErr error
 }
 
-func (f *FakeService) UpdateWorkoutPhoto(ctx context.Context, workoutID int, path string) error {
+func (f *FakeService) UpdateWorkoutPhoto(ctx context.Context, userID, workoutID int, path string) error {
 	return f.UpdatePhotoErr
 }
 
 func (f *FakeService) CreateWorkout(ctx context.Context, userID int, name, title, category string,
-	exercises []join.WorkoutExercise) error {
+	blocks []join.Block) error {
+	f.LastBlocks = blocks
 	return f.CreateErr
 }
 func (f *FakeService) UpdateWorkout(ctx context.Context, userID, workoutID int, name, title, category string,
-	exercises []join.WorkoutExercise) error {
+	blocks []join.Block) error {
+	f.LastBlocks = blocks
 	return f.UpdateErr
 }
 func (f *FakeService) DeleteWorkout(ctx context.Context, userID, workoutID int) error {
 	return f.DeleteErr
 }
-func (f *FakeService) GetAllWorkoutsWithExercises(ctx context.Context, userID int) ([]dto.WorkoutWithExercises, error) {
+func (f *FakeService) GetAllWorkoutsWithExercises(ctx context.Context, userID int, filter dto.WorkoutFilter) (*dto.WorkoutPage, error) {
+	f.LastFilter = filter
 	return f.AllResponse, f.AllErr
 }
 func (f *FakeService) GetWorkoutByID(ctx context.Context, userID, workoutID int) (*dto.WorkoutWithExercises, error) {
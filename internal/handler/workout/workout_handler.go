@@ -5,7 +5,8 @@ import (
 	"net/http"
 	"strconv"
 	dto "workout-tracker/internal/dto/workout"
-	"workout-tracker/internal/model/workoutexercisejoin"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
 	"workout-tracker/pkg/logger"
 
 	"github.com/gin-gonic/gin"
@@ -13,6 +14,8 @@ import (
 	"go.uber.org/dig"
 )
 
+var errInvalidID = erorrs.BadRequest("invalid workout id")
+
 type WorkoutHandlerParams struct {
 	dig.In
 
@@ -35,23 +38,14 @@ func NewWorkoutHandler(params WorkoutHandlerParams) *WorkoutHandler {
 func (h *WorkoutHandler) Create(c *gin.Context) {
 	var req dto.CreateWorkoutWithExercisesRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(erorrs.FromBinding(err))
 		return
 	}
 	userID := c.GetInt("userID")
 
-	var exercises []workoutexercisejoin.WorkoutExercise
-	for _, e := range req.Exercises {
-		exercises = append(exercises, workoutexercisejoin.WorkoutExercise{
-			ExerciseID: e.ExerciseID,
-			Reps:       e.Reps,
-			Sets:       e.Sets,
-		})
-	}
-
-	if err := h.Service.CreateWorkout(c.Request.Context(), userID, req.Name, req.Title, req.Category, exercises); err != nil {
+	if err := h.Service.CreateWorkout(c.Request.Context(), userID, req.Name, req.Title, req.Category, req.ToBlocks(handler.UnitSystem(c))); err != nil {
 		h.Log.Errorw("error creating workout", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create workout"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -60,26 +54,21 @@ func (h *WorkoutHandler) Create(c *gin.Context) {
 
 func (h *WorkoutHandler) Update(c *gin.Context) {
 	var req dto.CreateWorkoutWithExercisesRequest
-	workoutID, _ := strconv.Atoi(c.Param("id"))
+	workoutID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidID.Wrap(err))
+		return
+	}
 	userID := c.GetInt("userID")
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(erorrs.FromBinding(err))
 		return
 	}
 
-	var exercises []workoutexercisejoin.WorkoutExercise
-	for _, e := range req.Exercises {
-		exercises = append(exercises, workoutexercisejoin.WorkoutExercise{
-			ExerciseID: e.ExerciseID,
-			Reps:       e.Reps,
-			Sets:       e.Sets,
-		})
-	}
-
-	if err := h.Service.UpdateWorkout(c, userID, workoutID, req.Name, req.Title, req.Category, exercises); err != nil {
+	if err := h.Service.UpdateWorkout(c, userID, workoutID, req.Name, req.Title, req.Category, req.ToBlocks(handler.UnitSystem(c))); err != nil {
 		h.Log.Errorw("error updating workout", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update workout"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -87,27 +76,40 @@ func (h *WorkoutHandler) Update(c *gin.Context) {
 }
 
 func (h *WorkoutHandler) Delete(c *gin.Context) {
-	workoutID, _ := strconv.Atoi(c.Param("id"))
+	workoutID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errInvalidID.Wrap(err))
+		return
+	}
 	userID := c.GetInt("userID")
 
 	if err := h.Service.DeleteWorkout(c, userID, workoutID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete workout"})
+		h.Log.Errorw("error deleting workout", "error", err)
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "workout deleted"})
 }
 
+// GetAll lists the user's workouts page by page. Supported query parameters are limit, cursor,
+// category, q (name or title substring), from/to (creation date), sort and order.
 func (h *WorkoutHandler) GetAll(c *gin.Context) {
 	userID := c.GetInt("userID")
 
-	workouts, err := h.Service.GetAllWorkoutsWithExercises(c, userID)
+	filter, err := parseFilter(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	page, err := h.Service.GetAllWorkoutsWithExercises(c, userID, filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not get workouts"})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, workouts)
+	c.JSON(http.StatusOK, page.InUnits(handler.UnitSystem(c)))
 }
 
 func (h *WorkoutHandler) Get(c *gin.Context) {
@@ -116,41 +118,50 @@ func (h *WorkoutHandler) Get(c *gin.Context) {
 
 	workoutID, err := strconv.Atoi(workoutIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid workout id"})
+		_ = c.Error(errInvalidID.Wrap(err))
 		return
 	}
 
 	result, err := h.Service.GetWorkoutByID(c, userID, workoutID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "workout not found or access denied"})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, result.InUnits(handler.UnitSystem(c)))
 }
 
+// UpdatePhoto stores an uploaded photo for one of the user's workouts. Access is checked before the
+// file is written so another user's photo can never be overwritten on disk.
 func (h *WorkoutHandler) UpdatePhoto(c *gin.Context) {
+	userID := c.GetInt("userID")
 	workoutID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid workout ID"})
+		_ = c.Error(errInvalidID.Wrap(err))
 		return
 	}
 
 	file, err := c.FormFile("photo")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "photo is required"})
+		_ = c.Error(erorrs.BadRequest("photo is required").Wrap(err))
+		return
+	}
+
+	if _, err := h.Service.GetWorkoutByID(c.Request.Context(), userID, workoutID); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
 	filename := fmt.Sprintf("uploads/workouts/%d/photo.jpg", workoutID)
 
 	if err := c.SaveUploadedFile(file, filename); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save file"})
+		_ = c.Error(fmt.Errorf("save uploaded photo: %w", err))
 		return
 	}
 
-	if err := h.Service.UpdateWorkoutPhoto(c, workoutID, filename); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update workout photo"})
+	if err := h.Service.UpdateWorkoutPhoto(c, userID, workoutID, filename); err != nil {
+		h.Log.Errorw("error updating workout photo", "error", err)
+		_ = c.Error(err)
 		return
 	}
 
@@ -163,18 +174,18 @@ func (h *WorkoutHandler) GetPhoto(c *gin.Context) {
 
 	workoutID, err := strconv.Atoi(workoutIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid workout ID"})
+		_ = c.Error(errInvalidID.Wrap(err))
 		return
 	}
 
 	workout, err := h.Service.GetWorkoutByID(c.Request.Context(), userID, workoutID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "workout not found or access denied"})
+		_ = c.Error(err)
 		return
 	}
 
 	if workout.Workout.PhotoPath == nil || *workout.Workout.PhotoPath == "" {
-		c.JSON(http.StatusNotFound, gin.H{"error": "no photo found for this workout"})
+		_ = c.Error(erorrs.NotFound("no photo found for this workout"))
 		return
 	}
 
@@ -8,8 +8,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	dto "workout-tracker/internal/dto/workout"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/handler"
+	"workout-tracker/internal/model/units"
+	join "workout-tracker/internal/model/workoutexercisejoin"
+	"workout-tracker/internal/validation"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -18,9 +24,18 @@ import (
 )
 
 func setupRouter(fs *FakeService) *gin.Engine {
+	return setupRouterWithUnits(fs, units.Metric)
+}
+
+func setupRouterWithUnits(fs *FakeService, system units.System) *gin.Engine {
+	if err := validation.Register(); err != nil {
+		panic(err)
+	}
 	r := gin.New()
+	r.Use(handler.ErrorHandler(zap.NewNop().Sugar()))
 	r.Use(func(c *gin.Context) {
 		c.Set("userID", 7)
+		handler.SetUnitSystem(c, system)
 		c.Next()
 	})
 	h := NewWorkoutHandler(WorkoutHandlerParams{
@@ -38,6 +53,8 @@ func setupRouter(fs *FakeService) *gin.Engine {
 	return r
 }
 
+const validPayload = `{"name":"n","title":"t","category":"strength","exercises":[{"exercise_id":1,"reps":5,"sets":2}]}`
+
 func TestCreate_BadJSON(t *testing.T) {
 	r := setupRouter(&FakeService{})
 	w := httptest.NewRecorder()
@@ -49,8 +66,7 @@ func TestCreate_BadJSON(t *testing.T) {
 func TestCreate_ServiceError(t *testing.T) {
 	fs := &FakeService{CreateErr: errors.New("fail")}
 	r := setupRouter(fs)
-	payload := `{"name":"n","title":"t","category":"c","exercises":[{"exercise_id":1,"reps":5,"sets":2}]}`
-	body := bytes.NewBufferString(payload)
+	body := bytes.NewBufferString(validPayload)
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/workouts", body)
 	req.Header.Set("Content-Type", "application/json")
@@ -58,13 +74,183 @@ func TestCreate_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+func TestCreate_UnknownExercise(t *testing.T) {
+	fs := &FakeService{CreateErr: erorrs.ErrUnknownExercise}
+	r := setupRouter(fs)
+	payload := `{"name":"n","title":"t","category":"strength","exercises":[{"exercise_id":99,"reps":5,"sets":2}]}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestCreate_ValidationReportsAllFields(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	payload := `{"name":"  ","category":"` + strings.Repeat("x", 65) + `","exercises":[` +
+		`{"exercise_id":1,"reps":5,"sets":0},{"exercise_id":2,"reps":5000,"sets":2}]}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, erorrs.CodeValidationFailed, problem.Code)
+
+	fields := make([]string, 0, len(problem.Errors))
+	for _, fe := range problem.Errors {
+		fields = append(fields, fe.Field)
+	}
+	assert.ElementsMatch(t, []string{"name", "category", "exercises[0].sets", "exercises[1].reps"}, fields)
+}
+
+func TestCreate_DuplicateExercises(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	payload := `{"name":"n","exercises":[{"exercise_id":1,"reps":5,"sets":2},{"exercise_id":1,"reps":8,"sets":3}]}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "exercises", problem.Errors[0].Field)
+	assert.Equal(t, "must not contain duplicates", problem.Errors[0].Message)
+}
+
+func TestCreate_NoExercises(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts", bytes.NewBufferString(`{"name":"n","exercises":[]}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestCreate_Blocks(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	payload := `{"name":"n","blocks":[
+		{"exercises":[{"exercise_id":1,"reps":5,"sets":5}]},
+		{"type":"superset","rounds":3,"rest_seconds":90,"exercises":[
+			{"exercise_id":2,"reps":10,"sets":1},{"exercise_id":3,"reps":12,"sets":1}]}]}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Len(t, fs.LastBlocks, 2)
+	assert.Equal(t, join.BlockSuperset, fs.LastBlocks[1].Type)
+	assert.Equal(t, 3, fs.LastBlocks[1].Rounds)
+	assert.Equal(t, 90, fs.LastBlocks[1].RestSeconds)
+	assert.Len(t, fs.LastBlocks[1].Exercises, 2)
+}
+
+func TestCreate_FlatExercisesBecomeOneBlock(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts", bytes.NewBufferString(validPayload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Len(t, fs.LastBlocks, 1)
+	assert.Equal(t, join.BlockStraight, fs.LastBlocks[0].Type)
+	assert.Len(t, fs.LastBlocks[0].Exercises, 1)
+}
+
+func TestCreate_ExercisesAndBlocks(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	payload := `{"name":"n","exercises":[{"exercise_id":1,"reps":5,"sets":2}],
+		"blocks":[{"exercises":[{"exercise_id":2,"reps":5,"sets":2}]}]}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestCreate_InvalidBlockType(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	payload := `{"name":"n","blocks":[{"type":"tabata","exercises":[{"exercise_id":2,"reps":5,"sets":2}]}]}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "blocks[0].type", problem.Errors[0].Field)
+}
+
+func TestCreate_SetPrescription(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouter(fs)
+	payload := `{"name":"n","exercises":[{"exercise_id":1,"prescription":[
+		{"type":"warmup","reps":10,"weight":40},
+		{"reps":6,"reps_max":8,"percent_1rm":80,"rpe":8.5,"rest_seconds":180}]}]}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	sets := fs.LastBlocks[0].Exercises[0].Prescription
+	require.Len(t, sets, 2)
+	assert.Equal(t, join.SetWarmup, sets[0].Type)
+	assert.InDelta(t, 40.0, *sets[0].Weight, 0.001)
+	assert.Equal(t, 8, *sets[1].RepsMax)
+	assert.InDelta(t, 80.0, *sets[1].PercentOneRM, 0.001)
+	assert.InDelta(t, 8.5, *sets[1].RPE, 0.001)
+	assert.Equal(t, 180, *sets[1].RestSeconds)
+}
+
+func TestCreate_InvalidSetPrescription(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	payload := `{"name":"n","exercises":[{"exercise_id":1,"prescription":[{"type":"cluster","rpe":11}]}]}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	fields := make([]string, 0, len(problem.Errors))
+	for _, fe := range problem.Errors {
+		fields = append(fields, fe.Field)
+	}
+	assert.ElementsMatch(t, []string{"exercises[0].prescription[0].type", "exercises[0].prescription[0].rpe"}, fields)
+}
+
+func TestCreate_SetsRequiredWithoutPrescription(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts", bytes.NewBufferString(`{"name":"n","exercises":[{"exercise_id":1}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	require.Len(t, problem.Errors, 2)
+	assert.Equal(t, "is required", problem.Errors[0].Message)
+}
+
 func TestUpdate_ServiceError(t *testing.T) {
 	fs := &FakeService{UpdateErr: errors.New("fail update")}
 	r := setupRouter(fs)
-	reqBody := dto.CreateWorkoutWithExercisesRequest{Name: "n"}
-	bts, _ := json.Marshal(reqBody)
 	w := httptest.NewRecorder()
-	req := httptest.NewRequest(http.MethodPut, "/workouts/5", bytes.NewBuffer(bts))
+	req := httptest.NewRequest(http.MethodPut, "/workouts/5", bytes.NewBufferString(validPayload))
 	req.Header.Set("Content-Type", "application/json")
 	r.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -73,10 +259,8 @@ func TestUpdate_ServiceError(t *testing.T) {
 func TestUpdate_Success(t *testing.T) {
 	fs := &FakeService{UpdateErr: nil}
 	r := setupRouter(fs)
-	reqBody := dto.CreateWorkoutWithExercisesRequest{Name: "n"}
-	bts, _ := json.Marshal(reqBody)
 	w := httptest.NewRecorder()
-	req := httptest.NewRequest(http.MethodPut, "/workouts/5", bytes.NewBuffer(bts))
+	req := httptest.NewRequest(http.MethodPut, "/workouts/5", bytes.NewBufferString(validPayload))
 	req.Header.Set("Content-Type", "application/json")
 	r.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
@@ -115,6 +299,78 @@ func TestGetAll_Error(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+func TestGetAll_ParsesFilter(t *testing.T) {
+	fs := &FakeService{AllResponse: &dto.WorkoutPage{Items: []dto.WorkoutWithExercises{}, NextCursor: "abc"}}
+	r := setupRouter(fs)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet,
+		"/workouts?limit=10&cursor=xyz&category=legs&q=squat&from=2025-01-01&to=2025-01-31&sort=name&order=asc", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.Equal(t, 10, fs.LastFilter.Limit)
+	assert.Equal(t, "xyz", fs.LastFilter.Cursor)
+	assert.Equal(t, "legs", fs.LastFilter.Category)
+	assert.Equal(t, "squat", fs.LastFilter.Search)
+	assert.Equal(t, "name", string(fs.LastFilter.SortBy))
+	assert.Equal(t, "asc", string(fs.LastFilter.Order))
+	require.NotNil(t, fs.LastFilter.To)
+	assert.Equal(t, 23, fs.LastFilter.To.Hour())
+
+	var resp dto.WorkoutPage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "abc", resp.NextCursor)
+}
+
+func TestGetAll_InvalidLimit(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/workouts?limit=zero", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetAll_InvalidCursor(t *testing.T) {
+	r := setupRouter(&FakeService{AllErr: erorrs.ErrInvalidCursor})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/workouts?cursor=bad", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreate_ImperialWeightsStoredInKilograms(t *testing.T) {
+	fs := &FakeService{}
+	r := setupRouterWithUnits(fs, units.Imperial)
+	payload := `{"name":"n","exercises":[{"exercise_id":1,"prescription":[{"reps":5,"weight":225}]}]}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 102.06, *fs.LastBlocks[0].Exercises[0].Prescription[0].Weight)
+}
+
+func TestGet_ImperialWeights(t *testing.T) {
+	weight := 102.06
+	exercise := join.WorkoutExercise{ExerciseID: 1, Prescription: []join.SetPrescription{{Weight: &weight}}}
+	fs := &FakeService{GetResponse: &dto.WorkoutWithExercises{
+		Exercises: []join.WorkoutExercise{exercise},
+		Blocks:    []join.Block{{Exercises: []join.WorkoutExercise{exercise}}},
+	}}
+	r := setupRouterWithUnits(fs, units.Imperial)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/workouts/5", http.NoBody)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.WorkoutWithExercises
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 225.0, *resp.Exercises[0].Prescription[0].Weight)
+	assert.Equal(t, 225.0, *resp.Blocks[0].Exercises[0].Prescription[0].Weight, "shared sets are converted once")
+	assert.Equal(t, 102.06, weight, "the service's workout is left alone")
+}
+
 func TestGet_InvalidID(t *testing.T) {
 	r := setupRouter(&FakeService{})
 	w := httptest.NewRecorder()
@@ -124,7 +380,7 @@ func TestGet_InvalidID(t *testing.T) {
 }
 
 func TestGet_NotFound(t *testing.T) {
-	fs := &FakeService{GetErr: errors.New("not found")}
+	fs := &FakeService{GetErr: erorrs.ErrNotFound}
 	r := setupRouter(fs)
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/workouts/5", http.NoBody)
@@ -132,6 +388,89 @@ func TestGet_NotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+func TestGet_OtherUsersWorkout(t *testing.T) {
+	r := setupRouter(&FakeService{GetErr: erorrs.ErrForbidden})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/workouts/5", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, erorrs.CodeForbidden, problem.Code)
+	assert.Equal(t, "/workouts/5", problem.Instance)
+}
+
+func TestGet_ServiceError(t *testing.T) {
+	r := setupRouter(&FakeService{GetErr: errors.New("db down")})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/workouts/5", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestUpdate_OtherUsersWorkout(t *testing.T) {
+	r := setupRouter(&FakeService{UpdateErr: erorrs.ErrForbidden})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/workouts/5", bytes.NewBufferString(validPayload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestUpdate_NotFound(t *testing.T) {
+	r := setupRouter(&FakeService{UpdateErr: erorrs.ErrNotFound})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/workouts/5", bytes.NewBufferString(validPayload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUpdate_InvalidID(t *testing.T) {
+	r := setupRouter(&FakeService{})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/workouts/abc", bytes.NewBufferString(`{"name":"n"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDelete_OtherUsersWorkout(t *testing.T) {
+	r := setupRouter(&FakeService{DeleteErr: erorrs.ErrForbidden})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/workouts/5", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	r := setupRouter(&FakeService{DeleteErr: erorrs.ErrNotFound})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/workouts/5", http.NoBody)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUpdatePhoto_OtherUsersWorkout(t *testing.T) {
+	defer os.RemoveAll("uploads")
+	r := setupRouter(&FakeService{GetErr: erorrs.ErrForbidden})
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	fw, _ := writer.CreateFormFile("photo", "test.txt")
+	fw.Write([]byte("data"))
+	writer.Close()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workouts/5/photo", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NoFileExists(t, "uploads/workouts/5/photo.jpg")
+}
+
 func TestUpdatePhoto_InvalidID(t *testing.T) {
 	fs := &FakeService{}
 	r := setupRouter(fs)
@@ -223,7 +562,7 @@ func TestGetPhoto_InvalidID(t *testing.T) {
 }
 
 func TestGetPhoto_NotFoundInService(t *testing.T) {
-	fs := &FakeService{GetErr: errors.New("not found")}
+	fs := &FakeService{GetErr: erorrs.ErrNotFound}
 	r := setupRouter(fs)
 
 	w := httptest.NewRecorder()
@@ -0,0 +1,27 @@
+package category
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Category is an admin-managed workout category. Workouts refer to it by Slug.
+type Category struct {
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	Icon      string    `json:"icon"`
+	Color     string    `json:"color"`
+	Position  int       `json:"position"`
+	ID        int       `json:"id"`
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slugify turns free text into a category slug: "Leg Day" and " leg_day " both become "leg-day".
+// Migration 012 applies the same rule to the categories stored before the table existed.
+func Slugify(raw string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(raw), "-"), "-")
+}
@@ -0,0 +1,22 @@
+package category
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"Legs":          "legs",
+		"  legs ":       "legs",
+		"Leg Day":       "leg-day",
+		"leg_day!!":     "leg-day",
+		"Upper / Lower": "upper-lower",
+		"5x5":           "5x5",
+		"💪":             "",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, Slugify(in), in)
+	}
+}
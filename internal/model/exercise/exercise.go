@@ -2,11 +2,50 @@ package exercise
 
 import "time"
 
+type MovementType string
+
+const (
+	Compound  = MovementType("compound")
+	Isolation = MovementType("isolation")
+)
+
+func (m MovementType) IsValid() bool {
+	return m == Compound || m == Isolation
+}
+
+type MeasurementKind string
+
+const (
+	WeightReps = MeasurementKind("weight_reps")
+	Time       = MeasurementKind("time")
+	Distance   = MeasurementKind("distance")
+)
+
+func (k MeasurementKind) IsValid() bool {
+	switch k {
+	case WeightReps, Time, Distance:
+		return true
+	default:
+		return false
+	}
+}
+
 type Exercise struct {
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"update_at"`
-	DeletedAt   *time.Time `json:"deleted_at"`
-	Name        string     `json:"name"`
-	ID          int        `json:"id"`
-	Description string     `json:"description"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"update_at"`
+	DeletedAt        *time.Time      `json:"deleted_at"`
+	OwnerUserID      *int            `json:"owner_user_id,omitempty"`
+	Name             string          `json:"name"`
+	Description      string          `json:"description"`
+	MovementType     MovementType    `json:"movement_type"`
+	MeasurementKind  MeasurementKind `json:"measurement_kind"`
+	PrimaryMuscles   []string        `json:"primary_muscles"`
+	SecondaryMuscles []string        `json:"secondary_muscles"`
+	Equipment        []string        `json:"equipment"`
+	ID               int             `json:"id"`
+}
+
+// IsAccessibleBy reports whether the exercise belongs to the global catalogue or to the given user.
+func (e *Exercise) IsAccessibleBy(userID int) bool {
+	return e.OwnerUserID == nil || *e.OwnerUserID == userID
 }
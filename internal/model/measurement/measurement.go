@@ -0,0 +1,186 @@
+package measurement
+
+import (
+	"fmt"
+	"math"
+	"time"
+	unitsModel "workout-tracker/internal/model/units"
+)
+
+// Kind says what a metric measures and so which units it may be entered in.
+type Kind string
+
+const (
+	KindMass    = Kind("mass")
+	KindLength  = Kind("length")
+	KindPercent = Kind("percent")
+)
+
+func (k Kind) IsValid() bool {
+	switch k {
+	case KindMass, KindLength, KindPercent:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanonicalUnit is the unit values of the kind are stored in.
+func (k Kind) CanonicalUnit() Unit {
+	switch k {
+	case KindMass:
+		return UnitKilogram
+	case KindLength:
+		return UnitCentimetre
+	default:
+		return UnitPercent
+	}
+}
+
+// PreferredUnit is the unit values of the kind are entered and shown in for the unit system.
+func (k Kind) PreferredUnit(system unitsModel.System) Unit {
+	switch k {
+	case KindMass:
+		return Unit(system.MassUnit())
+	case KindLength:
+		return Unit(system.LengthUnit())
+	default:
+		return UnitPercent
+	}
+}
+
+type Unit string
+
+const (
+	UnitKilogram   = Unit("kg")
+	UnitPound      = Unit("lb")
+	UnitCentimetre = Unit("cm")
+	UnitInch       = Unit("in")
+	UnitPercent    = Unit("%")
+)
+
+// units maps every unit to its kind and to how many canonical units one of it is.
+var units = map[Unit]struct {
+	kind   Kind
+	factor float64
+}{
+	UnitKilogram:   {KindMass, 1},
+	UnitPound:      {KindMass, 0.45359237},
+	UnitCentimetre: {KindLength, 1},
+	UnitInch:       {KindLength, 2.54},
+	UnitPercent:    {KindPercent, 1},
+}
+
+// Fits reports whether values of the kind can be given in unit.
+func (k Kind) Fits(unit Unit) bool {
+	u, ok := units[unit]
+	return ok && u.kind == k
+}
+
+// ToCanonical converts a value given in unit to the canonical unit of the kind.
+func ToCanonical(value float64, unit Unit, kind Kind) (float64, error) {
+	if !kind.Fits(unit) {
+		return 0, fmt.Errorf("unit %q does not fit %s", unit, kind)
+	}
+	return value * units[unit].factor, nil
+}
+
+// FromCanonical converts a canonical value to unit, which must fit the value's kind.
+func FromCanonical(value float64, unit Unit) float64 {
+	u, ok := units[unit]
+	if !ok {
+		return value
+	}
+	return value / u.factor
+}
+
+// Bodyweight is the slug of the built-in bodyweight metric.
+const Bodyweight = "bodyweight"
+
+// Metric is something a user measures. Built-in metrics have no UserID.
+type Metric struct {
+	CreatedAt time.Time `json:"created_at"`
+	UserID    *int      `json:"user_id,omitempty"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	Kind      Kind      `json:"kind"`
+	Unit      Unit      `json:"unit"`
+	ID        int       `json:"id"`
+}
+
+func (m Metric) BuiltIn() bool {
+	return m.UserID == nil
+}
+
+// Measurement is one value of a metric, stored in the canonical unit of the metric's kind.
+type Measurement struct {
+	MeasuredAt time.Time `json:"measured_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	Note       string    `json:"note"`
+	Value      float64   `json:"value"`
+	ID         int       `json:"id"`
+	UserID     int       `json:"user_id"`
+	MetricID   int       `json:"metric_id"`
+}
+
+// Point is a measurement of a series with the average of the window ending at it.
+type Point struct {
+	MeasuredAt    time.Time `json:"measured_at"`
+	Value         float64   `json:"value"`
+	MovingAverage float64   `json:"moving_average"`
+	ID            int       `json:"id"`
+}
+
+// Series is a metric's measurements over time in Unit. WeeklyChange compares the latest moving
+// average with the one a week earlier and is missing until the series spans a week.
+type Series struct {
+	Metric       Metric   `json:"metric"`
+	Latest       *float64 `json:"latest"`
+	WeeklyChange *float64 `json:"weekly_change"`
+	Unit         Unit     `json:"unit"`
+	Points       []Point  `json:"points"`
+	WindowDays   int      `json:"window_days"`
+}
+
+const week = 7 * 24 * time.Hour
+
+// BuildSeries turns measurements ordered by time into a series in unit. Each point's moving
+// average covers the measurements of the windowDays days up to and including it.
+func BuildSeries(metric Metric, measurements []Measurement, windowDays int, unit Unit) Series {
+	series := Series{Metric: metric, Unit: unit, WindowDays: windowDays, Points: make([]Point, len(measurements))}
+	window := time.Duration(windowDays) * 24 * time.Hour
+
+	start, sum := 0, 0.0
+	for i, m := range measurements {
+		value := FromCanonical(m.Value, unit)
+		sum += value
+		for start < i && !measurements[start].MeasuredAt.Add(window).After(m.MeasuredAt) {
+			sum -= FromCanonical(measurements[start].Value, unit)
+			start++
+		}
+		series.Points[i] = Point{
+			ID:            m.ID,
+			MeasuredAt:    m.MeasuredAt,
+			Value:         round(value),
+			MovingAverage: round(sum / float64(i-start+1)),
+		}
+	}
+
+	if len(series.Points) == 0 {
+		return series
+	}
+	last := series.Points[len(series.Points)-1]
+	series.Latest = &last.Value
+	for i := len(series.Points) - 1; i >= 0; i-- {
+		if p := series.Points[i]; !p.MeasuredAt.After(last.MeasuredAt.Add(-week)) {
+			change := round(last.MovingAverage - p.MovingAverage)
+			series.WeeklyChange = &change
+			break
+		}
+	}
+	return series
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
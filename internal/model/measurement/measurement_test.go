@@ -0,0 +1,67 @@
+package measurement
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToCanonical(t *testing.T) {
+	kg, err := ToCanonical(220, UnitPound, KindMass)
+	require.NoError(t, err)
+	assert.InDelta(t, 99.79, kg, 0.01)
+
+	cm, err := ToCanonical(32, UnitInch, KindLength)
+	require.NoError(t, err)
+	assert.InDelta(t, 81.28, cm, 0.001)
+
+	_, err = ToCanonical(80, UnitKilogram, KindLength)
+	assert.Error(t, err)
+
+	assert.InDelta(t, 220, FromCanonical(kg, UnitPound), 0.001)
+}
+
+func at(day int) time.Time {
+	return time.Date(2026, 3, day, 8, 0, 0, 0, time.UTC)
+}
+
+func TestBuildSeries(t *testing.T) {
+	metric := Metric{Slug: Bodyweight, Kind: KindMass}
+	series := BuildSeries(metric, []Measurement{
+		{MeasuredAt: at(1), Value: 80},
+		{MeasuredAt: at(3), Value: 82},
+		{MeasuredAt: at(7), Value: 81},
+		{MeasuredAt: at(8), Value: 79},
+		{MeasuredAt: at(10), Value: 78},
+	}, 7, UnitKilogram)
+
+	require.Len(t, series.Points, 5)
+	averages := make([]float64, len(series.Points))
+	for i, p := range series.Points {
+		averages[i] = p.MovingAverage
+	}
+	// The window of the 8th no longer includes the 1st; the one of the 10th starts after the 3rd.
+	assert.Equal(t, []float64{80, 81, 81, 80.67, 79.33}, averages)
+	assert.Equal(t, 78.0, *series.Latest)
+	// A week before the 10th, the moving average was 81 (on the 3rd).
+	require.NotNil(t, series.WeeklyChange)
+	assert.Equal(t, -1.67, *series.WeeklyChange)
+}
+
+func TestBuildSeries_ConvertsUnitAndNeedsAWeek(t *testing.T) {
+	series := BuildSeries(Metric{Kind: KindMass}, []Measurement{
+		{MeasuredAt: at(1), Value: 100},
+		{MeasuredAt: at(4), Value: 101},
+	}, 7, UnitPound)
+
+	assert.Equal(t, 220.46, series.Points[0].Value)
+	assert.Nil(t, series.WeeklyChange)
+}
+
+func TestBuildSeries_Empty(t *testing.T) {
+	series := BuildSeries(Metric{Kind: KindMass}, nil, 7, UnitKilogram)
+	assert.Empty(t, series.Points)
+	assert.Nil(t, series.Latest)
+}
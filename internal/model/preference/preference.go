@@ -0,0 +1,19 @@
+package preference
+
+import (
+	"time"
+	"workout-tracker/internal/model/progression"
+	"workout-tracker/internal/model/units"
+)
+
+// Preferences are a user's settings. Users who never saved any get Default.
+type Preferences struct {
+	UpdatedAt           time.Time            `json:"updated_at"`
+	ProgressionStrategy progression.Strategy `json:"progression_strategy"`
+	UnitSystem          units.System         `json:"unit_system"`
+	UserID              int                  `json:"-"`
+}
+
+func Default(userID int) Preferences {
+	return Preferences{UserID: userID, ProgressionStrategy: progression.DefaultStrategy, UnitSystem: units.DefaultSystem}
+}
@@ -0,0 +1,185 @@
+package program
+
+import (
+	"time"
+	"workout-tracker/internal/model/units"
+	join "workout-tracker/internal/model/workoutexercisejoin"
+)
+
+const (
+	DaysPerWeek = 7
+	// minReps is the fewest reps a rule that lowers rep targets leaves.
+	minReps = 1
+)
+
+// Program is a multi-week training plan. Each week maps some of its days to workout templates of the
+// program's author; days without a workout are rest days.
+type Program struct {
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Weeks       []Week            `json:"weeks,omitempty"`
+	Progression []ProgressionRule `json:"progression,omitempty"`
+	ID          int               `json:"id"`
+	UserID      int               `json:"user_id"`
+	WeekCount   int               `json:"week_count"`
+}
+
+type Week struct {
+	Number int   `json:"number"`
+	Days   []Day `json:"days"`
+}
+
+// Day assigns a workout to one day of a program week. Both Week and Day count from one.
+type Day struct {
+	Week      int `json:"-"`
+	Day       int `json:"day"`
+	WorkoutID int `json:"workout_id"`
+}
+
+// InUnits returns a copy of the program with the amounts of its weight rules converted from
+// kilograms to the system's mass unit.
+func (p Program) InUnits(system units.System) Program {
+	if len(p.Progression) == 0 {
+		return p
+	}
+	rules := make([]ProgressionRule, len(p.Progression))
+	for i, r := range p.Progression {
+		if r.Type == ProgressWeight {
+			r.Amount = system.FromKilograms(r.Amount)
+		}
+		rules[i] = r
+	}
+	p.Progression = rules
+	return p
+}
+
+// GroupWeeks arranges the program's days into weekCount weeks, keeping empty weeks.
+func GroupWeeks(weekCount int, days []Day) []Week {
+	weeks := make([]Week, weekCount)
+	for i := range weeks {
+		weeks[i] = Week{Number: i + 1, Days: []Day{}}
+	}
+	for _, d := range days {
+		if d.Week >= 1 && d.Week <= weekCount {
+			weeks[d.Week-1].Days = append(weeks[d.Week-1].Days, d)
+		}
+	}
+	return weeks
+}
+
+type ProgressionType string
+
+const (
+	ProgressWeight       = ProgressionType("weight")
+	ProgressPercentOneRM = ProgressionType("percent_1rm")
+	ProgressReps         = ProgressionType("reps")
+)
+
+func (t ProgressionType) IsValid() bool {
+	switch t {
+	case ProgressWeight, ProgressPercentOneRM, ProgressReps:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProgressionRule raises a target by Amount every EveryWeeks weeks: weight targets by kilograms,
+// %1RM targets by percentage points, rep targets by reps. A rule without ExerciseID applies to every
+// exercise of the program.
+type ProgressionRule struct {
+	ExerciseID *int            `json:"exercise_id,omitempty"`
+	Type       ProgressionType `json:"type"`
+	Amount     float64         `json:"amount"`
+	EveryWeeks int             `json:"every_weeks"`
+}
+
+// Steps is how often the rule has been applied by the given week; week one is never progressed.
+func (r ProgressionRule) Steps(week int) int {
+	every := r.EveryWeeks
+	if every < 1 {
+		every = 1
+	}
+	if week <= 1 {
+		return 0
+	}
+	return (week - 1) / every
+}
+
+// Apply progresses the exercise's targets to the given week. Targets the rule does not cover, such
+// as a weight rule on an exercise prescribed only as sets×reps, are left as they are. Rules with a
+// negative amount never lower weights and percentages below zero or reps below one.
+func (r ProgressionRule) Apply(e *join.WorkoutExercise, week int) {
+	if r.ExerciseID != nil && *r.ExerciseID != e.ExerciseID {
+		return
+	}
+	steps := r.Steps(week)
+	if steps == 0 {
+		return
+	}
+	delta := r.Amount * float64(steps)
+
+	if r.Type == ProgressReps && e.Reps > 0 {
+		e.Reps = max(e.Reps+int(delta), minReps)
+	}
+	for i := range e.Prescription {
+		set := &e.Prescription[i]
+		switch r.Type {
+		case ProgressWeight:
+			set.Weight = addFloat(set.Weight, delta)
+		case ProgressPercentOneRM:
+			set.PercentOneRM = addFloat(set.PercentOneRM, delta)
+		case ProgressReps:
+			set.Reps = addInt(set.Reps, int(delta))
+			set.RepsMax = addInt(set.RepsMax, int(delta))
+		}
+	}
+}
+
+func addFloat(v *float64, delta float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	sum := max(*v+delta, 0)
+	return &sum
+}
+
+func addInt(v *int, delta int) *int {
+	if v == nil {
+		return nil
+	}
+	sum := max(*v+delta, minReps)
+	return &sum
+}
+
+// Enrollment puts a user on a program. StartDate is day one of week one.
+type Enrollment struct {
+	StartDate time.Time `json:"start_date"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    int       `json:"user_id"`
+	ProgramID int       `json:"program_id"`
+}
+
+// Position returns the one-based week and day of date within the enrollment, or zero for both
+// before the start date. Only the calendar dates count, not the time of day.
+func (e Enrollment) Position(date time.Time) (week, day int) {
+	start := time.Date(e.StartDate.Year(), e.StartDate.Month(), e.StartDate.Day(), 0, 0, 0, 0, time.UTC)
+	current := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	offset := int(current.Sub(start).Hours() / 24)
+	if offset < 0 {
+		return 0, 0
+	}
+	return offset/DaysPerWeek + 1, offset%DaysPerWeek + 1
+}
+
+// TodayStatus says what an enrolled user's program has planned for a day.
+type TodayStatus string
+
+const (
+	TodayWorkout    = TodayStatus("workout")
+	TodayRest       = TodayStatus("rest")
+	TodayNotStarted = TodayStatus("not_started")
+	TodayFinished   = TodayStatus("finished")
+)
@@ -0,0 +1,90 @@
+package program
+
+import (
+	"testing"
+	"time"
+	"workout-tracker/internal/model/units"
+	join "workout-tracker/internal/model/workoutexercisejoin"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressionRuleSteps(t *testing.T) {
+	every2 := ProgressionRule{EveryWeeks: 2}
+	assert.Equal(t, []int{0, 0, 1, 1, 2}, []int{every2.Steps(1), every2.Steps(2), every2.Steps(3), every2.Steps(4), every2.Steps(5)})
+	assert.Equal(t, 3, ProgressionRule{}.Steps(4), "rules without an interval progress weekly")
+}
+
+func TestProgressionRuleApply(t *testing.T) {
+	reps, weight, percent := 8, 60.0, 70.0
+	exercise := join.WorkoutExercise{ExerciseID: 3, Reps: 8, Prescription: []join.SetPrescription{
+		{Reps: &reps, Weight: &weight},
+		{Reps: &reps, PercentOneRM: &percent},
+	}}
+	other := 4
+
+	ProgressionRule{Type: ProgressWeight, Amount: 2.5, EveryWeeks: 1}.Apply(&exercise, 3)
+	ProgressionRule{Type: ProgressPercentOneRM, Amount: 2.5, EveryWeeks: 1}.Apply(&exercise, 3)
+	ProgressionRule{Type: ProgressReps, Amount: 1, EveryWeeks: 1}.Apply(&exercise, 2)
+	ProgressionRule{ExerciseID: &other, Type: ProgressReps, Amount: 5, EveryWeeks: 1}.Apply(&exercise, 3)
+
+	assert.Equal(t, 9, exercise.Reps)
+	assert.InDelta(t, 65.0, *exercise.Prescription[0].Weight, 0.001)
+	assert.Nil(t, exercise.Prescription[0].PercentOneRM)
+	assert.InDelta(t, 75.0, *exercise.Prescription[1].PercentOneRM, 0.001)
+	assert.Nil(t, exercise.Prescription[1].Weight)
+	assert.Equal(t, 9, *exercise.Prescription[1].Reps)
+	assert.Equal(t, 8, reps)
+}
+
+func TestProgressionRuleApply_NegativeAmountsStopAtTheFloor(t *testing.T) {
+	reps, repsMax, weight := 5, 8, 20.0
+	exercise := join.WorkoutExercise{ExerciseID: 3, Reps: 5, Prescription: []join.SetPrescription{
+		{Reps: &reps, RepsMax: &repsMax, Weight: &weight},
+	}}
+
+	ProgressionRule{Type: ProgressWeight, Amount: -10, EveryWeeks: 1}.Apply(&exercise, 4)
+	ProgressionRule{Type: ProgressReps, Amount: -3, EveryWeeks: 1}.Apply(&exercise, 4)
+
+	assert.Equal(t, 1, exercise.Reps)
+	assert.Equal(t, 0.0, *exercise.Prescription[0].Weight)
+	assert.Equal(t, 1, *exercise.Prescription[0].Reps)
+	assert.Equal(t, 1, *exercise.Prescription[0].RepsMax)
+}
+
+func TestProgramInUnits(t *testing.T) {
+	p := Program{Progression: []ProgressionRule{
+		{Type: ProgressWeight, Amount: 2.27},
+		{Type: ProgressReps, Amount: 1},
+	}}
+	imperial := p.InUnits(units.Imperial)
+	assert.Equal(t, 5.0, imperial.Progression[0].Amount)
+	assert.Equal(t, 1.0, imperial.Progression[1].Amount)
+	assert.Equal(t, 2.27, p.Progression[0].Amount, "the program itself is left as it is")
+}
+
+func TestEnrollmentPosition(t *testing.T) {
+	e := Enrollment{StartDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)}
+	cases := []struct {
+		date      time.Time
+		week, day int
+	}{
+		{time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC), 0, 0},
+		{time.Date(2026, 3, 2, 23, 59, 0, 0, time.UTC), 1, 1},
+		{time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), 1, 7},
+		{time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), 2, 1},
+		{time.Date(2026, 4, 1, 6, 0, 0, 0, time.FixedZone("UTC+10", 10*3600)), 5, 3},
+	}
+	for _, c := range cases {
+		week, day := e.Position(c.date)
+		assert.Equal(t, [2]int{c.week, c.day}, [2]int{week, day}, c.date.String())
+	}
+}
+
+func TestGroupWeeks(t *testing.T) {
+	weeks := GroupWeeks(2, []Day{{Week: 2, Day: 1, WorkoutID: 7}, {Week: 3, Day: 1, WorkoutID: 8}})
+	assert.Equal(t, []Week{
+		{Number: 1, Days: []Day{}},
+		{Number: 2, Days: []Day{{Week: 2, Day: 1, WorkoutID: 7}}},
+	}, weeks)
+}
@@ -0,0 +1,217 @@
+package progression
+
+import (
+	"fmt"
+	"workout-tracker/internal/model/session"
+	"workout-tracker/internal/model/units"
+	join "workout-tracker/internal/model/workoutexercisejoin"
+)
+
+// Strategy decides how the next session's targets follow from the last ones.
+type Strategy string
+
+const (
+	// Linear adds a plate step to the weight after every session in which all target sets and reps
+	// were done.
+	Linear = Strategy("linear")
+	// Double first works up to the top of the rep range and only then adds weight, starting again at
+	// the bottom of the range.
+	Double = Strategy("double")
+	// RPE moves the weight so that the top set lands on the target RPE.
+	RPE = Strategy("rpe")
+)
+
+const DefaultStrategy = Linear
+
+func (s Strategy) IsValid() bool {
+	switch s {
+	case Linear, Double, RPE:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	// DefaultTargetRPE is aimed for by the RPE strategy when the template sets no RPE.
+	DefaultTargetRPE = 8.0
+	// rpeStep is the share of the weight one point of RPE is assumed to be worth.
+	rpeStep = 0.025
+	// DeloadAfter is the number of sessions in a row that must miss the targets before the weight is
+	// deloaded. It is also how many past sessions suggestions look at.
+	DeloadAfter = 3
+	// DeloadFactor is applied to the weight on a deload.
+	DeloadFactor = 0.9
+)
+
+// Target is what a workout template asks of one exercise. RepsMax equals Reps unless the
+// template prescribes a rep range.
+type Target struct {
+	Weight  *float64
+	RPE     *float64
+	Sets    int
+	Reps    int
+	RepsMax int
+}
+
+// TargetOf reads the target of an exercise from its first working set with a rep goal, falling back
+// to its sets×reps summary.
+func TargetOf(e join.WorkoutExercise) Target {
+	t := Target{Sets: e.Sets, Reps: e.Reps}
+	for _, set := range e.Prescription {
+		if set.Type != join.SetWorking || set.Reps == nil {
+			continue
+		}
+		t.Reps = *set.Reps
+		if set.RepsMax != nil {
+			t.RepsMax = *set.RepsMax
+		}
+		t.Weight, t.RPE = set.Weight, set.RPE
+		break
+	}
+	if t.RepsMax < t.Reps {
+		t.RepsMax = t.Reps
+	}
+	return t
+}
+
+// Performance summarises how an exercise went in one session: the heaviest weight used, how many
+// sets were done with it, the fewest reps of those sets and the highest RPE reported for them.
+type Performance struct {
+	RPE    *float64
+	Weight float64
+	Sets   int
+	Reps   int
+}
+
+func Summarise(sets []session.SessionSet) Performance {
+	var p Performance
+	for _, s := range sets {
+		switch {
+		case s.Weight > p.Weight || p.Sets == 0:
+			p = Performance{Weight: s.Weight, Sets: 1, Reps: s.Reps, RPE: s.RPE}
+		case s.Weight == p.Weight:
+			p.Sets++
+			p.Reps = min(p.Reps, s.Reps)
+			if s.RPE != nil && (p.RPE == nil || *s.RPE > *p.RPE) {
+				p.RPE = s.RPE
+			}
+		}
+	}
+	return p
+}
+
+// Met reports whether every target set was done with at least the target reps.
+func (p Performance) Met(t Target) bool {
+	return p.Sets >= t.Sets && p.Reps >= t.Reps
+}
+
+// Suggestion is what the next session should aim for. Reason explains it in a sentence. Weight is
+// in kilograms and not rounded, it is rounded to the plates of the user's unit system for display.
+type Suggestion struct {
+	Reason string  `json:"reason"`
+	Weight float64 `json:"weight"`
+	Sets   int     `json:"sets"`
+	Reps   int     `json:"reps"`
+	Deload bool    `json:"deload,omitempty"`
+}
+
+// Suggest works out the next session of an exercise from the sessions it was last done in, newest
+// first, each holding only that exercise's sets. Whatever the strategy, missing the targets
+// DeloadAfter sessions in a row deloads the weight. Exercises done without weight progress by reps.
+// Weight is added in plate steps of system, and Reason names them in its mass unit.
+func Suggest(strategy Strategy, system units.System, t Target, history []session.Session) Suggestion {
+	next := Suggestion{Sets: t.Sets, Reps: t.Reps}
+	if len(history) == 0 {
+		if t.Weight != nil {
+			next.Weight = *t.Weight
+		}
+		next.Reason = "no logged sessions yet, start with the template"
+		return next
+	}
+
+	last := Summarise(history[0].Sets)
+	next.Weight = last.Weight
+
+	if failedInARow(t, history) >= DeloadAfter && last.Weight > 0 {
+		next.Weight = last.Weight * DeloadFactor
+		next.Deload = true
+		next.Reason = fmt.Sprintf("targets missed %d sessions in a row, deload by %.0f%%", DeloadAfter, (1-DeloadFactor)*100)
+		return next
+	}
+
+	if last.Weight == 0 {
+		if last.Met(t) {
+			next.Reps = last.Reps + 1
+			next.Reason = "all sets done without weight, add a rep"
+		} else {
+			next.Reason = "repeat until all sets are done"
+		}
+		return next
+	}
+
+	switch strategy {
+	case Double:
+		return suggestDouble(next, system, t, last)
+	case RPE:
+		if last.RPE != nil {
+			return suggestRPE(next, t, last)
+		}
+	}
+	return suggestLinear(next, system, t, last)
+}
+
+func suggestLinear(next Suggestion, system units.System, t Target, last Performance) Suggestion {
+	if last.Met(t) {
+		next.Weight = last.Weight + system.ToKilograms(system.PlateStep())
+		next.Reason = fmt.Sprintf("all %d×%d done, add %s", t.Sets, t.Reps, step(system))
+		return next
+	}
+	next.Reason = "targets missed, repeat the weight"
+	return next
+}
+
+func suggestDouble(next Suggestion, system units.System, t Target, last Performance) Suggestion {
+	switch {
+	case last.Sets >= t.Sets && last.Reps >= t.RepsMax:
+		next.Weight = last.Weight + system.ToKilograms(system.PlateStep())
+		next.Reason = fmt.Sprintf("top of the %d-%d range reached, add %s", t.Reps, t.RepsMax, step(system))
+	case last.Met(t):
+		next.Reps = min(last.Reps+1, t.RepsMax)
+		next.Reason = "within the rep range, add a rep"
+	default:
+		next.Reason = "bottom of the rep range missed, repeat the weight"
+	}
+	return next
+}
+
+func suggestRPE(next Suggestion, t Target, last Performance) Suggestion {
+	target := DefaultTargetRPE
+	if t.RPE != nil {
+		target = *t.RPE
+	}
+	diff := target - *last.RPE
+	if !last.Met(t) && diff > 0 {
+		diff = 0
+	}
+	next.Weight = max(last.Weight*(1+rpeStep*diff), 0)
+	next.Reason = fmt.Sprintf("top sets at RPE %.1f, aiming for RPE %.1f", *last.RPE, target)
+	return next
+}
+
+// failedInARow counts the most recent sessions that missed the targets.
+func failedInARow(t Target, history []session.Session) int {
+	n := 0
+	for _, s := range history {
+		if Summarise(s.Sets).Met(t) {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// step names the plate step of system, such as "2.5 kg".
+func step(system units.System) string {
+	return fmt.Sprintf("%g %s", system.PlateStep(), system.MassUnit())
+}
@@ -0,0 +1,112 @@
+package progression
+
+import (
+	"testing"
+	"workout-tracker/internal/model/session"
+	"workout-tracker/internal/model/units"
+	join "workout-tracker/internal/model/workoutexercisejoin"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sessionOf builds a logged session of sets at one weight with the given reps.
+func sessionOf(weight float64, rpe *float64, reps ...int) session.Session {
+	s := session.Session{}
+	for i, r := range reps {
+		s.Sets = append(s.Sets, session.SessionSet{SetNumber: i + 1, Weight: weight, Reps: r, RPE: rpe})
+	}
+	return s
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestTargetOf(t *testing.T) {
+	assert.Equal(t, Target{Sets: 3, Reps: 5, RepsMax: 5}, TargetOf(join.WorkoutExercise{Sets: 3, Reps: 5}))
+
+	e := join.WorkoutExercise{Sets: 3, Reps: 8, Prescription: []join.SetPrescription{
+		{Type: join.SetWarmup, Reps: ptr(10)},
+		{Type: join.SetWorking, Reps: ptr(8), RepsMax: ptr(12), Weight: ptr(60.0), RPE: ptr(8.5)},
+	}}
+	assert.Equal(t, Target{Sets: 3, Reps: 8, RepsMax: 12, Weight: ptr(60.0), RPE: ptr(8.5)}, TargetOf(e))
+}
+
+func TestSummarise(t *testing.T) {
+	p := Summarise([]session.SessionSet{
+		{Weight: 40, Reps: 10},
+		{Weight: 60, Reps: 5, RPE: ptr(7.0)},
+		{Weight: 60, Reps: 4, RPE: ptr(9.0)},
+	})
+	assert.Equal(t, Performance{Weight: 60, Sets: 2, Reps: 4, RPE: ptr(9.0)}, p)
+}
+
+func TestSuggest_NoHistory(t *testing.T) {
+	s := Suggest(Linear, units.Metric, Target{Sets: 3, Reps: 5, RepsMax: 5, Weight: ptr(50.0)}, nil)
+	assert.Equal(t, 50.0, s.Weight)
+	assert.Equal(t, 5, s.Reps)
+	assert.False(t, s.Deload)
+}
+
+func TestSuggest_Linear(t *testing.T) {
+	target := Target{Sets: 3, Reps: 5, RepsMax: 5}
+
+	s := Suggest(Linear, units.Metric, target, []session.Session{sessionOf(100, nil, 5, 5, 5)})
+	assert.Equal(t, 102.5, s.Weight)
+
+	assert.Equal(t, "all 3×5 done, add 2.5 kg", s.Reason)
+
+	s = Suggest(Linear, units.Metric, target, []session.Session{sessionOf(100, nil, 5, 5, 4)})
+	assert.Equal(t, 100.0, s.Weight)
+}
+
+func TestSuggest_ImperialAddsPounds(t *testing.T) {
+	target := Target{Sets: 3, Reps: 5, RepsMax: 5}
+
+	s := Suggest(Linear, units.Imperial, target, []session.Session{sessionOf(100, nil, 5, 5, 5)})
+	assert.Equal(t, 102.27, s.Weight, "5 lb on top of 100 kg")
+	assert.Equal(t, "all 3×5 done, add 5 lb", s.Reason)
+	assert.Equal(t, 225.0, units.Imperial.RoundToPlates(s.Weight))
+}
+
+func TestSuggest_Double(t *testing.T) {
+	target := Target{Sets: 3, Reps: 8, RepsMax: 12}
+
+	s := Suggest(Double, units.Metric, target, []session.Session{sessionOf(40, nil, 10, 9, 9)})
+	assert.Equal(t, 40.0, s.Weight)
+	assert.Equal(t, 10, s.Reps)
+
+	s = Suggest(Double, units.Metric, target, []session.Session{sessionOf(40, nil, 12, 12, 12)})
+	assert.Equal(t, 42.5, s.Weight)
+	assert.Equal(t, 8, s.Reps)
+}
+
+func TestSuggest_RPE(t *testing.T) {
+	target := Target{Sets: 3, Reps: 5, RepsMax: 5, RPE: ptr(8.0)}
+
+	s := Suggest(RPE, units.Metric, target, []session.Session{sessionOf(100, ptr(6.0), 5, 5, 5)})
+	assert.InDelta(t, 105.0, s.Weight, 1e-9)
+
+	s = Suggest(RPE, units.Metric, target, []session.Session{sessionOf(100, ptr(10.0), 5, 5, 5)})
+	assert.InDelta(t, 95.0, s.Weight, 1e-9)
+
+	s = Suggest(RPE, units.Metric, target, []session.Session{sessionOf(100, nil, 5, 5, 5)})
+	assert.Equal(t, 102.5, s.Weight, "without RPE logged the strategy falls back to linear")
+}
+
+func TestSuggest_DeloadAfterRepeatedFailures(t *testing.T) {
+	target := Target{Sets: 3, Reps: 5, RepsMax: 5}
+	failed := sessionOf(100, nil, 5, 4, 3)
+
+	s := Suggest(Double, units.Metric, target, []session.Session{failed, failed, failed})
+	assert.True(t, s.Deload)
+	assert.InDelta(t, 90.0, s.Weight, 1e-9)
+
+	s = Suggest(Double, units.Metric, target, []session.Session{failed, failed, sessionOf(100, nil, 5, 5, 5)})
+	assert.False(t, s.Deload)
+	assert.Equal(t, 100.0, s.Weight)
+}
+
+func TestSuggest_Bodyweight(t *testing.T) {
+	s := Suggest(Linear, units.Metric, Target{Sets: 3, Reps: 10, RepsMax: 10}, []session.Session{sessionOf(0, nil, 10, 10, 11)})
+	assert.Equal(t, 0.0, s.Weight)
+	assert.Equal(t, 11, s.Reps)
+}
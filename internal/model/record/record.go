@@ -0,0 +1,76 @@
+package record
+
+import (
+	"time"
+	"workout-tracker/internal/model/units"
+)
+
+type RecordType string
+
+const (
+	MaxWeight     = RecordType("max_weight")
+	RepsAtWeight  = RecordType("reps_at_weight")
+	OneRepMax     = RecordType("estimated_1rm")
+	SessionVolume = RecordType("session_volume")
+)
+
+// brzyckiMaxReps is the rep count above which the Brzycki formula stops being reliable and Epley is used instead.
+const brzyckiMaxReps = 10
+
+type PersonalRecord struct {
+	AchievedAt time.Time  `json:"achieved_at"`
+	SessionID  *int       `json:"session_id,omitempty"`
+	SetID      *int       `json:"set_id,omitempty"`
+	RecordType RecordType `json:"record_type"`
+	Value      float64    `json:"value"`
+	Weight     float64    `json:"weight"`
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	ExerciseID int        `json:"exercise_id"`
+	Reps       int        `json:"reps"`
+}
+
+type Records struct {
+	Current []PersonalRecord `json:"current"`
+	History []PersonalRecord `json:"history"`
+}
+
+// InUnits returns a copy of the record with its weights converted from kilograms to the system's
+// mass unit. The value of a RepsAtWeight record counts reps and is left as it is.
+func (r PersonalRecord) InUnits(system units.System) PersonalRecord {
+	r.Weight = system.FromKilograms(r.Weight)
+	if r.RecordType != RepsAtWeight {
+		r.Value = system.FromKilograms(r.Value)
+	}
+	return r
+}
+
+func (r Records) InUnits(system units.System) Records {
+	return Records{Current: recordsInUnits(r.Current, system), History: recordsInUnits(r.History, system)}
+}
+
+func recordsInUnits(list []PersonalRecord, system units.System) []PersonalRecord {
+	if list == nil {
+		return nil
+	}
+	converted := make([]PersonalRecord, len(list))
+	for i, r := range list {
+		converted[i] = r.InUnits(system)
+	}
+	return converted
+}
+
+// EstimateOneRepMax returns the estimated one-rep max for a set, using Brzycki up to
+// ten reps and Epley beyond that.
+func EstimateOneRepMax(weight float64, reps int) float64 {
+	switch {
+	case weight <= 0 || reps <= 0:
+		return 0
+	case reps == 1:
+		return weight
+	case reps <= brzyckiMaxReps:
+		return weight * 36 / float64(37-reps)
+	default:
+		return weight * (1 + float64(reps)/30)
+	}
+}
@@ -0,0 +1,15 @@
+package record
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateOneRepMax(t *testing.T) {
+	assert.InDelta(t, 0, EstimateOneRepMax(0, 5), 0.001)
+	assert.InDelta(t, 0, EstimateOneRepMax(100, 0), 0.001)
+	assert.InDelta(t, 100, EstimateOneRepMax(100, 1), 0.001)
+	assert.InDelta(t, 112.5, EstimateOneRepMax(100, 5), 0.001)
+	assert.InDelta(t, 140, EstimateOneRepMax(100, 12), 0.001)
+}
@@ -0,0 +1,89 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	icalTime = "20060102T150405Z"
+	icalDate = "20060102"
+	// icalLineLimit is the longest a content line may be, in octets, before it has to be folded.
+	icalLineLimit = 75
+)
+
+// ICS renders the entries as an iCalendar (RFC 5545) feed. Every occurrence gets an event of its
+// own with a stable UID, so calendar apps update events in place when the feed is polled again.
+func ICS(entries []Entry, now time.Time) string {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(fold(s))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//workout-tracker//calendar//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:Workouts")
+	for _, e := range entries {
+		line("BEGIN:VEVENT")
+		line("UID:" + uid(e))
+		line("DTSTAMP:" + now.UTC().Format(icalTime))
+		line("DTSTART:" + e.StartsAt.UTC().Format(icalTime))
+		if e.EndsAt.After(e.StartsAt) {
+			line("DTEND:" + e.EndsAt.UTC().Format(icalTime))
+		}
+		line("SUMMARY:" + escape(e.WorkoutName))
+		switch e.Status {
+		case StatusSkipped:
+			line("STATUS:CANCELLED")
+		case StatusCompleted:
+			line("STATUS:CONFIRMED")
+			line("DESCRIPTION:Completed")
+		default:
+			line("STATUS:CONFIRMED")
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+func uid(e Entry) string {
+	if e.ScheduleID != nil {
+		return fmt.Sprintf("schedule-%d-%s@workout-tracker", *e.ScheduleID, e.StartsAt.UTC().Format(icalDate))
+	}
+	if e.SessionID != nil {
+		return fmt.Sprintf("session-%d@workout-tracker", *e.SessionID)
+	}
+	return fmt.Sprintf("entry-%s@workout-tracker", e.StartsAt.UTC().Format(icalTime))
+}
+
+// escape quotes the characters RFC 5545 reserves in TEXT values.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// fold splits a content line longer than icalLineLimit octets into continuation lines, never
+// cutting a UTF-8 sequence apart.
+func fold(s string) string {
+	if len(s) <= icalLineLimit {
+		return s
+	}
+	var b strings.Builder
+	limit := icalLineLimit
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			// The leading space of a continuation line counts towards its length.
+			width, limit = 0, icalLineLimit-1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
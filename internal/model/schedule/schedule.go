@@ -0,0 +1,240 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Weekdays is a set of days of the week, one bit per time.Weekday.
+type Weekdays uint8
+
+var weekdayNames = [...]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseWeekdays turns day names such as "mon" into a set.
+func ParseWeekdays(names []string) (Weekdays, error) {
+	var w Weekdays
+	for _, name := range names {
+		found := false
+		for d, n := range weekdayNames {
+			if n == name {
+				w |= 1 << d
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown weekday %q", name)
+		}
+	}
+	return w, nil
+}
+
+func (w Weekdays) Has(d time.Weekday) bool {
+	return w&(1<<d) != 0
+}
+
+// Names lists the days of the set from Monday to Sunday.
+func (w Weekdays) Names() []string {
+	names := []string{}
+	for i := 1; i <= len(weekdayNames); i++ {
+		d := time.Weekday(i % len(weekdayNames))
+		if w.Has(d) {
+			names = append(names, weekdayNames[d])
+		}
+	}
+	return names
+}
+
+func (w Weekdays) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.Names())
+}
+
+func (w *Weekdays) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+	parsed, err := ParseWeekdays(names)
+	if err != nil {
+		return err
+	}
+	*w = parsed
+	return nil
+}
+
+// Schedule plans one of the user's workouts at StartsAt. With Weekdays set it repeats at the same
+// time of day on those days from StartsAt on, up to and including Until when that is set.
+// Recurrences are worked out in Timezone, so the time of day stays put across daylight saving
+// changes.
+type Schedule struct {
+	StartsAt        time.Time  `json:"starts_at"`
+	Until           *time.Time `json:"until,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	WorkoutName     string     `json:"workout_name"`
+	Timezone        string     `json:"timezone"`
+	ID              int        `json:"id"`
+	UserID          int        `json:"user_id"`
+	WorkoutID       int        `json:"workout_id"`
+	DurationMinutes int        `json:"duration_minutes"`
+	Weekdays        Weekdays   `json:"repeat"`
+}
+
+func (s Schedule) Recurring() bool {
+	return s.Weekdays != 0
+}
+
+// Location returns the location of Timezone, or UTC when it is unset or unknown.
+func (s Schedule) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Occurrences returns the start times of the schedule within [from, to), in UTC.
+func (s Schedule) Occurrences(from, to time.Time) []time.Time {
+	loc := s.Location()
+	start := s.StartsAt.In(loc)
+	if !s.Recurring() {
+		if !start.Before(from) && start.Before(to) {
+			return []time.Time{start.UTC()}
+		}
+		return nil
+	}
+
+	var result []time.Time
+	day := Date(start)
+	if first := Date(from.In(loc)); first.After(day) {
+		day = first
+	}
+	for ; ; day = day.AddDate(0, 0, 1) {
+		if s.Until != nil && day.After(Date(*s.Until)) {
+			break
+		}
+		at := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), start.Second(), 0, loc)
+		if !at.Before(to) {
+			break
+		}
+		if s.Weekdays.Has(day.Weekday()) && !at.Before(start) && !at.Before(from) {
+			result = append(result, at.UTC())
+		}
+	}
+	return result
+}
+
+// OccurrencesBetween returns the start times of the occurrences on the dates from up to, but not
+// including, to. The dates are taken in the schedule's timezone.
+func (s Schedule) OccurrencesBetween(from, to time.Time) []time.Time {
+	loc := s.Location()
+	return s.Occurrences(midnight(from, loc), midnight(to, loc))
+}
+
+// OccursOn reports whether the schedule has an occurrence on the given date in its timezone.
+func (s Schedule) OccursOn(date time.Time) bool {
+	return len(s.OccurrencesBetween(date, date.AddDate(0, 0, 1))) > 0
+}
+
+// DateOf returns the date an occurrence falls on in the schedule's timezone, which is the date its
+// mark is stored under.
+func (s Schedule) DateOf(at time.Time) time.Time {
+	return Date(at.In(s.Location()))
+}
+
+// Date truncates t to midnight UTC of its calendar date.
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// midnight returns the start of the calendar date of t in loc.
+func midnight(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+type Status string
+
+const (
+	StatusPlanned   = Status("planned")
+	StatusCompleted = Status("completed")
+	StatusSkipped   = Status("skipped")
+)
+
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusPlanned, StatusCompleted, StatusSkipped:
+		return true
+	default:
+		return false
+	}
+}
+
+// Mark records that one occurrence of a schedule was completed, optionally by a logged session, or
+// skipped. Unmarked occurrences are planned.
+type Mark struct {
+	Date       time.Time `json:"date"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	SessionID  *int      `json:"session_id,omitempty"`
+	Status     Status    `json:"status"`
+	ScheduleID int       `json:"schedule_id"`
+}
+
+// Entry is one item of the calendar: an occurrence of a schedule, or a finished session that was
+// not planned.
+type Entry struct {
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	ScheduleID  *int      `json:"schedule_id,omitempty"`
+	SessionID   *int      `json:"session_id,omitempty"`
+	WorkoutName string    `json:"workout_name"`
+	Status      Status    `json:"status"`
+	WorkoutID   int       `json:"workout_id"`
+}
+
+// BuildCalendar lays out the occurrences of the schedules on the dates [from, to) with their marks,
+// and adds the finished sessions no mark refers to. Entries are ordered by start time.
+func BuildCalendar(schedules []Schedule, marks []Mark, sessions []Entry, from, to time.Time) []Entry {
+	type key struct {
+		schedule int
+		date     time.Time
+	}
+	marked := make(map[key]Mark, len(marks))
+	linked := make(map[int]bool)
+	for _, m := range marks {
+		marked[key{m.ScheduleID, Date(m.Date)}] = m
+		if m.SessionID != nil {
+			linked[*m.SessionID] = true
+		}
+	}
+
+	entries := []Entry{}
+	for _, s := range schedules {
+		for _, at := range s.OccurrencesBetween(from, to) {
+			scheduleID := s.ID
+			entry := Entry{
+				StartsAt:    at,
+				EndsAt:      at.Add(time.Duration(s.DurationMinutes) * time.Minute),
+				ScheduleID:  &scheduleID,
+				WorkoutID:   s.WorkoutID,
+				WorkoutName: s.WorkoutName,
+				Status:      StatusPlanned,
+			}
+			if m, ok := marked[key{s.ID, s.DateOf(at)}]; ok {
+				entry.Status = m.Status
+				entry.SessionID = m.SessionID
+			}
+			entries = append(entries, entry)
+		}
+	}
+	for _, e := range sessions {
+		if e.SessionID == nil || !linked[*e.SessionID] {
+			entries = append(entries, e)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartsAt.Before(entries[j].StartsAt)
+	})
+	return entries
+}
@@ -0,0 +1,126 @@
+package schedule
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(d int, hour int) time.Time {
+	// March 2026 starts on a Sunday.
+	return time.Date(2026, 3, d, hour, 0, 0, 0, time.UTC)
+}
+
+func TestWeekdays(t *testing.T) {
+	w, err := ParseWeekdays([]string{"fri", "mon", "wed"})
+	require.NoError(t, err)
+	assert.True(t, w.Has(time.Monday))
+	assert.False(t, w.Has(time.Tuesday))
+	assert.Equal(t, []string{"mon", "wed", "fri"}, w.Names())
+
+	data, err := json.Marshal(w)
+	require.NoError(t, err)
+	assert.JSONEq(t, `["mon","wed","fri"]`, string(data))
+
+	_, err = ParseWeekdays([]string{"monday"})
+	assert.Error(t, err)
+}
+
+func TestOccurrences_Once(t *testing.T) {
+	s := Schedule{StartsAt: day(4, 18)}
+	assert.Equal(t, []time.Time{day(4, 18)}, s.Occurrences(day(1, 0), day(8, 0)))
+	assert.Empty(t, s.Occurrences(day(5, 0), day(8, 0)))
+}
+
+func TestOccurrences_Recurring(t *testing.T) {
+	until := day(13, 0)
+	w, _ := ParseWeekdays([]string{"mon", "wed", "fri"})
+	s := Schedule{StartsAt: day(4, 7), Weekdays: w, Until: &until}
+
+	// Wednesday the 4th up to Friday the 13th; the range starts on the 1st.
+	assert.Equal(t, []time.Time{day(4, 7), day(6, 7), day(9, 7), day(11, 7), day(13, 7)},
+		s.Occurrences(day(1, 0), day(31, 0)))
+	assert.Equal(t, []time.Time{day(9, 7)}, s.Occurrences(day(7, 0), day(10, 0)))
+	assert.True(t, s.OccursOn(day(11, 0)))
+	assert.False(t, s.OccursOn(day(10, 0)))
+	assert.False(t, s.OccursOn(day(2, 0)), "no occurrences before the schedule starts")
+}
+
+func TestOccurrences_KeepTheLocalTimeAcrossDaylightSaving(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	w, _ := ParseWeekdays([]string{"fri", "mon"})
+	// Clocks in Berlin go forward on Sunday the 29th.
+	s := Schedule{StartsAt: time.Date(2026, 3, 27, 7, 0, 0, 0, berlin), Weekdays: w, Timezone: "Europe/Berlin"}
+
+	assert.Equal(t, []time.Time{day(27, 6), day(30, 5)}, s.Occurrences(day(27, 0), day(31, 0)))
+}
+
+func TestOccurrences_WeekdaysAreLocal(t *testing.T) {
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+	w, _ := ParseWeekdays([]string{"mon"})
+	// Monday the 9th at 07:00 in Sydney is Sunday the 8th at 20:00 UTC.
+	s := Schedule{ID: 1, StartsAt: time.Date(2026, 3, 9, 7, 0, 0, 0, sydney), Weekdays: w, Timezone: "Australia/Sydney"}
+
+	assert.Equal(t, []time.Time{day(8, 20), day(15, 20)}, s.OccurrencesBetween(day(9, 0), day(17, 0)))
+	assert.True(t, s.OccursOn(day(16, 0)))
+	assert.False(t, s.OccursOn(day(15, 0)))
+	assert.Equal(t, day(16, 0), s.DateOf(day(15, 20)))
+
+	marks := []Mark{{ScheduleID: 1, Date: day(16, 0), Status: StatusSkipped}}
+	entries := BuildCalendar([]Schedule{s}, marks, nil, day(16, 0), day(17, 0))
+	require.Len(t, entries, 1)
+	assert.Equal(t, StatusSkipped, entries[0].Status)
+}
+
+func TestLocation_FallsBackToUTC(t *testing.T) {
+	assert.Equal(t, time.UTC, Schedule{}.Location())
+	assert.Equal(t, time.UTC, Schedule{Timezone: "Mars/Olympus"}.Location())
+}
+
+func TestBuildCalendar(t *testing.T) {
+	w, _ := ParseWeekdays([]string{"mon", "wed"})
+	schedules := []Schedule{{ID: 1, WorkoutID: 5, WorkoutName: "Legs", StartsAt: day(2, 7), DurationMinutes: 60, Weekdays: w}}
+	linkedSession, otherSession := 40, 41
+	marks := []Mark{
+		{ScheduleID: 1, Date: day(2, 0), Status: StatusCompleted, SessionID: &linkedSession},
+		{ScheduleID: 1, Date: day(4, 0), Status: StatusSkipped},
+	}
+	sessions := []Entry{
+		{StartsAt: day(2, 7), SessionID: &linkedSession, Status: StatusCompleted, WorkoutID: 5},
+		{StartsAt: day(3, 12), SessionID: &otherSession, Status: StatusCompleted, WorkoutID: 6, WorkoutName: "Run"},
+	}
+
+	entries := BuildCalendar(schedules, marks, sessions, day(1, 0), day(10, 0))
+	require.Len(t, entries, 4)
+	assert.Equal(t, StatusCompleted, entries[0].Status)
+	assert.Equal(t, &linkedSession, entries[0].SessionID)
+	assert.Equal(t, day(2, 8), entries[0].EndsAt)
+	assert.Equal(t, "Run", entries[1].WorkoutName)
+	assert.Equal(t, StatusSkipped, entries[2].Status)
+	assert.Equal(t, StatusPlanned, entries[3].Status)
+}
+
+func TestICS(t *testing.T) {
+	scheduleID := 1
+	entries := []Entry{{
+		StartsAt: day(2, 7), EndsAt: day(2, 8), ScheduleID: &scheduleID,
+		WorkoutName: "Legs, glutes; " + strings.Repeat("x", 80), Status: StatusSkipped,
+	}}
+
+	ics := ICS(entries, day(1, 0))
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	assert.Contains(t, ics, "UID:schedule-1-20260302@workout-tracker\r\n")
+	assert.Contains(t, ics, "DTSTART:20260302T070000Z\r\n")
+	assert.Contains(t, ics, "STATUS:CANCELLED\r\n")
+	assert.Contains(t, ics, `SUMMARY:Legs\, glutes\; xx`)
+	for _, line := range strings.Split(ics, "\r\n") {
+		assert.LessOrEqual(t, len(line), icalLineLimit)
+	}
+}
@@ -0,0 +1,62 @@
+package session
+
+import (
+	"time"
+	record "workout-tracker/internal/model/record"
+	"workout-tracker/internal/model/units"
+)
+
+type Session struct {
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+	Notes      string       `json:"notes"`
+	Sets       []SessionSet `json:"sets"`
+	ID         int          `json:"id"`
+	UserID     int          `json:"user_id"`
+	WorkoutID  int          `json:"workout_id"`
+}
+
+type SessionSet struct {
+	CompletedAt time.Time               `json:"completed_at"`
+	RPE         *float64                `json:"rpe,omitempty"`
+	RestSeconds *int                    `json:"rest_seconds,omitempty"`
+	NewRecords  []record.PersonalRecord `json:"new_records,omitempty"`
+	Weight      float64                 `json:"weight"`
+	ID          int                     `json:"id"`
+	SessionID   int                     `json:"session_id"`
+	ExerciseID  int                     `json:"exercise_id"`
+	SetNumber   int                     `json:"set_number"`
+	Reps        int                     `json:"reps"`
+}
+
+func (s *Session) IsFinished() bool {
+	return s.FinishedAt != nil
+}
+
+// InUnits returns a copy of the session with the weights of its sets converted from kilograms to
+// the system's mass unit.
+func (s Session) InUnits(system units.System) Session {
+	if s.Sets == nil {
+		return s
+	}
+	sets := make([]SessionSet, len(s.Sets))
+	for i, set := range s.Sets {
+		sets[i] = set.InUnits(system)
+	}
+	s.Sets = sets
+	return s
+}
+
+func (s SessionSet) InUnits(system units.System) SessionSet {
+	s.Weight = system.FromKilograms(s.Weight)
+	if s.NewRecords != nil {
+		records := make([]record.PersonalRecord, len(s.NewRecords))
+		for i, r := range s.NewRecords {
+			records[i] = r.InUnits(system)
+		}
+		s.NewRecords = records
+	}
+	return s
+}
@@ -1,5 +1,27 @@
 package statistics
 
+import (
+	"time"
+	"workout-tracker/internal/model/units"
+)
+
+type Bucket string
+
+const (
+	BucketDay   = Bucket("day")
+	BucketWeek  = Bucket("week")
+	BucketMonth = Bucket("month")
+)
+
+func (b Bucket) IsValid() bool {
+	switch b {
+	case BucketDay, BucketWeek, BucketMonth:
+		return true
+	default:
+		return false
+	}
+}
+
 type WorkoutStatistics struct {
 	Category    string  `json:"category"`
 	TotalWeight float64 `json:"total_weight"`
@@ -7,3 +29,43 @@ type WorkoutStatistics struct {
 	TotalSets   int     `json:"total_sets"`
 	TotalReps   int     `json:"total_reps"`
 }
+
+// InUnits returns a copy of the statistics with the volume converted from kilograms to the system's
+// mass unit.
+func (s WorkoutStatistics) InUnits(system units.System) WorkoutStatistics {
+	s.TotalWeight = system.FromKilograms(s.TotalWeight)
+	return s
+}
+
+// ExerciseStatistics aggregates the sets of one exercise. RelativeStrength is MaxWeight divided by
+// the user's bodyweight and is missing when they never recorded one.
+type ExerciseStatistics struct {
+	RelativeStrength *float64 `json:"relative_strength,omitempty"`
+	ExerciseName     string   `json:"exercise_name"`
+	TotalWeight      float64  `json:"total_weight"`
+	MaxWeight        float64  `json:"max_weight"`
+	ExerciseID       int      `json:"exercise_id"`
+	TotalSets        int      `json:"total_sets"`
+	TotalReps        int      `json:"total_reps"`
+}
+
+// InUnits returns a copy of the statistics with the volume and max weight converted from kilograms to
+// the system's mass unit. RelativeStrength is a ratio of two weights and stays as it is.
+func (s ExerciseStatistics) InUnits(system units.System) ExerciseStatistics {
+	s.TotalWeight = system.FromKilograms(s.TotalWeight)
+	s.MaxWeight = system.FromKilograms(s.MaxWeight)
+	return s
+}
+
+type PeriodStatistics struct {
+	PeriodStart time.Time `json:"period_start"`
+	TotalWeight float64   `json:"total_weight"`
+	Sessions    int       `json:"sessions"`
+	TotalSets   int       `json:"total_sets"`
+	TotalReps   int       `json:"total_reps"`
+}
+
+func (s PeriodStatistics) InUnits(system units.System) PeriodStatistics {
+	s.TotalWeight = system.FromKilograms(s.TotalWeight)
+	return s
+}
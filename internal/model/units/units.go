@@ -0,0 +1,124 @@
+package units
+
+import "math"
+
+// System is the unit system a user enters and reads values in. Values are always stored in metric
+// units: kilograms, centimetres and kilometres.
+type System string
+
+const (
+	Metric   = System("metric")
+	Imperial = System("imperial")
+
+	DefaultSystem = Metric
+)
+
+const (
+	KilogramsPerPound   = 0.45359237
+	CentimetresPerInch  = 2.54
+	KilometresPerMile   = 1.609344
+	metricPlateStep     = 2.5
+	imperialPlateStep   = 5.0
+	displayDecimalScale = 100
+)
+
+func (s System) IsValid() bool {
+	switch s {
+	case Metric, Imperial:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s System) MassUnit() string {
+	if s == Imperial {
+		return "lb"
+	}
+	return "kg"
+}
+
+func (s System) LengthUnit() string {
+	if s == Imperial {
+		return "in"
+	}
+	return "cm"
+}
+
+func (s System) DistanceUnit() string {
+	if s == Imperial {
+		return "mi"
+	}
+	return "km"
+}
+
+// ToKilograms converts a weight given in the system's mass unit, rounded to the two decimals weights
+// are stored with. Comparing against stored weights, as record detection does, relies on that.
+func (s System) ToKilograms(weight float64) float64 {
+	if s == Imperial {
+		weight *= KilogramsPerPound
+	}
+	return round(weight)
+}
+
+// FromKilograms converts a stored weight to the system's mass unit, rounded for display.
+func (s System) FromKilograms(kg float64) float64 {
+	if s == Imperial {
+		kg /= KilogramsPerPound
+	}
+	return round(kg)
+}
+
+func (s System) ToKilometres(distance float64) float64 {
+	if s == Imperial {
+		return distance * KilometresPerMile
+	}
+	return distance
+}
+
+func (s System) FromKilometres(km float64) float64 {
+	if s == Imperial {
+		km /= KilometresPerMile
+	}
+	return round(km)
+}
+
+// ToCentimetres converts a length given in the system's length unit.
+func (s System) ToCentimetres(length float64) float64 {
+	if s == Imperial {
+		return length * CentimetresPerInch
+	}
+	return length
+}
+
+// FromCentimetres converts a stored length to the system's length unit, rounded for display.
+func (s System) FromCentimetres(cm float64) float64 {
+	if s == Imperial {
+		cm /= CentimetresPerInch
+	}
+	return round(cm)
+}
+
+// PlateStep is the smallest weight step a barbell can be loaded in, in the system's mass unit: a
+// pair of 1.25 kg or 2.5 lb plates.
+func (s System) PlateStep() float64 {
+	if s == Imperial {
+		return imperialPlateStep
+	}
+	return metricPlateStep
+}
+
+// RoundToPlates converts a weight in kilograms to the nearest weight that can be loaded with the
+// system's plates, in the system's mass unit.
+func (s System) RoundToPlates(kg float64) float64 {
+	step := s.PlateStep()
+	weight := kg
+	if s == Imperial {
+		weight /= KilogramsPerPound
+	}
+	return math.Round(weight/step) * step
+}
+
+func round(v float64) float64 {
+	return math.Round(v*displayDecimalScale) / displayDecimalScale
+}
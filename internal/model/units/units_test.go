@@ -0,0 +1,39 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystem_RoundTrip(t *testing.T) {
+	assert.Equal(t, 225.0, Imperial.FromKilograms(Imperial.ToKilograms(225)))
+	assert.Equal(t, 100.0, Metric.FromKilograms(Metric.ToKilograms(100)))
+	assert.Equal(t, 3.1, Imperial.FromKilometres(Imperial.ToKilometres(3.1)))
+	assert.Equal(t, 70.0, Imperial.FromCentimetres(Imperial.ToCentimetres(70)))
+	assert.Equal(t, 177.8, Imperial.ToCentimetres(70))
+}
+
+func TestSystem_ToKilogramsRoundsToStoragePrecision(t *testing.T) {
+	assert.Equal(t, 102.06, Imperial.ToKilograms(225))
+	assert.Equal(t, 100.13, Metric.ToKilograms(100.125))
+}
+
+func TestSystem_FromKilogramsRoundsForDisplay(t *testing.T) {
+	assert.Equal(t, 102.06, Metric.FromKilograms(102.0583))
+	assert.Equal(t, 225.0, Imperial.FromKilograms(102.06))
+}
+
+func TestSystem_RoundToPlates(t *testing.T) {
+	assert.Equal(t, 102.5, Metric.RoundToPlates(101.3))
+	assert.Equal(t, 100.0, Metric.RoundToPlates(101.2))
+	assert.Equal(t, 225.0, Imperial.RoundToPlates(102.5))
+	assert.Equal(t, 230.0, Imperial.RoundToPlates(103.5))
+}
+
+func TestSystem_Units(t *testing.T) {
+	assert.Equal(t, "lb", Imperial.MassUnit())
+	assert.Equal(t, "cm", Metric.LengthUnit())
+	assert.Equal(t, "mi", Imperial.DistanceUnit())
+	assert.False(t, System("nautical").IsValid())
+}
@@ -2,12 +2,54 @@ package jwt
 
 import (
 	"time"
+	"workout-tracker/internal/model/user"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
 )
 
-type RefreshToken struct {
-	ExpiresAt time.Time `json:"expires_at"`
-	CreatedAt time.Time `json:"created_at"`
-	Token     string    `json:"token"`
-	ID        string    `json:"id"`
+// AccessClaims are the claims of an access token. Version must match the user's token version and
+// SessionID names the refresh token family the token was issued with.
+type AccessClaims struct {
+	jwtlib.RegisteredClaims
+	Role      user.Role `json:"role"`
+	SessionID string    `json:"sid,omitempty"`
 	UserID    int       `json:"user_id"`
+	Version   int       `json:"version"`
+}
+
+// Device describes where a refresh token was issued to.
+type Device struct {
+	Name      string `json:"device_name"`
+	UserAgent string `json:"user_agent"`
+	IP        string `json:"ip"`
+}
+
+// RefreshToken is a stored refresh token. Only the hash of the token is kept. Rotating a token marks
+// it used and issues its successor in the same family.
+type RefreshToken struct {
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	Device
+	TokenHash string `json:"-"`
+	ID        string `json:"id"`
+	FamilyID  string `json:"family_id"`
+	UserID    int    `json:"user_id"`
+}
+
+// Expired reports whether the token can no longer be used at now.
+func (t RefreshToken) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// Session is a signed-in device: a family of refresh tokens that is still active. Its ID is the
+// family's. The device is the one of the latest refresh.
+type Session struct {
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Device
+	ID      string `json:"id"`
+	Current bool   `json:"current"`
 }
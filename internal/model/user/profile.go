@@ -0,0 +1,34 @@
+package user
+
+import (
+	"time"
+	"workout-tracker/internal/model/units"
+)
+
+type Sex string
+
+const (
+	Female = Sex("female")
+	Male   = Sex("male")
+	Other  = Sex("other")
+)
+
+// DefaultTimezone is the timezone of users who never chose one.
+const DefaultTimezone = "UTC"
+
+// Profile is what a user tells about themselves. Empty strings and nil values are unset; the height
+// is stored in centimetres whatever the user's unit system.
+type Profile struct {
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	BirthDate   *time.Time
+	HeightCm    *float64
+	Username    string
+	DisplayName string
+	Email       string
+	Sex         Sex
+	Timezone    string
+	AvatarURL   string
+	UnitSystem  units.System
+	ID          int
+}
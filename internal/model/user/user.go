@@ -1,6 +1,9 @@
 package user
 
-import "time"
+import (
+	"time"
+	"workout-tracker/internal/model/units"
+)
 
 type Role string
 
@@ -8,11 +11,12 @@ const AdminRole = Role("admin")
 const UserRole = Role("user")
 
 type User struct {
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	Username     string    `json:"username"`
-	Password     string    `json:"password"`
-	Role         Role      `json:"role"`
-	ID           int       `json:"id"`
-	TokenVersion int       `json:"token_version"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	Username     string       `json:"username"`
+	Password     string       `json:"password"`
+	Role         Role         `json:"role"`
+	UnitSystem   units.System `json:"unit_system"`
+	ID           int          `json:"id"`
+	TokenVersion int          `json:"token_version"`
 }
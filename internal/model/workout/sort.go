@@ -0,0 +1,30 @@
+package workout
+
+type SortField string
+
+const (
+	SortByCreatedAt = SortField("created_at")
+	SortByUpdatedAt = SortField("updated_at")
+	SortByName      = SortField("name")
+	SortByTitle     = SortField("title")
+)
+
+func (f SortField) IsValid() bool {
+	switch f {
+	case SortByCreatedAt, SortByUpdatedAt, SortByName, SortByTitle:
+		return true
+	default:
+		return false
+	}
+}
+
+type SortOrder string
+
+const (
+	OrderAsc  = SortOrder("asc")
+	OrderDesc = SortOrder("desc")
+)
+
+func (o SortOrder) IsValid() bool {
+	return o == OrderAsc || o == OrderDesc
+}
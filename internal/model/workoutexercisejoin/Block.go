@@ -0,0 +1,60 @@
+package workoutexercisejoin
+
+type BlockType string
+
+const (
+	BlockStraight = BlockType("straight")
+	BlockSuperset = BlockType("superset")
+	BlockCircuit  = BlockType("circuit")
+	BlockEMOM     = BlockType("emom")
+	BlockAMRAP    = BlockType("amrap")
+)
+
+func (t BlockType) IsValid() bool {
+	switch t {
+	case BlockStraight, BlockSuperset, BlockCircuit, BlockEMOM, BlockAMRAP:
+		return true
+	default:
+		return false
+	}
+}
+
+// MinExercises is the number of exercises a block of this type needs: supersets and circuits
+// alternate between exercises, so one is not enough.
+func (t BlockType) MinExercises() int {
+	if t == BlockSuperset || t == BlockCircuit {
+		return 2
+	}
+	return 1
+}
+
+// Block groups consecutive exercises of a workout. Rounds is how often the block is repeated
+// (minutes for an EMOM) and RestSeconds the rest after each round. Implicit marks the block a plain
+// list of exercises is wrapped in, so that errors can name the exercises the way they were sent.
+type Block struct {
+	WorkoutID   int               `json:"workout_id"`
+	Position    int               `json:"position"`
+	Type        BlockType         `json:"type"`
+	Rounds      int               `json:"rounds"`
+	RestSeconds int               `json:"rest_seconds"`
+	Exercises   []WorkoutExercise `json:"exercises"`
+	Implicit    bool              `json:"-"`
+}
+
+// Group puts the ordered exercises of a workout into their blocks. Exercises that name a missing
+// block are dropped, which the foreign key on workout_exercise rules out.
+func Group(blocks []Block, exercises []WorkoutExercise) []Block {
+	result := make([]Block, len(blocks))
+	index := make(map[int]int, len(blocks))
+	for i, b := range blocks {
+		b.Exercises = []WorkoutExercise{}
+		result[i] = b
+		index[b.Position] = i
+	}
+	for _, e := range exercises {
+		if i, ok := index[e.BlockPosition]; ok {
+			result[i].Exercises = append(result[i].Exercises, e)
+		}
+	}
+	return result
+}
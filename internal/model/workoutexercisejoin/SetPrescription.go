@@ -0,0 +1,48 @@
+package workoutexercisejoin
+
+type SetType string
+
+const (
+	SetWarmup  = SetType("warmup")
+	SetWorking = SetType("working")
+	SetDrop    = SetType("drop")
+	SetFailure = SetType("failure")
+)
+
+func (t SetType) IsValid() bool {
+	switch t {
+	case SetWarmup, SetWorking, SetDrop, SetFailure:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetPrescription is the target of a single set. Reps alone is an exact target and together with
+// RepsMax a rep range. The load is given either as an absolute Weight or as PercentOneRM, a
+// percentage of the lifter's one-rep max; every target is optional.
+type SetPrescription struct {
+	WorkoutID        int      `json:"-"`
+	ExercisePosition int      `json:"-"`
+	SetNumber        int      `json:"set_number"`
+	Type             SetType  `json:"type"`
+	Reps             *int     `json:"reps,omitempty"`
+	RepsMax          *int     `json:"reps_max,omitempty"`
+	Weight           *float64 `json:"weight,omitempty"`
+	PercentOneRM     *float64 `json:"percent_1rm,omitempty"`
+	RPE              *float64 `json:"rpe,omitempty"`
+	RestSeconds      *int     `json:"rest_seconds,omitempty"`
+}
+
+// AttachSets hands the ordered set prescriptions of a workout to the exercises they belong to.
+func AttachSets(exercises []WorkoutExercise, sets []SetPrescription) {
+	index := make(map[int]int, len(exercises))
+	for i, e := range exercises {
+		index[e.Position] = i
+	}
+	for _, set := range sets {
+		if i, ok := index[set.ExercisePosition]; ok {
+			exercises[i].Prescription = append(exercises[i].Prescription, set)
+		}
+	}
+}
@@ -1,11 +1,38 @@
 package workoutexercisejoin
 
-import "workout-tracker/internal/model/exercise"
+import (
+	"workout-tracker/internal/model/exercise"
+	"workout-tracker/internal/model/units"
+)
 
+// WorkoutExercise is one exercise of a workout. Position orders the exercises across the whole
+// workout and BlockPosition names the block the exercise belongs to. Prescription, when present,
+// describes every set individually; Sets and Reps then summarise it.
 type WorkoutExercise struct {
-	WorkoutID  int                `json:"workout_id"`
-	ExerciseID int                `json:"exercise_id"`
-	Reps       int                `json:"reps,omitempty"`
-	Sets       int                `json:"sets,omitempty"`
-	Exercise   *exercise.Exercise `json:"exercise,omitempty"`
+	WorkoutID     int                `json:"workout_id"`
+	ExerciseID    int                `json:"exercise_id"`
+	Position      int                `json:"position"`
+	BlockPosition int                `json:"block_position"`
+	Reps          int                `json:"reps,omitempty"`
+	Sets          int                `json:"sets,omitempty"`
+	Prescription  []SetPrescription  `json:"prescription,omitempty"`
+	Exercise      *exercise.Exercise `json:"exercise,omitempty"`
+}
+
+// InUnits returns a copy of the exercise with its prescribed weights converted from kilograms to
+// the system's mass unit.
+func (e WorkoutExercise) InUnits(system units.System) WorkoutExercise {
+	if len(e.Prescription) == 0 {
+		return e
+	}
+	sets := make([]SetPrescription, len(e.Prescription))
+	for i, set := range e.Prescription {
+		if set.Weight != nil {
+			weight := system.FromKilograms(*set.Weight)
+			set.Weight = &weight
+		}
+		sets[i] = set
+	}
+	e.Prescription = sets
+	return e
 }
@@ -0,0 +1,161 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	dto "workout-tracker/internal/dto/category"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/category"
+	"workout-tracker/pkg/db"
+	"workout-tracker/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/dig"
+)
+
+type DBPool interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+type CategoryRepositoryParams struct {
+	dig.In
+	DB  *db.DB
+	Log logger.SugaredLoggerInterface
+}
+
+type CategoryRepository struct {
+	Pool DBPool
+	Log  logger.SugaredLoggerInterface
+}
+
+func NewCategoryRepository(params CategoryRepositoryParams) *CategoryRepository {
+	return &CategoryRepository{
+		Pool: params.DB.Pool,
+		Log:  params.Log,
+	}
+}
+
+const categoryColumns = `id, slug, name, icon, color, position, createdat, updatedat`
+
+// CreateCategory expects input.Slug to be filled in already.
+func (r *CategoryRepository) CreateCategory(ctx context.Context, input dto.CategoryRequest) (int, error) {
+	var id int
+	err := r.Pool.QueryRow(ctx,
+		`INSERT INTO categories (slug, name, icon, color, position)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING id`,
+		input.Slug, input.Name, input.Icon, input.Color, input.Position).
+		Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, erorrs.ErrCategoryAlreadyExists
+		}
+		r.Log.Errorw("failed to create category", "error", err)
+		return 0, fmt.Errorf("create category: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateCategory replaces every field of the category. Renaming the slug carries over to the
+// workouts that use it through the foreign key's ON UPDATE CASCADE.
+func (r *CategoryRepository) UpdateCategory(ctx context.Context, id int, input dto.CategoryRequest) error {
+	tag, err := r.Pool.Exec(ctx,
+		`UPDATE categories
+		    SET slug = $1, name = $2, icon = $3, color = $4, position = $5, updatedat = $6
+		  WHERE id = $7`,
+		input.Slug, input.Name, input.Icon, input.Color, input.Position, time.Now(), id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return erorrs.ErrCategoryAlreadyExists
+		}
+		r.Log.Errorw("failed to update category", "id", id, "error", err)
+		return fmt.Errorf("update category: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteCategory removes the category; workouts that used it become uncategorised.
+func (r *CategoryRepository) DeleteCategory(ctx context.Context, id int) error {
+	tag, err := r.Pool.Exec(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		r.Log.Errorw("failed to delete category", "id", id, "error", err)
+		return fmt.Errorf("delete category: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotFound
+	}
+	return nil
+}
+
+// GetAllCategories returns the categories in display order.
+func (r *CategoryRepository) GetAllCategories(ctx context.Context) ([]model.Category, error) {
+	rows, err := r.Pool.Query(ctx, `SELECT `+categoryColumns+` FROM categories ORDER BY position, name, id`)
+	if err != nil {
+		r.Log.Errorw("failed to get categories", "error", err)
+		return nil, fmt.Errorf("get categories: %w", err)
+	}
+	defer rows.Close()
+
+	result := []model.Category{}
+	for rows.Next() {
+		var c model.Category
+		if err := rows.Scan(&c.ID, &c.Slug, &c.Name, &c.Icon, &c.Color, &c.Position, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get categories: %w", err)
+		}
+		result = append(result, c)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get categories: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *CategoryRepository) GetCategoryBySlug(ctx context.Context, slug string) (*model.Category, error) {
+	var c model.Category
+	err := r.Pool.QueryRow(ctx, `SELECT `+categoryColumns+` FROM categories WHERE slug = $1`, slug).
+		Scan(&c.ID, &c.Slug, &c.Name, &c.Icon, &c.Color, &c.Position, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, erorrs.ErrNotFound
+		}
+		r.Log.Errorw("failed to get category", "slug", slug, "error", err)
+		return nil, fmt.Errorf("get category: %w", err)
+	}
+	return &c, nil
+}
+
+// ReorderCategories sets each category's position to its index in ids. Nothing is changed and
+// ErrNotFound is returned when any of the ids does not exist.
+func (r *CategoryRepository) ReorderCategories(ctx context.Context, ids []int) error {
+	tag, err := r.Pool.Exec(ctx,
+		`UPDATE categories c
+		    SET position = o.position - 1, updatedat = $2
+		   FROM unnest($1::int[]) WITH ORDINALITY AS o(id, position)
+		  WHERE c.id = o.id
+		    AND (SELECT COUNT(*) FROM categories WHERE id = ANY ($1)) = cardinality($1::int[])`,
+		ids, time.Now())
+	if err != nil {
+		r.Log.Errorw("failed to reorder categories", "error", err)
+		return fmt.Errorf("reorder categories: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotFound
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,149 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	dto "workout-tracker/internal/dto/category"
+	"workout-tracker/internal/erorrs"
+)
+
+func setupRepo(mp *MockPool) *CategoryRepository {
+	return &CategoryRepository{Pool: mp, Log: zap.NewNop().Sugar()}
+}
+
+func TestCreateCategory_Success(t *testing.T) {
+	mp := &MockPool{}
+	repo := setupRepo(mp)
+	ctx := context.Background()
+
+	mr := &MockRow{}
+	mp.On("QueryRow", ctx, mock.Anything, "leg-day", "Leg day", "legs", "#ff0000", 2).Return(mr)
+	mr.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*(args.Get(0).(*int)) = 7
+	}).Return(nil)
+
+	id, err := repo.CreateCategory(ctx, dto.CategoryRequest{Slug: "leg-day", Name: "Leg day", Icon: "legs", Color: "#ff0000", Position: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 7, id)
+	mp.AssertExpectations(t)
+}
+
+func TestCreateCategory_Duplicate(t *testing.T) {
+	mp := &MockPool{}
+	repo := setupRepo(mp)
+	ctx := context.Background()
+
+	mr := &MockRow{}
+	mp.On("QueryRow", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mr)
+	mr.On("Scan", mock.Anything).Return(&pgconn.PgError{Code: "23505"})
+
+	_, err := repo.CreateCategory(ctx, dto.CategoryRequest{Slug: "cardio", Name: "Cardio"})
+	assert.ErrorIs(t, err, erorrs.ErrCategoryAlreadyExists)
+}
+
+func TestUpdateCategory_NotFound(t *testing.T) {
+	mp := &MockPool{}
+	repo := setupRepo(mp)
+	ctx := context.Background()
+
+	mp.On("Exec", ctx, mock.Anything, "cardio", "Cardio", "", "", 0, mock.AnythingOfType("time.Time"), 9).
+		Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+	err := repo.UpdateCategory(ctx, 9, dto.CategoryRequest{Slug: "cardio", Name: "Cardio"})
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestDeleteCategory(t *testing.T) {
+	mp := &MockPool{}
+	repo := setupRepo(mp)
+	ctx := context.Background()
+
+	mp.On("Exec", ctx, mock.Anything, 3).Return(pgconn.NewCommandTag("DELETE 1"), nil).Once()
+	mp.On("Exec", ctx, mock.Anything, 4).Return(pgconn.NewCommandTag("DELETE 0"), nil).Once()
+
+	assert.NoError(t, repo.DeleteCategory(ctx, 3))
+	assert.ErrorIs(t, repo.DeleteCategory(ctx, 4), erorrs.ErrNotFound)
+}
+
+func TestGetAllCategories(t *testing.T) {
+	mp := &MockPool{}
+	repo := setupRepo(mp)
+	ctx := context.Background()
+
+	rows := &MockRow{}
+	mp.On("Query", ctx, mock.Anything).Return(rows, nil)
+	rows.On("Next").Return(true).Once()
+	rows.On("Next").Return(false).Once()
+	rows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*int) = 1
+		*args.Get(1).(*string) = "strength"
+		*args.Get(2).(*string) = "Strength"
+		*args.Get(5).(*int) = 0
+		*args.Get(6).(*time.Time) = time.Now()
+	}).Return(nil)
+	rows.On("Err").Return(nil)
+	rows.On("Close").Return()
+
+	categories, err := repo.GetAllCategories(ctx)
+	require.NoError(t, err)
+	require.Len(t, categories, 1)
+	assert.Equal(t, "strength", categories[0].Slug)
+}
+
+func TestGetCategoryBySlug_NotFound(t *testing.T) {
+	mp := &MockPool{}
+	repo := setupRepo(mp)
+	ctx := context.Background()
+
+	mr := &MockRow{}
+	mp.On("QueryRow", ctx, mock.Anything, "yoga").Return(mr)
+	mr.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
+
+	_, err := repo.GetCategoryBySlug(ctx, "yoga")
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestReorderCategories(t *testing.T) {
+	mp := &MockPool{}
+	repo := setupRepo(mp)
+	ctx := context.Background()
+
+	mp.On("Exec", ctx, mock.Anything, []int{3, 1, 2}, mock.AnythingOfType("time.Time")).
+		Return(pgconn.NewCommandTag("UPDATE 3"), nil)
+
+	assert.NoError(t, repo.ReorderCategories(ctx, []int{3, 1, 2}))
+}
+
+func TestReorderCategories_UnknownID(t *testing.T) {
+	mp := &MockPool{}
+	repo := setupRepo(mp)
+	ctx := context.Background()
+
+	mp.On("Exec", ctx, mock.Anything, []int{3, 99}, mock.AnythingOfType("time.Time")).
+		Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+	assert.ErrorIs(t, repo.ReorderCategories(ctx, []int{3, 99}), erorrs.ErrNotFound)
+}
+
+func TestReorderCategories_DBError(t *testing.T) {
+	mp := &MockPool{}
+	repo := setupRepo(mp)
+	ctx := context.Background()
+
+	mp.On("Exec", ctx, mock.Anything, mock.Anything, mock.Anything).
+		Return(pgconn.CommandTag{}, errors.New("db down"))
+
+	assert.Error(t, repo.ReorderCategories(ctx, []int{1}))
+}
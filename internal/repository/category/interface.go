@@ -0,0 +1,18 @@
+package category
+
+import (
+	"context"
+	dto "workout-tracker/internal/dto/category"
+	model "workout-tracker/internal/model/category"
+)
+
+type CategoryRepositoryInterface interface {
+	CreateCategory(ctx context.Context, input dto.CategoryRequest) (int, error)
+	UpdateCategory(ctx context.Context, id int, input dto.CategoryRequest) error
+	DeleteCategory(ctx context.Context, id int) error
+	GetAllCategories(ctx context.Context) ([]model.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*model.Category, error)
+	ReorderCategories(ctx context.Context, ids []int) error
+}
+
+var _ CategoryRepositoryInterface = (*CategoryRepository)(nil)
@@ -40,12 +40,21 @@ func NewRepository(params ExerciseRepositoryParams) *ExerciseRepository {
 	}
 }
 
-func (r *ExerciseRepository) CreateExercise(ctx context.Context, input dto.CreateExerciseRequest) (int, error) {
+const exerciseColumns = `id, name, description, primary_muscles, secondary_muscles, equipment,
+       movement_type, measurement_kind, owner_user_id, createdat, updatedat`
+
+// CreateExercise adds an exercise to the global catalogue, or to the owner's private exercises when ownerID is set.
+func (r *ExerciseRepository) CreateExercise(ctx context.Context, ownerID *int, input dto.CreateExerciseRequest) (int, error) {
 	var id int
 	err := r.Pool.
 		QueryRow(ctx,
-			`INSERT INTO exercises(NAME, DESCRIPTION) VALUES ($1, $2) RETURNING id`,
-			input.Name, input.Description).
+			`INSERT INTO exercises (name, description, primary_muscles, secondary_muscles, equipment, movement_type, measurement_kind,
+			                        owner_user_id)
+			 VALUES ($1, $2, COALESCE($3, '{}'::text[]), COALESCE($4, '{}'::text[]), COALESCE($5, '{}'::text[]),
+			         $6, COALESCE(NULLIF($7, ''), 'weight_reps'), $8)
+			 RETURNING id`,
+			input.Name, input.Description, input.PrimaryMuscles, input.SecondaryMuscles, input.Equipment,
+			input.MovementType, input.MeasurementKind, ownerID).
 		Scan(&id)
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -55,76 +64,153 @@ func (r *ExerciseRepository) CreateExercise(ctx context.Context, input dto.Creat
 		}
 		return 0, err
 	}
+
 	return id, nil
 }
 
-func (r *ExerciseRepository) GetAllExercises(ctx context.Context) ([]model.Exercise, error) {
+// GetAllExercises returns one page of the catalogue. With a search term the results are ranked by
+// full-text relevance, otherwise they are ordered by name. When filter.UserID is set that user's
+// private exercises are merged in.
+func (r *ExerciseRepository) GetAllExercises(ctx context.Context, filter dto.ExerciseFilter) ([]model.Exercise, int, error) {
 	rows, err := r.Pool.Query(ctx,
-		`SELECT id, name, description, createdat, updatedat
+		`SELECT `+exerciseColumns+`, COUNT(*) OVER ()
            FROM exercises
-          WHERE deletedat IS NULL`,
+          WHERE deletedat IS NULL
+            AND ($1 = '' OR search_vector @@ websearch_to_tsquery('english', $1))
+            AND ($2 = '' OR $2 = ANY (primary_muscles) OR $2 = ANY (secondary_muscles))
+            AND ($3 = '' OR $3 = ANY (equipment))
+            AND ($4 = '' OR movement_type = $4)
+            AND ($5 = '' OR measurement_kind = $5)
+            AND (owner_user_id IS NULL OR owner_user_id = $8)
+          ORDER BY CASE WHEN $1 = '' THEN 0 ELSE ts_rank(search_vector, websearch_to_tsquery('english', $1)) END DESC,
+                   name, id
+          LIMIT $6 OFFSET $7`,
+		filter.Search, filter.Muscle, filter.Equipment, filter.MovementType, filter.MeasurementKind,
+		filter.Limit, filter.Offset, filter.UserID,
 	)
 	if err != nil {
 		r.Log.Errorw("error getting all exercises", "error", err)
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var result []model.Exercise
+	result := []model.Exercise{}
+	total := 0
 	for rows.Next() {
 		var e model.Exercise
-		if err := rows.Scan(&e.ID, &e.Name, &e.Description, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		if err := rows.Scan(
+			&e.ID, &e.Name, &e.Description, &e.PrimaryMuscles, &e.SecondaryMuscles, &e.Equipment,
+			&e.MovementType, &e.MeasurementKind, &e.OwnerUserID, &e.CreatedAt, &e.UpdatedAt, &total,
+		); err != nil {
 			r.Log.Errorw("error scanning exercise", "error", err)
-			return nil, err
+			return nil, 0, err
 		}
 		result = append(result, e)
 	}
+
 	if err := rows.Err(); err != nil {
 		r.Log.Errorw("row iteration error", "error", err)
-		return nil, err
+		return nil, 0, err
 	}
-	return result, nil
+
+	return result, total, nil
 }
 
-func (r *ExerciseRepository) DeleteExercise(ctx context.Context, id int) error {
-	_, err := r.Pool.Exec(ctx,
-		"UPDATE exercises SET deletedat = $1 WHERE id = $2",
-		time.Now(), id,
+// DeleteExercise soft-deletes an exercise. When ownerID is set only that user's private exercise matches.
+func (r *ExerciseRepository) DeleteExercise(ctx context.Context, id int, ownerID *int) error {
+	tag, err := r.Pool.Exec(ctx,
+		"UPDATE exercises SET deletedat = $1 WHERE id = $2 AND deletedat IS NULL AND ($3::int IS NULL OR owner_user_id = $3)",
+		time.Now(), id, ownerID,
 	)
 	if err != nil {
 		r.Log.Errorw("error deleting exercise", "id", id, "error", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotFound
+	}
+	return nil
+}
+
+// GetExercisesByIDs returns the non-deleted exercises among ids, private ones included.
+func (r *ExerciseRepository) GetExercisesByIDs(ctx context.Context, ids []int) ([]model.Exercise, error) {
+	rows, err := r.Pool.Query(ctx,
+		`SELECT `+exerciseColumns+`
+           FROM exercises
+          WHERE id = ANY ($1) AND deletedat IS NULL`,
+		ids,
+	)
+	if err != nil {
+		r.Log.Errorw("error getting exercises by ids", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []model.Exercise{}
+	for rows.Next() {
+		var e model.Exercise
+		if err := rows.Scan(
+			&e.ID, &e.Name, &e.Description, &e.PrimaryMuscles, &e.SecondaryMuscles, &e.Equipment,
+			&e.MovementType, &e.MeasurementKind, &e.OwnerUserID, &e.CreatedAt, &e.UpdatedAt,
+		); err != nil {
+			r.Log.Errorw("error scanning exercise", "error", err)
+			return nil, err
+		}
+		result = append(result, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, err
 	}
-	return err
+
+	return result, nil
 }
 
 func (r *ExerciseRepository) GetExerciseByID(ctx context.Context, id int) (*model.Exercise, error) {
 	var e model.Exercise
 	err := r.Pool.
 		QueryRow(ctx,
-			`SELECT id, name, description, createdat, updatedat
+			`SELECT `+exerciseColumns+`
                FROM exercises
               WHERE id = $1 AND deletedat IS NULL`,
 			id).
-		Scan(&e.ID, &e.Name, &e.Description, &e.CreatedAt, &e.UpdatedAt)
+		Scan(&e.ID, &e.Name, &e.Description, &e.PrimaryMuscles, &e.SecondaryMuscles, &e.Equipment,
+			&e.MovementType, &e.MeasurementKind, &e.OwnerUserID, &e.CreatedAt, &e.UpdatedAt)
 	if err != nil {
 		r.Log.Errorw("error getting exercise", "id", id, "error", err)
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, err
-		}
 		return nil, err
 	}
+
 	return &e, nil
 }
 
-func (r *ExerciseRepository) UpdateExercise(ctx context.Context, id int, input dto.CreateExerciseRequest) error {
-	_, err := r.Pool.Exec(ctx,
+// UpdateExercise replaces the name and description. Metadata lists that are nil and an empty
+// movement type or measurement kind keep their stored values. When ownerID is set only that
+// user's private exercise matches.
+func (r *ExerciseRepository) UpdateExercise(ctx context.Context, id int, ownerID *int, input dto.CreateExerciseRequest) error {
+	tag, err := r.Pool.Exec(ctx,
 		`UPDATE exercises
-            SET name = $1, description = $2, updatedat = $3
-          WHERE id = $4`,
-		input.Name, input.Description, time.Now(), id,
+            SET name = $1, description = $2, updatedat = $3,
+                primary_muscles = COALESCE($4, primary_muscles),
+                secondary_muscles = COALESCE($5, secondary_muscles),
+                equipment = COALESCE($6, equipment),
+                movement_type = COALESCE(NULLIF($7, ''), movement_type),
+                measurement_kind = COALESCE(NULLIF($8, ''), measurement_kind)
+          WHERE id = $9 AND deletedat IS NULL AND ($10::int IS NULL OR owner_user_id = $10)`,
+		input.Name, input.Description, time.Now(), input.PrimaryMuscles, input.SecondaryMuscles, input.Equipment,
+		input.MovementType, input.MeasurementKind, id, ownerID,
 	)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return erorrs.ErrExerciseAlreadyExists
+		}
 		r.Log.Errorw("error updating exercise", "id", id, "error", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotFound
 	}
-	return err
+	return nil
 }
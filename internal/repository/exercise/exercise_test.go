@@ -3,6 +3,7 @@ package exercise
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 
 	model "workout-tracker/internal/dto/exercise"
 	"workout-tracker/internal/erorrs"
+	exmodel "workout-tracker/internal/model/exercise"
 )
 
 // Re-import mocks
@@ -29,16 +31,16 @@ func TestCreateExercise_Success(t *testing.T) {
 	repo := setupRepo(mp)
 
 	ctx := context.Background()
-	sql := `INSERT INTO exercises(NAME, DESCRIPTION) VALUES ($1, $2) RETURNING id`
 	// stub QueryRow -> MockRow
 	mr := &MockRow{}
-	mp.On("QueryRow", ctx, sql, "Name", "Desc").Return(mr)
+	mp.On("QueryRow", ctx, mock.Anything, "Name", "Desc", []string(nil), []string(nil), []string(nil),
+		exmodel.MovementType(""), exmodel.MeasurementKind(""), (*int)(nil)).Return(mr)
 	// Scan sets id
 	mr.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
 		*(args.Get(0).(*int)) = 42
 	}).Return(nil)
 
-	id, err := repo.CreateExercise(ctx, model.CreateExerciseRequest{Name: "Name", Description: "Desc"})
+	id, err := repo.CreateExercise(ctx, nil, model.CreateExerciseRequest{Name: "Name", Description: "Desc"})
 	assert.NoError(t, err)
 	assert.Equal(t, 42, id)
 
@@ -51,12 +53,11 @@ func TestCreateExercise_Duplicate(t *testing.T) {
 	repo := setupRepo(mp)
 
 	ctx := context.Background()
-	sql := `INSERT INTO exercises(NAME, DESCRIPTION) VALUES ($1, $2) RETURNING id`
 	mr := &MockRow{}
-	mp.On("QueryRow", ctx, sql, "N", "D").Return(mr)
+	mp.On("QueryRow", ctx, mock.Anything, "N", "D", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mr)
 	mr.On("Scan", mock.Anything).Return(&pgconn.PgError{Code: "23505"})
 
-	id, err := repo.CreateExercise(ctx, model.CreateExerciseRequest{Name: "N", Description: "D"})
+	id, err := repo.CreateExercise(ctx, nil, model.CreateExerciseRequest{Name: "N", Description: "D"})
 	assert.ErrorIs(t, err, erorrs.ErrExerciseAlreadyExists)
 	assert.Zero(t, id)
 }
@@ -66,29 +67,31 @@ func TestGetAllExercises(t *testing.T) {
 	repo := setupRepo(mp)
 
 	ctx := context.Background()
-	sql := `SELECT id, name, description, createdat, updatedat
-           FROM exercises
-          WHERE deletedat IS NULL`
 	mr := &MockRow{}
-	mp.On("Query", ctx, sql).Return(mr, nil)
+	mp.On("Query", ctx, mock.Anything, "", "", "", exmodel.MovementType(""), exmodel.MeasurementKind(""), 20, 0, 0).Return(mr, nil)
 	// Next once true then false
 	mr.On("Next").Return(true).Once()
-	mr.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+	mr.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Run(func(args mock.Arguments) {
 			*args.Get(0).(*int) = 1
 			*args.Get(1).(*string) = "nm"
 			*args.Get(2).(*string) = "ds"
-			*args.Get(3).(*time.Time) = time.Now()
-			*args.Get(4).(*time.Time) = time.Now()
+			*args.Get(3).(*[]string) = []string{"quads"}
+			*args.Get(6).(*exmodel.MovementType) = exmodel.Compound
+			*args.Get(11).(*int) = 31
 		}).Return(nil).Once()
 	mr.On("Next").Return(false).Once()
 	mr.On("Err").Return(nil)
 	mr.On("Close").Return()
 
-	xs, err := repo.GetAllExercises(ctx)
+	xs, total, err := repo.GetAllExercises(ctx, model.ExerciseFilter{Limit: 20})
 	assert.NoError(t, err)
 	assert.Len(t, xs, 1)
 	assert.Equal(t, 1, xs[0].ID)
+	assert.Equal(t, []string{"quads"}, xs[0].PrimaryMuscles)
+	assert.Equal(t, exmodel.Compound, xs[0].MovementType)
+	assert.Equal(t, 31, total)
 }
 
 func TestDeleteExercise(t *testing.T) {
@@ -96,22 +99,32 @@ func TestDeleteExercise(t *testing.T) {
 	repo := setupRepo(mp)
 
 	ctx := context.Background()
-	sql := "UPDATE exercises SET deletedat = $1 WHERE id = $2"
-	mp.On("Exec", ctx, sql, mock.Anything, 5).Return(pgconn.NewCommandTag(""), nil)
+	mp.On("Exec", ctx, mock.Anything, mock.Anything, 5, (*int)(nil)).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
 
-	err := repo.DeleteExercise(ctx, 5)
+	err := repo.DeleteExercise(ctx, 5, nil)
 	assert.NoError(t, err)
 }
 
+func TestDeleteExercise_OtherOwner(t *testing.T) {
+	mp := &MockPool{}
+	repo := setupRepo(mp)
+
+	ctx := context.Background()
+	owner := 3
+	mp.On("Exec", ctx, mock.Anything, mock.Anything, 5, &owner).Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+	err := repo.DeleteExercise(ctx, 5, &owner)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
 func TestDeleteExercise_Error(t *testing.T) {
 	mp := &MockPool{}
 	repo := setupRepo(mp)
 
 	ctx := context.Background()
-	sql := "UPDATE exercises SET deletedat = $1 WHERE id = $2"
-	mp.On("Exec", ctx, sql, mock.Anything, 5).Return(pgconn.NewCommandTag(""), errors.New("fail"))
+	mp.On("Exec", ctx, mock.Anything, mock.Anything, 5, (*int)(nil)).Return(pgconn.NewCommandTag(""), errors.New("fail"))
 
-	err := repo.DeleteExercise(ctx, 5)
+	err := repo.DeleteExercise(ctx, 5, nil)
 	assert.Error(t, err)
 }
 
@@ -120,18 +133,16 @@ func TestGetExerciseByID(t *testing.T) {
 	repo := setupRepo(mp)
 
 	ctx := context.Background()
-	sql := `SELECT id, name, description, createdat, updatedat
-               FROM exercises
-              WHERE id = $1 AND deletedat IS NULL`
 	mr := &MockRow{}
-	mp.On("QueryRow", ctx, sql, 7).Return(mr)
-	mr.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+	mp.On("QueryRow", ctx, mock.Anything, 7).Return(mr)
+	mr.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Run(func(args mock.Arguments) {
 			*args.Get(0).(*int) = 7
 			*args.Get(1).(*string) = "e"
 			*args.Get(2).(*string) = "d"
-			*args.Get(3).(*time.Time) = time.Now()
-			*args.Get(4).(*time.Time) = time.Now()
+			*args.Get(9).(*time.Time) = time.Now()
+			*args.Get(10).(*time.Time) = time.Now()
 		}).Return(nil)
 
 	e, err := repo.GetExerciseByID(ctx, 7)
@@ -144,13 +155,11 @@ func TestGetExerciseByID_NotFound(t *testing.T) {
 	repo := setupRepo(mp)
 
 	ctx := context.Background()
-	sql := `SELECT id, name, description, createdat, updatedat
-               FROM exercises
-              WHERE id = $1 AND deletedat IS NULL`
 	mr := &MockRow{}
-	mp.On("QueryRow", ctx, sql, 8).Return(mr)
+	mp.On("QueryRow", ctx, mock.Anything, 8).Return(mr)
 	// stub Scan to simulate not found
-	mr.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+	mr.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(pgx.ErrNoRows)
 
 	e, err := repo.GetExerciseByID(ctx, 8)
@@ -163,12 +172,10 @@ func TestUpdateExercise(t *testing.T) {
 	repo := setupRepo(mp)
 
 	ctx := context.Background()
-	sql := `UPDATE exercises
-            SET name = $1, description = $2, updatedat = $3
-          WHERE id = $4`
-	mp.On("Exec", ctx, sql, "n", "d", mock.Anything, 9).Return(pgconn.NewCommandTag(""), nil)
+	mp.On("Exec", ctx, mock.Anything, "n", "d", mock.Anything, []string(nil), []string(nil), []string(nil),
+		exmodel.MovementType(""), exmodel.MeasurementKind(""), 9, (*int)(nil)).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
 
-	err := repo.UpdateExercise(ctx, 9, model.CreateExerciseRequest{Name: "n", Description: "d"})
+	err := repo.UpdateExercise(ctx, 9, nil, model.CreateExerciseRequest{Name: "n", Description: "d"})
 	assert.NoError(t, err)
 }
 
@@ -177,12 +184,10 @@ func TestUpdateExercise_Error(t *testing.T) {
 	repo := setupRepo(mp)
 
 	ctx := context.Background()
-	sql := `UPDATE exercises
-            SET name = $1, description = $2, updatedat = $3
-          WHERE id = $4`
-	mp.On("Exec", ctx, sql, "n", "d", mock.Anything, 9).Return(pgconn.NewCommandTag(""), errors.New("err"))
+	mp.On("Exec", ctx, mock.Anything, "n", "d", mock.Anything, []string(nil), []string(nil), []string(nil),
+		exmodel.MovementType(""), exmodel.MeasurementKind(""), 9, (*int)(nil)).Return(pgconn.NewCommandTag(""), errors.New("err"))
 
-	err := repo.UpdateExercise(ctx, 9, model.CreateExerciseRequest{Name: "n", Description: "d"})
+	err := repo.UpdateExercise(ctx, 9, nil, model.CreateExerciseRequest{Name: "n", Description: "d"})
 	assert.Error(t, err)
 }
 
@@ -191,12 +196,11 @@ func TestCreateExercise_OtherError(t *testing.T) {
 	repo := setupRepo(mp)
 
 	ctx := context.Background()
-	sql := `INSERT INTO exercises(NAME, DESCRIPTION) VALUES ($1, $2) RETURNING id`
 	mr := &MockRow{}
-	mp.On("QueryRow", ctx, sql, "X", "Y").Return(mr)
+	mp.On("QueryRow", ctx, mock.Anything, "X", "Y", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mr)
 	mr.On("Scan", mock.Anything).Return(errors.New("oops"))
 
-	id, err := repo.CreateExercise(ctx, model.CreateExerciseRequest{Name: "X", Description: "Y"})
+	id, err := repo.CreateExercise(ctx, nil, model.CreateExerciseRequest{Name: "X", Description: "Y"})
 	assert.EqualError(t, err, "oops")
 	assert.Zero(t, id)
 }
@@ -206,13 +210,10 @@ func TestGetAllExercises_QueryError(t *testing.T) {
 	repo := setupRepo(mp)
 
 	ctx := context.Background()
-	sql := `SELECT id, name, description, createdat, updatedat
-           FROM exercises
-          WHERE deletedat IS NULL`
 	var rows pgx.Rows = (*MockRow)(nil) // typed nil to avoid panic
-	mp.On("Query", ctx, sql).Return(rows, errors.New("qerr"))
+	mp.On("Query", ctx, mock.Anything, "", "", "", exmodel.MovementType(""), exmodel.MeasurementKind(""), 20, 0, 0).Return(rows, errors.New("qerr"))
 
-	xs, err := repo.GetAllExercises(ctx)
+	xs, _, err := repo.GetAllExercises(ctx, model.ExerciseFilter{Limit: 20})
 	assert.Nil(t, xs)
 	assert.EqualError(t, err, "qerr")
 }
@@ -222,17 +223,15 @@ func TestGetAllExercises_ScanError(t *testing.T) {
 	repo := setupRepo(mp)
 
 	ctx := context.Background()
-	sql := `SELECT id, name, description, createdat, updatedat
-           FROM exercises
-          WHERE deletedat IS NULL`
 	mr := &MockRow{}
-	mp.On("Query", ctx, sql).Return(mr, nil)
+	mp.On("Query", ctx, mock.Anything, "", "", "", exmodel.MovementType(""), exmodel.MeasurementKind(""), 20, 0, 0).Return(mr, nil)
 	mr.On("Next").Return(true)
-	mr.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+	mr.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(errors.New("scanfail"))
 	mr.On("Close").Return()
 
-	xs, err := repo.GetAllExercises(ctx)
+	xs, _, err := repo.GetAllExercises(ctx, model.ExerciseFilter{Limit: 20})
 	assert.Nil(t, xs)
 	assert.EqualError(t, err, "scanfail")
 }
@@ -242,16 +241,13 @@ func TestGetAllExercises_RowsErr(t *testing.T) {
 	repo := setupRepo(mp)
 
 	ctx := context.Background()
-	sql := `SELECT id, name, description, createdat, updatedat
-           FROM exercises
-          WHERE deletedat IS NULL`
 	mr := &MockRow{}
-	mp.On("Query", ctx, sql).Return(mr, nil)
+	mp.On("Query", ctx, mock.Anything, "", "", "", exmodel.MovementType(""), exmodel.MeasurementKind(""), 20, 0, 0).Return(mr, nil)
 	mr.On("Next").Return(false)
 	mr.On("Err").Return(errors.New("itererr"))
 	mr.On("Close").Return()
 
-	xs, err := repo.GetAllExercises(ctx)
+	xs, _, err := repo.GetAllExercises(ctx, model.ExerciseFilter{Limit: 20})
 	assert.Nil(t, xs)
 	assert.EqualError(t, err, "itererr")
 }
@@ -261,15 +257,81 @@ func TestGetExerciseByID_ScanError(t *testing.T) {
 	repo := setupRepo(mp)
 
 	ctx := context.Background()
-	sql := `SELECT id, name, description, createdat, updatedat
-               FROM exercises
-              WHERE id = $1 AND deletedat IS NULL`
 	mr := &MockRow{}
-	mp.On("QueryRow", ctx, sql, 10).Return(mr)
-	mr.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+	mp.On("QueryRow", ctx, mock.Anything, 10).Return(mr)
+	mr.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(errors.New("scanidfail"))
 
 	e, err := repo.GetExerciseByID(ctx, 10)
 	assert.Nil(t, e)
 	assert.EqualError(t, err, "scanidfail")
 }
+
+func TestGetAllExercises_PassesFilter(t *testing.T) {
+	mp := &MockPool{}
+	repo := setupRepo(mp)
+
+	ctx := context.Background()
+	filter := model.ExerciseFilter{
+		Search:          "bench press",
+		Muscle:          "chest",
+		Equipment:       "barbell",
+		MovementType:    exmodel.Compound,
+		MeasurementKind: exmodel.WeightReps,
+		Limit:           10,
+		Offset:          30,
+		UserID:          4,
+	}
+	mr := &MockRow{}
+	mp.On("Query", ctx, mock.MatchedBy(func(sql string) bool {
+		return strings.Contains(sql, "websearch_to_tsquery('english', $1)")
+	}), "bench press", "chest", "barbell", exmodel.Compound, exmodel.WeightReps, 10, 30, 4).Return(mr, nil)
+	mr.On("Next").Return(false)
+	mr.On("Err").Return(nil)
+	mr.On("Close").Return()
+
+	xs, total, err := repo.GetAllExercises(ctx, filter)
+	assert.NoError(t, err)
+	assert.Empty(t, xs)
+	assert.Zero(t, total)
+	mp.AssertExpectations(t)
+}
+
+func TestUpdateExercise_DuplicateName(t *testing.T) {
+	mp := &MockPool{}
+	repo := setupRepo(mp)
+
+	ctx := context.Background()
+	owner := 2
+	mp.On("Exec", ctx, mock.Anything, "n", "d", mock.Anything, []string(nil), []string(nil), []string(nil),
+		exmodel.MovementType(""), exmodel.MeasurementKind(""), 9, &owner).Return(pgconn.NewCommandTag(""), &pgconn.PgError{Code: "23505"})
+
+	err := repo.UpdateExercise(ctx, 9, &owner, model.CreateExerciseRequest{Name: "n", Description: "d"})
+	assert.ErrorIs(t, err, erorrs.ErrExerciseAlreadyExists)
+}
+
+func TestGetExercisesByIDs(t *testing.T) {
+	mp := &MockPool{}
+	repo := setupRepo(mp)
+
+	ctx := context.Background()
+	owner := 6
+	mr := &MockRow{}
+	mp.On("Query", ctx, mock.Anything, []int{1, 2}).Return(mr, nil)
+	mr.On("Next").Return(true).Once()
+	mr.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 2
+			*args.Get(8).(**int) = &owner
+		}).Return(nil).Once()
+	mr.On("Next").Return(false).Once()
+	mr.On("Err").Return(nil)
+	mr.On("Close").Return()
+
+	xs, err := repo.GetExercisesByIDs(ctx, []int{1, 2})
+	assert.NoError(t, err)
+	assert.Len(t, xs, 1)
+	assert.Equal(t, &owner, xs[0].OwnerUserID)
+}
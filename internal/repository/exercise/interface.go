@@ -7,11 +7,12 @@ import (
 )
 
 type ExerciseRepositoryInterface interface {
-	CreateExercise(ctx context.Context, input dto.CreateExerciseRequest) (int, error)
-	GetAllExercises(ctx context.Context) ([]model.Exercise, error)
-	DeleteExercise(ctx context.Context, id int) error
+	CreateExercise(ctx context.Context, ownerID *int, input dto.CreateExerciseRequest) (int, error)
+	GetAllExercises(ctx context.Context, filter dto.ExerciseFilter) ([]model.Exercise, int, error)
+	DeleteExercise(ctx context.Context, id int, ownerID *int) error
 	GetExerciseByID(ctx context.Context, id int) (*model.Exercise, error)
-	UpdateExercise(ctx context.Context, id int, input dto.CreateExerciseRequest) error
+	UpdateExercise(ctx context.Context, id int, ownerID *int, input dto.CreateExerciseRequest) error
+	GetExercisesByIDs(ctx context.Context, ids []int) ([]model.Exercise, error)
 }
 
 var _ ExerciseRepositoryInterface = (*ExerciseRepository)(nil)
@@ -0,0 +1,20 @@
+package measurement
+
+import (
+	"context"
+	"time"
+	model "workout-tracker/internal/model/measurement"
+)
+
+type MeasurementRepositoryInterface interface {
+	GetMetrics(ctx context.Context, userID int) ([]model.Metric, error)
+	GetMetricBySlug(ctx context.Context, userID int, slug string) (*model.Metric, error)
+	CreateMetric(ctx context.Context, m model.Metric) (int, error)
+	DeleteMetric(ctx context.Context, userID int, slug string) error
+	CreateMeasurement(ctx context.Context, m model.Measurement) (int, error)
+	GetMeasurements(ctx context.Context, userID, metricID int, from, to *time.Time) ([]model.Measurement, error)
+	DeleteMeasurement(ctx context.Context, userID, metricID, id int) error
+	GetLatestValue(ctx context.Context, userID int, slug string, at time.Time) (*float64, error)
+}
+
+var _ MeasurementRepositoryInterface = (*MeasurementRepository)(nil)
@@ -0,0 +1,219 @@
+package measurement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/measurement"
+	"workout-tracker/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/dig"
+)
+
+type DBPool interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+type MeasurementRepositoryParams struct {
+	dig.In
+
+	Log logger.SugaredLoggerInterface
+	DB  DBPool
+}
+
+type MeasurementRepository struct {
+	Log  logger.SugaredLoggerInterface
+	Pool DBPool
+}
+
+func NewMeasurementRepository(params MeasurementRepositoryParams) MeasurementRepositoryInterface {
+	return &MeasurementRepository{
+		Log:  params.Log,
+		Pool: params.DB,
+	}
+}
+
+const metricColumns = `id, user_id, slug, name, kind, createdat`
+
+func scanMetric(row pgx.Row) (*model.Metric, error) {
+	var m model.Metric
+	if err := row.Scan(&m.ID, &m.UserID, &m.Slug, &m.Name, &m.Kind, &m.CreatedAt); err != nil {
+		return nil, err
+	}
+	m.Unit = m.Kind.CanonicalUnit()
+	return &m, nil
+}
+
+// GetMetrics lists the built-in metrics followed by the user's own, each ordered by name.
+func (r *MeasurementRepository) GetMetrics(ctx context.Context, userID int) ([]model.Metric, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT `+metricColumns+`
+		FROM measurement_metrics
+		WHERE user_id IS NULL OR user_id = $1
+		ORDER BY user_id NULLS FIRST, name, id
+	`, userID)
+	if err != nil {
+		r.Log.Errorw("failed to get metrics", "error", err)
+		return nil, fmt.Errorf("get metrics: %w", err)
+	}
+	defer rows.Close()
+
+	result := []model.Metric{}
+	for rows.Next() {
+		m, err := scanMetric(rows)
+		if err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get metrics: %w", err)
+		}
+		result = append(result, *m)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get metrics: %w", err)
+	}
+	return result, nil
+}
+
+// GetMetricBySlug finds a built-in metric or one of the user's own. Built-in metrics win, since
+// users cannot create metrics with their slugs.
+func (r *MeasurementRepository) GetMetricBySlug(ctx context.Context, userID int, slug string) (*model.Metric, error) {
+	m, err := scanMetric(r.Pool.QueryRow(ctx, `
+		SELECT `+metricColumns+`
+		FROM measurement_metrics
+		WHERE slug = $2 AND (user_id IS NULL OR user_id = $1)
+		ORDER BY user_id NULLS FIRST
+		LIMIT 1
+	`, userID, slug))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, erorrs.ErrNotFound
+		}
+		r.Log.Errorw("failed to get metric", "slug", slug, "error", err)
+		return nil, fmt.Errorf("get metric: %w", err)
+	}
+	return m, nil
+}
+
+func (r *MeasurementRepository) CreateMetric(ctx context.Context, m model.Metric) (int, error) {
+	var id int
+	err := r.Pool.QueryRow(ctx, `
+		INSERT INTO measurement_metrics (user_id, slug, name, kind, createdat)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, m.UserID, m.Slug, m.Name, string(m.Kind), m.CreatedAt).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, erorrs.ErrMetricAlreadyExists
+		}
+		r.Log.Errorw("failed to create metric", "slug", m.Slug, "error", err)
+		return 0, fmt.Errorf("create metric: %w", err)
+	}
+	return id, nil
+}
+
+// DeleteMetric removes one of the user's own metrics together with its measurements. Built-in
+// metrics cannot be deleted.
+func (r *MeasurementRepository) DeleteMetric(ctx context.Context, userID int, slug string) error {
+	tag, err := r.Pool.Exec(ctx, `DELETE FROM measurement_metrics WHERE user_id = $1 AND slug = $2`, userID, slug)
+	if err != nil {
+		r.Log.Errorw("failed to delete metric", "slug", slug, "error", err)
+		return fmt.Errorf("delete metric: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MeasurementRepository) CreateMeasurement(ctx context.Context, m model.Measurement) (int, error) {
+	var id int
+	err := r.Pool.QueryRow(ctx, `
+		INSERT INTO measurements (user_id, metric_id, value, note, measured_at, createdat)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, m.UserID, m.MetricID, m.Value, m.Note, m.MeasuredAt, m.CreatedAt).Scan(&id)
+	if err != nil {
+		r.Log.Errorw("failed to create measurement", "metricID", m.MetricID, "error", err)
+		return 0, fmt.Errorf("create measurement: %w", err)
+	}
+	return id, nil
+}
+
+// GetMeasurements lists the user's measurements of the metric ordered by time. Either bound may be
+// nil; from is inclusive and to exclusive.
+func (r *MeasurementRepository) GetMeasurements(ctx context.Context, userID, metricID int, from, to *time.Time) (
+	[]model.Measurement, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT id, user_id, metric_id, value, note, measured_at, createdat
+		FROM measurements
+		WHERE user_id = $1 AND metric_id = $2
+		  AND ($3::timestamptz IS NULL OR measured_at >= $3)
+		  AND ($4::timestamptz IS NULL OR measured_at < $4)
+		ORDER BY measured_at, id
+	`, userID, metricID, from, to)
+	if err != nil {
+		r.Log.Errorw("failed to get measurements", "error", err)
+		return nil, fmt.Errorf("get measurements: %w", err)
+	}
+	defer rows.Close()
+
+	result := []model.Measurement{}
+	for rows.Next() {
+		var m model.Measurement
+		if err := rows.Scan(&m.ID, &m.UserID, &m.MetricID, &m.Value, &m.Note, &m.MeasuredAt, &m.CreatedAt); err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get measurements: %w", err)
+		}
+		result = append(result, m)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get measurements: %w", err)
+	}
+	return result, nil
+}
+
+func (r *MeasurementRepository) DeleteMeasurement(ctx context.Context, userID, metricID, id int) error {
+	tag, err := r.Pool.Exec(ctx, `DELETE FROM measurements WHERE id = $1 AND user_id = $2 AND metric_id = $3`, id, userID, metricID)
+	if err != nil {
+		r.Log.Errorw("failed to delete measurement", "id", id, "error", err)
+		return fmt.Errorf("delete measurement: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotFound
+	}
+	return nil
+}
+
+// GetLatestValue returns the user's last value of the built-in metric measured at or before at, or
+// nil when there is none.
+func (r *MeasurementRepository) GetLatestValue(ctx context.Context, userID int, slug string, at time.Time) (*float64, error) {
+	var value float64
+	err := r.Pool.QueryRow(ctx, `
+		SELECT m.value
+		FROM measurements m
+		JOIN measurement_metrics mm ON mm.id = m.metric_id
+		WHERE m.user_id = $1 AND mm.user_id IS NULL AND mm.slug = $2 AND m.measured_at <= $3
+		ORDER BY m.measured_at DESC, m.id DESC
+		LIMIT 1
+	`, userID, slug, at).Scan(&value)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.Log.Errorw("failed to get latest measurement", "slug", slug, "error", err)
+		return nil, fmt.Errorf("get latest measurement: %w", err)
+	}
+	return &value, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
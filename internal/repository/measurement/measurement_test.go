@@ -0,0 +1,113 @@
+package measurement
+
+import (
+	"testing"
+	"time"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/measurement"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupRepo(mp *MockPool) *MeasurementRepository {
+	return &MeasurementRepository{Pool: mp, Log: zap.NewNop().Sugar()}
+}
+
+func metricScanArgs() []any {
+	args := make([]any, 6)
+	for i := range args {
+		args[i] = mock.Anything
+	}
+	return args
+}
+
+func TestGetMetricBySlug_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, 7, "waist").Return(row)
+	row.On("Scan", metricScanArgs()...).Run(func(args mock.Arguments) {
+		*args.Get(0).(*int) = 3
+		*args.Get(2).(*string) = "waist"
+		*args.Get(4).(*model.Kind) = model.KindLength
+	}).Return(nil)
+
+	m, err := setupRepo(mp).GetMetricBySlug(ctx, 7, "waist")
+	require.NoError(t, err)
+	assert.Equal(t, 3, m.ID)
+	assert.True(t, m.BuiltIn())
+	assert.Equal(t, model.UnitCentimetre, m.Unit)
+}
+
+func TestGetMetricBySlug_NotFound(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, 7, "calves").Return(row)
+	row.On("Scan", metricScanArgs()...).Return(pgx.ErrNoRows)
+
+	_, err := setupRepo(mp).GetMetricBySlug(ctx, 7, "calves")
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestCreateMetric_Duplicate(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	userID := 7
+	now := time.Now()
+	mp.On("QueryRow", ctx, mock.Anything, &userID, "calves", "Calves", "length", now).Return(row)
+	row.On("Scan", mock.Anything).Return(&pgconn.PgError{Code: "23505"})
+
+	_, err := setupRepo(mp).CreateMetric(ctx, model.Metric{UserID: &userID, Slug: "calves", Name: "Calves", Kind: model.KindLength, CreatedAt: now})
+	assert.ErrorIs(t, err, erorrs.ErrMetricAlreadyExists)
+}
+
+func TestDeleteMetric_NotFound(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	mp.On("Exec", ctx, mock.Anything, 7, "bodyweight").Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+	err := setupRepo(mp).DeleteMetric(ctx, 7, "bodyweight")
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestGetMeasurements_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	rows := new(MockRow)
+	from := time.Now()
+	mp.On("Query", ctx, mock.Anything, 7, 1, &from, (*time.Time)(nil)).Return(rows, nil)
+	rows.On("Next").Return(true).Once()
+	rows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 11
+			*args.Get(3).(*float64) = 80.5
+		}).Return(nil).Once()
+	rows.On("Next").Return(false).Once()
+	rows.On("Err").Return(nil)
+	rows.On("Close").Return()
+
+	measurements, err := setupRepo(mp).GetMeasurements(ctx, 7, 1, &from, nil)
+	require.NoError(t, err)
+	require.Len(t, measurements, 1)
+	assert.Equal(t, 80.5, measurements[0].Value)
+}
+
+func TestGetLatestValue_None(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	at := time.Now()
+	mp.On("QueryRow", ctx, mock.Anything, 7, model.Bodyweight, at).Return(row)
+	row.On("Scan", mock.Anything).Return(pgx.ErrNoRows)
+
+	value, err := setupRepo(mp).GetLatestValue(ctx, 7, model.Bodyweight, at)
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
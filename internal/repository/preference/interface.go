@@ -0,0 +1,13 @@
+package preference
+
+import (
+	"context"
+	model "workout-tracker/internal/model/preference"
+)
+
+type PreferenceRepositoryInterface interface {
+	GetPreferences(ctx context.Context, userID int) (*model.Preferences, error)
+	UpsertPreferences(ctx context.Context, p model.Preferences) error
+}
+
+var _ PreferenceRepositoryInterface = (*PreferenceRepository)(nil)
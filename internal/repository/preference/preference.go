@@ -0,0 +1,72 @@
+package preference
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	model "workout-tracker/internal/model/preference"
+	"workout-tracker/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/dig"
+)
+
+type DBPool interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+type PreferenceRepositoryParams struct {
+	dig.In
+
+	Log logger.SugaredLoggerInterface
+	DB  DBPool
+}
+
+type PreferenceRepository struct {
+	Log  logger.SugaredLoggerInterface
+	Pool DBPool
+}
+
+func NewPreferenceRepository(params PreferenceRepositoryParams) PreferenceRepositoryInterface {
+	return &PreferenceRepository{
+		Log:  params.Log,
+		Pool: params.DB,
+	}
+}
+
+// GetPreferences returns the user's saved preferences, or the defaults when they never saved any.
+func (r *PreferenceRepository) GetPreferences(ctx context.Context, userID int) (*model.Preferences, error) {
+	p := model.Default(userID)
+	err := r.Pool.QueryRow(ctx, `
+		SELECT progression_strategy, unit_system, updatedat
+		FROM user_preferences
+		WHERE user_id = $1
+	`, userID).Scan(&p.ProgressionStrategy, &p.UnitSystem, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &p, nil
+		}
+		r.Log.Errorw("failed to get preferences", "userID", userID, "error", err)
+		return nil, fmt.Errorf("get preferences: %w", err)
+	}
+	return &p, nil
+}
+
+func (r *PreferenceRepository) UpsertPreferences(ctx context.Context, p model.Preferences) error {
+	_, err := r.Pool.Exec(ctx, `
+		INSERT INTO user_preferences (user_id, progression_strategy, unit_system, updatedat)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		    SET progression_strategy = EXCLUDED.progression_strategy,
+		        unit_system          = EXCLUDED.unit_system,
+		        updatedat            = EXCLUDED.updatedat
+	`, p.UserID, p.ProgressionStrategy, p.UnitSystem, p.UpdatedAt)
+	if err != nil {
+		r.Log.Errorw("failed to save preferences", "userID", p.UserID, "error", err)
+		return fmt.Errorf("save preferences: %w", err)
+	}
+	return nil
+}
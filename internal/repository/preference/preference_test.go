@@ -0,0 +1,74 @@
+package preference
+
+import (
+	"errors"
+	"testing"
+	"time"
+	model "workout-tracker/internal/model/preference"
+	"workout-tracker/internal/model/progression"
+	"workout-tracker/internal/model/units"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupRepo(mp *MockPool) *PreferenceRepository {
+	return &PreferenceRepository{Pool: mp, Log: zap.NewNop().Sugar()}
+}
+
+func TestGetPreferences_Saved(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, 7).Return(row)
+	row.On("Scan", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*progression.Strategy) = progression.Double
+		*args.Get(1).(*units.System) = units.Imperial
+	}).Return(nil)
+
+	p, err := setupRepo(mp).GetPreferences(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, progression.Double, p.ProgressionStrategy)
+	assert.Equal(t, units.Imperial, p.UnitSystem)
+	assert.Equal(t, 7, p.UserID)
+}
+
+func TestGetPreferences_DefaultsWhenMissing(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, 7).Return(row)
+	row.On("Scan", mock.Anything, mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
+
+	p, err := setupRepo(mp).GetPreferences(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, model.Default(7), *p)
+}
+
+func TestGetPreferences_Error(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, 7).Return(row)
+	row.On("Scan", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("boom"))
+
+	_, err := setupRepo(mp).GetPreferences(ctx, 7)
+	assert.Error(t, err)
+}
+
+func TestUpsertPreferences_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	now := time.Now()
+	mp.On("Exec", ctx, mock.Anything, 7, progression.RPE, units.Imperial, now).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+	err := setupRepo(mp).UpsertPreferences(ctx, model.Preferences{
+		UserID: 7, ProgressionStrategy: progression.RPE, UnitSystem: units.Imperial, UpdatedAt: now,
+	})
+	require.NoError(t, err)
+	mp.AssertExpectations(t)
+}
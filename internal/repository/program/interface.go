@@ -0,0 +1,25 @@
+package program
+
+import (
+	"context"
+	model "workout-tracker/internal/model/program"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ProgramRepositoryInterface interface {
+	WithTx(tx pgx.Tx) ProgramRepositoryInterface
+	CreateProgram(ctx context.Context, p model.Program) (int, error)
+	BulkInsertProgramDays(ctx context.Context, programID int, days []model.Day) error
+	BulkInsertProgressionRules(ctx context.Context, programID int, rules []model.ProgressionRule) error
+	GetPrograms(ctx context.Context) ([]model.Program, error)
+	GetProgramByID(ctx context.Context, id int) (*model.Program, error)
+	GetProgramDays(ctx context.Context, programID int) ([]model.Day, error)
+	GetProgressionRules(ctx context.Context, programID int) ([]model.ProgressionRule, error)
+	DeleteProgram(ctx context.Context, id int) error
+	UpsertEnrollment(ctx context.Context, e model.Enrollment) error
+	GetEnrollment(ctx context.Context, userID int) (*model.Enrollment, error)
+	DeleteEnrollment(ctx context.Context, userID int) error
+}
+
+var _ ProgramRepositoryInterface = (*ProgramRepository)(nil)
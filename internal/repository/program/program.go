@@ -0,0 +1,272 @@
+package program
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/program"
+	"workout-tracker/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/dig"
+)
+
+type DBPool interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+type ProgramRepositoryParams struct {
+	dig.In
+
+	Log logger.SugaredLoggerInterface
+	DB  DBPool
+}
+
+type ProgramRepository struct {
+	Log  logger.SugaredLoggerInterface
+	Pool DBPool
+}
+
+func NewProgramRepository(params ProgramRepositoryParams) ProgramRepositoryInterface {
+	return &ProgramRepository{
+		Log:  params.Log,
+		Pool: params.DB,
+	}
+}
+
+// WithTx returns a copy of the repository that runs its queries inside tx.
+func (r *ProgramRepository) WithTx(tx pgx.Tx) ProgramRepositoryInterface {
+	return &ProgramRepository{
+		Log:  r.Log,
+		Pool: tx,
+	}
+}
+
+const programColumns = `id, user_id, name, description, weeks, createdat, updatedat`
+
+func scanProgram(row pgx.Row) (*model.Program, error) {
+	var p model.Program
+	err := row.Scan(&p.ID, &p.UserID, &p.Name, &p.Description, &p.WeekCount, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *ProgramRepository) CreateProgram(ctx context.Context, p model.Program) (int, error) {
+	var id int
+	err := r.Pool.QueryRow(ctx, `
+		INSERT INTO programs (user_id, name, description, weeks, createdat, updatedat)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, p.UserID, p.Name, p.Description, p.WeekCount, p.CreatedAt, p.UpdatedAt).Scan(&id)
+	if err != nil {
+		r.Log.Errorw("failed to create program", "error", err)
+		return 0, fmt.Errorf("create program: %w", err)
+	}
+	return id, nil
+}
+
+// BulkInsertProgramDays writes the days of a program with a single COPY.
+func (r *ProgramRepository) BulkInsertProgramDays(ctx context.Context, programID int, days []model.Day) error {
+	if len(days) == 0 {
+		return nil
+	}
+
+	_, err := r.Pool.CopyFrom(ctx,
+		pgx.Identifier{"program_days"},
+		[]string{"program_id", "week", "day", "workout_id"},
+		pgx.CopyFromSlice(len(days), func(i int) ([]any, error) {
+			d := days[i]
+			return []any{programID, d.Week, d.Day, d.WorkoutID}, nil
+		}),
+	)
+	if err != nil {
+		r.Log.Errorw("failed to insert program days", "error", err)
+		return fmt.Errorf("insert program days: %w", err)
+	}
+	return nil
+}
+
+// BulkInsertProgressionRules writes the rules of a program in their given order with a single COPY.
+func (r *ProgramRepository) BulkInsertProgressionRules(ctx context.Context, programID int, rules []model.ProgressionRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	_, err := r.Pool.CopyFrom(ctx,
+		pgx.Identifier{"program_progressions"},
+		[]string{"program_id", "position", "exercise_id", "rule_type", "amount", "every_weeks"},
+		pgx.CopyFromSlice(len(rules), func(i int) ([]any, error) {
+			p := rules[i]
+			return []any{programID, i, p.ExerciseID, string(p.Type), p.Amount, p.EveryWeeks}, nil
+		}),
+	)
+	if err != nil {
+		r.Log.Errorw("failed to insert progression rules", "error", err)
+		return fmt.Errorf("insert progression rules: %w", err)
+	}
+	return nil
+}
+
+// GetPrograms lists every program without its weeks and rules.
+func (r *ProgramRepository) GetPrograms(ctx context.Context) ([]model.Program, error) {
+	rows, err := r.Pool.Query(ctx, `SELECT `+programColumns+` FROM programs ORDER BY name, id`)
+	if err != nil {
+		r.Log.Errorw("failed to get programs", "error", err)
+		return nil, fmt.Errorf("get programs: %w", err)
+	}
+	defer rows.Close()
+
+	result := []model.Program{}
+	for rows.Next() {
+		p, err := scanProgram(rows)
+		if err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get programs: %w", err)
+		}
+		result = append(result, *p)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get programs: %w", err)
+	}
+	return result, nil
+}
+
+// GetProgramByID returns the program without its weeks and rules.
+func (r *ProgramRepository) GetProgramByID(ctx context.Context, id int) (*model.Program, error) {
+	p, err := scanProgram(r.Pool.QueryRow(ctx, `SELECT `+programColumns+` FROM programs WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, erorrs.ErrNotFound
+		}
+		r.Log.Errorw("failed to get program", "id", id, "error", err)
+		return nil, fmt.Errorf("get program: %w", err)
+	}
+	return p, nil
+}
+
+// GetProgramDays returns the program's days ordered by week and day.
+func (r *ProgramRepository) GetProgramDays(ctx context.Context, programID int) ([]model.Day, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT week, day, workout_id
+		FROM program_days
+		WHERE program_id = $1
+		ORDER BY week, day
+	`, programID)
+	if err != nil {
+		r.Log.Errorw("failed to get program days", "error", err)
+		return nil, fmt.Errorf("get program days: %w", err)
+	}
+	defer rows.Close()
+
+	var days []model.Day
+	for rows.Next() {
+		var d model.Day
+		if err := rows.Scan(&d.Week, &d.Day, &d.WorkoutID); err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get program days: %w", err)
+		}
+		days = append(days, d)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get program days: %w", err)
+	}
+	return days, nil
+}
+
+// GetProgressionRules returns the program's rules in the order they were given.
+func (r *ProgramRepository) GetProgressionRules(ctx context.Context, programID int) ([]model.ProgressionRule, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT exercise_id, rule_type, amount, every_weeks
+		FROM program_progressions
+		WHERE program_id = $1
+		ORDER BY position
+	`, programID)
+	if err != nil {
+		r.Log.Errorw("failed to get progression rules", "error", err)
+		return nil, fmt.Errorf("get progression rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []model.ProgressionRule
+	for rows.Next() {
+		var p model.ProgressionRule
+		if err := rows.Scan(&p.ExerciseID, &p.Type, &p.Amount, &p.EveryWeeks); err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get progression rules: %w", err)
+		}
+		rules = append(rules, p)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get progression rules: %w", err)
+	}
+	return rules, nil
+}
+
+// DeleteProgram removes the program together with its days, rules and enrollments.
+func (r *ProgramRepository) DeleteProgram(ctx context.Context, id int) error {
+	tag, err := r.Pool.Exec(ctx, `DELETE FROM programs WHERE id = $1`, id)
+	if err != nil {
+		r.Log.Errorw("failed to delete program", "id", id, "error", err)
+		return fmt.Errorf("delete program: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotFound
+	}
+	return nil
+}
+
+// UpsertEnrollment enrolls the user, replacing the program they were following before.
+func (r *ProgramRepository) UpsertEnrollment(ctx context.Context, e model.Enrollment) error {
+	_, err := r.Pool.Exec(ctx, `
+		INSERT INTO program_enrollments (user_id, program_id, start_date, createdat)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		    SET program_id = EXCLUDED.program_id,
+		        start_date = EXCLUDED.start_date,
+		        createdat  = EXCLUDED.createdat
+	`, e.UserID, e.ProgramID, e.StartDate, e.CreatedAt)
+	if err != nil {
+		r.Log.Errorw("failed to enroll", "userID", e.UserID, "programID", e.ProgramID, "error", err)
+		return fmt.Errorf("enroll: %w", err)
+	}
+	return nil
+}
+
+func (r *ProgramRepository) GetEnrollment(ctx context.Context, userID int) (*model.Enrollment, error) {
+	var e model.Enrollment
+	err := r.Pool.QueryRow(ctx, `
+		SELECT user_id, program_id, start_date, createdat
+		FROM program_enrollments
+		WHERE user_id = $1
+	`, userID).Scan(&e.UserID, &e.ProgramID, &e.StartDate, &e.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, erorrs.ErrNotEnrolled
+		}
+		r.Log.Errorw("failed to get enrollment", "userID", userID, "error", err)
+		return nil, fmt.Errorf("get enrollment: %w", err)
+	}
+	return &e, nil
+}
+
+func (r *ProgramRepository) DeleteEnrollment(ctx context.Context, userID int) error {
+	tag, err := r.Pool.Exec(ctx, `DELETE FROM program_enrollments WHERE user_id = $1`, userID)
+	if err != nil {
+		r.Log.Errorw("failed to delete enrollment", "userID", userID, "error", err)
+		return fmt.Errorf("delete enrollment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotEnrolled
+	}
+	return nil
+}
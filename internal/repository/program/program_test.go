@@ -0,0 +1,170 @@
+package program
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/program"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupRepo(mp *MockPool) *ProgramRepository {
+	return &ProgramRepository{Pool: mp, Log: zap.NewNop().Sugar()}
+}
+
+// collectRows makes a CopyFrom expectation record the rows it is given.
+func collectRows(t *testing.T, rows *[][]any) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		src := args.Get(3).(pgx.CopyFromSource)
+		for src.Next() {
+			values, err := src.Values()
+			assert.NoError(t, err)
+			*rows = append(*rows, values)
+		}
+	}
+}
+
+func TestCreateProgram_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	now := time.Now()
+	mp.On("QueryRow", ctx, mock.Anything, 1, "5/3/1", "", 4, now, now).Return(row)
+	row.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*int) = 9
+	}).Return(nil)
+
+	id, err := setupRepo(mp).CreateProgram(ctx, model.Program{UserID: 1, Name: "5/3/1", WeekCount: 4, CreatedAt: now, UpdatedAt: now})
+	require.NoError(t, err)
+	assert.Equal(t, 9, id)
+}
+
+func TestBulkInsertProgramDays_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	var rows [][]any
+	mp.On("CopyFrom", ctx, pgx.Identifier{"program_days"}, []string{"program_id", "week", "day", "workout_id"}, mock.Anything).
+		Run(collectRows(t, &rows)).Return(int64(2), nil)
+
+	err := setupRepo(mp).BulkInsertProgramDays(ctx, 9, []model.Day{{Week: 1, Day: 1, WorkoutID: 4}, {Week: 2, Day: 3, WorkoutID: 5}})
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{9, 1, 1, 4}, {9, 2, 3, 5}}, rows)
+}
+
+func TestBulkInsertProgressionRules_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	exerciseID := 3
+	var rows [][]any
+	mp.On("CopyFrom", ctx, pgx.Identifier{"program_progressions"},
+		[]string{"program_id", "position", "exercise_id", "rule_type", "amount", "every_weeks"}, mock.Anything).
+		Run(collectRows(t, &rows)).Return(int64(2), nil)
+
+	err := setupRepo(mp).BulkInsertProgressionRules(ctx, 9, []model.ProgressionRule{
+		{ExerciseID: &exerciseID, Type: model.ProgressWeight, Amount: 2.5, EveryWeeks: 1},
+		{Type: model.ProgressReps, Amount: 1, EveryWeeks: 2},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{
+		{9, 0, &exerciseID, "weight", 2.5, 1},
+		{9, 1, (*int)(nil), "reps", 1.0, 2},
+	}, rows)
+}
+
+func TestBulkInserts_SkipEmpty(t *testing.T) {
+	mp := new(MockPool)
+	repo := setupRepo(mp)
+	require.NoError(t, repo.BulkInsertProgramDays(t.Context(), 9, nil))
+	require.NoError(t, repo.BulkInsertProgressionRules(t.Context(), 9, nil))
+	mp.AssertNotCalled(t, "CopyFrom", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetProgramByID_NotFound(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, 9).Return(row)
+	row.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(pgx.ErrNoRows)
+
+	_, err := setupRepo(mp).GetProgramByID(ctx, 9)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestGetProgramDays_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	rows := new(MockRow)
+	mp.On("Query", ctx, mock.Anything, 9).Return(rows, nil)
+	rows.On("Next").Return(true).Once()
+	rows.On("Scan", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*int) = 2
+		*args.Get(1).(*int) = 5
+		*args.Get(2).(*int) = 4
+	}).Return(nil).Once()
+	rows.On("Next").Return(false).Once()
+	rows.On("Err").Return(nil)
+	rows.On("Close").Return()
+
+	days, err := setupRepo(mp).GetProgramDays(ctx, 9)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Day{{Week: 2, Day: 5, WorkoutID: 4}}, days)
+}
+
+func TestGetProgressionRules_QueryError(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	mp.On("Query", ctx, mock.Anything, 9).Return(new(MockRow), errors.New("boom"))
+
+	rules, err := setupRepo(mp).GetProgressionRules(ctx, 9)
+	assert.Nil(t, rules)
+	assert.Error(t, err)
+}
+
+func TestDeleteProgram_NotFound(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	mp.On("Exec", ctx, mock.Anything, 9).Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+	err := setupRepo(mp).DeleteProgram(ctx, 9)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestUpsertEnrollment_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	now := time.Now()
+	mp.On("Exec", ctx, mock.Anything, 2, 9, start, now).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+	err := setupRepo(mp).UpsertEnrollment(ctx, model.Enrollment{UserID: 2, ProgramID: 9, StartDate: start, CreatedAt: now})
+	require.NoError(t, err)
+	mp.AssertExpectations(t)
+}
+
+func TestGetEnrollment_NotEnrolled(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, 2).Return(row)
+	row.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
+
+	_, err := setupRepo(mp).GetEnrollment(ctx, 2)
+	assert.ErrorIs(t, err, erorrs.ErrNotEnrolled)
+}
+
+func TestDeleteEnrollment_NotEnrolled(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	mp.On("Exec", ctx, mock.Anything, 2).Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+	err := setupRepo(mp).DeleteEnrollment(ctx, 2)
+	assert.ErrorIs(t, err, erorrs.ErrNotEnrolled)
+}
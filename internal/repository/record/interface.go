@@ -0,0 +1,21 @@
+package record
+
+import (
+	"context"
+	model "workout-tracker/internal/model/record"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type RecordRepositoryInterface interface {
+	WithTx(tx pgx.Tx) RecordRepositoryInterface
+	LockExerciseRecords(ctx context.Context, userID, exerciseID int) error
+	CreateRecord(ctx context.Context, r model.PersonalRecord) (int, error)
+	UpdateRecord(ctx context.Context, r model.PersonalRecord) error
+	GetBestRecord(ctx context.Context, userID, exerciseID int, recordType model.RecordType, weight float64) (*model.PersonalRecord, error)
+	GetSessionVolume(ctx context.Context, sessionID, exerciseID int) (float64, error)
+	GetRecordHistory(ctx context.Context, userID int, exerciseID *int) ([]model.PersonalRecord, error)
+	GetCurrentRecords(ctx context.Context, userID int, exerciseID *int) ([]model.PersonalRecord, error)
+}
+
+var _ RecordRepositoryInterface = (*RecordRepository)(nil)
@@ -0,0 +1,195 @@
+package record
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	model "workout-tracker/internal/model/record"
+	"workout-tracker/pkg/db"
+	"workout-tracker/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/dig"
+)
+
+type DBPool interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+type RecordRepositoryParams struct {
+	dig.In
+
+	DB  *db.DB
+	Log logger.SugaredLoggerInterface
+}
+
+type RecordRepository struct {
+	Pool DBPool
+	Log  logger.SugaredLoggerInterface
+}
+
+func NewRecordRepository(params RecordRepositoryParams) *RecordRepository {
+	return &RecordRepository{
+		Pool: params.DB.Pool,
+		Log:  params.Log,
+	}
+}
+
+// WithTx returns a copy of the repository that runs its queries inside tx.
+func (r *RecordRepository) WithTx(tx pgx.Tx) RecordRepositoryInterface {
+	return &RecordRepository{
+		Pool: tx,
+		Log:  r.Log,
+	}
+}
+
+const recordColumns = `id, user_id, exercise_id, record_type, value, weight, reps, session_id, set_id, achieved_at`
+
+// LockExerciseRecords takes a lock on the user's records of the exercise that is held until the
+// transaction ends. There may be no record yet to lock a row of, so it is an advisory lock.
+func (r *RecordRepository) LockExerciseRecords(ctx context.Context, userID, exerciseID int) error {
+	if _, err := r.Pool.Exec(ctx, `SELECT pg_advisory_xact_lock($1, $2)`, userID, exerciseID); err != nil {
+		r.Log.Errorw("failed to lock personal records", "error", err)
+		return fmt.Errorf("lock personal records: %w", err)
+	}
+	return nil
+}
+
+func (r *RecordRepository) CreateRecord(ctx context.Context, rec model.PersonalRecord) (int, error) {
+	var id int
+	err := r.Pool.QueryRow(ctx, `
+		INSERT INTO personal_records (user_id, exercise_id, record_type, value, weight, reps, session_id, set_id, achieved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, rec.UserID, rec.ExerciseID, rec.RecordType, rec.Value, rec.Weight, rec.Reps, rec.SessionID, rec.SetID, rec.AchievedAt).Scan(&id)
+	if err != nil {
+		r.Log.Errorw("failed to create personal record", "error", err)
+		return 0, fmt.Errorf("create personal record: %w", err)
+	}
+	return id, nil
+}
+
+func (r *RecordRepository) UpdateRecord(ctx context.Context, rec model.PersonalRecord) error {
+	_, err := r.Pool.Exec(ctx, `
+		UPDATE personal_records
+		SET value = $1, weight = $2, reps = $3, set_id = $4, achieved_at = $5
+		WHERE id = $6
+	`, rec.Value, rec.Weight, rec.Reps, rec.SetID, rec.AchievedAt, rec.ID)
+	if err != nil {
+		r.Log.Errorw("failed to update personal record", "error", err)
+		return fmt.Errorf("update personal record: %w", err)
+	}
+	return nil
+}
+
+// GetBestRecord returns the highest record of the given type, or nil if none exists yet.
+// The weight is only taken into account for reps-at-weight records.
+func (r *RecordRepository) GetBestRecord(
+	ctx context.Context, userID, exerciseID int, recordType model.RecordType, weight float64) (*model.PersonalRecord, error) {
+	row := r.Pool.QueryRow(ctx, `
+		SELECT `+recordColumns+`
+		FROM personal_records
+		WHERE user_id = $1 AND exercise_id = $2 AND record_type = $3
+		  AND (record_type <> 'reps_at_weight' OR weight = $4)
+		ORDER BY value DESC, achieved_at DESC
+		LIMIT 1
+	`, userID, exerciseID, recordType, weight)
+
+	rec, err := scanRecord(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.Log.Errorw("failed to get best record", "error", err)
+		return nil, fmt.Errorf("get best record: %w", err)
+	}
+	return rec, nil
+}
+
+func (r *RecordRepository) GetSessionVolume(ctx context.Context, sessionID, exerciseID int) (float64, error) {
+	var volume float64
+	err := r.Pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(weight * reps), 0)
+		FROM session_sets
+		WHERE session_id = $1 AND exercise_id = $2
+	`, sessionID, exerciseID).Scan(&volume)
+	if err != nil {
+		r.Log.Errorw("failed to get session volume", "error", err)
+		return 0, fmt.Errorf("get session volume: %w", err)
+	}
+	return volume, nil
+}
+
+func (r *RecordRepository) GetRecordHistory(ctx context.Context, userID int, exerciseID *int) ([]model.PersonalRecord, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT `+recordColumns+`
+		FROM personal_records
+		WHERE user_id = $1 AND ($2::int IS NULL OR exercise_id = $2)
+		ORDER BY achieved_at DESC, id DESC
+	`, userID, exerciseID)
+	if err != nil {
+		r.Log.Errorw("failed to get record history", "error", err)
+		return nil, fmt.Errorf("get record history: %w", err)
+	}
+	return r.collect(rows, "get record history")
+}
+
+// GetCurrentRecords returns the best record per exercise and type; reps-at-weight records are kept per weight.
+func (r *RecordRepository) GetCurrentRecords(ctx context.Context, userID int, exerciseID *int) ([]model.PersonalRecord, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT DISTINCT ON (exercise_id, record_type, CASE WHEN record_type = 'reps_at_weight' THEN weight END)
+		       `+recordColumns+`
+		FROM personal_records
+		WHERE user_id = $1 AND ($2::int IS NULL OR exercise_id = $2)
+		ORDER BY exercise_id, record_type, CASE WHEN record_type = 'reps_at_weight' THEN weight END,
+		         value DESC, achieved_at DESC
+	`, userID, exerciseID)
+	if err != nil {
+		r.Log.Errorw("failed to get current records", "error", err)
+		return nil, fmt.Errorf("get current records: %w", err)
+	}
+	return r.collect(rows, "get current records")
+}
+
+func (r *RecordRepository) collect(rows pgx.Rows, op string) ([]model.PersonalRecord, error) {
+	defer rows.Close()
+
+	result := []model.PersonalRecord{}
+	for rows.Next() {
+		rec, err := scanRecord(rows)
+		if err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, *rec)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, nil
+}
+
+func scanRecord(row pgx.Row) (*model.PersonalRecord, error) {
+	var rec model.PersonalRecord
+	err := row.Scan(
+		&rec.ID,
+		&rec.UserID,
+		&rec.ExerciseID,
+		&rec.RecordType,
+		&rec.Value,
+		&rec.Weight,
+		&rec.Reps,
+		&rec.SessionID,
+		&rec.SetID,
+		&rec.AchievedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
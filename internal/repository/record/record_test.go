@@ -0,0 +1,127 @@
+package record
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	model "workout-tracker/internal/model/record"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func setupRepo(t *testing.T, mp *MockPool) *RecordRepository {
+	t.Helper()
+	return &RecordRepository{Pool: mp, Log: zaptest.NewLogger(t).Sugar()}
+}
+
+func recordScanArgs() []interface{} {
+	args := make([]interface{}, 10)
+	for i := range args {
+		args[i] = mock.Anything
+	}
+	return args
+}
+
+func TestCreateRecord_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	r := new(MockRow)
+	rec := model.PersonalRecord{UserID: 1, ExerciseID: 2, RecordType: model.MaxWeight, Value: 100, Weight: 100, Reps: 5}
+	mp.On("QueryRow", ctx, mock.Anything, rec.UserID, rec.ExerciseID, rec.RecordType, rec.Value, rec.Weight, rec.Reps,
+		rec.SessionID, rec.SetID, rec.AchievedAt).Return(r)
+	r.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*int) = 11
+	}).Return(nil)
+
+	id, err := setupRepo(t, mp).CreateRecord(ctx, rec)
+	require.NoError(t, err)
+	assert.Equal(t, 11, id)
+}
+
+func TestUpdateRecord_Error(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	rec := model.PersonalRecord{ID: 4, Value: 120}
+	mp.On("Exec", ctx, mock.Anything, rec.Value, rec.Weight, rec.Reps, rec.SetID, rec.AchievedAt, rec.ID).
+		Return(pgconn.CommandTag{}, errors.New("exec"))
+
+	assert.Error(t, setupRepo(t, mp).UpdateRecord(ctx, rec))
+}
+
+func TestGetBestRecord_NoRows(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	r := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, 1, 2, model.MaxWeight, 0.0).Return(r)
+	r.On("Scan", recordScanArgs()...).Return(pgx.ErrNoRows)
+
+	rec, err := setupRepo(t, mp).GetBestRecord(ctx, 1, 2, model.MaxWeight, 0)
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+}
+
+func TestGetBestRecord_Found(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	r := new(MockRow)
+	now := time.Now()
+	mp.On("QueryRow", ctx, mock.Anything, 1, 2, model.RepsAtWeight, 80.0).Return(r)
+	r.On("Scan", recordScanArgs()...).Run(func(args mock.Arguments) {
+		*args.Get(0).(*int) = 3
+		*args.Get(3).(*model.RecordType) = model.RepsAtWeight
+		*args.Get(4).(*float64) = 8
+		*args.Get(5).(*float64) = 80
+		*args.Get(6).(*int) = 8
+		*args.Get(9).(*time.Time) = now
+	}).Return(nil)
+
+	rec, err := setupRepo(t, mp).GetBestRecord(ctx, 1, 2, model.RepsAtWeight, 80)
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, 3, rec.ID)
+	assert.InDelta(t, 8.0, rec.Value, 0.001)
+	assert.Equal(t, now, rec.AchievedAt)
+}
+
+func TestGetCurrentRecords_QueryError(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	mp.On("Query", ctx, mock.Anything, 1, (*int)(nil)).Return((*MockRow)(nil), errors.New("qerr"))
+
+	recs, err := setupRepo(t, mp).GetCurrentRecords(ctx, 1, nil)
+	assert.Error(t, err)
+	assert.Nil(t, recs)
+}
+
+func TestGetRecordHistory_Empty(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	r := new(MockRow)
+	exerciseID := 2
+	mp.On("Query", ctx, mock.Anything, 1, &exerciseID).Return(r, nil)
+	r.On("Next").Return(false)
+	r.On("Err").Return(nil)
+	r.On("Close").Return()
+
+	recs, err := setupRepo(t, mp).GetRecordHistory(ctx, 1, &exerciseID)
+	require.NoError(t, err)
+	assert.Empty(t, recs)
+	assert.NotNil(t, recs)
+}
+
+func TestLockExerciseRecords(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	mp.On("Exec", ctx, mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "pg_advisory_xact_lock") }), 1, 2).
+		Return(pgconn.CommandTag{}, nil).Once()
+	mp.On("Exec", ctx, mock.Anything, 1, 3).Return(pgconn.CommandTag{}, errors.New("exec"))
+
+	require.NoError(t, setupRepo(t, mp).LockExerciseRecords(ctx, 1, 2))
+	assert.Error(t, setupRepo(t, mp).LockExerciseRecords(ctx, 1, 3))
+}
@@ -0,0 +1,23 @@
+package schedule
+
+import (
+	"context"
+	"time"
+	model "workout-tracker/internal/model/schedule"
+)
+
+type ScheduleRepositoryInterface interface {
+	CreateSchedule(ctx context.Context, s model.Schedule) (int, error)
+	GetSchedules(ctx context.Context, userID int) ([]model.Schedule, error)
+	GetScheduleByID(ctx context.Context, id, userID int) (*model.Schedule, error)
+	DeleteSchedule(ctx context.Context, id, userID int) error
+	UpsertMark(ctx context.Context, m model.Mark) error
+	DeleteMark(ctx context.Context, scheduleID int, date time.Time) error
+	GetMarks(ctx context.Context, userID int, from, to time.Time) ([]model.Mark, error)
+	GetSessionEntries(ctx context.Context, userID int, from, to time.Time) ([]model.Entry, error)
+	SetCalendarToken(ctx context.Context, userID int, tokenHash string) error
+	DeleteCalendarToken(ctx context.Context, userID int) error
+	GetCalendarTokenOwner(ctx context.Context, tokenHash string) (int, error)
+}
+
+var _ ScheduleRepositoryInterface = (*ScheduleRepository)(nil)
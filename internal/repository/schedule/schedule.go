@@ -0,0 +1,264 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/schedule"
+	"workout-tracker/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/dig"
+)
+
+type DBPool interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+type ScheduleRepositoryParams struct {
+	dig.In
+
+	Log logger.SugaredLoggerInterface
+	DB  DBPool
+}
+
+type ScheduleRepository struct {
+	Log  logger.SugaredLoggerInterface
+	Pool DBPool
+}
+
+func NewScheduleRepository(params ScheduleRepositoryParams) ScheduleRepositoryInterface {
+	return &ScheduleRepository{
+		Log:  params.Log,
+		Pool: params.DB,
+	}
+}
+
+const scheduleColumns = `s.id, s.user_id, s.workout_id, w.name, s.starts_at, s.duration_minutes, s.weekdays, s.until,
+	s.timezone, s.createdat, s.updatedat`
+
+func scanSchedule(row pgx.Row) (*model.Schedule, error) {
+	var s model.Schedule
+	var weekdays int16
+	err := row.Scan(&s.ID, &s.UserID, &s.WorkoutID, &s.WorkoutName, &s.StartsAt, &s.DurationMinutes, &weekdays, &s.Until,
+		&s.Timezone, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	s.Weekdays = model.Weekdays(weekdays)
+	return &s, nil
+}
+
+func (r *ScheduleRepository) CreateSchedule(ctx context.Context, s model.Schedule) (int, error) {
+	var id int
+	err := r.Pool.QueryRow(ctx, `
+		INSERT INTO schedules (user_id, workout_id, starts_at, duration_minutes, weekdays, until, timezone, createdat, updatedat)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, s.UserID, s.WorkoutID, s.StartsAt, s.DurationMinutes, int16(s.Weekdays), s.Until, s.Timezone, s.CreatedAt, s.UpdatedAt).
+		Scan(&id)
+	if err != nil {
+		r.Log.Errorw("failed to create schedule", "error", err)
+		return 0, fmt.Errorf("create schedule: %w", err)
+	}
+	return id, nil
+}
+
+// GetSchedules lists the user's schedules ordered by their first occurrence.
+func (r *ScheduleRepository) GetSchedules(ctx context.Context, userID int) ([]model.Schedule, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT `+scheduleColumns+`
+		FROM schedules s
+		JOIN workouts w ON w.id = s.workout_id
+		WHERE s.user_id = $1
+		ORDER BY s.starts_at, s.id
+	`, userID)
+	if err != nil {
+		r.Log.Errorw("failed to get schedules", "error", err)
+		return nil, fmt.Errorf("get schedules: %w", err)
+	}
+	defer rows.Close()
+
+	result := []model.Schedule{}
+	for rows.Next() {
+		s, err := scanSchedule(rows)
+		if err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get schedules: %w", err)
+		}
+		result = append(result, *s)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get schedules: %w", err)
+	}
+	return result, nil
+}
+
+func (r *ScheduleRepository) GetScheduleByID(ctx context.Context, id, userID int) (*model.Schedule, error) {
+	s, err := scanSchedule(r.Pool.QueryRow(ctx, `
+		SELECT `+scheduleColumns+`
+		FROM schedules s
+		JOIN workouts w ON w.id = s.workout_id
+		WHERE s.id = $1 AND s.user_id = $2
+	`, id, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, erorrs.ErrNotFound
+		}
+		r.Log.Errorw("failed to get schedule", "id", id, "error", err)
+		return nil, fmt.Errorf("get schedule: %w", err)
+	}
+	return s, nil
+}
+
+// DeleteSchedule removes the schedule together with its marks.
+func (r *ScheduleRepository) DeleteSchedule(ctx context.Context, id, userID int) error {
+	tag, err := r.Pool.Exec(ctx, `DELETE FROM schedules WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		r.Log.Errorw("failed to delete schedule", "id", id, "error", err)
+		return fmt.Errorf("delete schedule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ScheduleRepository) UpsertMark(ctx context.Context, m model.Mark) error {
+	_, err := r.Pool.Exec(ctx, `
+		INSERT INTO schedule_occurrences (schedule_id, occurs_on, status, session_id, updatedat)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (schedule_id, occurs_on) DO UPDATE
+		    SET status     = EXCLUDED.status,
+		        session_id = EXCLUDED.session_id,
+		        updatedat  = EXCLUDED.updatedat
+	`, m.ScheduleID, m.Date, string(m.Status), m.SessionID, m.UpdatedAt)
+	if err != nil {
+		r.Log.Errorw("failed to mark occurrence", "scheduleID", m.ScheduleID, "error", err)
+		return fmt.Errorf("mark occurrence: %w", err)
+	}
+	return nil
+}
+
+// DeleteMark turns the occurrence back into a planned one. Occurrences without a mark are left alone.
+func (r *ScheduleRepository) DeleteMark(ctx context.Context, scheduleID int, date time.Time) error {
+	_, err := r.Pool.Exec(ctx, `DELETE FROM schedule_occurrences WHERE schedule_id = $1 AND occurs_on = $2`, scheduleID, date)
+	if err != nil {
+		r.Log.Errorw("failed to unmark occurrence", "scheduleID", scheduleID, "error", err)
+		return fmt.Errorf("unmark occurrence: %w", err)
+	}
+	return nil
+}
+
+// GetMarks returns the marks of the user's schedules for the dates within [from, to).
+func (r *ScheduleRepository) GetMarks(ctx context.Context, userID int, from, to time.Time) ([]model.Mark, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT o.schedule_id, o.occurs_on, o.status, o.session_id, o.updatedat
+		FROM schedule_occurrences o
+		JOIN schedules s ON s.id = o.schedule_id
+		WHERE s.user_id = $1 AND o.occurs_on >= $2::date AND o.occurs_on < $3::date
+		ORDER BY o.occurs_on, o.schedule_id
+	`, userID, from, to)
+	if err != nil {
+		r.Log.Errorw("failed to get occurrence marks", "error", err)
+		return nil, fmt.Errorf("get occurrence marks: %w", err)
+	}
+	defer rows.Close()
+
+	var marks []model.Mark
+	for rows.Next() {
+		var m model.Mark
+		if err := rows.Scan(&m.ScheduleID, &m.Date, &m.Status, &m.SessionID, &m.UpdatedAt); err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get occurrence marks: %w", err)
+		}
+		marks = append(marks, m)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get occurrence marks: %w", err)
+	}
+	return marks, nil
+}
+
+// GetSessionEntries returns the user's sessions finished within [from, to) as completed calendar
+// entries.
+func (r *ScheduleRepository) GetSessionEntries(ctx context.Context, userID int, from, to time.Time) ([]model.Entry, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT ws.id, ws.workout_id, w.name, ws.started_at, ws.finished_at
+		FROM workout_sessions ws
+		JOIN workouts w ON w.id = ws.workout_id
+		WHERE ws.user_id = $1 AND ws.finished_at IS NOT NULL AND ws.started_at >= $2 AND ws.started_at < $3
+		ORDER BY ws.started_at
+	`, userID, from, to)
+	if err != nil {
+		r.Log.Errorw("failed to get calendar sessions", "error", err)
+		return nil, fmt.Errorf("get calendar sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []model.Entry
+	for rows.Next() {
+		var sessionID int
+		e := model.Entry{Status: model.StatusCompleted}
+		if err := rows.Scan(&sessionID, &e.WorkoutID, &e.WorkoutName, &e.StartsAt, &e.EndsAt); err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get calendar sessions: %w", err)
+		}
+		e.SessionID = &sessionID
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get calendar sessions: %w", err)
+	}
+	return entries, nil
+}
+
+// SetCalendarToken replaces the user's feed token, invalidating the previous one.
+func (r *ScheduleRepository) SetCalendarToken(ctx context.Context, userID int, tokenHash string) error {
+	_, err := r.Pool.Exec(ctx, `
+		INSERT INTO calendar_tokens (user_id, token_hash, createdat)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		    SET token_hash = EXCLUDED.token_hash,
+		        createdat  = EXCLUDED.createdat
+	`, userID, tokenHash)
+	if err != nil {
+		r.Log.Errorw("failed to store calendar token", "userID", userID, "error", err)
+		return fmt.Errorf("store calendar token: %w", err)
+	}
+	return nil
+}
+
+func (r *ScheduleRepository) DeleteCalendarToken(ctx context.Context, userID int) error {
+	tag, err := r.Pool.Exec(ctx, `DELETE FROM calendar_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		r.Log.Errorw("failed to delete calendar token", "userID", userID, "error", err)
+		return fmt.Errorf("delete calendar token: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotFound
+	}
+	return nil
+}
+
+// GetCalendarTokenOwner returns the user the feed token belongs to, or ErrTokenNotFound.
+func (r *ScheduleRepository) GetCalendarTokenOwner(ctx context.Context, tokenHash string) (int, error) {
+	var userID int
+	err := r.Pool.QueryRow(ctx, `SELECT user_id FROM calendar_tokens WHERE token_hash = $1`, tokenHash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, erorrs.ErrTokenNotFound
+		}
+		r.Log.Errorw("failed to look up calendar token", "error", err)
+		return 0, fmt.Errorf("look up calendar token: %w", err)
+	}
+	return userID, nil
+}
@@ -0,0 +1,149 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/schedule"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupRepo(mp *MockPool) *ScheduleRepository {
+	return &ScheduleRepository{Pool: mp, Log: zap.NewNop().Sugar()}
+}
+
+func scheduleScanArgs() []any {
+	args := make([]any, 11)
+	for i := range args {
+		args[i] = mock.Anything
+	}
+	return args
+}
+
+func TestCreateSchedule_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	now := time.Now()
+	weekdays, _ := model.ParseWeekdays([]string{"mon", "fri"})
+	s := model.Schedule{UserID: 1, WorkoutID: 2, StartsAt: now, DurationMinutes: 45, Weekdays: weekdays, Timezone: "Europe/Berlin",
+		CreatedAt: now, UpdatedAt: now}
+	mp.On("QueryRow", ctx, mock.Anything, 1, 2, now, 45, int16(weekdays), (*time.Time)(nil), "Europe/Berlin", now, now).Return(row)
+	row.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*int) = 8
+	}).Return(nil)
+
+	id, err := setupRepo(mp).CreateSchedule(ctx, s)
+	require.NoError(t, err)
+	assert.Equal(t, 8, id)
+}
+
+func TestGetScheduleByID_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, 8, 1).Return(row)
+	row.On("Scan", scheduleScanArgs()...).Run(func(args mock.Arguments) {
+		*args.Get(0).(*int) = 8
+		*args.Get(3).(*string) = "Legs"
+		*args.Get(6).(*int16) = 0b0100010
+	}).Return(nil)
+
+	s, err := setupRepo(mp).GetScheduleByID(ctx, 8, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Legs", s.WorkoutName)
+	assert.Equal(t, []string{"mon", "fri"}, s.Weekdays.Names())
+}
+
+func TestGetScheduleByID_NotFound(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, 8, 1).Return(row)
+	row.On("Scan", scheduleScanArgs()...).Return(pgx.ErrNoRows)
+
+	_, err := setupRepo(mp).GetScheduleByID(ctx, 8, 1)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestDeleteSchedule_NotFound(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	mp.On("Exec", ctx, mock.Anything, 8, 1).Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+	err := setupRepo(mp).DeleteSchedule(ctx, 8, 1)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestUpsertMark_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	date := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	now := time.Now()
+	sessionID := 40
+	mp.On("Exec", ctx, mock.Anything, 8, date, "completed", &sessionID, now).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+	err := setupRepo(mp).UpsertMark(ctx, model.Mark{ScheduleID: 8, Date: date, Status: model.StatusCompleted, SessionID: &sessionID, UpdatedAt: now})
+	require.NoError(t, err)
+	mp.AssertExpectations(t)
+}
+
+func TestGetSessionEntries_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	rows := new(MockRow)
+	from, to := time.Now(), time.Now().Add(time.Hour)
+	mp.On("Query", ctx, mock.Anything, 1, from, to).Return(rows, nil)
+	rows.On("Next").Return(true).Once()
+	rows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*int) = 40
+		*args.Get(2).(*string) = "Legs"
+	}).Return(nil).Once()
+	rows.On("Next").Return(false).Once()
+	rows.On("Err").Return(nil)
+	rows.On("Close").Return()
+
+	entries, err := setupRepo(mp).GetSessionEntries(ctx, 1, from, to)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 40, *entries[0].SessionID)
+	assert.Equal(t, model.StatusCompleted, entries[0].Status)
+}
+
+func TestGetMarks_QueryError(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	from, to := time.Now(), time.Now().Add(time.Hour)
+	mp.On("Query", ctx, mock.Anything, 1, from, to).Return(new(MockRow), errors.New("boom"))
+
+	marks, err := setupRepo(mp).GetMarks(ctx, 1, from, to)
+	assert.Nil(t, marks)
+	assert.Error(t, err)
+}
+
+func TestGetCalendarTokenOwner_Unknown(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, "abc").Return(row)
+	row.On("Scan", mock.Anything).Return(pgx.ErrNoRows)
+
+	_, err := setupRepo(mp).GetCalendarTokenOwner(ctx, "abc")
+	assert.ErrorIs(t, err, erorrs.ErrTokenNotFound)
+}
+
+func TestDeleteCalendarToken_NotFound(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	mp.On("Exec", ctx, mock.Anything, 1).Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+	err := setupRepo(mp).DeleteCalendarToken(ctx, 1)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
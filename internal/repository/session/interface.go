@@ -0,0 +1,19 @@
+package session
+
+import (
+	"context"
+	"time"
+	model "workout-tracker/internal/model/session"
+)
+
+type SessionRepositoryInterface interface {
+	CreateSession(ctx context.Context, s model.Session) (int, error)
+	GetSessionByID(ctx context.Context, sessionID, userID int) (*model.Session, error)
+	GetSessionsByWorkout(ctx context.Context, workoutID, userID int) ([]model.Session, error)
+	FinishSession(ctx context.Context, sessionID, userID int, finishedAt time.Time, notes *string) error
+	AddSet(ctx context.Context, set model.SessionSet) (int, error)
+	GetSessionSets(ctx context.Context, sessionID int) ([]model.SessionSet, error)
+	GetExerciseHistory(ctx context.Context, userID int, exerciseIDs []int, sessions int) (map[int][]model.Session, error)
+}
+
+var _ SessionRepositoryInterface = (*SessionRepository)(nil)
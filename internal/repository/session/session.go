@@ -0,0 +1,231 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/session"
+	"workout-tracker/pkg/db"
+	"workout-tracker/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/dig"
+)
+
+type DBPool interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+type SessionRepositoryParams struct {
+	dig.In
+
+	DB  *db.DB
+	Log logger.SugaredLoggerInterface
+}
+
+type SessionRepository struct {
+	Pool DBPool
+	Log  logger.SugaredLoggerInterface
+}
+
+func NewSessionRepository(params SessionRepositoryParams) *SessionRepository {
+	return &SessionRepository{
+		Pool: params.DB.Pool,
+		Log:  params.Log,
+	}
+}
+
+func (r *SessionRepository) CreateSession(ctx context.Context, s model.Session) (int, error) {
+	var id int
+	err := r.Pool.QueryRow(ctx, `
+		INSERT INTO workout_sessions (user_id, workout_id, notes, started_at, createdat, updatedat)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, s.UserID, s.WorkoutID, s.Notes, s.StartedAt, s.CreatedAt, s.UpdatedAt).Scan(&id)
+	if err != nil {
+		r.Log.Errorw("failed to create session", "error", err)
+		return 0, fmt.Errorf("create session: %w", err)
+	}
+	return id, nil
+}
+
+func (r *SessionRepository) GetSessionByID(ctx context.Context, sessionID, userID int) (*model.Session, error) {
+	var s model.Session
+	err := r.Pool.QueryRow(ctx, `
+		SELECT id, user_id, workout_id, notes, started_at, finished_at, createdat, updatedat
+		FROM workout_sessions
+		WHERE id = $1 AND user_id = $2
+	`, sessionID, userID).Scan(
+		&s.ID,
+		&s.UserID,
+		&s.WorkoutID,
+		&s.Notes,
+		&s.StartedAt,
+		&s.FinishedAt,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, erorrs.ErrNotFound
+		}
+		r.Log.Errorw("failed to get session", "error", err)
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *SessionRepository) GetSessionsByWorkout(ctx context.Context, workoutID, userID int) ([]model.Session, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT id, user_id, workout_id, notes, started_at, finished_at, createdat, updatedat
+		FROM workout_sessions
+		WHERE workout_id = $1 AND user_id = $2
+		ORDER BY started_at DESC
+	`, workoutID, userID)
+	if err != nil {
+		r.Log.Errorw("failed to fetch sessions", "error", err)
+		return nil, fmt.Errorf("get sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []model.Session
+	for rows.Next() {
+		var s model.Session
+		if err := rows.Scan(&s.ID, &s.UserID, &s.WorkoutID, &s.Notes, &s.StartedAt, &s.FinishedAt, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get sessions: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepository) FinishSession(ctx context.Context, sessionID, userID int, finishedAt time.Time, notes *string) error {
+	tag, err := r.Pool.Exec(ctx, `
+		UPDATE workout_sessions
+		SET finished_at = $1, notes = COALESCE($2, notes), updatedat = $1
+		WHERE id = $3 AND user_id = $4 AND finished_at IS NULL
+	`, finishedAt, notes, sessionID, userID)
+	if err != nil {
+		r.Log.Errorw("failed to finish session", "error", err)
+		return fmt.Errorf("finish session: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SessionRepository) AddSet(ctx context.Context, set model.SessionSet) (int, error) {
+	var id int
+	err := r.Pool.QueryRow(ctx, `
+		INSERT INTO session_sets (session_id, exercise_id, set_number, reps, weight, rpe, rest_seconds, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, set.SessionID, set.ExerciseID, set.SetNumber, set.Reps, set.Weight, set.RPE, set.RestSeconds, set.CompletedAt).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return 0, erorrs.ErrSetAlreadyLogged
+		}
+		r.Log.Errorw("failed to add session set", "error", err)
+		return 0, fmt.Errorf("add session set: %w", err)
+	}
+	return id, nil
+}
+
+func (r *SessionRepository) GetSessionSets(ctx context.Context, sessionID int) ([]model.SessionSet, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT id, session_id, exercise_id, set_number, reps, weight, rpe, rest_seconds, completed_at
+		FROM session_sets
+		WHERE session_id = $1
+		ORDER BY completed_at, set_number
+	`, sessionID)
+	if err != nil {
+		r.Log.Errorw("failed to get session sets", "error", err)
+		return nil, fmt.Errorf("get session sets: %w", err)
+	}
+	defer rows.Close()
+
+	var sets []model.SessionSet
+	for rows.Next() {
+		var s model.SessionSet
+		if err := rows.Scan(&s.ID, &s.SessionID, &s.ExerciseID, &s.SetNumber, &s.Reps, &s.Weight, &s.RPE, &s.RestSeconds, &s.CompletedAt); err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get session sets: %w", err)
+		}
+		sets = append(sets, s)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get session sets: %w", err)
+	}
+
+	return sets, nil
+}
+
+// GetExerciseHistory returns, for each of the exercises, the user's last finished sessions that
+// included it, newest first. Each session only carries the sets of that exercise.
+func (r *SessionRepository) GetExerciseHistory(ctx context.Context, userID int, exerciseIDs []int, sessions int) (map[int][]model.Session, error) {
+	result := make(map[int][]model.Session, len(exerciseIDs))
+	if len(exerciseIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.Pool.Query(ctx, `
+		WITH ranked AS (
+			SELECT ws.id AS session_id, ws.workout_id, ws.started_at, ws.finished_at,
+			       ss.id, ss.exercise_id, ss.set_number, ss.reps, ss.weight, ss.rpe, ss.rest_seconds, ss.completed_at,
+			       DENSE_RANK() OVER (PARTITION BY ss.exercise_id ORDER BY ws.started_at DESC, ws.id DESC) AS recency
+			FROM session_sets ss
+			JOIN workout_sessions ws ON ws.id = ss.session_id
+			WHERE ws.user_id = $1 AND ws.finished_at IS NOT NULL AND ss.exercise_id = ANY($2)
+		)
+		SELECT session_id, workout_id, started_at, finished_at,
+		       id, exercise_id, set_number, reps, weight, rpe, rest_seconds, completed_at
+		FROM ranked
+		WHERE recency <= $3
+		ORDER BY exercise_id, recency, set_number
+	`, userID, exerciseIDs, sessions)
+	if err != nil {
+		r.Log.Errorw("failed to get exercise history", "error", err)
+		return nil, fmt.Errorf("get exercise history: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s model.Session
+		var set model.SessionSet
+		if err := rows.Scan(&s.ID, &s.WorkoutID, &s.StartedAt, &s.FinishedAt,
+			&set.ID, &set.ExerciseID, &set.SetNumber, &set.Reps, &set.Weight, &set.RPE, &set.RestSeconds, &set.CompletedAt); err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get exercise history: %w", err)
+		}
+		set.SessionID = s.ID
+
+		history := result[set.ExerciseID]
+		if len(history) == 0 || history[len(history)-1].ID != s.ID {
+			s.UserID = userID
+			history = append(history, s)
+		}
+		last := &history[len(history)-1]
+		last.Sets = append(last.Sets, set)
+		result[set.ExerciseID] = history
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get exercise history: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,179 @@
+package session
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/session"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func setupRepo(t *testing.T, mp *MockPool) *SessionRepository {
+	t.Helper()
+	return &SessionRepository{Pool: mp, Log: zaptest.NewLogger(t).Sugar()}
+}
+
+func TestCreateSession_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	now := time.Now()
+	s := model.Session{UserID: 1, WorkoutID: 2, Notes: "n", StartedAt: now, CreatedAt: now, UpdatedAt: now}
+	mp.On("QueryRow", ctx, mock.Anything, s.UserID, s.WorkoutID, s.Notes, s.StartedAt, s.CreatedAt, s.UpdatedAt).Return(row)
+	row.On("Scan", mock.AnythingOfType("*int")).Run(func(args mock.Arguments) {
+		ptr, ok := args.Get(0).(*int)
+		if !ok {
+			t.Fatal("expected *int as first argument")
+		}
+		*ptr = 11
+	}).Return(nil)
+
+	id, err := setupRepo(t, mp).CreateSession(ctx, s)
+	assert.NoError(t, err)
+	assert.Equal(t, 11, id)
+}
+
+func TestCreateSession_Error(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	s := model.Session{UserID: 1, WorkoutID: 2}
+	mp.On("QueryRow", ctx, mock.Anything, s.UserID, s.WorkoutID, s.Notes, s.StartedAt, s.CreatedAt, s.UpdatedAt).Return(row)
+	row.On("Scan", mock.Anything).Return(errors.New("fail"))
+
+	id, err := setupRepo(t, mp).CreateSession(ctx, s)
+	assert.Error(t, err)
+	assert.Zero(t, id)
+}
+
+func TestGetSessionByID_NotFound(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, 3, 4).Return(row)
+	row.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
+
+	res, err := setupRepo(t, mp).GetSessionByID(ctx, 3, 4)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+	assert.Nil(t, res)
+}
+
+func TestGetSessionByID_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, 3, 4).Return(row)
+	row.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	res, err := setupRepo(t, mp).GetSessionByID(ctx, 3, 4)
+	assert.NoError(t, err)
+	assert.NotNil(t, res)
+}
+
+func TestFinishSession_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	now := time.Now()
+	mp.On("Exec", ctx, mock.Anything, now, (*string)(nil), 5, 6).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+
+	err := setupRepo(t, mp).FinishSession(ctx, 5, 6, now, nil)
+	assert.NoError(t, err)
+}
+
+func TestFinishSession_NoRows(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	now := time.Now()
+	mp.On("Exec", ctx, mock.Anything, now, (*string)(nil), 5, 6).Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+	err := setupRepo(t, mp).FinishSession(ctx, 5, 6, now, nil)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestAddSet_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	set := model.SessionSet{SessionID: 1, ExerciseID: 2, SetNumber: 1, Reps: 5, Weight: 100, CompletedAt: time.Now()}
+	mp.On("QueryRow", ctx, mock.Anything, set.SessionID, set.ExerciseID, set.SetNumber, set.Reps,
+		set.Weight, set.RPE, set.RestSeconds, set.CompletedAt).Return(row)
+	row.On("Scan", mock.AnythingOfType("*int")).Return(nil)
+
+	_, err := setupRepo(t, mp).AddSet(ctx, set)
+	assert.NoError(t, err)
+}
+
+func TestGetSessionSets_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	r := new(MockRow)
+	mp.On("Query", ctx, mock.Anything, 9).Return(r, nil)
+	r.On("Next").Return(true).Once()
+	r.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	r.On("Next").Return(false).Once()
+	r.On("Err").Return(nil)
+	r.On("Close").Return()
+
+	sets, err := setupRepo(t, mp).GetSessionSets(ctx, 9)
+	assert.NoError(t, err)
+	assert.Len(t, sets, 1)
+}
+
+func TestGetSessionsByWorkout_QueryError(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	mp.On("Query", ctx, mock.Anything, 1, 2).Return((*MockRow)(nil), errors.New("qerr"))
+
+	list, err := setupRepo(t, mp).GetSessionsByWorkout(ctx, 1, 2)
+	assert.Error(t, err)
+	assert.Nil(t, list)
+}
+
+func TestGetExerciseHistory_GroupsSetsBySession(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	r := new(MockRow)
+	mp.On("Query", ctx, mock.Anything, 1, []int{3}, 3).Return(r, nil)
+
+	// Two sets of session 20, then one of the older session 10.
+	rowsOf := []struct{ session, set int }{{20, 1}, {20, 2}, {10, 1}}
+	for _, row := range rowsOf {
+		r.On("Next").Return(true).Once()
+		r.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*int) = row.session
+				*args.Get(5).(*int) = 3
+				*args.Get(6).(*int) = row.set
+			}).Return(nil).Once()
+	}
+	r.On("Next").Return(false).Once()
+	r.On("Err").Return(nil)
+	r.On("Close").Return()
+
+	history, err := setupRepo(t, mp).GetExerciseHistory(ctx, 1, []int{3}, 3)
+	assert.NoError(t, err)
+	if assert.Len(t, history[3], 2) {
+		assert.Equal(t, 20, history[3][0].ID)
+		assert.Len(t, history[3][0].Sets, 2)
+		assert.Equal(t, 10, history[3][1].ID)
+		assert.Equal(t, 10, history[3][1].Sets[0].SessionID)
+	}
+}
+
+func TestGetExerciseHistory_NoExercises(t *testing.T) {
+	mp := new(MockPool)
+	history, err := setupRepo(t, mp).GetExerciseHistory(t.Context(), 1, nil, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+	mp.AssertNotCalled(t, "Query")
+}
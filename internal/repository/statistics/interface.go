@@ -0,0 +1,15 @@
+package statistics
+
+import (
+	"context"
+	dto "workout-tracker/internal/dto/statistics"
+	model "workout-tracker/internal/model/statistics"
+)
+
+type StatisticsRepositoryInterface interface {
+	GetCategoryStatistics(ctx context.Context, userID int, filter dto.StatisticsFilter) ([]model.WorkoutStatistics, error)
+	GetExerciseStatistics(ctx context.Context, userID int, filter dto.StatisticsFilter) ([]model.ExerciseStatistics, error)
+	GetPeriodStatistics(ctx context.Context, userID int, bucket model.Bucket, filter dto.StatisticsFilter) ([]model.PeriodStatistics, error)
+}
+
+var _ StatisticsRepositoryInterface = (*StatisticsRepository)(nil)
@@ -0,0 +1,142 @@
+package statistics
+
+import (
+	"context"
+	"fmt"
+	dto "workout-tracker/internal/dto/statistics"
+	model "workout-tracker/internal/model/statistics"
+	"workout-tracker/pkg/db"
+	"workout-tracker/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/dig"
+)
+
+type DBPool interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+type StatisticsRepositoryParams struct {
+	dig.In
+
+	DB  *db.DB
+	Log logger.SugaredLoggerInterface
+}
+
+type StatisticsRepository struct {
+	Pool DBPool
+	Log  logger.SugaredLoggerInterface
+}
+
+func NewStatisticsRepository(params StatisticsRepositoryParams) *StatisticsRepository {
+	return &StatisticsRepository{
+		Pool: params.DB.Pool,
+		Log:  params.Log,
+	}
+}
+
+// setsScope joins performed sets to their session, workout and exercise and applies the user and date filters.
+// $1 is the user id, $2 and $3 the optional inclusive completed_at bounds.
+const setsScope = `
+		FROM session_sets ss
+		JOIN workout_sessions s ON s.id = ss.session_id
+		JOIN workouts w ON w.id = s.workout_id
+		JOIN exercises e ON e.id = ss.exercise_id
+		WHERE s.user_id = $1
+		  AND ($2::timestamptz IS NULL OR ss.completed_at >= $2)
+		  AND ($3::timestamptz IS NULL OR ss.completed_at <= $3)`
+
+func (r *StatisticsRepository) GetCategoryStatistics(
+	ctx context.Context, userID int, filter dto.StatisticsFilter) ([]model.WorkoutStatistics, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT COALESCE(w.category, ''), COALESCE(SUM(ss.weight * ss.reps), 0), COUNT(ss.id), COALESCE(SUM(ss.reps), 0)`+setsScope+`
+		GROUP BY 1
+		ORDER BY 1
+	`, userID, filter.From, filter.To)
+	if err != nil {
+		r.Log.Errorw("failed to get category statistics", "error", err)
+		return nil, fmt.Errorf("get category statistics: %w", err)
+	}
+	defer rows.Close()
+
+	result := []model.WorkoutStatistics{}
+	for rows.Next() {
+		st := model.WorkoutStatistics{UserID: userID}
+		if err := rows.Scan(&st.Category, &st.TotalWeight, &st.TotalSets, &st.TotalReps); err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get category statistics: %w", err)
+		}
+		result = append(result, st)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get category statistics: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *StatisticsRepository) GetExerciseStatistics(
+	ctx context.Context, userID int, filter dto.StatisticsFilter) ([]model.ExerciseStatistics, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT e.id, e.name, COALESCE(SUM(ss.weight * ss.reps), 0), COALESCE(MAX(ss.weight), 0),
+		       COUNT(ss.id), COALESCE(SUM(ss.reps), 0)`+setsScope+`
+		GROUP BY e.id, e.name
+		ORDER BY e.name
+	`, userID, filter.From, filter.To)
+	if err != nil {
+		r.Log.Errorw("failed to get exercise statistics", "error", err)
+		return nil, fmt.Errorf("get exercise statistics: %w", err)
+	}
+	defer rows.Close()
+
+	result := []model.ExerciseStatistics{}
+	for rows.Next() {
+		var st model.ExerciseStatistics
+		if err := rows.Scan(&st.ExerciseID, &st.ExerciseName, &st.TotalWeight, &st.MaxWeight, &st.TotalSets, &st.TotalReps); err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get exercise statistics: %w", err)
+		}
+		result = append(result, st)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get exercise statistics: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *StatisticsRepository) GetPeriodStatistics(
+	ctx context.Context, userID int, bucket model.Bucket, filter dto.StatisticsFilter) ([]model.PeriodStatistics, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT date_trunc($4::text, ss.completed_at) AS period, COALESCE(SUM(ss.weight * ss.reps), 0),
+		       COUNT(DISTINCT ss.session_id), COUNT(ss.id), COALESCE(SUM(ss.reps), 0)`+setsScope+`
+		GROUP BY period
+		ORDER BY period
+	`, userID, filter.From, filter.To, string(bucket))
+	if err != nil {
+		r.Log.Errorw("failed to get period statistics", "error", err)
+		return nil, fmt.Errorf("get period statistics: %w", err)
+	}
+	defer rows.Close()
+
+	result := []model.PeriodStatistics{}
+	for rows.Next() {
+		var st model.PeriodStatistics
+		if err := rows.Scan(&st.PeriodStart, &st.TotalWeight, &st.Sessions, &st.TotalSets, &st.TotalReps); err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get period statistics: %w", err)
+		}
+		result = append(result, st)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get period statistics: %w", err)
+	}
+
+	return result, nil
+}
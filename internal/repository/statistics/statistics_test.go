@@ -0,0 +1,85 @@
+package statistics
+
+import (
+	"errors"
+	"testing"
+	"time"
+	dto "workout-tracker/internal/dto/statistics"
+	model "workout-tracker/internal/model/statistics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func setupRepo(t *testing.T, mp *MockPool) *StatisticsRepository {
+	t.Helper()
+	return &StatisticsRepository{Pool: mp, Log: zaptest.NewLogger(t).Sugar()}
+}
+
+func TestGetCategoryStatistics_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	r := new(MockRow)
+	from := time.Now().Add(-time.Hour)
+	filter := dto.StatisticsFilter{From: &from}
+	mp.On("Query", ctx, mock.Anything, 1, filter.From, filter.To).Return(r, nil)
+	r.On("Next").Return(true).Once()
+	r.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*string) = "legs"
+		*args.Get(1).(*float64) = 1500
+		*args.Get(2).(*int) = 3
+		*args.Get(3).(*int) = 15
+	}).Return(nil).Once()
+	r.On("Next").Return(false).Once()
+	r.On("Err").Return(nil)
+	r.On("Close").Return()
+
+	stats, err := setupRepo(t, mp).GetCategoryStatistics(ctx, 1, filter)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, model.WorkoutStatistics{Category: "legs", TotalWeight: 1500, UserID: 1, TotalSets: 3, TotalReps: 15}, stats[0])
+}
+
+func TestGetCategoryStatistics_QueryError(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	filter := dto.StatisticsFilter{}
+	mp.On("Query", ctx, mock.Anything, 1, filter.From, filter.To).Return((*MockRow)(nil), errors.New("qerr"))
+
+	stats, err := setupRepo(t, mp).GetCategoryStatistics(ctx, 1, filter)
+	assert.Error(t, err)
+	assert.Nil(t, stats)
+}
+
+func TestGetExerciseStatistics_Empty(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	r := new(MockRow)
+	filter := dto.StatisticsFilter{}
+	mp.On("Query", ctx, mock.Anything, 2, filter.From, filter.To).Return(r, nil)
+	r.On("Next").Return(false)
+	r.On("Err").Return(nil)
+	r.On("Close").Return()
+
+	stats, err := setupRepo(t, mp).GetExerciseStatistics(ctx, 2, filter)
+	require.NoError(t, err)
+	assert.Empty(t, stats)
+	assert.NotNil(t, stats)
+}
+
+func TestGetPeriodStatistics_ScanError(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	r := new(MockRow)
+	filter := dto.StatisticsFilter{}
+	mp.On("Query", ctx, mock.Anything, 3, filter.From, filter.To, "week").Return(r, nil)
+	r.On("Next").Return(true)
+	r.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("scan"))
+	r.On("Close").Return()
+
+	stats, err := setupRepo(t, mp).GetPeriodStatistics(ctx, 3, model.BucketWeek, filter)
+	assert.Error(t, err)
+	assert.Nil(t, stats)
+}
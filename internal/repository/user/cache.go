@@ -0,0 +1,240 @@
+package user
+
+import (
+	"context"
+	"sync"
+	"time"
+	"workout-tracker/internal/model/user"
+	"workout-tracker/pkg/cache"
+	"workout-tracker/pkg/db"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// UserCache holds what the auth middleware looks up on every request: the user, most importantly
+// their token version, and whether the session of the access token is still active. A shared
+// implementation lets several instances see each other's invalidations.
+type UserCache interface {
+	Get(ctx context.Context, userID int) (*user.User, bool)
+	Set(ctx context.Context, u *user.User)
+	Invalidate(ctx context.Context, userID int)
+	GetSession(ctx context.Context, sessionID string) (active, ok bool)
+	SetSession(ctx context.Context, sessionID string, active bool)
+	InvalidateSession(ctx context.Context, sessionID string)
+}
+
+// LRUCache is the in-process UserCache.
+type LRUCache struct {
+	LRU      *cache.LRU[int, user.User]
+	Sessions *cache.LRU[string, bool]
+}
+
+var _ UserCache = (*LRUCache)(nil)
+
+func NewLRUCache(size int, ttl time.Duration) *LRUCache {
+	return &LRUCache{
+		LRU:      cache.NewLRU[int, user.User](size, ttl),
+		Sessions: cache.NewLRU[string, bool](size, ttl),
+	}
+}
+
+func (c *LRUCache) Get(ctx context.Context, userID int) (*user.User, bool) {
+	u, ok := c.LRU.Get(userID)
+	if !ok {
+		return nil, false
+	}
+	return &u, true
+}
+
+func (c *LRUCache) Set(ctx context.Context, u *user.User) {
+	c.LRU.Set(u.ID, *u)
+}
+
+func (c *LRUCache) Invalidate(ctx context.Context, userID int) {
+	c.LRU.Delete(userID)
+}
+
+func (c *LRUCache) GetSession(ctx context.Context, sessionID string) (bool, bool) {
+	return c.Sessions.Get(sessionID)
+}
+
+func (c *LRUCache) SetSession(ctx context.Context, sessionID string, active bool) {
+	c.Sessions.Set(sessionID, active)
+}
+
+func (c *LRUCache) InvalidateSession(ctx context.Context, sessionID string) {
+	c.Sessions.Delete(sessionID)
+}
+
+// loads tracks the cache misses that are being read from the database. A row read before an
+// invalidation and stored after it would stay cached for the whole TTL, so such a load is not
+// stored at all.
+type loads[K comparable] struct {
+	mu       sync.Mutex
+	inFlight map[K]*load
+}
+
+type load struct {
+	running       int
+	invalidations uint64
+}
+
+func newLoads[K comparable]() *loads[K] {
+	return &loads[K]{inFlight: make(map[K]*load)}
+}
+
+// start registers a load of key and returns the invalidation count finish compares against.
+func (l *loads[K]) start(key K) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current, ok := l.inFlight[key]
+	if !ok {
+		current = &load{}
+		l.inFlight[key] = current
+	}
+	current.running++
+	return current.invalidations
+}
+
+// finish ends a load of key and runs store, if given, unless key was invalidated since start.
+func (l *loads[K]) finish(key K, started uint64, store func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current := l.inFlight[key]
+	current.running--
+	if current.running == 0 {
+		delete(l.inFlight, key)
+	}
+	if store != nil && current.invalidations == started {
+		store()
+	}
+}
+
+// invalidate marks the loads of key that are running as stale. It has to be called before the
+// cache entry is dropped.
+func (l *loads[K]) invalidate(key K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if current, ok := l.inFlight[key]; ok {
+		current.invalidations++
+	}
+}
+
+// CachedRepository serves GetUserByUserID and SessionActive from Cache and drops an entry whenever
+// the user or the session is written. Inside a transaction the entry is dropped after the commit, so
+// that a concurrent lookup can not cache the old row again.
+type CachedRepository struct {
+	UserRepositoryInterface
+	Cache    UserCache
+	tx       pgx.Tx
+	users    *loads[int]
+	sessions *loads[string]
+}
+
+var _ UserRepositoryInterface = (*CachedRepository)(nil)
+
+func NewCachedRepository(repo UserRepositoryInterface, cache UserCache) *CachedRepository {
+	return &CachedRepository{
+		UserRepositoryInterface: repo,
+		Cache:                   cache,
+		users:                   newLoads[int](),
+		sessions:                newLoads[string](),
+	}
+}
+
+func (r *CachedRepository) WithTx(tx pgx.Tx) UserRepositoryInterface {
+	withTx := *r
+	withTx.UserRepositoryInterface = r.UserRepositoryInterface.WithTx(tx)
+	withTx.tx = tx
+	return &withTx
+}
+
+// dropUser drops the cached user once the write is visible to other connections, see
+// db.AfterCommit.
+func (r *CachedRepository) dropUser(ctx context.Context, userID int) {
+	db.AfterCommit(r.tx, func() {
+		r.users.invalidate(userID)
+		r.Cache.Invalidate(ctx, userID)
+	})
+}
+
+// dropSessions is dropUser for sessions.
+func (r *CachedRepository) dropSessions(ctx context.Context, sessionIDs ...string) {
+	db.AfterCommit(r.tx, func() {
+		for _, sessionID := range sessionIDs {
+			r.sessions.invalidate(sessionID)
+			r.Cache.InvalidateSession(ctx, sessionID)
+		}
+	})
+}
+
+func (r *CachedRepository) GetUserByUserID(ctx context.Context, id int) (*user.User, error) {
+	if u, ok := r.Cache.Get(ctx, id); ok {
+		return u, nil
+	}
+
+	started := r.users.start(id)
+	u, err := r.UserRepositoryInterface.GetUserByUserID(ctx, id)
+	if err != nil {
+		r.users.finish(id, started, nil)
+		return nil, err
+	}
+	r.users.finish(id, started, func() { r.Cache.Set(ctx, u) })
+
+	return u, nil
+}
+
+func (r *CachedRepository) IncrementTokenVersion(ctx context.Context, userID int) error {
+	defer r.dropUser(ctx, userID)
+	return r.UserRepositoryInterface.IncrementTokenVersion(ctx, userID)
+}
+
+func (r *CachedRepository) UpdateProfile(ctx context.Context, p user.Profile) error {
+	defer r.dropUser(ctx, p.ID)
+	return r.UserRepositoryInterface.UpdateProfile(ctx, p)
+}
+
+func (r *CachedRepository) UpdatePassword(ctx context.Context, userID int, password string) error {
+	defer r.dropUser(ctx, userID)
+	return r.UserRepositoryInterface.UpdatePassword(ctx, userID, password)
+}
+
+func (r *CachedRepository) DeleteUser(ctx context.Context, userID int, at time.Time) error {
+	defer r.dropUser(ctx, userID)
+	return r.UserRepositoryInterface.DeleteUser(ctx, userID, at)
+}
+
+func (r *CachedRepository) SessionActive(ctx context.Context, userID int, sessionID string) (bool, error) {
+	if active, ok := r.Cache.GetSession(ctx, sessionID); ok {
+		return active, nil
+	}
+
+	started := r.sessions.start(sessionID)
+	active, err := r.UserRepositoryInterface.SessionActive(ctx, userID, sessionID)
+	if err != nil {
+		r.sessions.finish(sessionID, started, nil)
+		return false, err
+	}
+	r.sessions.finish(sessionID, started, func() { r.Cache.SetSession(ctx, sessionID, active) })
+
+	return active, nil
+}
+
+func (r *CachedRepository) RevokeSession(ctx context.Context, userID int, sessionID string, at time.Time) error {
+	defer r.dropSessions(ctx, sessionID)
+	return r.UserRepositoryInterface.RevokeSession(ctx, userID, sessionID, at)
+}
+
+func (r *CachedRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string, at time.Time) error {
+	defer r.dropSessions(ctx, familyID)
+	return r.UserRepositoryInterface.RevokeRefreshTokenFamily(ctx, familyID, at)
+}
+
+func (r *CachedRepository) RevokeOtherSessions(ctx context.Context, userID int, keepSessionID string, at time.Time) ([]string, error) {
+	revoked, err := r.UserRepositoryInterface.RevokeOtherSessions(ctx, userID, keepSessionID, at)
+	r.dropSessions(ctx, revoked...)
+	return revoked, err
+}
@@ -0,0 +1,191 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+	"workout-tracker/internal/model/user"
+	"workout-tracker/pkg/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type countingRepo struct {
+	UserRepositoryInterface
+	// onLoad runs after a lookup read the row, as a write that commits meanwhile would.
+	onLoad  func()
+	version int
+	lookups int
+	revoked bool
+}
+
+func (r *countingRepo) WithTx(tx pgx.Tx) UserRepositoryInterface { return r }
+
+func (r *countingRepo) GetUserByUserID(ctx context.Context, id int) (*user.User, error) {
+	r.lookups++
+	u := &user.User{ID: id, TokenVersion: r.version}
+	if onLoad := r.onLoad; onLoad != nil {
+		r.onLoad = nil
+		onLoad()
+	}
+	return u, nil
+}
+
+func (r *countingRepo) IncrementTokenVersion(ctx context.Context, userID int) error {
+	r.version++
+	return nil
+}
+
+func (r *countingRepo) UpdateProfile(ctx context.Context, p user.Profile) error { return nil }
+
+func (r *countingRepo) SessionActive(ctx context.Context, userID int, sessionID string) (bool, error) {
+	r.lookups++
+	active := !r.revoked
+	if onLoad := r.onLoad; onLoad != nil {
+		r.onLoad = nil
+		onLoad()
+	}
+	return active, nil
+}
+
+func (r *countingRepo) RevokeSession(ctx context.Context, userID int, sessionID string, at time.Time) error {
+	r.revoked = true
+	return nil
+}
+
+func (r *countingRepo) RevokeOtherSessions(ctx context.Context, userID int, keepSessionID string, at time.Time) ([]string, error) {
+	r.revoked = true
+	return []string{"other"}, nil
+}
+
+func TestCachedRepository_ServesLookupsFromCache(t *testing.T) {
+	inner := &countingRepo{}
+	repo := NewCachedRepository(inner, NewLRUCache(10, time.Minute))
+
+	for range 3 {
+		u, err := repo.GetUserByUserID(t.Context(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, u.ID)
+	}
+	assert.Equal(t, 1, inner.lookups)
+
+	u, _ := repo.GetUserByUserID(t.Context(), 1)
+	u.TokenVersion = 99
+	cached, _ := repo.GetUserByUserID(t.Context(), 1)
+	assert.Equal(t, 0, cached.TokenVersion, "callers get their own copy")
+}
+
+func TestCachedRepository_InvalidatesOnWrites(t *testing.T) {
+	inner := &countingRepo{}
+	repo := NewCachedRepository(inner, NewLRUCache(10, time.Minute))
+	ctx := t.Context()
+
+	_, err := repo.GetUserByUserID(ctx, 1)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.WithTx(nil).IncrementTokenVersion(ctx, 1))
+	u, err := repo.GetUserByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, u.TokenVersion)
+	assert.Equal(t, 2, inner.lookups)
+
+	require.NoError(t, repo.UpdateProfile(ctx, user.Profile{ID: 1}))
+	_, err = repo.GetUserByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, inner.lookups)
+}
+
+func TestCachedRepository_InvalidatesAfterCommit(t *testing.T) {
+	inner := &countingRepo{}
+	repo := NewCachedRepository(inner, NewLRUCache(10, time.Minute))
+	ctx := t.Context()
+
+	pool := new(db.MockPool)
+	tx := new(db.MockTx)
+	pool.On("Begin", ctx).Return(tx, nil)
+	tx.On("Commit", ctx).Return(nil)
+	tx.On("Rollback", ctx).Return(pgx.ErrTxClosed)
+	manager := &db.TxManager{Pool: pool, Log: zap.NewNop().Sugar()}
+
+	_, err := repo.GetUserByUserID(ctx, 1)
+	require.NoError(t, err)
+	err = manager.WithinTx(ctx, func(tx pgx.Tx) error {
+		require.NoError(t, repo.WithTx(tx).IncrementTokenVersion(ctx, 1))
+		_, err := repo.GetUserByUserID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, inner.lookups, "the entry is kept until the commit")
+		return nil
+	})
+	require.NoError(t, err)
+
+	u, err := repo.GetUserByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, u.TokenVersion)
+	assert.Equal(t, 2, inner.lookups)
+}
+
+func TestCachedRepository_KeepsRowsInvalidatedDuringTheLoadOutOfTheCache(t *testing.T) {
+	inner := &countingRepo{}
+	repo := NewCachedRepository(inner, NewLRUCache(10, time.Minute))
+	ctx := t.Context()
+
+	inner.onLoad = func() { require.NoError(t, repo.IncrementTokenVersion(ctx, 1)) }
+	u, err := repo.GetUserByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, u.TokenVersion, "the row was read before the write")
+
+	u, err = repo.GetUserByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, u.TokenVersion, "the stale row was not cached")
+	assert.Equal(t, 2, inner.lookups)
+
+	inner.onLoad = func() { require.NoError(t, repo.RevokeSession(ctx, 1, "family", time.Now())) }
+	active, err := repo.SessionActive(ctx, 1, "family")
+	require.NoError(t, err)
+	assert.True(t, active)
+	active, err = repo.SessionActive(ctx, 1, "family")
+	require.NoError(t, err)
+	assert.False(t, active, "the stale session was not cached")
+
+	assert.Empty(t, repo.users.inFlight)
+	assert.Empty(t, repo.sessions.inFlight)
+}
+
+func TestCachedRepository_Sessions(t *testing.T) {
+	inner := &countingRepo{}
+	repo := NewCachedRepository(inner, NewLRUCache(10, time.Minute))
+	ctx := t.Context()
+
+	for range 2 {
+		active, err := repo.SessionActive(ctx, 1, "family")
+		require.NoError(t, err)
+		assert.True(t, active)
+	}
+	assert.Equal(t, 1, inner.lookups)
+
+	require.NoError(t, repo.RevokeSession(ctx, 1, "family", time.Now()))
+	active, err := repo.SessionActive(ctx, 1, "family")
+	require.NoError(t, err)
+	assert.False(t, active, "a revoked session is looked up again")
+	assert.Equal(t, 2, inner.lookups)
+}
+
+func TestCachedRepository_RevokeOtherSessions(t *testing.T) {
+	inner := &countingRepo{}
+	repo := NewCachedRepository(inner, NewLRUCache(10, time.Minute))
+	ctx := t.Context()
+
+	_, err := repo.SessionActive(ctx, 1, "other")
+	require.NoError(t, err)
+
+	revoked, err := repo.RevokeOtherSessions(ctx, 1, "current", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other"}, revoked)
+	active, err := repo.SessionActive(ctx, 1, "other")
+	require.NoError(t, err)
+	assert.False(t, active, "the revoked session is looked up again")
+	assert.Equal(t, 2, inner.lookups)
+}
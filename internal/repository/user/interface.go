@@ -7,16 +7,28 @@ import (
 	"workout-tracker/internal/model/user/jwt"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 type UserRepositoryInterface interface {
+	WithTx(tx pgx.Tx) UserRepositoryInterface
 	CreateUser(ctx context.Context, u user.User) (int, error)
 	GetUserByUsername(ctx context.Context, username string) (*user.User, error)
 	GetUserByUserID(ctx context.Context, id int) (*user.User, error)
-	StoreRefreshToken(ctx context.Context, token string, userID int, expires time.Time) (uuid.UUID, error)
-	GetRefreshToken(ctx context.Context, token string) (*jwt.RefreshToken, error)
-	DeleteRefreshToken(ctx context.Context, token string) error
+	StoreRefreshToken(ctx context.Context, rt jwt.RefreshToken) (uuid.UUID, error)
+	GetRefreshToken(ctx context.Context, tokenHash string) (*jwt.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, id string, at time.Time) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string, at time.Time) error
+	GetSessions(ctx context.Context, userID int, now time.Time) ([]jwt.Session, error)
+	RevokeSession(ctx context.Context, userID int, sessionID string, at time.Time) error
+	SessionActive(ctx context.Context, userID int, sessionID string) (bool, error)
+	RevokeOtherSessions(ctx context.Context, userID int, keepSessionID string, at time.Time) ([]string, error)
 	IncrementTokenVersion(ctx context.Context, userID int) error
+	GetProfile(ctx context.Context, userID int) (*user.Profile, error)
+	UpdateProfile(ctx context.Context, p user.Profile) error
+	UpdatePassword(ctx context.Context, userID int, password string) error
+	DeleteRefreshTokens(ctx context.Context, userID int) error
+	DeleteUser(ctx context.Context, userID int, at time.Time) error
 }
 
 var _ UserRepositoryInterface = (*UserRepository)(nil)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/model/units"
 	"workout-tracker/internal/model/user"
 	"workout-tracker/internal/model/user/jwt"
 	"workout-tracker/pkg/db"
@@ -42,6 +43,14 @@ func NewRepository(params UserRepositoryParams) *UserRepository {
 	}
 }
 
+// WithTx returns a copy of the repository that runs its queries inside tx.
+func (r *UserRepository) WithTx(tx pgx.Tx) UserRepositoryInterface {
+	return &UserRepository{
+		Pool: tx,
+		Log:  r.Log,
+	}
+}
+
 func (r *UserRepository) CreateUser(ctx context.Context, user user.User) (int, error) {
 	var id int
 
@@ -60,20 +69,27 @@ func (r *UserRepository) CreateUser(ctx context.Context, user user.User) (int, e
 	return id, nil
 }
 
+// userQuery selects a user who has not deleted their account together with the unit system from
+// their preferences.
+const userQuery = `
+	SELECT u.id, u.username, u.password, u.role, u.createdat, u.updatedat, u.token_version,
+	       COALESCE(p.unit_system, '` + string(units.DefaultSystem) + `')
+	FROM users u
+	LEFT JOIN user_preferences p ON p.user_id = u.id
+	WHERE u.deletedat IS NULL`
+
 func (r *UserRepository) GetUserByUsername(ctx context.Context, username string) (*user.User, error) {
-	query := `SELECT id, username, password, role, createdat, updatedat, token_version FROM users WHERE username = $1`
-	return r.getUser(ctx, query, username)
+	return r.getUser(ctx, userQuery+` AND u.username = $1`, username)
 }
 
 func (r *UserRepository) GetUserByUserID(ctx context.Context, id int) (*user.User, error) {
-	query := `SELECT id, username, password, role, createdat, updatedat, token_version FROM users WHERE id = $1`
-	return r.getUser(ctx, query, id)
+	return r.getUser(ctx, userQuery+` AND u.id = $1`, id)
 }
 
 func (r *UserRepository) getUser(ctx context.Context, query string, arg any) (*user.User, error) {
 	var u user.User
 	err := r.Pool.QueryRow(ctx, query, arg).
-		Scan(&u.ID, &u.Username, &u.Password, &u.Role, &u.CreatedAt, &u.UpdatedAt, &u.TokenVersion)
+		Scan(&u.ID, &u.Username, &u.Password, &u.Role, &u.CreatedAt, &u.UpdatedAt, &u.TokenVersion, &u.UnitSystem)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -86,11 +102,19 @@ func (r *UserRepository) getUser(ctx context.Context, query string, arg any) (*u
 	return &u, nil
 }
 
-func (r *UserRepository) StoreRefreshToken(ctx context.Context, token string, userID int, expires time.Time) (uuid.UUID, error) {
+// StoreRefreshToken stores the hashed token and returns its id. A token without a family starts a
+// new one, named after the token's id.
+func (r *UserRepository) StoreRefreshToken(ctx context.Context, rt jwt.RefreshToken) (uuid.UUID, error) {
 	id := uuid.New()
+	familyID := rt.FamilyID
+	if familyID == "" {
+		familyID = id.String()
+	}
 
-	_, err := r.Pool.Exec(ctx, "INSERT INTO refresh_tokens(id, user_id, token, expires_at) VALUES($1, $2, $3, $4)",
-		id, userID, token, expires)
+	_, err := r.Pool.Exec(ctx, `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, device_name, user_agent, ip)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, id, rt.UserID, familyID, rt.TokenHash, rt.ExpiresAt, rt.Name, rt.UserAgent, rt.IP)
 	if err != nil {
 		r.Log.Errorw("error inserting refresh token", erorrs.ErrorKey, err)
 		return uuid.Nil, fmt.Errorf("error inserting refresh token: %w", err)
@@ -99,33 +123,156 @@ func (r *UserRepository) StoreRefreshToken(ctx context.Context, token string, us
 	return id, nil
 }
 
-func (r *UserRepository) GetRefreshToken(ctx context.Context, token string) (*jwt.RefreshToken, error) {
+func (r *UserRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*jwt.RefreshToken, error) {
 	var rt jwt.RefreshToken
 
-	err := r.Pool.QueryRow(ctx, "SELECT id, user_id, token, expires_at, created_at from refresh_tokens WHERE token = $1",
-		token).Scan(&rt.ID, &rt.UserID, &rt.Token, &rt.ExpiresAt, &rt.CreatedAt)
+	err := r.Pool.QueryRow(ctx, `
+		SELECT id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at, device_name, user_agent, ip
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`, tokenHash).Scan(&rt.ID, &rt.UserID, &rt.FamilyID, &rt.TokenHash, &rt.ExpiresAt, &rt.UsedAt, &rt.RevokedAt, &rt.CreatedAt,
+		&rt.Name, &rt.UserAgent, &rt.IP)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			r.Log.Info("refresh token not found", "token", token)
+			r.Log.Info("refresh token not found")
 			return nil, erorrs.ErrTokenNotFound
 		}
 
-		r.Log.Errorw("error getting refresh token", erorrs.ErrorKey, err, "token", token)
+		r.Log.Errorw("error getting refresh token", erorrs.ErrorKey, err)
 		return nil, fmt.Errorf("error getting refresh token: %w", err)
 	}
 
 	return &rt, nil
 }
 
-func (r *UserRepository) DeleteRefreshToken(ctx context.Context, token string) error {
-	_, err := r.Pool.Exec(ctx, `DELETE FROM refresh_tokens WHERE token = $1`, token)
+// MarkRefreshTokenUsed marks the token rotated. It reports false when the token was used already,
+// so of two concurrent rotations only one succeeds.
+func (r *UserRepository) MarkRefreshTokenUsed(ctx context.Context, id string, at time.Time) (bool, error) {
+	tag, err := r.Pool.Exec(ctx, `
+		UPDATE refresh_tokens SET used_at = $2 WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL
+	`, id, at)
+	if err != nil {
+		r.Log.Errorw("error marking refresh token used", erorrs.ErrorKey, err)
+		return false, fmt.Errorf("error marking refresh token used: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// RevokeRefreshTokenFamily revokes every token of the family.
+func (r *UserRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string, at time.Time) error {
+	_, err := r.Pool.Exec(ctx, `
+		UPDATE refresh_tokens SET revoked_at = $2 WHERE family_id = $1 AND revoked_at IS NULL
+	`, familyID, at)
+	if err != nil {
+		r.Log.Errorw("error revoking refresh token family", "familyID", familyID, erorrs.ErrorKey, err)
+		return fmt.Errorf("error revoking refresh token family: %w", err)
+	}
+	return nil
+}
+
+// GetSessions lists the user's token families that still have an active token at now, most recently
+// used first.
+func (r *UserRepository) GetSessions(ctx context.Context, userID int, now time.Time) ([]jwt.Session, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT t.family_id, f.started_at, t.created_at, t.expires_at, t.device_name, t.user_agent, t.ip
+		FROM refresh_tokens t
+		JOIN (
+			SELECT family_id, MIN(created_at) AS started_at
+			FROM refresh_tokens
+			WHERE user_id = $1
+			GROUP BY family_id
+		) f ON f.family_id = t.family_id
+		WHERE t.user_id = $1 AND t.used_at IS NULL AND t.revoked_at IS NULL AND t.expires_at > $2
+		ORDER BY t.created_at DESC
+	`, userID, now)
+	if err != nil {
+		r.Log.Errorw("failed to get sessions", "userID", userID, erorrs.ErrorKey, err)
+		return nil, fmt.Errorf("get sessions: %w", err)
+	}
+	defer rows.Close()
+
+	result := []jwt.Session{}
+	for rows.Next() {
+		var s jwt.Session
+		if err := rows.Scan(&s.ID, &s.CreatedAt, &s.LastUsedAt, &s.ExpiresAt, &s.Name, &s.UserAgent, &s.IP); err != nil {
+			r.Log.Errorw("scan failed", erorrs.ErrorKey, err)
+			return nil, fmt.Errorf("get sessions: %w", err)
+		}
+		result = append(result, s)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", erorrs.ErrorKey, err)
+		return nil, fmt.Errorf("get sessions: %w", err)
+	}
+	return result, nil
+}
+
+// RevokeSession revokes one of the user's token families. It returns ErrNotFound when the user has
+// no such active session.
+func (r *UserRepository) RevokeSession(ctx context.Context, userID int, sessionID string, at time.Time) error {
+	tag, err := r.Pool.Exec(ctx, `
+		UPDATE refresh_tokens SET revoked_at = $3
+		WHERE user_id = $1 AND family_id = $2 AND revoked_at IS NULL
+	`, userID, sessionID, at)
 	if err != nil {
-		r.Log.Errorw("error deleting refresh token", erorrs.ErrorKey, err)
-		return fmt.Errorf("error deleting refresh token: %w", err)
+		r.Log.Errorw("failed to revoke session", "userID", userID, erorrs.ErrorKey, err)
+		return fmt.Errorf("revoke session: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotFound
 	}
 	return nil
 }
 
+// SessionActive reports whether the user's token family sessionID has a token that was not revoked.
+func (r *UserRepository) SessionActive(ctx context.Context, userID int, sessionID string) (bool, error) {
+	var active bool
+	err := r.Pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM refresh_tokens
+			WHERE user_id = $1 AND family_id::text = $2 AND revoked_at IS NULL
+		)
+	`, userID, sessionID).Scan(&active)
+	if err != nil {
+		r.Log.Errorw("failed to check session", "userID", userID, erorrs.ErrorKey, err)
+		return false, fmt.Errorf("check session: %w", err)
+	}
+	return active, nil
+}
+
+// RevokeOtherSessions revokes every token family of the user except keepSessionID and returns the
+// ids of the families it revoked.
+func (r *UserRepository) RevokeOtherSessions(ctx context.Context, userID int, keepSessionID string, at time.Time) ([]string, error) {
+	rows, err := r.Pool.Query(ctx, `
+		WITH revoked AS (
+			UPDATE refresh_tokens SET revoked_at = $3
+			WHERE user_id = $1 AND family_id::text <> $2 AND revoked_at IS NULL
+			RETURNING family_id
+		)
+		SELECT DISTINCT family_id::text FROM revoked
+	`, userID, keepSessionID, at)
+	if err != nil {
+		r.Log.Errorw("failed to revoke other sessions", "userID", userID, erorrs.ErrorKey, err)
+		return nil, fmt.Errorf("revoke other sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var revoked []string
+	for rows.Next() {
+		var familyID string
+		if err := rows.Scan(&familyID); err != nil {
+			r.Log.Errorw("scan failed", erorrs.ErrorKey, err)
+			return nil, fmt.Errorf("revoke other sessions: %w", err)
+		}
+		revoked = append(revoked, familyID)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", erorrs.ErrorKey, err)
+		return nil, fmt.Errorf("revoke other sessions: %w", err)
+	}
+	return revoked, nil
+}
+
 func (r *UserRepository) IncrementTokenVersion(ctx context.Context, userID int) error {
 	_, err := r.Pool.Exec(ctx, `
 		UPDATE users SET token_version = token_version + 1 WHERE id = $1
@@ -137,3 +284,99 @@ func (r *UserRepository) IncrementTokenVersion(ctx context.Context, userID int)
 	}
 	return nil
 }
+
+func (r *UserRepository) GetProfile(ctx context.Context, userID int) (*user.Profile, error) {
+	var p user.Profile
+	err := r.Pool.QueryRow(ctx, `
+		SELECT u.id, u.username, u.display_name, COALESCE(u.email, ''), u.birth_date, u.height_cm,
+		       COALESCE(u.sex, ''), u.timezone, COALESCE(u.avatar_url, ''),
+		       COALESCE(p.unit_system, '`+string(units.DefaultSystem)+`'), u.createdat, u.updatedat
+		FROM users u
+		LEFT JOIN user_preferences p ON p.user_id = u.id
+		WHERE u.id = $1 AND u.deletedat IS NULL
+	`, userID).Scan(&p.ID, &p.Username, &p.DisplayName, &p.Email, &p.BirthDate, &p.HeightCm,
+		&p.Sex, &p.Timezone, &p.AvatarURL, &p.UnitSystem, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, erorrs.ErrUserNotFound
+		}
+		r.Log.Errorw("failed to get profile", "userID", userID, erorrs.ErrorKey, err)
+		return nil, fmt.Errorf("get profile: %w", err)
+	}
+	return &p, nil
+}
+
+// UpdateProfile replaces the user's profile. The unit system is kept with the user's preferences.
+func (r *UserRepository) UpdateProfile(ctx context.Context, p user.Profile) error {
+	tag, err := r.Pool.Exec(ctx, `
+		WITH preferences AS (
+			INSERT INTO user_preferences (user_id, unit_system, updatedat)
+			VALUES ($1, $9, $10)
+			ON CONFLICT (user_id) DO UPDATE SET unit_system = EXCLUDED.unit_system, updatedat = EXCLUDED.updatedat
+		)
+		UPDATE users
+		SET display_name = $2, email = NULLIF($3, ''), birth_date = $4, height_cm = $5, sex = NULLIF($6, ''),
+		    timezone = $7, avatar_url = NULLIF($8, ''), updatedat = $10
+		WHERE id = $1 AND deletedat IS NULL
+	`, p.ID, p.DisplayName, p.Email, p.BirthDate, p.HeightCm, string(p.Sex), p.Timezone, p.AvatarURL,
+		string(p.UnitSystem), p.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return erorrs.ErrEmailAlreadyExists
+		}
+		r.Log.Errorw("failed to update profile", "userID", p.ID, erorrs.ErrorKey, err)
+		return fmt.Errorf("update profile: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID int, password string) error {
+	tag, err := r.Pool.Exec(ctx, `
+		UPDATE users SET password = $2, updatedat = NOW() WHERE id = $1 AND deletedat IS NULL
+	`, userID, password)
+	if err != nil {
+		r.Log.Errorw("failed to update password", "userID", userID, erorrs.ErrorKey, err)
+		return fmt.Errorf("update password: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrUserNotFound
+	}
+	return nil
+}
+
+// DeleteRefreshTokens revokes every refresh token of the user.
+func (r *UserRepository) DeleteRefreshTokens(ctx context.Context, userID int) error {
+	_, err := r.Pool.Exec(ctx, `DELETE FROM refresh_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		r.Log.Errorw("failed to delete refresh tokens", "userID", userID, erorrs.ErrorKey, err)
+		return fmt.Errorf("delete refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// DeleteUser soft-deletes the user together with their workouts and revokes their refresh tokens
+// and calendar feed. The rows are kept, but the user can no longer sign in.
+func (r *UserRepository) DeleteUser(ctx context.Context, userID int, at time.Time) error {
+	tag, err := r.Pool.Exec(ctx, `
+		WITH workouts AS (
+			UPDATE workouts SET deletedat = $2 WHERE user_id = $1 AND deletedat IS NULL
+		), refresh AS (
+			DELETE FROM refresh_tokens WHERE user_id = $1
+		), feed AS (
+			DELETE FROM calendar_tokens WHERE user_id = $1
+		)
+		UPDATE users SET deletedat = $2, updatedat = $2 WHERE id = $1 AND deletedat IS NULL
+	`, userID, at)
+	if err != nil {
+		r.Log.Errorw("failed to delete user", "userID", userID, erorrs.ErrorKey, err)
+		return fmt.Errorf("delete user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrUserNotFound
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 	"workout-tracker/internal/erorrs"
 	"workout-tracker/internal/model/user"
+	"workout-tracker/internal/model/user/jwt"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -105,7 +106,8 @@ func TestGetUserByUsername_NotFound(t *testing.T) {
 	username := "nouser"
 
 	mockPool.On("QueryRow", ctx, mock.Anything, username).Return(mockRow)
-	mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+	mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything).
 		Return(pgx.ErrNoRows)
 
 	repo := &UserRepository{Pool: mockPool, Log: log}
@@ -124,7 +126,8 @@ func TestGetUserByUserID_Error(t *testing.T) {
 	userID := 123
 	testErr := errors.New("some db error")
 	mockPool.On("QueryRow", ctx, mock.Anything, userID).Return(mockRow)
-	mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+	mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything).
 		Return(testErr)
 
 	repo := &UserRepository{Pool: mockPool, Log: log}
@@ -140,18 +143,27 @@ func TestStoreRefreshToken_Success(t *testing.T) {
 	mockPool := new(MockPool)
 	log := zaptest.NewLogger(t).Sugar()
 
-	token := "some-token"
+	hash := "some-hash"
 	userID := 123
 	expires := time.Now().Add(time.Hour)
 
-	mockPool.On("Exec", ctx, mock.Anything, mock.AnythingOfType("uuid.UUID"), userID, token, expires).
+	var family string
+	mockPool.On("Exec", ctx, mock.Anything, mock.AnythingOfType("uuid.UUID"), userID, mock.AnythingOfType("string"), hash, expires,
+		"Pixel", "okhttp", "10.0.0.1").
+		Run(func(args mock.Arguments) { family = args.String(4) }).
 		Return(pgconn.NewCommandTag(""), nil)
 
 	repo := &UserRepository{Pool: mockPool, Log: log}
-	id, err := repo.StoreRefreshToken(ctx, token, userID, expires)
+	id, err := repo.StoreRefreshToken(ctx, jwt.RefreshToken{
+		TokenHash: hash,
+		UserID:    userID,
+		ExpiresAt: expires,
+		Device:    jwt.Device{Name: "Pixel", UserAgent: "okhttp", IP: "10.0.0.1"},
+	})
 
 	assert.NoError(t, err)
 	assert.NotEqual(t, uuid.Nil, id)
+	assert.Equal(t, id.String(), family, "a token without a family starts one")
 }
 
 func TestGetRefreshToken_TokenNotFound(t *testing.T) {
@@ -162,7 +174,8 @@ func TestGetRefreshToken_TokenNotFound(t *testing.T) {
 
 	token := "missing-token"
 	mockPool.On("QueryRow", ctx, mock.Anything, token).Return(mockRow)
-	mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+	mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(pgx.ErrNoRows)
 
 	repo := &UserRepository{Pool: mockPool, Log: log}
@@ -172,21 +185,19 @@ func TestGetRefreshToken_TokenNotFound(t *testing.T) {
 	assert.ErrorIs(t, err, erorrs.ErrTokenNotFound)
 }
 
-func TestDeleteRefreshToken_Error(t *testing.T) {
+func TestMarkRefreshTokenUsed_AlreadyUsed(t *testing.T) {
 	ctx := t.Context()
 	mockPool := new(MockPool)
 	log := zaptest.NewLogger(t).Sugar()
 
-	token := "tok"
-	testErr := errors.New("db error")
-
-	mockPool.On("Exec", ctx, mock.Anything, token).Return(pgconn.NewCommandTag(""), testErr)
+	at := time.Now()
+	mockPool.On("Exec", ctx, mock.Anything, "token-id", at).Return(pgconn.NewCommandTag("UPDATE 0"), nil)
 
 	repo := &UserRepository{Pool: mockPool, Log: log}
-	err := repo.DeleteRefreshToken(ctx, token)
+	fresh, err := repo.MarkRefreshTokenUsed(ctx, "token-id", at)
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "error deleting refresh token: db error")
+	assert.NoError(t, err)
+	assert.False(t, fresh)
 }
 
 func TestIncrementTokenVersion_Error(t *testing.T) {
@@ -205,3 +216,66 @@ func TestIncrementTokenVersion_Error(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "increment token version")
 }
+
+func TestUpdateProfile_EmailTaken(t *testing.T) {
+	ctx := t.Context()
+	mockPool := new(MockPool)
+	log := zaptest.NewLogger(t).Sugar()
+
+	profile := user.Profile{ID: 5, Email: "a@example.com", Timezone: "UTC", UnitSystem: "metric"}
+	mockPool.On("Exec", ctx, mock.Anything, 5, "", "a@example.com", profile.BirthDate, profile.HeightCm, "", "UTC", "",
+		"metric", profile.UpdatedAt).
+		Return(pgconn.NewCommandTag(""), &pgconn.PgError{Code: "23505"})
+
+	repo := &UserRepository{Pool: mockPool, Log: log}
+	err := repo.UpdateProfile(ctx, profile)
+
+	assert.ErrorIs(t, err, erorrs.ErrEmailAlreadyExists)
+}
+
+func TestDeleteUser_NotFound(t *testing.T) {
+	ctx := t.Context()
+	mockPool := new(MockPool)
+	log := zaptest.NewLogger(t).Sugar()
+
+	at := time.Now()
+	mockPool.On("Exec", ctx, mock.Anything, 9, at).Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+	repo := &UserRepository{Pool: mockPool, Log: log}
+	err := repo.DeleteUser(ctx, 9, at)
+
+	assert.ErrorIs(t, err, erorrs.ErrUserNotFound)
+}
+
+func TestRevokeSession_NotFound(t *testing.T) {
+	ctx := t.Context()
+	mockPool := new(MockPool)
+	log := zaptest.NewLogger(t).Sugar()
+
+	at := time.Now()
+	mockPool.On("Exec", ctx, mock.Anything, 4, "family", at).Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+	repo := &UserRepository{Pool: mockPool, Log: log}
+	err := repo.RevokeSession(ctx, 4, "family", at)
+
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestSessionActive(t *testing.T) {
+	ctx := t.Context()
+	mockPool := new(MockPool)
+	mockRow := new(MockRow)
+	log := zaptest.NewLogger(t).Sugar()
+
+	mockPool.On("QueryRow", ctx, mock.Anything, 4, "family").Return(mockRow)
+	mockRow.On("Scan", mock.AnythingOfType("*bool")).Run(func(args mock.Arguments) {
+		*args.Get(0).(*bool) = true
+	}).Return(nil)
+
+	repo := &UserRepository{Pool: mockPool, Log: log}
+	active, err := repo.SessionActive(ctx, 4, "family")
+
+	assert.NoError(t, err)
+	assert.True(t, active)
+	mockPool.AssertExpectations(t)
+}
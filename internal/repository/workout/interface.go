@@ -2,20 +2,31 @@ package workout
 
 import (
 	"context"
+	dto "workout-tracker/internal/dto/workout"
 	model "workout-tracker/internal/model/workout"
 	"workout-tracker/internal/model/workoutexercisejoin"
+
+	"github.com/jackc/pgx/v5"
 )
 
 type WorkoutRepositoryInterface interface {
+	WithTx(tx pgx.Tx) WorkoutRepositoryInterface
 	CreateWorkout(ctx context.Context, w model.Workout) (int, error)
 	UpdateWorkout(ctx context.Context, w model.Workout) error
 	DeleteWorkout(ctx context.Context, workoutID, userID int) error
 	GetWorkoutByID(ctx context.Context, workoutID, userID int) (*model.Workout, error)
+	GetTemplateByID(ctx context.Context, workoutID, userID int) (*model.Workout, error)
+	GetWorkoutOwner(ctx context.Context, workoutID int) (int, error)
+	BulkInsertWorkoutBlocks(ctx context.Context, blocks []workoutexercisejoin.Block) error
 	BulkInsertWorkoutExercises(ctx context.Context, list []workoutexercisejoin.WorkoutExercise) error
+	BulkInsertSetPrescriptions(ctx context.Context, sets []workoutexercisejoin.SetPrescription) error
 	DeleteWorkoutExercises(ctx context.Context, workoutID int) error
 	GetWorkoutExercises(ctx context.Context, workoutID int) ([]workoutexercisejoin.WorkoutExercise, error)
-	GetAllWorkouts(ctx context.Context, userID int) ([]model.Workout, error)
-	UpdateWorkoutPhoto(ctx context.Context, workoutID int, path string) error
+	GetSetPrescriptions(ctx context.Context, workoutID int) ([]workoutexercisejoin.SetPrescription, error)
+	GetAllWorkouts(ctx context.Context, userID int, filter dto.WorkoutFilter, after *dto.WorkoutCursor) ([]model.Workout, error)
+	GetExercisesByWorkoutIDs(ctx context.Context, workoutIDs []int) (map[int][]workoutexercisejoin.WorkoutExercise, error)
+	GetBlocksByWorkoutIDs(ctx context.Context, workoutIDs []int) (map[int][]workoutexercisejoin.Block, error)
+	UpdateWorkoutPhoto(ctx context.Context, workoutID, userID int, path string) error
 }
 
 var _ WorkoutRepositoryInterface = (*WorkoutRepository)(nil)
@@ -36,6 +36,11 @@ func (m *MockPool) Exec(ctx context.Context, sql string, args ...interface{}) (p
 	return called.Get(0).(pgconn.CommandTag), called.Error(1)
 }
 
+func (m *MockPool) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	called := m.Called(ctx, tableName, columnNames, rowSrc)
+	return called.Get(0).(int64), called.Error(1)
+}
+
 type MockRow struct {
 	mock.Mock
 }
@@ -74,7 +79,7 @@ func (m *MockRow) Conn() *pgx.Conn {
 
 func (m *MockRow) Err() error {
 	args := m.Called()
-	return fmt.Errorf("mock error: %w", args.Error(0))
+	return args.Error(0)
 }
 
 func (m *MockRow) RawValues() [][]byte {
@@ -2,8 +2,12 @@ package workout
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
+	dto "workout-tracker/internal/dto/workout"
+	"workout-tracker/internal/erorrs"
 	model "workout-tracker/internal/model/workout"
 	"workout-tracker/internal/model/workoutexercisejoin"
 	"workout-tracker/pkg/logger"
@@ -18,6 +22,7 @@ type DBPool interface {
 	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
 	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
 	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
 }
 
 type WorkoutRepositoryParams struct {
@@ -39,11 +44,19 @@ func NewWorkoutRepository(params WorkoutRepositoryParams) WorkoutRepositoryInter
 	}
 }
 
+// WithTx returns a copy of the repository that runs its queries inside tx.
+func (r *WorkoutRepository) WithTx(tx pgx.Tx) WorkoutRepositoryInterface {
+	return &WorkoutRepository{
+		Log:  r.Log,
+		Pool: tx,
+	}
+}
+
 func (r *WorkoutRepository) CreateWorkout(ctx context.Context, input model.Workout) (int, error) {
 	var id int
 	err := r.Pool.QueryRow(ctx, `
 		INSERT INTO workouts (user_id, name ,title, category, createdat, updatedat)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
 		RETURNING id
 	`, input.UserID, input.Name, input.Title, input.Category, input.CreatedAt, input.UpdatedAt).Scan(&id)
 
@@ -55,9 +68,9 @@ func (r *WorkoutRepository) CreateWorkout(ctx context.Context, input model.Worko
 }
 
 func (r *WorkoutRepository) UpdateWorkout(ctx context.Context, workout model.Workout) error {
-	_, err := r.Pool.Exec(ctx, `
+	tag, err := r.Pool.Exec(ctx, `
 		UPDATE workouts
-		SET title = $1, category = $2, updatedat = $3, name = $4
+		SET title = $1, category = NULLIF($2, ''), updatedat = $3, name = $4
 		WHERE id = $5 AND user_id = $6 AND deletedat IS NULL
 	`, workout.Title, workout.Category, workout.UpdatedAt, workout.Name, workout.ID, workout.UserID)
 
@@ -65,13 +78,16 @@ func (r *WorkoutRepository) UpdateWorkout(ctx context.Context, workout model.Wor
 		r.Log.Errorw("failed to update workout", "error", err)
 		return fmt.Errorf("update workout: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotFound
+	}
 	return nil
 }
 
 func (r *WorkoutRepository) DeleteWorkout(ctx context.Context, workoutID int, userID int) error {
 	now := time.Now()
 
-	_, err := r.Pool.Exec(ctx, `
+	tag, err := r.Pool.Exec(ctx, `
 		UPDATE workouts
 		SET deletedat = $1
 		WHERE id = $2 AND user_id = $3 AND deletedat IS NULL
@@ -81,16 +97,28 @@ func (r *WorkoutRepository) DeleteWorkout(ctx context.Context, workoutID int, us
 		r.Log.Errorw("failed to soft delete workout", "error", err)
 		return fmt.Errorf("soft delete workout: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotFound
+	}
 	return nil
 }
 
 func (r *WorkoutRepository) GetWorkoutByID(ctx context.Context, workoutID int, userID int) (*model.Workout, error) {
+	return r.getWorkout(ctx, workoutID, userID, "AND deletedat IS NULL")
+}
+
+// GetTemplateByID is GetWorkoutByID for the workouts of a program. It also finds workouts their
+// author has deleted since, which the program's users keep following.
+func (r *WorkoutRepository) GetTemplateByID(ctx context.Context, workoutID int, userID int) (*model.Workout, error) {
+	return r.getWorkout(ctx, workoutID, userID, "")
+}
+
+func (r *WorkoutRepository) getWorkout(ctx context.Context, workoutID, userID int, filter string) (*model.Workout, error) {
 	var w model.Workout
 	err := r.Pool.QueryRow(ctx, `
-		SELECT id, user_id, name, title, category, photo_path, createdat, updatedat
+		SELECT id, user_id, name, title, COALESCE(category, ''), photo_path, createdat, updatedat
 		FROM workouts
-		WHERE id = $1 AND user_id = $2 AND deletedat IS NULL
-	`, workoutID, userID).Scan(
+		WHERE id = $1 AND user_id = $2 `+filter, workoutID, userID).Scan(
 		&w.ID,
 		&w.UserID,
 		&w.Name,
@@ -101,36 +129,79 @@ func (r *WorkoutRepository) GetWorkoutByID(ctx context.Context, workoutID int, u
 		&w.UpdatedAt,
 	)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, erorrs.ErrNotFound
+		}
 		r.Log.Errorw("failed to get workout", "error", err)
 		return nil, fmt.Errorf("get workout: %w", err)
 	}
 	return &w, nil
 }
 
-func (r *WorkoutRepository) BulkInsertWorkoutExercises(ctx context.Context, list []workoutexercisejoin.WorkoutExercise) error {
-	query := `
-		INSERT INTO workout_exercise (workout_id, exercise_id, reps, sets)
-		VALUES ($1, $2, $3, $4)
-	`
-
-	for _, item := range list {
-		_, err := r.Pool.Exec(ctx, query,
-			item.WorkoutID,
-			item.ExerciseID,
-			item.Reps,
-			item.Sets,
-		)
-		if err != nil {
-			r.Log.Errorw("failed to insert workout exercise", "error", err)
-			return fmt.Errorf("insert workout exercise: %w", err)
+// GetWorkoutOwner returns the id of the user owning a non-deleted workout.
+func (r *WorkoutRepository) GetWorkoutOwner(ctx context.Context, workoutID int) (int, error) {
+	var userID int
+	err := r.Pool.QueryRow(ctx, `
+		SELECT user_id FROM workouts WHERE id = $1 AND deletedat IS NULL
+	`, workoutID).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, erorrs.ErrNotFound
 		}
+		r.Log.Errorw("failed to get workout owner", "error", err)
+		return 0, fmt.Errorf("get workout owner: %w", err)
+	}
+	return userID, nil
+}
+
+// BulkInsertWorkoutBlocks writes all blocks with a single COPY. The blocks must be stored before
+// their exercises.
+func (r *WorkoutRepository) BulkInsertWorkoutBlocks(ctx context.Context, blocks []workoutexercisejoin.Block) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	_, err := r.Pool.CopyFrom(ctx,
+		pgx.Identifier{"workout_blocks"},
+		[]string{"workout_id", "position", "block_type", "rounds", "rest_seconds"},
+		pgx.CopyFromSlice(len(blocks), func(i int) ([]any, error) {
+			b := blocks[i]
+			return []any{b.WorkoutID, b.Position, string(b.Type), b.Rounds, b.RestSeconds}, nil
+		}),
+	)
+	if err != nil {
+		r.Log.Errorw("failed to insert workout blocks", "error", err)
+		return fmt.Errorf("insert workout blocks: %w", err)
 	}
 	return nil
 }
 
+// BulkInsertWorkoutExercises writes all rows with a single COPY.
+func (r *WorkoutRepository) BulkInsertWorkoutExercises(ctx context.Context, list []workoutexercisejoin.WorkoutExercise) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	_, err := r.Pool.CopyFrom(ctx,
+		pgx.Identifier{"workout_exercise"},
+		[]string{"workout_id", "exercise_id", "position", "block_position", "reps", "sets"},
+		pgx.CopyFromSlice(len(list), func(i int) ([]any, error) {
+			we := list[i]
+			return []any{we.WorkoutID, we.ExerciseID, we.Position, we.BlockPosition, we.Reps, we.Sets}, nil
+		}),
+	)
+	if err != nil {
+		r.Log.Errorw("failed to insert workout exercises", "error", err)
+		return fmt.Errorf("insert workout exercises: %w", err)
+	}
+	return nil
+}
+
+// DeleteWorkoutExercises removes the workout's blocks; their exercises and set prescriptions go with
+// them through the foreign keys.
 func (r *WorkoutRepository) DeleteWorkoutExercises(ctx context.Context, workoutID int) error {
 	_, err := r.Pool.Exec(ctx, `
-		DELETE FROM workout_exercise WHERE workout_id = $1
+		DELETE FROM workout_blocks WHERE workout_id = $1
 	`, workoutID)
 
 	if err != nil {
@@ -140,11 +211,69 @@ func (r *WorkoutRepository) DeleteWorkoutExercises(ctx context.Context, workoutI
 	return nil
 }
 
+// BulkInsertSetPrescriptions writes the per-set targets of a workout's exercises with a single COPY.
+func (r *WorkoutRepository) BulkInsertSetPrescriptions(ctx context.Context, sets []workoutexercisejoin.SetPrescription) error {
+	if len(sets) == 0 {
+		return nil
+	}
+
+	_, err := r.Pool.CopyFrom(ctx,
+		pgx.Identifier{"workout_exercise_sets"},
+		[]string{"workout_id", "exercise_position", "set_number", "set_type",
+			"reps", "reps_max", "weight", "percent_1rm", "rpe", "rest_seconds"},
+		pgx.CopyFromSlice(len(sets), func(i int) ([]any, error) {
+			s := sets[i]
+			return []any{s.WorkoutID, s.ExercisePosition, s.SetNumber, string(s.Type),
+				s.Reps, s.RepsMax, s.Weight, s.PercentOneRM, s.RPE, s.RestSeconds}, nil
+		}),
+	)
+	if err != nil {
+		r.Log.Errorw("failed to insert set prescriptions", "error", err)
+		return fmt.Errorf("insert set prescriptions: %w", err)
+	}
+	return nil
+}
+
+// GetSetPrescriptions returns the per-set targets of a workout ordered by exercise and set.
+func (r *WorkoutRepository) GetSetPrescriptions(ctx context.Context, workoutID int) ([]workoutexercisejoin.SetPrescription, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT workout_id, exercise_position, set_number, set_type,
+		       reps, reps_max, weight, percent_1rm, rpe, rest_seconds
+		FROM workout_exercise_sets
+		WHERE workout_id = $1
+		ORDER BY exercise_position, set_number
+	`, workoutID)
+	if err != nil {
+		r.Log.Errorw("failed to get set prescriptions", "error", err)
+		return nil, fmt.Errorf("get set prescriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var sets []workoutexercisejoin.SetPrescription
+	for rows.Next() {
+		var s workoutexercisejoin.SetPrescription
+		if err := rows.Scan(&s.WorkoutID, &s.ExercisePosition, &s.SetNumber, &s.Type,
+			&s.Reps, &s.RepsMax, &s.Weight, &s.PercentOneRM, &s.RPE, &s.RestSeconds); err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get set prescriptions: %w", err)
+		}
+		sets = append(sets, s)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get set prescriptions: %w", err)
+	}
+
+	return sets, nil
+}
+
+// GetWorkoutExercises returns the workout's exercises in order.
 func (r *WorkoutRepository) GetWorkoutExercises(ctx context.Context, workoutID int) ([]workoutexercisejoin.WorkoutExercise, error) {
 	rows, err := r.Pool.Query(ctx, `
-		SELECT workout_id, exercise_id, reps, sets
+		SELECT workout_id, exercise_id, position, block_position, reps, sets
 		FROM workout_exercise
 		WHERE workout_id = $1
+		ORDER BY position
 	`, workoutID)
 
 	if err != nil {
@@ -156,7 +285,7 @@ func (r *WorkoutRepository) GetWorkoutExercises(ctx context.Context, workoutID i
 	var list []workoutexercisejoin.WorkoutExercise
 	for rows.Next() {
 		var we workoutexercisejoin.WorkoutExercise
-		if err := rows.Scan(&we.WorkoutID, &we.ExerciseID, &we.Reps, &we.Sets); err != nil {
+		if err := rows.Scan(&we.WorkoutID, &we.ExerciseID, &we.Position, &we.BlockPosition, &we.Reps, &we.Sets); err != nil {
 			r.Log.Errorw("scan failed", "error", err)
 			return nil, fmt.Errorf("get workout exercises: %w", err)
 		}
@@ -166,20 +295,100 @@ func (r *WorkoutRepository) GetWorkoutExercises(ctx context.Context, workoutID i
 	return list, nil
 }
 
-func (r *WorkoutRepository) GetAllWorkouts(ctx context.Context, userID int) ([]model.Workout, error) {
+// GetBlocksByWorkoutIDs loads the blocks of several workouts in one query, keyed by workout id and
+// ordered by position. The blocks' Exercises are left empty.
+func (r *WorkoutRepository) GetBlocksByWorkoutIDs(ctx context.Context, workoutIDs []int) (map[int][]workoutexercisejoin.Block, error) {
+	result := make(map[int][]workoutexercisejoin.Block, len(workoutIDs))
+	if len(workoutIDs) == 0 {
+		return result, nil
+	}
+
 	rows, err := r.Pool.Query(ctx, `
-		SELECT id, user_id, name, title, category, photo_path, createdat, updatedat
+		SELECT workout_id, position, block_type, rounds, rest_seconds
+		FROM workout_blocks
+		WHERE workout_id = ANY($1)
+		ORDER BY workout_id, position
+	`, workoutIDs)
+	if err != nil {
+		r.Log.Errorw("failed to get workout blocks", "error", err)
+		return nil, fmt.Errorf("get workout blocks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b workoutexercisejoin.Block
+		if err := rows.Scan(&b.WorkoutID, &b.Position, &b.Type, &b.Rounds, &b.RestSeconds); err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get workout blocks: %w", err)
+		}
+		result[b.WorkoutID] = append(result[b.WorkoutID], b)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get workout blocks: %w", err)
+	}
+
+	return result, nil
+}
+
+// sortColumns maps the public sort fields to their column and the type the cursor value is cast to.
+var sortColumns = map[model.SortField][2]string{
+	model.SortByCreatedAt: {"createdat", "timestamptz"},
+	model.SortByUpdatedAt: {"updatedat", "timestamptz"},
+	model.SortByName:      {"name", "text"},
+	model.SortByTitle:     {"title", "text"},
+}
+
+// likeEscaper escapes LIKE wildcards so a search term is matched literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// GetAllWorkouts returns at most filter.Limit workouts matching the filter, ordered by the requested
+// field with the id as tie-breaker. When after is set only workouts following that cursor are returned.
+func (r *WorkoutRepository) GetAllWorkouts(
+	ctx context.Context, userID int, filter dto.WorkoutFilter, after *dto.WorkoutCursor) ([]model.Workout, error) {
+	column, ok := sortColumns[filter.SortBy]
+	if !ok {
+		column = sortColumns[model.SortByCreatedAt]
+	}
+	direction, comparison := "DESC", "<"
+	if filter.Order == model.OrderAsc {
+		direction, comparison = "ASC", ">"
+	}
+
+	var cursorValue *string
+	var cursorID int
+	if after != nil {
+		cursorValue = &after.Value
+		cursorID = after.ID
+	}
+
+	var pattern string
+	if filter.Search != "" {
+		pattern = "%" + likeEscaper.Replace(filter.Search) + "%"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, user_id, name, title, COALESCE(category, ''), photo_path, createdat, updatedat
 		FROM workouts
 		WHERE user_id = $1 AND deletedat IS NULL
-		ORDER BY createdat DESC
-	`, userID)
+		  AND ($2 = '' OR category = $2)
+		  AND ($3 = '' OR name ILIKE $3 OR title ILIKE $3)
+		  AND ($4::timestamptz IS NULL OR createdat >= $4)
+		  AND ($5::timestamptz IS NULL OR createdat <= $5)
+		  AND ($6::text IS NULL OR (%[1]s, id) %[3]s ($6::%[2]s, $7))
+		ORDER BY %[1]s %[4]s, id %[4]s
+		LIMIT $8
+	`, column[0], column[1], comparison, direction)
+
+	rows, err := r.Pool.Query(ctx, query,
+		userID, filter.Category, pattern, filter.From, filter.To, cursorValue, cursorID, filter.Limit)
 	if err != nil {
 		r.Log.Errorw("failed to fetch workouts", "error", err)
 		return nil, fmt.Errorf("get workouts: %w", err)
 	}
 	defer rows.Close()
 
-	var workouts []model.Workout
+	workouts := []model.Workout{}
 	for rows.Next() {
 		var w model.Workout
 		if err := rows.Scan(&w.ID, &w.UserID, &w.Name, &w.Title, &w.Category, &w.PhotoPath, &w.CreatedAt, &w.UpdatedAt); err != nil {
@@ -187,20 +396,63 @@ func (r *WorkoutRepository) GetAllWorkouts(ctx context.Context, userID int) ([]m
 		}
 		workouts = append(workouts, w)
 	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get workouts: %w", err)
+	}
 
 	return workouts, nil
 }
 
-func (r *WorkoutRepository) UpdateWorkoutPhoto(ctx context.Context, workoutID int, path string) error {
-	_, err := r.Pool.Exec(ctx, `
+// GetExercisesByWorkoutIDs loads the exercises of several workouts in one query, keyed by workout id.
+func (r *WorkoutRepository) GetExercisesByWorkoutIDs(
+	ctx context.Context, workoutIDs []int) (map[int][]workoutexercisejoin.WorkoutExercise, error) {
+	result := make(map[int][]workoutexercisejoin.WorkoutExercise, len(workoutIDs))
+	if len(workoutIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.Pool.Query(ctx, `
+		SELECT workout_id, exercise_id, position, block_position, reps, sets
+		FROM workout_exercise
+		WHERE workout_id = ANY($1)
+		ORDER BY workout_id, position
+	`, workoutIDs)
+	if err != nil {
+		r.Log.Errorw("failed to get workout exercises", "error", err)
+		return nil, fmt.Errorf("get workout exercises: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var we workoutexercisejoin.WorkoutExercise
+		if err := rows.Scan(&we.WorkoutID, &we.ExerciseID, &we.Position, &we.BlockPosition, &we.Reps, &we.Sets); err != nil {
+			r.Log.Errorw("scan failed", "error", err)
+			return nil, fmt.Errorf("get workout exercises: %w", err)
+		}
+		result[we.WorkoutID] = append(result[we.WorkoutID], we)
+	}
+	if err := rows.Err(); err != nil {
+		r.Log.Errorw("row iteration error", "error", err)
+		return nil, fmt.Errorf("get workout exercises: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *WorkoutRepository) UpdateWorkoutPhoto(ctx context.Context, workoutID, userID int, path string) error {
+	tag, err := r.Pool.Exec(ctx, `
 		UPDATE workouts
 		SET photo_path = $1, updatedat = NOW()
-		WHERE id = $2
-	`, path, workoutID)
+		WHERE id = $2 AND user_id = $3 AND deletedat IS NULL
+	`, path, workoutID, userID)
 	if err != nil {
 		r.Log.Errorw("failed to update workout photo", "error", err)
 		return fmt.Errorf("update workout photo: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return erorrs.ErrNotFound
+	}
 
 	return nil
 }
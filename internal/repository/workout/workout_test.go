@@ -2,16 +2,21 @@ package workout
 
 import (
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap/zaptest"
 
+	dto "workout-tracker/internal/dto/workout"
+	"workout-tracker/internal/erorrs"
 	model "workout-tracker/internal/model/workout"
 	we "workout-tracker/internal/model/workoutexercisejoin"
+	"workout-tracker/pkg/db"
 )
 
 func setupRepo(mockPool *MockPool) *WorkoutRepository {
@@ -60,13 +65,25 @@ func TestUpdateWorkout_Success(t *testing.T) {
 	mp := new(MockPool)
 	w := model.Workout{ID: 10, UserID: 3, Name: "n", Title: "tt", Category: "cc", UpdatedAt: time.Now()}
 	mp.On("Exec", ctx, mock.Anything,
-		w.Title, w.Category, w.UpdatedAt, w.Name, w.ID, w.UserID).Return(pgconn.NewCommandTag(""), nil)
+		w.Title, w.Category, w.UpdatedAt, w.Name, w.ID, w.UserID).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
 
 	repo := setupRepo(mp)
 	err := repo.UpdateWorkout(ctx, w)
 	assert.NoError(t, err)
 }
 
+func TestUpdateWorkout_OtherUser(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	w := model.Workout{ID: 10, UserID: 99, Name: "n", UpdatedAt: time.Now()}
+	mp.On("Exec", ctx, mock.Anything,
+		w.Title, w.Category, w.UpdatedAt, w.Name, w.ID, w.UserID).Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+	repo := setupRepo(mp)
+	err := repo.UpdateWorkout(ctx, w)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
 func TestUpdateWorkout_Error(t *testing.T) {
 	ctx := t.Context()
 	mp := new(MockPool)
@@ -82,13 +99,23 @@ func TestUpdateWorkout_Error(t *testing.T) {
 func TestDeleteWorkout_Success(t *testing.T) {
 	ctx := t.Context()
 	mp := new(MockPool)
-	mp.On("Exec", ctx, mock.Anything, mock.Anything, 5, 20).Return(pgconn.NewCommandTag(""), nil)
+	mp.On("Exec", ctx, mock.Anything, mock.Anything, 5, 20).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
 
 	repo := setupRepo(mp)
 	err := repo.DeleteWorkout(ctx, 5, 20)
 	assert.NoError(t, err)
 }
 
+func TestDeleteWorkout_NotFound(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	mp.On("Exec", ctx, mock.Anything, mock.Anything, 5, 99).Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+	repo := setupRepo(mp)
+	err := repo.DeleteWorkout(ctx, 5, 99)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
 func TestDeleteWorkout_Error(t *testing.T) {
 	ctx := t.Context()
 	mp := new(MockPool)
@@ -114,29 +141,272 @@ func TestGetWorkoutByID_Success(t *testing.T) {
 	assert.NotNil(t, res)
 }
 
+func TestGetWorkoutByID_NotFound(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, 7, 99).Return(row)
+	row.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(pgx.ErrNoRows)
+
+	repo := setupRepo(mp)
+	_, err := repo.GetWorkoutByID(ctx, 7, 99)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestGetTemplateByID_IncludesDeletedWorkouts(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.MatchedBy(func(sql string) bool { return !strings.Contains(sql, "deletedat") }), 7, 8).Return(row)
+	row.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	res, err := setupRepo(mp).GetTemplateByID(ctx, 7, 8)
+	assert.NoError(t, err)
+	assert.NotNil(t, res)
+	mp.AssertExpectations(t)
+}
+
+func TestGetWorkoutOwner(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, 7).Return(row)
+	row.On("Scan", mock.AnythingOfType("*int")).Run(func(args mock.Arguments) {
+		*args.Get(0).(*int) = 8
+	}).Return(nil)
+
+	owner, err := setupRepo(mp).GetWorkoutOwner(ctx, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, owner)
+}
+
+func TestGetWorkoutOwner_NotFound(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	row := new(MockRow)
+	mp.On("QueryRow", ctx, mock.Anything, 7).Return(row)
+	row.On("Scan", mock.AnythingOfType("*int")).Return(pgx.ErrNoRows)
+
+	_, err := setupRepo(mp).GetWorkoutOwner(ctx, 7)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestUpdateWorkoutPhoto_FiltersByUser(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	mp.On("Exec", ctx, mock.MatchedBy(func(sql string) bool {
+		return strings.Contains(sql, "user_id = $3")
+	}), "uploads/p.jpg", 7, 8).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+
+	err := setupRepo(mp).UpdateWorkoutPhoto(ctx, 7, 8, "uploads/p.jpg")
+	assert.NoError(t, err)
+	mp.AssertExpectations(t)
+}
+
+func TestUpdateWorkoutPhoto_OtherUser(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	mp.On("Exec", ctx, mock.Anything, "uploads/p.jpg", 7, 99).Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+	err := setupRepo(mp).UpdateWorkoutPhoto(ctx, 7, 99, "uploads/p.jpg")
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
 func TestBulkInsertWorkoutExercises_Success(t *testing.T) {
 	ctx := t.Context()
 	mp := new(MockPool)
-	list := []we.WorkoutExercise{{WorkoutID: 1, ExerciseID: 2, Reps: 3, Sets: 4}}
-	mp.On("Exec", ctx, mock.Anything, list[0].WorkoutID, list[0].ExerciseID, list[0].Reps, list[0].Sets).
-		Return(pgconn.NewCommandTag(""), nil)
+	list := []we.WorkoutExercise{
+		{WorkoutID: 1, ExerciseID: 2, Position: 0, BlockPosition: 0, Reps: 3, Sets: 4},
+		{WorkoutID: 1, ExerciseID: 5, Position: 1, BlockPosition: 0, Reps: 8, Sets: 3},
+	}
+	var rows [][]any
+	mp.On("CopyFrom", ctx, pgx.Identifier{"workout_exercise"},
+		[]string{"workout_id", "exercise_id", "position", "block_position", "reps", "sets"}, mock.Anything).
+		Run(func(args mock.Arguments) {
+			src := args.Get(3).(pgx.CopyFromSource)
+			for src.Next() {
+				values, err := src.Values()
+				assert.NoError(t, err)
+				rows = append(rows, values)
+			}
+		}).
+		Return(int64(2), nil)
 
 	repo := setupRepo(mp)
 	err := repo.BulkInsertWorkoutExercises(ctx, list)
 	assert.NoError(t, err)
+	assert.Equal(t, [][]any{{1, 2, 0, 0, 3, 4}, {1, 5, 1, 0, 8, 3}}, rows)
+}
+
+func TestBulkInsertWorkoutBlocks_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	blocks := []we.Block{
+		{WorkoutID: 1, Position: 0, Type: we.BlockStraight, Rounds: 1},
+		{WorkoutID: 1, Position: 1, Type: we.BlockSuperset, Rounds: 3, RestSeconds: 90},
+	}
+	var rows [][]any
+	mp.On("CopyFrom", ctx, pgx.Identifier{"workout_blocks"},
+		[]string{"workout_id", "position", "block_type", "rounds", "rest_seconds"}, mock.Anything).
+		Run(func(args mock.Arguments) {
+			src := args.Get(3).(pgx.CopyFromSource)
+			for src.Next() {
+				values, err := src.Values()
+				assert.NoError(t, err)
+				rows = append(rows, values)
+			}
+		}).
+		Return(int64(2), nil)
+
+	repo := setupRepo(mp)
+	err := repo.BulkInsertWorkoutBlocks(ctx, blocks)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]any{{1, 0, "straight", 1, 0}, {1, 1, "superset", 3, 90}}, rows)
+}
+
+func TestBulkInsertWorkoutBlocks_Empty(t *testing.T) {
+	mp := new(MockPool)
+	repo := setupRepo(mp)
+	err := repo.BulkInsertWorkoutBlocks(t.Context(), nil)
+	assert.NoError(t, err)
+	mp.AssertNotCalled(t, "CopyFrom", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetBlocksByWorkoutIDs_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	r := new(MockRow)
+	mp.On("Query", ctx, mock.Anything, []int{4}).Return(r, nil)
+	r.On("Next").Return(true).Once()
+	r.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 4
+			*args.Get(1).(*int) = 0
+			*args.Get(2).(*we.BlockType) = we.BlockCircuit
+			*args.Get(3).(*int) = 2
+		}).Return(nil).Once()
+	r.On("Next").Return(false).Once()
+	r.On("Err").Return(nil)
+	r.On("Close").Return()
+
+	repo := setupRepo(mp)
+	blocks, err := repo.GetBlocksByWorkoutIDs(ctx, []int{4})
+	assert.NoError(t, err)
+	assert.Equal(t, []we.Block{{WorkoutID: 4, Type: we.BlockCircuit, Rounds: 2}}, blocks[4])
+}
+
+func TestGetBlocksByWorkoutIDs_Empty(t *testing.T) {
+	mp := new(MockPool)
+	repo := setupRepo(mp)
+	blocks, err := repo.GetBlocksByWorkoutIDs(t.Context(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, blocks)
+	mp.AssertNotCalled(t, "Query", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBulkInsertSetPrescriptions_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	reps, repsMax, rest := 8, 12, 90
+	percent := 75.0
+	sets := []we.SetPrescription{
+		{WorkoutID: 1, ExercisePosition: 2, SetNumber: 1, Type: we.SetWorking,
+			Reps: &reps, RepsMax: &repsMax, PercentOneRM: &percent, RestSeconds: &rest},
+	}
+	var rows [][]any
+	mp.On("CopyFrom", ctx, pgx.Identifier{"workout_exercise_sets"},
+		[]string{"workout_id", "exercise_position", "set_number", "set_type",
+			"reps", "reps_max", "weight", "percent_1rm", "rpe", "rest_seconds"}, mock.Anything).
+		Run(func(args mock.Arguments) {
+			src := args.Get(3).(pgx.CopyFromSource)
+			for src.Next() {
+				values, err := src.Values()
+				assert.NoError(t, err)
+				rows = append(rows, values)
+			}
+		}).
+		Return(int64(1), nil)
+
+	repo := setupRepo(mp)
+	err := repo.BulkInsertSetPrescriptions(ctx, sets)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]any{{1, 2, 1, "working", &reps, &repsMax, (*float64)(nil), &percent, (*float64)(nil), &rest}}, rows)
+}
+
+func TestBulkInsertSetPrescriptions_Empty(t *testing.T) {
+	mp := new(MockPool)
+	repo := setupRepo(mp)
+	err := repo.BulkInsertSetPrescriptions(t.Context(), nil)
+	assert.NoError(t, err)
+	mp.AssertNotCalled(t, "CopyFrom", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetSetPrescriptions_Success(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	r := new(MockRow)
+	mp.On("Query", ctx, mock.Anything, 3).Return(r, nil)
+	r.On("Next").Return(true).Once()
+	r.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 3
+			*args.Get(2).(*int) = 1
+			*args.Get(3).(*we.SetType) = we.SetWarmup
+		}).Return(nil).Once()
+	r.On("Next").Return(false).Once()
+	r.On("Err").Return(nil)
+	r.On("Close").Return()
+
+	repo := setupRepo(mp)
+	sets, err := repo.GetSetPrescriptions(ctx, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []we.SetPrescription{{WorkoutID: 3, SetNumber: 1, Type: we.SetWarmup}}, sets)
+}
+
+func TestGetSetPrescriptions_QueryError(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	mp.On("Query", ctx, mock.Anything, 3).Return(new(MockRow), errors.New("boom"))
+
+	repo := setupRepo(mp)
+	sets, err := repo.GetSetPrescriptions(ctx, 3)
+	assert.Nil(t, sets)
+	assert.Error(t, err)
+}
+
+func TestBulkInsertWorkoutExercises_Empty(t *testing.T) {
+	mp := new(MockPool)
+	repo := setupRepo(mp)
+	err := repo.BulkInsertWorkoutExercises(t.Context(), nil)
+	assert.NoError(t, err)
+	mp.AssertNotCalled(t, "CopyFrom", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestBulkInsertWorkoutExercises_Error(t *testing.T) {
 	ctx := t.Context()
 	mp := new(MockPool)
 	list := []we.WorkoutExercise{{WorkoutID: 9, ExerciseID: 8, Reps: 7, Sets: 6}}
-	mp.On("Exec", ctx, mock.Anything, 9, 8, 7, 6).Return(pgconn.NewCommandTag(""), errors.New("insfail"))
+	mp.On("CopyFrom", ctx, mock.Anything, mock.Anything, mock.Anything).Return(int64(0), errors.New("insfail"))
 
 	repo := setupRepo(mp)
 	err := repo.BulkInsertWorkoutExercises(ctx, list)
 	assert.Error(t, err)
 }
 
+func TestWithTx_UsesTransaction(t *testing.T) {
+	ctx := t.Context()
+	tx := new(db.MockTx)
+	tx.On("Exec", ctx, mock.Anything, 42).Return(pgconn.NewCommandTag("DELETE 1"), nil)
+
+	repo := setupRepo(new(MockPool)).WithTx(tx)
+	err := repo.DeleteWorkoutExercises(ctx, 42)
+	assert.NoError(t, err)
+	tx.AssertExpectations(t)
+}
+
 func TestDeleteWorkoutExercises_Success(t *testing.T) {
 	ctx := t.Context()
 	mp := new(MockPool)
@@ -163,7 +433,7 @@ func TestGetWorkoutExercises_Success(t *testing.T) {
 	r := new(MockRow)
 	mp.On("Query", ctx, mock.Anything, 50).Return(r, nil)
 	r.On("Next").Return(true).Once()
-	r.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	r.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 	r.On("Next").Return(false).Once()
 	r.On("Close").Return()
 
@@ -179,8 +449,8 @@ func TestGetWorkoutExercises_ScanError(t *testing.T) {
 	r := new(MockRow)
 	mp.On("Query", ctx, mock.Anything, 52).Return(r, nil)
 	r.On("Next").Return(true)
-	// scan for workout exercises expects 4 args
-	r.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("scanerr"))
+	// scan for workout exercises expects 6 args
+	r.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("scanerr"))
 	r.On("Close").Return()
 
 	repo := setupRepo(mp)
@@ -193,14 +463,15 @@ func TestGetAllWorkouts_Success(t *testing.T) {
 	ctx := t.Context()
 	mp := new(MockPool)
 	r := new(MockRow)
-	mp.On("Query", ctx, mock.Anything, 100).Return(r, nil)
+	mp.On("Query", ctx, mock.Anything, 100, "", "", (*time.Time)(nil), (*time.Time)(nil), (*string)(nil), 0, 20).Return(r, nil)
 	r.On("Next").Return(true).Once()
 	r.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 	r.On("Next").Return(false).Once()
+	r.On("Err").Return(nil)
 	r.On("Close").Return()
 
 	repo := setupRepo(mp)
-	wos, err := repo.GetAllWorkouts(ctx, 100)
+	wos, err := repo.GetAllWorkouts(ctx, 100, dto.WorkoutFilter{Limit: 20}, nil)
 	assert.NoError(t, err)
 	assert.Len(t, wos, 1)
 }
@@ -223,12 +494,13 @@ func TestGetAllWorkouts_Empty(t *testing.T) {
 	ctx := t.Context()
 	mp := new(MockPool)
 	r := new(MockRow)
-	mp.On("Query", ctx, mock.Anything, 101).Return(r, nil)
+	mp.On("Query", ctx, mock.Anything, 101, "", "", (*time.Time)(nil), (*time.Time)(nil), (*string)(nil), 0, 20).Return(r, nil)
 	r.On("Next").Return(false)
+	r.On("Err").Return(nil)
 	r.On("Close").Return()
 
 	repo := setupRepo(mp)
-	wos, err := repo.GetAllWorkouts(ctx, 101)
+	wos, err := repo.GetAllWorkouts(ctx, 101, dto.WorkoutFilter{Limit: 20}, nil)
 	assert.NoError(t, err)
 	assert.Empty(t, wos)
 }
@@ -237,14 +509,78 @@ func TestGetAllWorkouts_ScanError(t *testing.T) {
 	ctx := t.Context()
 	mp := new(MockPool)
 	r := new(MockRow)
-	mp.On("Query", ctx, mock.Anything, 103).Return(r, nil)
+	mp.On("Query", ctx, mock.Anything, 103, "", "", (*time.Time)(nil), (*time.Time)(nil), (*string)(nil), 0, 20).Return(r, nil)
 	r.On("Next").Return(true).Once()
 	r.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(errors.New("scanfail"))
 	r.On("Close").Return()
 
 	repo := setupRepo(mp)
-	wos, err := repo.GetAllWorkouts(ctx, 103)
+	wos, err := repo.GetAllWorkouts(ctx, 103, dto.WorkoutFilter{Limit: 20}, nil)
 	assert.Nil(t, wos)
 	assert.Error(t, err)
 }
+
+func TestGetAllWorkouts_FilterAndCursor(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	r := new(MockRow)
+	from := time.Now().Add(-time.Hour)
+	filter := dto.WorkoutFilter{
+		Category: "legs",
+		Search:   "100%_squat",
+		From:     &from,
+		SortBy:   model.SortByName,
+		Order:    model.OrderAsc,
+		Limit:    5,
+	}
+	after := &dto.WorkoutCursor{SortBy: model.SortByName, Value: "Leg day", ID: 12}
+	queryMatcher := mock.MatchedBy(func(sql string) bool {
+		return strings.Contains(sql, "(name, id) > ($6::text, $7)") && strings.Contains(sql, "ORDER BY name ASC, id ASC")
+	})
+	mp.On("Query", ctx, queryMatcher, 104, "legs", `%100\%\_squat%`, &from, (*time.Time)(nil), &after.Value, 12, 5).
+		Return(r, nil)
+	r.On("Next").Return(false)
+	r.On("Err").Return(nil)
+	r.On("Close").Return()
+
+	repo := setupRepo(mp)
+	wos, err := repo.GetAllWorkouts(ctx, 104, filter, after)
+	assert.NoError(t, err)
+	assert.Empty(t, wos)
+	mp.AssertExpectations(t)
+}
+
+func TestGetExercisesByWorkoutIDs_GroupsByWorkout(t *testing.T) {
+	ctx := t.Context()
+	mp := new(MockPool)
+	r := new(MockRow)
+	ids := []int{1, 2}
+	mp.On("Query", ctx, mock.Anything, ids).Return(r, nil)
+	r.On("Next").Return(true).Times(3)
+	workoutIDs := []int{1, 2, 1}
+	call := 0
+	r.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*int) = workoutIDs[call]
+		*args.Get(1).(*int) = 10 + call
+		call++
+	}).Return(nil)
+	r.On("Next").Return(false).Once()
+	r.On("Err").Return(nil)
+	r.On("Close").Return()
+
+	repo := setupRepo(mp)
+	res, err := repo.GetExercisesByWorkoutIDs(ctx, ids)
+	assert.NoError(t, err)
+	assert.Len(t, res[1], 2)
+	assert.Len(t, res[2], 1)
+}
+
+func TestGetExercisesByWorkoutIDs_EmptySkipsQuery(t *testing.T) {
+	mp := new(MockPool)
+	repo := setupRepo(mp)
+	res, err := repo.GetExercisesByWorkoutIDs(t.Context(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+	mp.AssertNotCalled(t, "Query")
+}
@@ -0,0 +1,209 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	dto "workout-tracker/internal/dto/user"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/model/units"
+	model "workout-tracker/internal/model/user"
+	"workout-tracker/internal/model/user/jwt"
+	repo "workout-tracker/internal/repository/user"
+	"workout-tracker/pkg/db"
+	"workout-tracker/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/dig"
+)
+
+var errNoCurrentSession = erorrs.BadRequest("access token names no session, refresh it first")
+
+// Passwords hashes and checks passwords the way the auth service does.
+type Passwords interface {
+	HashPassword(password string) (string, error)
+	CheckPassword(hashed, password string) error
+}
+
+type AccountServiceParams struct {
+	dig.In
+
+	Repo      repo.UserRepositoryInterface
+	Passwords Passwords
+	Tx        db.UnitOfWork
+	Log       logger.SugaredLoggerInterface
+}
+
+type AccountService struct {
+	Repo      repo.UserRepositoryInterface
+	Passwords Passwords
+	Tx        db.UnitOfWork
+	Log       logger.SugaredLoggerInterface
+}
+
+func NewAccountService(params AccountServiceParams) *AccountService {
+	return &AccountService{
+		Repo:      params.Repo,
+		Passwords: params.Passwords,
+		Tx:        params.Tx,
+		Log:       params.Log,
+	}
+}
+
+func (s *AccountService) GetProfile(ctx context.Context, userID int) (*dto.ProfileResponse, error) {
+	profile, err := s.Repo.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get profile: %w", err)
+	}
+	resp := dto.NewProfileResponse(*profile)
+	return &resp, nil
+}
+
+// UpdateProfile replaces the user's profile and returns it as stored. Switching the unit system
+// here also switches it for every other endpoint.
+func (s *AccountService) UpdateProfile(ctx context.Context, userID int, input dto.ProfileRequest) (*dto.ProfileResponse, error) {
+	current, err := s.Repo.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get profile: %w", err)
+	}
+
+	profile := model.Profile{
+		ID:          userID,
+		Username:    current.Username,
+		DisplayName: strings.TrimSpace(input.DisplayName),
+		Email:       strings.ToLower(strings.TrimSpace(input.Email)),
+		Sex:         model.Sex(input.Sex),
+		Timezone:    input.Timezone,
+		AvatarURL:   input.AvatarURL,
+		UnitSystem:  current.UnitSystem,
+		CreatedAt:   current.CreatedAt,
+		UpdatedAt:   time.Now(),
+	}
+	if profile.Timezone == "" {
+		profile.Timezone = model.DefaultTimezone
+	}
+	if input.UnitSystem != "" {
+		profile.UnitSystem = units.System(input.UnitSystem)
+	}
+	if input.BirthDate != "" {
+		birthDate, err := time.Parse(dto.DateLayout, input.BirthDate)
+		if err != nil {
+			return nil, erorrs.Validation(erorrs.FieldError{Field: "birth_date", Message: "must be a date such as " + dto.DateLayout})
+		}
+		if !birthDate.Before(profile.UpdatedAt) {
+			return nil, erorrs.Validation(erorrs.FieldError{Field: "birth_date", Message: "must be in the past"})
+		}
+		profile.BirthDate = &birthDate
+	}
+	if input.Height != nil {
+		height := profile.UnitSystem.ToCentimetres(*input.Height)
+		profile.HeightCm = &height
+	}
+
+	if err := s.Repo.UpdateProfile(ctx, profile); err != nil {
+		return nil, fmt.Errorf("update profile: %w", err)
+	}
+	resp := dto.NewProfileResponse(profile)
+	return &resp, nil
+}
+
+// ChangePassword replaces the user's password once the current one is confirmed. Every token issued
+// before is revoked, so other devices have to sign in again.
+func (s *AccountService) ChangePassword(ctx context.Context, userID int, input dto.ChangePasswordRequest) error {
+	if err := s.confirmPassword(ctx, userID, "current_password", input.CurrentPassword); err != nil {
+		return err
+	}
+
+	hashed, err := s.Passwords.HashPassword(input.NewPassword)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	return s.Tx.WithinTx(ctx, func(tx pgx.Tx) error {
+		users := s.Repo.WithTx(tx)
+
+		if err := users.UpdatePassword(ctx, userID, hashed); err != nil {
+			return fmt.Errorf("update password: %w", err)
+		}
+		return s.revokeTokens(ctx, users, userID)
+	})
+}
+
+// DeleteAccount soft-deletes the user and their workouts once their password is confirmed.
+func (s *AccountService) DeleteAccount(ctx context.Context, userID int, input dto.DeleteAccountRequest) error {
+	if err := s.confirmPassword(ctx, userID, "password", input.Password); err != nil {
+		return err
+	}
+
+	return s.Tx.WithinTx(ctx, func(tx pgx.Tx) error {
+		users := s.Repo.WithTx(tx)
+
+		if err := users.DeleteUser(ctx, userID, time.Now()); err != nil {
+			return fmt.Errorf("delete user: %w", err)
+		}
+		if err := users.IncrementTokenVersion(ctx, userID); err != nil {
+			return fmt.Errorf("increment token version: %w", err)
+		}
+		s.Log.Info("account deleted", "userID", userID)
+		return nil
+	})
+}
+
+// GetSessions lists the user's signed-in devices and marks the one of the current request.
+func (s *AccountService) GetSessions(ctx context.Context, userID int, currentSessionID string) ([]jwt.Session, error) {
+	sessions, err := s.Repo.GetSessions(ctx, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("get sessions: %w", err)
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs one of the user's devices out. Its refresh and access tokens stop working at
+// once; the user's other sessions are unaffected.
+func (s *AccountService) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	if err := s.Repo.RevokeSession(ctx, userID, sessionID, time.Now()); err != nil {
+		return fmt.Errorf("revoke session %s: %w", sessionID, err)
+	}
+	return nil
+}
+
+// RevokeOtherSessions signs out every device but the current one. Their access tokens stop working
+// at once, the one of the current session stays valid. An access token that names no session can
+// not tell which one to keep, so it is rejected rather than signing out every device.
+func (s *AccountService) RevokeOtherSessions(ctx context.Context, userID int, currentSessionID string) error {
+	if currentSessionID == "" {
+		return errNoCurrentSession
+	}
+
+	if _, err := s.Repo.RevokeOtherSessions(ctx, userID, currentSessionID, time.Now()); err != nil {
+		return fmt.Errorf("revoke other sessions: %w", err)
+	}
+	return nil
+}
+
+// confirmPassword reports a wrong password as a validation error on field.
+func (s *AccountService) confirmPassword(ctx context.Context, userID int, field, password string) error {
+	user, err := s.Repo.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+	if err := s.Passwords.CheckPassword(user.Password, password); err != nil {
+		return erorrs.Validation(erorrs.FieldError{Field: field, Message: "is incorrect"})
+	}
+	return nil
+}
+
+// revokeTokens invalidates the user's access tokens and deletes their refresh tokens.
+func (s *AccountService) revokeTokens(ctx context.Context, users repo.UserRepositoryInterface, userID int) error {
+	if err := users.IncrementTokenVersion(ctx, userID); err != nil {
+		return fmt.Errorf("increment token version: %w", err)
+	}
+	if err := users.DeleteRefreshTokens(ctx, userID); err != nil {
+		return fmt.Errorf("delete refresh tokens: %w", err)
+	}
+	return nil
+}
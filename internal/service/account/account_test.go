@@ -0,0 +1,204 @@
+package account_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+	dto "workout-tracker/internal/dto/user"
+	"workout-tracker/internal/erorrs"
+	"workout-tracker/internal/model/units"
+	model "workout-tracker/internal/model/user"
+	"workout-tracker/internal/model/user/jwt"
+	repo "workout-tracker/internal/repository/user"
+	"workout-tracker/internal/service/account"
+	"workout-tracker/pkg/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// stubUserRepo knows user 1 with the password "secret" and records what was changed.
+type stubUserRepo struct {
+	repo.UserRepositoryInterface
+	Profile         model.Profile
+	Updated         *model.Profile
+	Password        string
+	Calls           []string
+	IncrementedUser int
+	DeletedUser     int
+}
+
+func (s *stubUserRepo) WithTx(tx pgx.Tx) repo.UserRepositoryInterface { return s }
+
+func (s *stubUserRepo) GetUserByUserID(ctx context.Context, id int) (*model.User, error) {
+	if id != 1 {
+		return nil, erorrs.ErrUserNotFound
+	}
+	return &model.User{ID: 1, Password: "hashed:secret"}, nil
+}
+func (s *stubUserRepo) GetProfile(ctx context.Context, userID int) (*model.Profile, error) {
+	profile := s.Profile
+	return &profile, nil
+}
+func (s *stubUserRepo) UpdateProfile(ctx context.Context, p model.Profile) error {
+	s.Updated = &p
+	return nil
+}
+func (s *stubUserRepo) UpdatePassword(ctx context.Context, userID int, password string) error {
+	s.Calls = append(s.Calls, "UpdatePassword")
+	s.Password = password
+	return nil
+}
+func (s *stubUserRepo) IncrementTokenVersion(ctx context.Context, userID int) error {
+	s.Calls = append(s.Calls, "IncrementTokenVersion")
+	s.IncrementedUser = userID
+	return nil
+}
+func (s *stubUserRepo) DeleteRefreshTokens(ctx context.Context, userID int) error {
+	s.Calls = append(s.Calls, "DeleteRefreshTokens")
+	return nil
+}
+func (s *stubUserRepo) GetSessions(ctx context.Context, userID int, now time.Time) ([]jwt.Session, error) {
+	return []jwt.Session{{ID: "a"}, {ID: "b"}}, nil
+}
+func (s *stubUserRepo) RevokeOtherSessions(ctx context.Context, userID int, keepSessionID string, at time.Time) ([]string, error) {
+	s.Calls = append(s.Calls, "RevokeOtherSessions:"+keepSessionID)
+	return []string{"a"}, nil
+}
+func (s *stubUserRepo) DeleteUser(ctx context.Context, userID int, at time.Time) error {
+	s.Calls = append(s.Calls, "DeleteUser")
+	s.DeletedUser = userID
+	return nil
+}
+
+// fakePasswords "hashes" by prefixing, which is enough to tell hashes and passwords apart.
+type fakePasswords struct{}
+
+func (fakePasswords) HashPassword(password string) (string, error) { return "hashed:" + password, nil }
+func (fakePasswords) CheckPassword(hashed, password string) error {
+	if hashed != "hashed:"+password {
+		return errors.New("mismatch")
+	}
+	return nil
+}
+
+func newTestService(t *testing.T, r *stubUserRepo) (*account.AccountService, *db.MockUnitOfWork) {
+	t.Helper()
+	tx := &db.MockUnitOfWork{}
+	return account.NewAccountService(account.AccountServiceParams{
+		Repo:      r,
+		Passwords: fakePasswords{},
+		Tx:        tx,
+		Log:       zaptest.NewLogger(t).Sugar(),
+	}), tx
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestUpdateProfile_ConvertsHeight(t *testing.T) {
+	r := &stubUserRepo{Profile: model.Profile{ID: 1, Username: "ann", UnitSystem: units.Metric}}
+	service, _ := newTestService(t, r)
+
+	resp, err := service.UpdateProfile(t.Context(), 1, dto.ProfileRequest{
+		DisplayName: " Ann ",
+		Email:       "Ann@Example.com",
+		BirthDate:   "1990-05-17",
+		Height:      floatPtr(70),
+		UnitSystem:  "imperial",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, r.Updated)
+	assert.Equal(t, "Ann", r.Updated.DisplayName)
+	assert.Equal(t, "ann@example.com", r.Updated.Email)
+	assert.Equal(t, model.DefaultTimezone, r.Updated.Timezone)
+	assert.InDelta(t, 177.8, *r.Updated.HeightCm, 0.001, "the height is given in the new unit system")
+	assert.Equal(t, time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC), *r.Updated.BirthDate)
+
+	assert.Equal(t, 70.0, *resp.Height)
+	assert.Equal(t, "in", resp.HeightUnit)
+	assert.Equal(t, "1990-05-17", resp.BirthDate)
+	assert.Equal(t, "ann", resp.Username)
+}
+
+func TestUpdateProfile_BirthDateInFuture(t *testing.T) {
+	r := &stubUserRepo{Profile: model.Profile{ID: 1, UnitSystem: units.Metric}}
+	service, _ := newTestService(t, r)
+
+	_, err := service.UpdateProfile(t.Context(), 1, dto.ProfileRequest{
+		BirthDate: time.Now().AddDate(1, 0, 0).Format(dto.DateLayout),
+	})
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "birth_date", appErr.Fields[0].Field)
+	assert.Nil(t, r.Updated)
+}
+
+func TestChangePassword_RevokesTokens(t *testing.T) {
+	r := &stubUserRepo{}
+	service, tx := newTestService(t, r)
+
+	err := service.ChangePassword(t.Context(), 1, dto.ChangePasswordRequest{CurrentPassword: "secret", NewPassword: "much-longer"})
+	require.NoError(t, err)
+	assert.Equal(t, "hashed:much-longer", r.Password)
+	assert.Equal(t, []string{"UpdatePassword", "IncrementTokenVersion", "DeleteRefreshTokens"}, r.Calls)
+	assert.Equal(t, 1, r.IncrementedUser)
+	assert.Equal(t, 1, tx.Calls)
+}
+
+func TestChangePassword_WrongPassword(t *testing.T) {
+	r := &stubUserRepo{}
+	service, _ := newTestService(t, r)
+
+	err := service.ChangePassword(t.Context(), 1, dto.ChangePasswordRequest{CurrentPassword: "guess", NewPassword: "much-longer"})
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, []erorrs.FieldError{{Field: "current_password", Message: "is incorrect"}}, appErr.Fields)
+	assert.Empty(t, r.Calls)
+}
+
+func TestDeleteAccount(t *testing.T) {
+	r := &stubUserRepo{}
+	service, _ := newTestService(t, r)
+
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, service.DeleteAccount(t.Context(), 1, dto.DeleteAccountRequest{Password: "guess"}), &appErr)
+	assert.Empty(t, r.Calls)
+
+	require.NoError(t, service.DeleteAccount(t.Context(), 1, dto.DeleteAccountRequest{Password: "secret"}))
+	assert.Equal(t, []string{"DeleteUser", "IncrementTokenVersion"}, r.Calls)
+	assert.Equal(t, 1, r.DeletedUser)
+}
+
+func TestGetSessions_MarksCurrent(t *testing.T) {
+	service, _ := newTestService(t, &stubUserRepo{})
+
+	sessions, err := service.GetSessions(t.Context(), 1, "b")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.False(t, sessions[0].Current)
+	assert.True(t, sessions[1].Current)
+}
+
+func TestRevokeOtherSessions(t *testing.T) {
+	r := &stubUserRepo{}
+	service, tx := newTestService(t, r)
+
+	require.NoError(t, service.RevokeOtherSessions(t.Context(), 1, "b"))
+	assert.Equal(t, []string{"RevokeOtherSessions:b"}, r.Calls, "the access token of the current session stays valid")
+	assert.Equal(t, 0, tx.Calls)
+}
+
+func TestRevokeOtherSessions_WithoutCurrentSession(t *testing.T) {
+	r := &stubUserRepo{}
+	service, _ := newTestService(t, r)
+
+	err := service.RevokeOtherSessions(t.Context(), 1, "")
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Status)
+	assert.Empty(t, r.Calls, "no session is revoked")
+}
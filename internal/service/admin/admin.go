@@ -30,7 +30,8 @@ func NewAdminService(params AdminServiceParams) *AdminService {
 }
 
 func (s *AdminService) CreateExercise(ctx context.Context, input dto.CreateExerciseRequest) (int, error) {
-	created, err := s.ExerciseRepo.CreateExercise(ctx, input)
+	input = input.Normalize()
+	created, err := s.ExerciseRepo.CreateExercise(ctx, nil, input)
 	if err != nil {
 		s.Log.Errorw("Service failed to create exercise", "error", err)
 		return 0, fmt.Errorf("failed to create exercise in service: %w", err)
@@ -39,7 +40,8 @@ func (s *AdminService) CreateExercise(ctx context.Context, input dto.CreateExerc
 }
 
 func (s *AdminService) UpdateExercise(ctx context.Context, id int, input dto.CreateExerciseRequest) error {
-	err := s.ExerciseRepo.UpdateExercise(ctx, id, input)
+	input = input.Normalize()
+	err := s.ExerciseRepo.UpdateExercise(ctx, id, nil, input)
 	if err != nil {
 		s.Log.Errorw("Service failed to update exercise", "error", err)
 		return fmt.Errorf("failed to update exercise in service: %w", err)
@@ -47,17 +49,28 @@ func (s *AdminService) UpdateExercise(ctx context.Context, id int, input dto.Cre
 	return nil
 }
 
-func (s *AdminService) GetAllExercises(ctx context.Context) ([]exercise.Exercise, error) {
-	exercises, err := s.ExerciseRepo.GetAllExercises(ctx)
+func (s *AdminService) GetAllExercises(ctx context.Context, filter dto.ExerciseFilter) (*dto.ExercisePage, error) {
+	filter, err := filter.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	exercises, total, err := s.ExerciseRepo.GetAllExercises(ctx, filter)
 	if err != nil {
 		s.Log.Errorw("Service failed to get exercises", "error", err)
 		return nil, fmt.Errorf("failed to get exercises: %w", err)
 	}
-	return exercises, nil
+
+	return &dto.ExercisePage{
+		Items:  exercises,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
 }
 
 func (s *AdminService) DeleteExercise(ctx context.Context, id int) error {
-	err := s.ExerciseRepo.DeleteExercise(ctx, id)
+	err := s.ExerciseRepo.DeleteExercise(ctx, id, nil)
 	if err != nil {
 		s.Log.Errorw("Service failed to delete exercise", "id", id, "error", err)
 		return fmt.Errorf("failed to delete exercise: %w", err)
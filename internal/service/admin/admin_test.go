@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 	dto "workout-tracker/internal/dto/exercise"
+	"workout-tracker/internal/erorrs"
 	"workout-tracker/internal/model/exercise"
 
 	"github.com/stretchr/testify/assert"
@@ -19,7 +20,7 @@ func TestCreateExercise_Success(t *testing.T) {
 	ctx := t.Context()
 	mockRepo := new(MockExerciseRepo)
 	req := dto.CreateExerciseRequest{Name: "Push-up", Description: "Upper body exercise"}
-	mockRepo.On("CreateExercise", ctx, req).Return(0, nil)
+	mockRepo.On("CreateExercise", ctx, (*int)(nil), req).Return(0, nil)
 
 	svc := NewAdminService(AdminServiceParams{
 		Log:          zapLogger(),
@@ -37,7 +38,7 @@ func TestCreateExercise_Error(t *testing.T) {
 	mockRepo := new(MockExerciseRepo)
 	req := dto.CreateExerciseRequest{Name: "Squat"}
 	errRepo := errors.New("db error")
-	mockRepo.On("CreateExercise", ctx, req).Return(0, errRepo)
+	mockRepo.On("CreateExercise", ctx, (*int)(nil), req).Return(0, errRepo)
 
 	svc := NewAdminService(AdminServiceParams{
 		Log:          zapLogger(),
@@ -55,7 +56,7 @@ func TestUpdateExercise_Success(t *testing.T) {
 	ctx := t.Context()
 	mockRepo := new(MockExerciseRepo)
 	input := dto.CreateExerciseRequest{Name: "Lunge"}
-	mockRepo.On("UpdateExercise", ctx, 7, input).Return(nil)
+	mockRepo.On("UpdateExercise", ctx, 7, (*int)(nil), input).Return(nil)
 
 	svc := NewAdminService(AdminServiceParams{Log: zapLogger(), ExerciseRepo: mockRepo})
 	err := svc.UpdateExercise(ctx, 7, input)
@@ -68,7 +69,7 @@ func TestUpdateExercise_Error(t *testing.T) {
 	mockRepo := new(MockExerciseRepo)
 	input := dto.CreateExerciseRequest{Name: "Lunge"}
 	errRepo := errors.New("not found")
-	mockRepo.On("UpdateExercise", ctx, 7, input).Return(errRepo)
+	mockRepo.On("UpdateExercise", ctx, 7, (*int)(nil), input).Return(errRepo)
 
 	svc := NewAdminService(AdminServiceParams{Log: zapLogger(), ExerciseRepo: mockRepo})
 	err := svc.UpdateExercise(ctx, 7, input)
@@ -81,20 +82,69 @@ func TestGetAllExercises_Success(t *testing.T) {
 	ctx := t.Context()
 	mockRepo := new(MockExerciseRepo)
 	exs := []exercise.Exercise{{ID: 1, Name: "Plank"}, {ID: 2, Name: "Burpee"}}
-	mockRepo.On("GetAllExercises", ctx).Return(exs, nil)
+	mockRepo.On("GetAllExercises", ctx, dto.ExerciseFilter{Limit: 20}).Return(exs, 2, nil)
 
 	svc := NewAdminService(AdminServiceParams{Log: zapLogger(), ExerciseRepo: mockRepo})
 
-	result, err := svc.GetAllExercises(ctx)
+	result, err := svc.GetAllExercises(ctx, dto.ExerciseFilter{})
 	assert.NoError(t, err)
-	assert.Equal(t, exs, result)
+	assert.Equal(t, exs, result.Items)
+	assert.Equal(t, 2, result.Total)
+	assert.Equal(t, 20, result.Limit)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestGetAllExercises_NormalizesFilter(t *testing.T) {
+	ctx := t.Context()
+	mockRepo := new(MockExerciseRepo)
+	expected := dto.ExerciseFilter{Search: "press", Muscle: "chest", Equipment: "barbell", Limit: 100, Offset: 0}
+	mockRepo.On("GetAllExercises", ctx, expected).Return([]exercise.Exercise{}, 0, nil)
+	svc := NewAdminService(AdminServiceParams{Log: zapLogger(), ExerciseRepo: mockRepo})
+
+	_, err := svc.GetAllExercises(ctx, dto.ExerciseFilter{
+		Search: " press ", Muscle: "Chest", Equipment: " BARBELL", Limit: 500, Offset: -3,
+	})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetAllExercises_InvalidFilter(t *testing.T) {
+	ctx := t.Context()
+	svc := NewAdminService(AdminServiceParams{Log: zapLogger(), ExerciseRepo: new(MockExerciseRepo)})
+
+	_, err := svc.GetAllExercises(ctx, dto.ExerciseFilter{MovementType: "explosive"})
+	assert.ErrorIs(t, err, erorrs.ErrInvalidMovementType)
+
+	_, err = svc.GetAllExercises(ctx, dto.ExerciseFilter{MeasurementKind: "calories"})
+	assert.ErrorIs(t, err, erorrs.ErrInvalidMeasurementKind)
+}
+
+func TestCreateExercise_NormalizesMetadata(t *testing.T) {
+	ctx := t.Context()
+	mockRepo := new(MockExerciseRepo)
+	expected := dto.CreateExerciseRequest{
+		Name:           "Bench Press",
+		PrimaryMuscles: []string{"chest", "triceps"},
+		Equipment:      []string{"barbell", "bench"},
+		MovementType:   exercise.Compound,
+	}
+	mockRepo.On("CreateExercise", ctx, (*int)(nil), expected).Return(3, nil)
+	svc := NewAdminService(AdminServiceParams{Log: zapLogger(), ExerciseRepo: mockRepo})
+
+	id, err := svc.CreateExercise(ctx, dto.CreateExerciseRequest{
+		Name:           "Bench Press",
+		PrimaryMuscles: []string{" Chest", "triceps", "chest", ""},
+		Equipment:      []string{"Barbell", "bench"},
+		MovementType:   exercise.Compound,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, id)
+}
+
 func TestDeleteExercise_Success(t *testing.T) {
 	ctx := t.Context()
 	mockRepo := new(MockExerciseRepo)
-	mockRepo.On("DeleteExercise", ctx, 3).Return(nil)
+	mockRepo.On("DeleteExercise", ctx, 3, (*int)(nil)).Return(nil)
 
 	svc := NewAdminService(AdminServiceParams{Log: zapLogger(), ExerciseRepo: mockRepo})
 	err := svc.DeleteExercise(ctx, 3)
@@ -106,7 +156,7 @@ func TestDeleteExercise_Error(t *testing.T) {
 	ctx := t.Context()
 	mockRepo := new(MockExerciseRepo)
 	errRepo := errors.New("cannot delete")
-	mockRepo.On("DeleteExercise", ctx, 3).Return(errRepo)
+	mockRepo.On("DeleteExercise", ctx, 3, (*int)(nil)).Return(errRepo)
 
 	svc := NewAdminService(AdminServiceParams{Log: zapLogger(), ExerciseRepo: mockRepo})
 	err := svc.DeleteExercise(ctx, 3)
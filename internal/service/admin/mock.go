@@ -13,8 +13,8 @@ type MockExerciseRepo struct {
 	mock.Mock
 }
 
-func (m *MockExerciseRepo) CreateExercise(ctx context.Context, input dto.CreateExerciseRequest) (int, error) {
-	args := m.Called(ctx, input)
+func (m *MockExerciseRepo) CreateExercise(ctx context.Context, ownerID *int, input dto.CreateExerciseRequest) (int, error) {
+	args := m.Called(ctx, ownerID, input)
 	err := args.Error(1)
 	if err != nil {
 		return 0, fmt.Errorf("error creating exercise: %w", err)
@@ -22,8 +22,8 @@ func (m *MockExerciseRepo) CreateExercise(ctx context.Context, input dto.CreateE
 	return args.Int(0), nil
 }
 
-func (m *MockExerciseRepo) UpdateExercise(ctx context.Context, id int, input dto.CreateExerciseRequest) error {
-	args := m.Called(ctx, id, input)
+func (m *MockExerciseRepo) UpdateExercise(ctx context.Context, id int, ownerID *int, input dto.CreateExerciseRequest) error {
+	args := m.Called(ctx, id, ownerID, input)
 	err := args.Error(0)
 	if err != nil {
 		return fmt.Errorf("error updating exercise: %w", err)
@@ -31,8 +31,8 @@ func (m *MockExerciseRepo) UpdateExercise(ctx context.Context, id int, input dto
 	return nil
 }
 
-func (m *MockExerciseRepo) DeleteExercise(ctx context.Context, id int) error {
-	args := m.Called(ctx, id)
+func (m *MockExerciseRepo) DeleteExercise(ctx context.Context, id int, ownerID *int) error {
+	args := m.Called(ctx, id, ownerID)
 	err := args.Error(0)
 	if err != nil {
 		return fmt.Errorf("error deleting exercise: %w", err)
@@ -40,20 +40,20 @@ func (m *MockExerciseRepo) DeleteExercise(ctx context.Context, id int) error {
 	return nil
 }
 
-func (m *MockExerciseRepo) GetAllExercises(ctx context.Context) ([]exercise.Exercise, error) {
-	args := m.Called(ctx)
+func (m *MockExerciseRepo) GetAllExercises(ctx context.Context, filter dto.ExerciseFilter) ([]exercise.Exercise, int, error) {
+	args := m.Called(ctx, filter)
 
 	exList, ok := args.Get(0).([]exercise.Exercise)
 	if !ok {
-		return nil, fmt.Errorf("invalid type for []exercise.Exercise")
+		return nil, 0, fmt.Errorf("invalid type for []exercise.Exercise")
 	}
 
-	err := args.Error(1)
+	err := args.Error(2)
 	if err != nil {
-		return nil, fmt.Errorf("error in GetAllExercises: %w", err)
+		return nil, 0, fmt.Errorf("error in GetAllExercises: %w", err)
 	}
 
-	return exList, nil
+	return exList, args.Int(1), nil
 }
 
 func (m *MockExerciseRepo) GetExerciseByID(ctx context.Context, id int) (*exercise.Exercise, error) {
@@ -71,3 +71,19 @@ func (m *MockExerciseRepo) GetExerciseByID(ctx context.Context, id int) (*exerci
 
 	return ex, nil
 }
+
+func (m *MockExerciseRepo) GetExercisesByIDs(ctx context.Context, ids []int) ([]exercise.Exercise, error) {
+	args := m.Called(ctx, ids)
+
+	exList, ok := args.Get(0).([]exercise.Exercise)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for []exercise.Exercise")
+	}
+
+	err := args.Error(1)
+	if err != nil {
+		return nil, fmt.Errorf("error in GetExercisesByIDs: %w", err)
+	}
+
+	return exList, nil
+}
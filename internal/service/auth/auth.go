@@ -2,12 +2,17 @@ package auth
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"os"
+	"strconv"
 	"time"
 	"workout-tracker/internal/erorrs"
 	model "workout-tracker/internal/model/user"
+	jwtModel "workout-tracker/internal/model/user/jwt"
 	"workout-tracker/internal/repository/user"
+	"workout-tracker/pkg/jwtkeys"
 	"workout-tracker/pkg/logger"
 
 	"golang.org/x/crypto/bcrypt"
@@ -25,24 +30,29 @@ type AuthServiceParams struct {
 
 	Repo user.UserRepositoryInterface
 	Log  logger.SugaredLoggerInterface
+	// Keys falls back to the key set described by the environment.
+	Keys *jwtkeys.KeySet `optional:"true"`
 }
 
 type AuthService struct {
-	Repo   user.UserRepositoryInterface
-	Log    logger.SugaredLoggerInterface
-	Secret string
+	Repo user.UserRepositoryInterface
+	Log  logger.SugaredLoggerInterface
+	Keys *jwtkeys.KeySet
 }
 
 func NewAuthService(params AuthServiceParams) *AuthService {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		panic("JWT_SECRET environment variable is not set")
+	keys := params.Keys
+	if keys == nil {
+		var err error
+		if keys, err = jwtkeys.FromEnv(); err != nil {
+			panic(err)
+		}
 	}
 
 	return &AuthService{
-		Repo:   params.Repo,
-		Log:    params.Log,
-		Secret: secret,
+		Repo: params.Repo,
+		Log:  params.Log,
+		Keys: keys,
 	}
 }
 
@@ -96,16 +106,34 @@ func (s *AuthService) GetUserByUserID(ctx context.Context, id int) (*model.User,
 	return user, nil
 }
 
-func (s *AuthService) GenerateAccessToken(user *model.User) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": user.ID,
-		"role":    user.Role,
-		"version": user.TokenVersion,
-		"exp":     time.Now().Add(halfAnHour).Unix(),
+// SessionActive reports whether the session an access token was issued with has not been revoked.
+func (s *AuthService) SessionActive(ctx context.Context, userID int, sessionID string) (bool, error) {
+	active, err := s.Repo.SessionActive(ctx, userID, sessionID)
+	if err != nil {
+		s.Log.Errorw("failed to check session", "userID", userID, erorrs.ErrorKey, err)
+		return false, fmt.Errorf("failed to check session: %w", err)
+	}
+
+	return active, nil
+}
+
+// GenerateAccessToken signs an access token for the user's session, the refresh token family it
+// was issued with.
+func (s *AuthService) GenerateAccessToken(user *model.User, sessionID string) (string, error) {
+	now := time.Now()
+	claims := jwtModel.AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(user.ID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(halfAnHour)),
+		},
+		UserID:    user.ID,
+		Role:      user.Role,
+		Version:   user.TokenVersion,
+		SessionID: sessionID,
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	res, err := token.SignedString([]byte(s.Secret))
+	res, err := s.Keys.Sign(claims)
 	if err != nil {
 		s.Log.Errorw("error generating access token", erorrs.ErrorKey, err)
 		return "", fmt.Errorf("error generating access token: %w", err)
@@ -114,38 +142,71 @@ func (s *AuthService) GenerateAccessToken(user *model.User) (string, error) {
 	return res, nil
 }
 
-func (s *AuthService) GenerateAndStoreRefreshToken(ctx context.Context, userID int) (string, error) {
+// GenerateAndStoreRefreshToken issues the first refresh token of a new family, for a fresh login on
+// the device. It returns the token and the id of the session it starts.
+func (s *AuthService) GenerateAndStoreRefreshToken(ctx context.Context, userID int, device jwtModel.Device) (string, string, error) {
+	return s.storeRefreshToken(ctx, userID, "", device)
+}
+
+func (s *AuthService) storeRefreshToken(ctx context.Context, userID int, familyID string, device jwtModel.Device) (
+	string, string, error) {
 	token := uuid.NewString()
-	expires := time.Now().Add(oneMonth)
 
-	_, err := s.Repo.StoreRefreshToken(ctx, token, userID, expires)
+	id, err := s.Repo.StoreRefreshToken(ctx, jwtModel.RefreshToken{
+		TokenHash: hashToken(token),
+		FamilyID:  familyID,
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(oneMonth),
+		Device:    device,
+	})
 	if err != nil {
 		s.Log.Errorw("failed to store refresh token", erorrs.ErrorKey, err)
-		return "", fmt.Errorf("failed to store refresh token: %w", err)
+		return "", "", fmt.Errorf("failed to store refresh token: %w", err)
 	}
 
-	return token, nil
+	if familyID == "" {
+		familyID = id.String()
+	}
+	return token, familyID, nil
 }
 
-func (s *AuthService) UpdateRefreshToken(ctx context.Context, refreshToken string) (string, string, error) {
-	rt, err := s.Repo.GetRefreshToken(ctx, refreshToken)
+// UpdateRefreshToken rotates a refresh token: it is marked used and its successor joins the same
+// family. A token that was rotated before is presented again only when it leaked, so the whole
+// family is revoked and the user's access tokens are invalidated. The device keeps the name it
+// signed in with.
+func (s *AuthService) UpdateRefreshToken(ctx context.Context, refreshToken string, device jwtModel.Device) (string, string, error) {
+	now := time.Now()
+
+	rt, err := s.Repo.GetRefreshToken(ctx, hashToken(refreshToken))
 	if err != nil {
 		s.Log.Errorw("failed to get refresh token", erorrs.ErrorKey, err)
 		return "", "", erorrs.ErrInvalidToken
 	}
 
-	if err := s.Repo.DeleteRefreshToken(ctx, refreshToken); err != nil {
-		s.Log.Errorw("failed to delete refresh token", erorrs.ErrorKey, err)
+	switch {
+	case rt.RevokedAt != nil, rt.Expired(now):
+		return "", "", erorrs.ErrInvalidToken
+	case rt.UsedAt != nil:
+		return "", "", s.revokeFamily(ctx, rt, now)
+	}
+
+	fresh, err := s.Repo.MarkRefreshTokenUsed(ctx, rt.ID, now)
+	if err != nil {
+		s.Log.Errorw("failed to mark refresh token used", erorrs.ErrorKey, err)
 		return "", "", erorrs.ErrInternal
 	}
+	if !fresh {
+		return "", "", s.revokeFamily(ctx, rt, now)
+	}
 
-	access, err := s.RefreshAccessToken(ctx, rt.UserID)
+	access, err := s.RefreshAccessToken(ctx, rt.UserID, rt.FamilyID)
 	if err != nil {
 		s.Log.Errorw("access token generation failed", "error", err)
 		return "", "", erorrs.ErrInternal
 	}
 
-	refresh, err := s.GenerateAndStoreRefreshToken(ctx, rt.UserID)
+	device.Name = rt.Name
+	refresh, _, err := s.storeRefreshToken(ctx, rt.UserID, rt.FamilyID, device)
 	if err != nil {
 		s.Log.Errorw("refresh token generation failed", "error", err)
 		return "", "", erorrs.ErrInternal
@@ -154,16 +215,55 @@ func (s *AuthService) UpdateRefreshToken(ctx context.Context, refreshToken strin
 	return access, refresh, nil
 }
 
-func (s *AuthService) RefreshAccessToken(ctx context.Context, userID int) (string, error) {
-	err := s.Repo.IncrementTokenVersion(ctx, userID)
+// revokeFamily handles the reuse of a rotated token. Revoking the family ends the session, which
+// also invalidates the access tokens issued to it. It returns the error to report to the client.
+func (s *AuthService) revokeFamily(ctx context.Context, rt *jwtModel.RefreshToken, now time.Time) error {
+	s.Log.Errorw("refresh token reused, revoking its family", "userID", rt.UserID, "familyID", rt.FamilyID)
+
+	if err := s.Repo.RevokeRefreshTokenFamily(ctx, rt.FamilyID, now); err != nil {
+		s.Log.Errorw("failed to revoke refresh token family", erorrs.ErrorKey, err)
+		return erorrs.ErrInternal
+	}
+	return erorrs.ErrInvalidToken
+}
+
+// Logout ends the session the refresh token belongs to. Unknown tokens are ignored, so logging out
+// twice is harmless.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	rt, err := s.Repo.GetRefreshToken(ctx, hashToken(refreshToken))
 	if err != nil {
-		return "", fmt.Errorf("failed to invalidate old tokens: %w", err)
+		if errors.Is(err, erorrs.ErrTokenNotFound) {
+			return nil
+		}
+		s.Log.Errorw("failed to get refresh token", erorrs.ErrorKey, err)
+		return fmt.Errorf("get refresh token: %w", err)
+	}
+
+	if err := s.Repo.RevokeRefreshTokenFamily(ctx, rt.FamilyID, time.Now()); err != nil {
+		s.Log.Errorw("failed to revoke refresh token family", erorrs.ErrorKey, err)
+		return fmt.Errorf("revoke session: %w", err)
 	}
+	return nil
+}
 
+// RefreshAccessToken issues a new access token for the session. It leaves the user's token version
+// alone, so the access tokens of the user's other sessions stay valid.
+func (s *AuthService) RefreshAccessToken(ctx context.Context, userID int, sessionID string) (string, error) {
 	user, err := s.Repo.GetUserByUserID(ctx, userID)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch user: %w", err)
 	}
 
-	return s.GenerateAccessToken(user)
+	return s.GenerateAccessToken(user, sessionID)
+}
+
+// hashToken returns the form refresh tokens are stored in.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// JWKS returns the public keys other services verify our access tokens with.
+func (s *AuthService) JWKS() jwtkeys.JWKS {
+	return s.Keys.JWKS()
 }
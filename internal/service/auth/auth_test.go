@@ -7,10 +7,12 @@ import (
 	"time"
 	"workout-tracker/internal/erorrs"
 	"workout-tracker/internal/model/user"
-	"workout-tracker/internal/model/user/jwt"
+	jwtModel "workout-tracker/internal/model/user/jwt"
+	"workout-tracker/pkg/jwtkeys"
 
 	"go.uber.org/zap"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -91,9 +93,26 @@ func TestAuthService_GenerateAccessToken(t *testing.T) {
 		TokenVersion: 1,
 	}
 
-	token, err := service.GenerateAccessToken(usr)
+	token, err := service.GenerateAccessToken(usr, "session")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return []byte("testsecret"), nil })
+	assert.NoError(t, err)
+	assert.Equal(t, "session", claims["sid"])
+}
+
+func TestAuthService_UsesProvidedKeys(t *testing.T) {
+	keys, err := jwtkeys.NewHMAC("provided")
+	assert.NoError(t, err)
+	service := NewAuthService(AuthServiceParams{Log: getTestLogger(), Keys: keys})
+
+	token, err := service.GenerateAccessToken(&user.User{ID: 1, Role: user.UserRole}, "session")
+	assert.NoError(t, err)
+	_, err = jwt.Parse(token, keys.Keyfunc)
+	assert.NoError(t, err)
+	assert.Empty(t, service.JWKS().Keys, "a shared secret is never published")
 }
 
 func TestAuthService_HashAndCheckPassword(t *testing.T) {
@@ -194,11 +213,21 @@ func TestAuthService_GenerateAndStoreRefreshToken(t *testing.T) {
 	userID := 1
 
 	expectedUUID := uuid.New()
-	repo.On("StoreRefreshToken", ctx, mock.AnythingOfType("string"), userID, mock.AnythingOfType("time.Time")).Return(expectedUUID, nil)
+	var stored jwtModel.RefreshToken
+	repo.On("StoreRefreshToken", ctx, mock.AnythingOfType("jwt.RefreshToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(jwtModel.RefreshToken) }).
+		Return(expectedUUID, nil)
 
-	token, err := service.GenerateAndStoreRefreshToken(ctx, userID)
+	device := jwtModel.Device{Name: "Pixel", UserAgent: "okhttp", IP: "10.0.0.1"}
+	token, sessionID, err := service.GenerateAndStoreRefreshToken(ctx, userID, device)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
+	assert.Equal(t, expectedUUID.String(), sessionID, "the first token names the session")
+	assert.Equal(t, device, stored.Device)
+	assert.Equal(t, hashToken(token), stored.TokenHash, "only the hash is stored")
+	assert.NotEqual(t, token, stored.TokenHash)
+	assert.Empty(t, stored.FamilyID, "a login starts a new family")
+	assert.Equal(t, userID, stored.UserID)
 	repo.AssertExpectations(t)
 }
 
@@ -215,16 +244,22 @@ func TestAuthService_RefreshAccessToken(t *testing.T) {
 		TokenVersion: 2,
 	}
 
-	repo.On("IncrementTokenVersion", ctx, usr.ID).Return(nil)
 	repo.On("GetUserByUserID", ctx, usr.ID).Return(usr, nil)
 
-	token, err := service.RefreshAccessToken(ctx, usr.ID)
+	token, err := service.RefreshAccessToken(ctx, usr.ID, "session")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
 	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "IncrementTokenVersion", mock.Anything, mock.Anything)
+
+	claims := jwtModel.AccessClaims{}
+	_, err = jwt.ParseWithClaims(token, &claims, service.Keys.Keyfunc)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, claims.Version, "the other sessions keep their version")
+	assert.Equal(t, "session", claims.SessionID)
 }
 
-func TestAuthService_RefreshAccessToken_IncrementTokenVersionError(t *testing.T) {
+func TestAuthService_RefreshAccessToken_UserError(t *testing.T) {
 	setupTestEnvironment()
 
 	repo := new(mockUserRepository)
@@ -234,12 +269,31 @@ func TestAuthService_RefreshAccessToken_IncrementTokenVersionError(t *testing.T)
 	userID := 1
 	expectedError := errors.New("database error")
 
-	repo.On("IncrementTokenVersion", ctx, userID).Return(expectedError)
+	repo.On("GetUserByUserID", ctx, userID).Return(nil, expectedError)
 
-	token, err := service.RefreshAccessToken(ctx, userID)
+	token, err := service.RefreshAccessToken(ctx, userID, "session")
 	assert.Error(t, err)
 	assert.Empty(t, token)
-	assert.Contains(t, err.Error(), "failed to invalidate old tokens")
+	assert.Contains(t, err.Error(), "failed to fetch user")
+	repo.AssertExpectations(t)
+}
+
+func TestAuthService_SessionActive(t *testing.T) {
+	setupTestEnvironment()
+
+	repo := new(mockUserRepository)
+	service := NewAuthService(AuthServiceParams{Repo: repo, Log: getTestLogger()})
+	ctx := t.Context()
+
+	repo.On("SessionActive", ctx, 1, "live").Return(true, nil)
+	repo.On("SessionActive", ctx, 1, "broken").Return(false, errors.New("database error"))
+
+	active, err := service.SessionActive(ctx, 1, "live")
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	_, err = service.SessionActive(ctx, 1, "broken")
+	assert.Error(t, err)
 	repo.AssertExpectations(t)
 }
 
@@ -252,12 +306,14 @@ func TestAuthService_UpdateRefreshToken(t *testing.T) {
 
 	refreshToken := "valid-refresh-token"
 	tokenID := uuid.New()
-	refreshTokenData := &jwt.RefreshToken{
+	refreshTokenData := &jwtModel.RefreshToken{
 		ID:        tokenID.String(),
+		FamilyID:  "family",
 		UserID:    1,
-		Token:     refreshToken,
+		TokenHash: hashToken(refreshToken),
 		ExpiresAt: time.Now().Add(time.Hour),
 		CreatedAt: time.Now(),
+		Device:    jwtModel.Device{Name: "Pixel"},
 	}
 
 	user := &user.User{
@@ -266,20 +322,92 @@ func TestAuthService_UpdateRefreshToken(t *testing.T) {
 		TokenVersion: 2,
 	}
 
-	repo.On("GetRefreshToken", ctx, refreshToken).Return(refreshTokenData, nil)
-	repo.On("DeleteRefreshToken", ctx, refreshToken).Return(nil)
-	repo.On("IncrementTokenVersion", ctx, refreshTokenData.UserID).Return(nil)
+	repo.On("GetRefreshToken", ctx, hashToken(refreshToken)).Return(refreshTokenData, nil)
+	repo.On("MarkRefreshTokenUsed", ctx, tokenID.String(), mock.AnythingOfType("time.Time")).Return(true, nil)
 	repo.On("GetUserByUserID", ctx, refreshTokenData.UserID).Return(user, nil)
 	newTokenID := uuid.New()
-	repo.On("StoreRefreshToken", ctx, mock.AnythingOfType("string"), refreshTokenData.UserID, mock.AnythingOfType("time.Time")).Return(newTokenID, nil)
+	repo.On("StoreRefreshToken", ctx, mock.MatchedBy(func(rt jwtModel.RefreshToken) bool {
+		return rt.FamilyID == "family" && rt.UserID == 1 && rt.Device == jwtModel.Device{Name: "Pixel", IP: "10.0.0.2"}
+	})).Return(newTokenID, nil)
 
-	accessToken, newRefreshToken, err := service.UpdateRefreshToken(ctx, refreshToken)
+	accessToken, newRefreshToken, err := service.UpdateRefreshToken(ctx, refreshToken, jwtModel.Device{IP: "10.0.0.2"})
 	assert.NoError(t, err)
 	assert.NotEmpty(t, accessToken)
 	assert.NotEmpty(t, newRefreshToken)
 	repo.AssertExpectations(t)
 }
 
+func TestAuthService_UpdateRefreshToken_Expired(t *testing.T) {
+	setupTestEnvironment()
+
+	repo := new(mockUserRepository)
+	service := NewAuthService(AuthServiceParams{Repo: repo, Log: getTestLogger()})
+	ctx := t.Context()
+
+	repo.On("GetRefreshToken", ctx, hashToken("old")).Return(&jwtModel.RefreshToken{
+		ID: "id", FamilyID: "family", UserID: 1, ExpiresAt: time.Now().Add(-time.Minute),
+	}, nil)
+
+	_, _, err := service.UpdateRefreshToken(ctx, "old", jwtModel.Device{})
+	assert.ErrorIs(t, err, erorrs.ErrInvalidToken)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "MarkRefreshTokenUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_UpdateRefreshToken_ReuseRevokesFamily(t *testing.T) {
+	setupTestEnvironment()
+
+	repo := new(mockUserRepository)
+	service := NewAuthService(AuthServiceParams{Repo: repo, Log: getTestLogger()})
+	ctx := t.Context()
+
+	usedAt := time.Now().Add(-time.Hour)
+	repo.On("GetRefreshToken", ctx, hashToken("stolen")).Return(&jwtModel.RefreshToken{
+		ID: "id", FamilyID: "family", UserID: 1, ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt,
+	}, nil)
+	repo.On("RevokeRefreshTokenFamily", ctx, "family", mock.AnythingOfType("time.Time")).Return(nil)
+
+	_, _, err := service.UpdateRefreshToken(ctx, "stolen", jwtModel.Device{})
+	assert.ErrorIs(t, err, erorrs.ErrInvalidToken)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "StoreRefreshToken", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "IncrementTokenVersion", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_UpdateRefreshToken_ConcurrentRotation(t *testing.T) {
+	setupTestEnvironment()
+
+	repo := new(mockUserRepository)
+	service := NewAuthService(AuthServiceParams{Repo: repo, Log: getTestLogger()})
+	ctx := t.Context()
+
+	repo.On("GetRefreshToken", ctx, hashToken("raced")).Return(&jwtModel.RefreshToken{
+		ID: "id", FamilyID: "family", UserID: 1, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	repo.On("MarkRefreshTokenUsed", ctx, "id", mock.AnythingOfType("time.Time")).Return(false, nil)
+	repo.On("RevokeRefreshTokenFamily", ctx, "family", mock.AnythingOfType("time.Time")).Return(nil)
+
+	_, _, err := service.UpdateRefreshToken(ctx, "raced", jwtModel.Device{})
+	assert.ErrorIs(t, err, erorrs.ErrInvalidToken)
+	repo.AssertExpectations(t)
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	setupTestEnvironment()
+
+	repo := new(mockUserRepository)
+	service := NewAuthService(AuthServiceParams{Repo: repo, Log: getTestLogger()})
+	ctx := t.Context()
+
+	repo.On("GetRefreshToken", ctx, hashToken("current")).Return(&jwtModel.RefreshToken{ID: "id", FamilyID: "family"}, nil)
+	repo.On("RevokeRefreshTokenFamily", ctx, "family", mock.AnythingOfType("time.Time")).Return(nil)
+	repo.On("GetRefreshToken", ctx, hashToken("unknown")).Return(nil, erorrs.ErrTokenNotFound)
+
+	assert.NoError(t, service.Logout(ctx, "current"))
+	assert.NoError(t, service.Logout(ctx, "unknown"))
+	repo.AssertExpectations(t)
+}
+
 func TestAuthService_NewAuthService_PanicOnMissingSecret(t *testing.T) {
 	err := os.Unsetenv("JWT_SECRET")
 	if err != nil {
@@ -6,8 +6,10 @@ import (
 	"time"
 	"workout-tracker/internal/model/user"
 	"workout-tracker/internal/model/user/jwt"
+	repo "workout-tracker/internal/repository/user"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -54,8 +56,8 @@ func (m *mockUserRepository) GetUserByUserID(ctx context.Context, id int) (*user
 	return userVal, nil
 }
 
-func (m *mockUserRepository) StoreRefreshToken(ctx context.Context, token string, userID int, expires time.Time) (uuid.UUID, error) {
-	args := m.Called(ctx, token, userID, expires)
+func (m *mockUserRepository) StoreRefreshToken(ctx context.Context, rt jwt.RefreshToken) (uuid.UUID, error) {
+	args := m.Called(ctx, rt)
 
 	tokenVal, ok := args.Get(0).(uuid.UUID)
 	if !ok {
@@ -69,8 +71,8 @@ func (m *mockUserRepository) StoreRefreshToken(ctx context.Context, token string
 	return tokenVal, nil
 }
 
-func (m *mockUserRepository) GetRefreshToken(ctx context.Context, token string) (*jwt.RefreshToken, error) {
-	args := m.Called(ctx, token)
+func (m *mockUserRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*jwt.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
 
 	refreshToken, ok := args.Get(0).(*jwt.RefreshToken)
 	if !ok {
@@ -84,15 +86,13 @@ func (m *mockUserRepository) GetRefreshToken(ctx context.Context, token string)
 	return refreshToken, nil
 }
 
-func (m *mockUserRepository) DeleteRefreshToken(ctx context.Context, token string) error {
-	args := m.Called(ctx, token)
-
-	err := args.Error(0)
-	if err != nil {
-		return fmt.Errorf("error DeleteRefreshToken: %w", err)
-	}
+func (m *mockUserRepository) MarkRefreshTokenUsed(ctx context.Context, id string, at time.Time) (bool, error) {
+	args := m.Called(ctx, id, at)
+	return args.Bool(0), args.Error(1)
+}
 
-	return nil
+func (m *mockUserRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string, at time.Time) error {
+	return m.Called(ctx, familyID, at).Error(0)
 }
 
 func (m *mockUserRepository) IncrementTokenVersion(ctx context.Context, userID int) error {
@@ -104,3 +104,52 @@ func (m *mockUserRepository) IncrementTokenVersion(ctx context.Context, userID i
 	}
 	return nil
 }
+
+func (m *mockUserRepository) WithTx(tx pgx.Tx) repo.UserRepositoryInterface {
+	return m
+}
+
+func (m *mockUserRepository) GetProfile(ctx context.Context, userID int) (*user.Profile, error) {
+	args := m.Called(ctx, userID)
+
+	profile, _ := args.Get(0).(*user.Profile)
+	return profile, args.Error(1)
+}
+
+func (m *mockUserRepository) UpdateProfile(ctx context.Context, p user.Profile) error {
+	return m.Called(ctx, p).Error(0)
+}
+
+func (m *mockUserRepository) UpdatePassword(ctx context.Context, userID int, password string) error {
+	return m.Called(ctx, userID, password).Error(0)
+}
+
+func (m *mockUserRepository) DeleteRefreshTokens(ctx context.Context, userID int) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func (m *mockUserRepository) DeleteUser(ctx context.Context, userID int, at time.Time) error {
+	return m.Called(ctx, userID, at).Error(0)
+}
+
+func (m *mockUserRepository) GetSessions(ctx context.Context, userID int, now time.Time) ([]jwt.Session, error) {
+	args := m.Called(ctx, userID, now)
+
+	sessions, _ := args.Get(0).([]jwt.Session)
+	return sessions, args.Error(1)
+}
+
+func (m *mockUserRepository) RevokeSession(ctx context.Context, userID int, sessionID string, at time.Time) error {
+	return m.Called(ctx, userID, sessionID, at).Error(0)
+}
+
+func (m *mockUserRepository) SessionActive(ctx context.Context, userID int, sessionID string) (bool, error) {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockUserRepository) RevokeOtherSessions(ctx context.Context, userID int, keepSessionID string, at time.Time) ([]string, error) {
+	args := m.Called(ctx, userID, keepSessionID, at)
+	revoked, _ := args.Get(0).([]string)
+	return revoked, args.Error(1)
+}
@@ -0,0 +1,98 @@
+package category
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	dto "workout-tracker/internal/dto/category"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/category"
+	repo "workout-tracker/internal/repository/category"
+	"workout-tracker/pkg/logger"
+
+	"go.uber.org/dig"
+)
+
+type CategoryServiceParams struct {
+	dig.In
+	Log  logger.SugaredLoggerInterface
+	Repo repo.CategoryRepositoryInterface
+}
+
+type CategoryService struct {
+	Log  logger.SugaredLoggerInterface
+	Repo repo.CategoryRepositoryInterface
+}
+
+func NewCategoryService(params CategoryServiceParams) *CategoryService {
+	return &CategoryService{
+		Log:  params.Log,
+		Repo: params.Repo,
+	}
+}
+
+func (s *CategoryService) GetCategories(ctx context.Context) ([]model.Category, error) {
+	categories, err := s.Repo.GetAllCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get categories: %w", err)
+	}
+	return categories, nil
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, input dto.CategoryRequest) (int, error) {
+	input, err := normalize(input)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := s.Repo.CreateCategory(ctx, input)
+	if err != nil {
+		return 0, fmt.Errorf("create category: %w", err)
+	}
+	return id, nil
+}
+
+func (s *CategoryService) UpdateCategory(ctx context.Context, id int, input dto.CategoryRequest) error {
+	input, err := normalize(input)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repo.UpdateCategory(ctx, id, input); err != nil {
+		return fmt.Errorf("update category %d: %w", id, err)
+	}
+	return nil
+}
+
+func (s *CategoryService) DeleteCategory(ctx context.Context, id int) error {
+	if err := s.Repo.DeleteCategory(ctx, id); err != nil {
+		return fmt.Errorf("delete category %d: %w", id, err)
+	}
+	return nil
+}
+
+// ReorderCategories makes ids, in order, the new display order of the categories.
+func (s *CategoryService) ReorderCategories(ctx context.Context, ids []int) error {
+	if err := s.Repo.ReorderCategories(ctx, ids); err != nil {
+		return fmt.Errorf("reorder categories: %w", err)
+	}
+	return nil
+}
+
+// normalize trims the text fields, lowercases the color and derives the slug from the name when
+// none was given.
+func normalize(input dto.CategoryRequest) (dto.CategoryRequest, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Icon = strings.TrimSpace(input.Icon)
+	input.Color = strings.ToLower(input.Color)
+	if input.Slug == "" {
+		input.Slug = model.Slugify(input.Name)
+	}
+	if input.Slug == "" {
+		return input, erorrs.Validation(erorrs.FieldError{
+			Field:   "slug",
+			Message: "is required when the name contains no letters or digits",
+		})
+	}
+	return input, nil
+}
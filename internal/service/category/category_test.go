@@ -0,0 +1,86 @@
+package category_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	dto "workout-tracker/internal/dto/category"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/category"
+	"workout-tracker/internal/service/category"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func newService(t *testing.T, repo *category.MockCategoryRepo) *category.CategoryService {
+	t.Helper()
+	return category.NewCategoryService(category.CategoryServiceParams{
+		Log:  zaptest.NewLogger(t).Sugar(),
+		Repo: repo,
+	})
+}
+
+func TestCreateCategory_DerivesSlugFromName(t *testing.T) {
+	repo := new(category.MockCategoryRepo)
+	repo.On("CreateCategory", mock.Anything, dto.CategoryRequest{Slug: "leg-day", Name: "Leg Day", Color: "#aabbcc"}).
+		Return(4, nil)
+
+	id, err := newService(t, repo).CreateCategory(t.Context(), dto.CategoryRequest{Name: " Leg Day ", Color: "#AABBCC"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, id)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateCategory_KeepsExplicitSlug(t *testing.T) {
+	repo := new(category.MockCategoryRepo)
+	repo.On("CreateCategory", mock.Anything, dto.CategoryRequest{Slug: "legs", Name: "Leg Day"}).Return(5, nil)
+
+	_, err := newService(t, repo).CreateCategory(t.Context(), dto.CategoryRequest{Slug: "legs", Name: "Leg Day"})
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateCategory_NameWithoutSlug(t *testing.T) {
+	repo := new(category.MockCategoryRepo)
+
+	_, err := newService(t, repo).CreateCategory(t.Context(), dto.CategoryRequest{Name: "💪"})
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, appErr.Status)
+	repo.AssertNotCalled(t, "CreateCategory", mock.Anything, mock.Anything)
+}
+
+func TestUpdateCategory_Duplicate(t *testing.T) {
+	repo := new(category.MockCategoryRepo)
+	repo.On("UpdateCategory", mock.Anything, 2, mock.Anything).Return(erorrs.ErrCategoryAlreadyExists)
+
+	err := newService(t, repo).UpdateCategory(t.Context(), 2, dto.CategoryRequest{Name: "Cardio"})
+	assert.ErrorIs(t, err, erorrs.ErrCategoryAlreadyExists)
+}
+
+func TestGetCategories(t *testing.T) {
+	repo := new(category.MockCategoryRepo)
+	repo.On("GetAllCategories", mock.Anything).Return([]model.Category{{ID: 1, Slug: "strength"}}, nil)
+
+	categories, err := newService(t, repo).GetCategories(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, categories, 1)
+}
+
+func TestDeleteCategory_Error(t *testing.T) {
+	repo := new(category.MockCategoryRepo)
+	repo.On("DeleteCategory", mock.Anything, 3).Return(errors.New("db down"))
+
+	assert.Error(t, newService(t, repo).DeleteCategory(t.Context(), 3))
+}
+
+func TestReorderCategories(t *testing.T) {
+	repo := new(category.MockCategoryRepo)
+	repo.On("ReorderCategories", mock.Anything, []int{2, 1}).Return(erorrs.ErrNotFound)
+
+	err := newService(t, repo).ReorderCategories(t.Context(), []int{2, 1})
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
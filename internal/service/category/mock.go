@@ -0,0 +1,45 @@
+package category
+
+import (
+	"context"
+	dto "workout-tracker/internal/dto/category"
+	model "workout-tracker/internal/model/category"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCategoryRepo struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepo) CreateCategory(ctx context.Context, input dto.CategoryRequest) (int, error) {
+	args := m.Called(ctx, input)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCategoryRepo) UpdateCategory(ctx context.Context, id int, input dto.CategoryRequest) error {
+	args := m.Called(ctx, id, input)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepo) DeleteCategory(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepo) GetAllCategories(ctx context.Context) ([]model.Category, error) {
+	args := m.Called(ctx)
+	categories, _ := args.Get(0).([]model.Category)
+	return categories, args.Error(1)
+}
+
+func (m *MockCategoryRepo) GetCategoryBySlug(ctx context.Context, slug string) (*model.Category, error) {
+	args := m.Called(ctx, slug)
+	c, _ := args.Get(0).(*model.Category)
+	return c, args.Error(1)
+}
+
+func (m *MockCategoryRepo) ReorderCategories(ctx context.Context, ids []int) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
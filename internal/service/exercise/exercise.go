@@ -0,0 +1,79 @@
+package exercise
+
+import (
+	"context"
+	"fmt"
+	dto "workout-tracker/internal/dto/exercise"
+	repo "workout-tracker/internal/repository/exercise"
+	"workout-tracker/pkg/logger"
+
+	"go.uber.org/dig"
+)
+
+type ExerciseServiceParams struct {
+	dig.In
+
+	Repo repo.ExerciseRepositoryInterface
+	Log  logger.SugaredLoggerInterface
+}
+
+// ExerciseService manages the exercises users create for themselves. Private exercises are only
+// visible to and editable by their owner.
+type ExerciseService struct {
+	Repo repo.ExerciseRepositoryInterface
+	Log  logger.SugaredLoggerInterface
+}
+
+func NewExerciseService(params ExerciseServiceParams) *ExerciseService {
+	return &ExerciseService{
+		Repo: params.Repo,
+		Log:  params.Log,
+	}
+}
+
+// GetExercises returns the global catalogue merged with the user's private exercises.
+func (s *ExerciseService) GetExercises(ctx context.Context, userID int, filter dto.ExerciseFilter) (*dto.ExercisePage, error) {
+	filter, err := filter.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	filter.UserID = userID
+
+	exercises, total, err := s.Repo.GetAllExercises(ctx, filter)
+	if err != nil {
+		s.Log.Errorw("failed to get exercises", "userID", userID, "error", err)
+		return nil, fmt.Errorf("get exercises: %w", err)
+	}
+
+	return &dto.ExercisePage{
+		Items:  exercises,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+func (s *ExerciseService) CreateExercise(ctx context.Context, userID int, input dto.CreateExerciseRequest) (int, error) {
+	id, err := s.Repo.CreateExercise(ctx, &userID, input.Normalize())
+	if err != nil {
+		s.Log.Errorw("failed to create private exercise", "userID", userID, "error", err)
+		return 0, fmt.Errorf("create exercise: %w", err)
+	}
+	return id, nil
+}
+
+func (s *ExerciseService) UpdateExercise(ctx context.Context, userID, id int, input dto.CreateExerciseRequest) error {
+	if err := s.Repo.UpdateExercise(ctx, id, &userID, input.Normalize()); err != nil {
+		s.Log.Errorw("failed to update private exercise", "userID", userID, "id", id, "error", err)
+		return fmt.Errorf("update exercise: %w", err)
+	}
+	return nil
+}
+
+func (s *ExerciseService) DeleteExercise(ctx context.Context, userID, id int) error {
+	if err := s.Repo.DeleteExercise(ctx, id, &userID); err != nil {
+		s.Log.Errorw("failed to delete private exercise", "userID", userID, "id", id, "error", err)
+		return fmt.Errorf("delete exercise: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package exercise
+
+import (
+	"errors"
+	"testing"
+	dto "workout-tracker/internal/dto/exercise"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/exercise"
+	"workout-tracker/internal/service/admin"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newService(repo *admin.MockExerciseRepo) *ExerciseService {
+	return NewExerciseService(ExerciseServiceParams{Repo: repo, Log: zap.NewNop().Sugar()})
+}
+
+func TestGetExercises_MergesOwnExercises(t *testing.T) {
+	ctx := t.Context()
+	repo := new(admin.MockExerciseRepo)
+	owner := 5
+	exs := []model.Exercise{{ID: 1, Name: "Squat"}, {ID: 2, Name: "Zercher squat", OwnerUserID: &owner}}
+	repo.On("GetAllExercises", ctx, dto.ExerciseFilter{UserID: 5, Search: "squat", Limit: 20}).Return(exs, 2, nil)
+
+	page, err := newService(repo).GetExercises(ctx, 5, dto.ExerciseFilter{Search: "squat"})
+	require.NoError(t, err)
+	assert.Equal(t, exs, page.Items)
+	assert.Equal(t, 2, page.Total)
+	repo.AssertExpectations(t)
+}
+
+func TestGetExercises_IgnoresCallerSuppliedUser(t *testing.T) {
+	ctx := t.Context()
+	repo := new(admin.MockExerciseRepo)
+	repo.On("GetAllExercises", ctx, dto.ExerciseFilter{UserID: 5, Limit: 20}).Return([]model.Exercise{}, 0, nil)
+
+	_, err := newService(repo).GetExercises(ctx, 5, dto.ExerciseFilter{UserID: 9})
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateExercise_OwnedByUser(t *testing.T) {
+	ctx := t.Context()
+	repo := new(admin.MockExerciseRepo)
+	owner := 5
+	input := dto.CreateExerciseRequest{Name: "Sled push", Equipment: []string{"sled"}}
+	repo.On("CreateExercise", ctx, &owner, input).Return(11, nil)
+
+	id, err := newService(repo).CreateExercise(ctx, 5, dto.CreateExerciseRequest{Name: "Sled push", Equipment: []string{" Sled "}})
+	require.NoError(t, err)
+	assert.Equal(t, 11, id)
+}
+
+func TestUpdateExercise_NotOwner(t *testing.T) {
+	ctx := t.Context()
+	repo := new(admin.MockExerciseRepo)
+	owner := 5
+	input := dto.CreateExerciseRequest{Name: "Sled push"}
+	repo.On("UpdateExercise", ctx, 3, &owner, input).Return(erorrs.ErrNotFound)
+
+	err := newService(repo).UpdateExercise(ctx, 5, 3, input)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestDeleteExercise_Error(t *testing.T) {
+	ctx := t.Context()
+	repo := new(admin.MockExerciseRepo)
+	owner := 5
+	repo.On("DeleteExercise", ctx, 3, &owner).Return(errors.New("db"))
+
+	err := newService(repo).DeleteExercise(ctx, 5, 3)
+	assert.Error(t, err)
+}
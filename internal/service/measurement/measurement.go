@@ -0,0 +1,212 @@
+package measurement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	dto "workout-tracker/internal/dto/measurement"
+	"workout-tracker/internal/erorrs"
+	categoryModel "workout-tracker/internal/model/category"
+	model "workout-tracker/internal/model/measurement"
+	repo "workout-tracker/internal/repository/measurement"
+	"workout-tracker/pkg/logger"
+
+	"go.uber.org/dig"
+)
+
+const (
+	// DefaultWindowDays is the span of the moving average when none is asked for.
+	DefaultWindowDays = 7
+	MaxWindowDays     = 90
+)
+
+var errInvalidWindow = erorrs.BadRequest(fmt.Sprintf("window must be between 1 and %d days", MaxWindowDays))
+
+type MeasurementServiceParams struct {
+	dig.In
+
+	Repo repo.MeasurementRepositoryInterface
+	Log  logger.SugaredLoggerInterface
+}
+
+type MeasurementService struct {
+	Repo repo.MeasurementRepositoryInterface
+	Log  logger.SugaredLoggerInterface
+}
+
+func NewMeasurementService(params MeasurementServiceParams) *MeasurementService {
+	return &MeasurementService{
+		Repo: params.Repo,
+		Log:  params.Log,
+	}
+}
+
+func (s *MeasurementService) GetMetrics(ctx context.Context, userID int) ([]model.Metric, error) {
+	metrics, err := s.Repo.GetMetrics(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get metrics: %w", err)
+	}
+	return metrics, nil
+}
+
+// CreateMetric adds a metric of the user's own. Its slug may not shadow a built-in metric.
+func (s *MeasurementService) CreateMetric(ctx context.Context, userID int, input dto.MetricRequest) (*model.Metric, error) {
+	name := strings.TrimSpace(input.Name)
+	slug := input.Slug
+	if slug == "" {
+		slug = categoryModel.Slugify(name)
+	}
+	if slug == "" {
+		return nil, erorrs.Validation(erorrs.FieldError{
+			Field:   "slug",
+			Message: "is required when the name contains no letters or digits",
+		})
+	}
+
+	existing, err := s.Repo.GetMetricBySlug(ctx, userID, slug)
+	switch {
+	case err == nil && existing.BuiltIn():
+		return nil, erorrs.Validation(erorrs.FieldError{Field: "slug", Message: "is taken by a built-in metric"})
+	case err == nil:
+		return nil, erorrs.ErrMetricAlreadyExists
+	case !errors.Is(err, erorrs.ErrNotFound):
+		return nil, fmt.Errorf("get metric: %w", err)
+	}
+
+	kind := model.Kind(input.Kind)
+	metric := model.Metric{
+		UserID:    &userID,
+		Slug:      slug,
+		Name:      name,
+		Kind:      kind,
+		Unit:      kind.CanonicalUnit(),
+		CreatedAt: time.Now(),
+	}
+	id, err := s.Repo.CreateMetric(ctx, metric)
+	if err != nil {
+		return nil, fmt.Errorf("create metric: %w", err)
+	}
+	metric.ID = id
+	return &metric, nil
+}
+
+func (s *MeasurementService) DeleteMetric(ctx context.Context, userID int, slug string) error {
+	if err := s.Repo.DeleteMetric(ctx, userID, slug); err != nil {
+		return fmt.Errorf("delete metric %q: %w", slug, err)
+	}
+	return nil
+}
+
+// AddMeasurement records a value, converting it from the given unit, or the user's preferred one, to
+// the metric's canonical unit.
+func (s *MeasurementService) AddMeasurement(ctx context.Context, userID int, input dto.MeasurementRequest) (int, error) {
+	metric, err := s.Repo.GetMetricBySlug(ctx, userID, input.Metric)
+	if err != nil {
+		if errors.Is(err, erorrs.ErrNotFound) {
+			return 0, erorrs.Validation(erorrs.FieldError{Field: "metric", Message: "does not exist"})
+		}
+		return 0, fmt.Errorf("get metric: %w", err)
+	}
+
+	unit := metric.Kind.PreferredUnit(input.UnitSystem)
+	if input.Unit != "" {
+		unit = model.Unit(input.Unit)
+	}
+	value, err := model.ToCanonical(*input.Value, unit, metric.Kind)
+	if err != nil {
+		return 0, erorrs.Validation(erorrs.FieldError{
+			Field:   "unit",
+			Message: fmt.Sprintf("does not fit a %s metric", metric.Kind),
+		})
+	}
+
+	now := time.Now()
+	measuredAt := now
+	if input.MeasuredAt != nil {
+		measuredAt = *input.MeasuredAt
+	}
+	id, err := s.Repo.CreateMeasurement(ctx, model.Measurement{
+		UserID:     userID,
+		MetricID:   metric.ID,
+		Value:      value,
+		Note:       strings.TrimSpace(input.Note),
+		MeasuredAt: measuredAt,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("create measurement: %w", err)
+	}
+	return id, nil
+}
+
+// GetSeries returns the user's measurements of the metric with their moving averages. The
+// measurements of the window before From are fetched too, so the first averages are complete.
+func (s *MeasurementService) GetSeries(ctx context.Context, userID int, slug string, query dto.SeriesQuery) (*model.Series, error) {
+	if query.WindowDays == 0 {
+		query.WindowDays = DefaultWindowDays
+	}
+	if query.WindowDays < 1 || query.WindowDays > MaxWindowDays {
+		return nil, errInvalidWindow
+	}
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return nil, erorrs.ErrInvalidDateRange
+	}
+
+	metric, err := s.Repo.GetMetricBySlug(ctx, userID, slug)
+	if err != nil {
+		return nil, fmt.Errorf("get metric %q: %w", slug, err)
+	}
+	unit := metric.Kind.PreferredUnit(query.UnitSystem)
+	if query.Unit != "" {
+		unit = model.Unit(query.Unit)
+		if !metric.Kind.Fits(unit) {
+			return nil, erorrs.BadRequest(fmt.Sprintf("unit %q does not fit a %s metric", unit, metric.Kind))
+		}
+	}
+
+	var fetchFrom *time.Time
+	if query.From != nil {
+		start := query.From.AddDate(0, 0, -query.WindowDays)
+		fetchFrom = &start
+	}
+	measurements, err := s.Repo.GetMeasurements(ctx, userID, metric.ID, fetchFrom, query.To)
+	if err != nil {
+		return nil, fmt.Errorf("get measurements: %w", err)
+	}
+
+	series := model.BuildSeries(*metric, measurements, query.WindowDays, unit)
+	if query.From != nil {
+		first := 0
+		for first < len(series.Points) && series.Points[first].MeasuredAt.Before(*query.From) {
+			first++
+		}
+		series.Points = series.Points[first:]
+		if len(series.Points) == 0 {
+			series.Latest, series.WeeklyChange = nil, nil
+		}
+	}
+	return &series, nil
+}
+
+func (s *MeasurementService) DeleteMeasurement(ctx context.Context, userID int, slug string, id int) error {
+	metric, err := s.Repo.GetMetricBySlug(ctx, userID, slug)
+	if err != nil {
+		return fmt.Errorf("get metric %q: %w", slug, err)
+	}
+	if err := s.Repo.DeleteMeasurement(ctx, userID, metric.ID, id); err != nil {
+		return fmt.Errorf("delete measurement %d: %w", id, err)
+	}
+	return nil
+}
+
+// LatestBodyweight returns the user's last bodyweight in kilograms measured at or before at, or nil
+// when they never recorded one.
+func (s *MeasurementService) LatestBodyweight(ctx context.Context, userID int, at time.Time) (*float64, error) {
+	value, err := s.Repo.GetLatestValue(ctx, userID, model.Bodyweight, at)
+	if err != nil {
+		return nil, fmt.Errorf("get latest bodyweight: %w", err)
+	}
+	return value, nil
+}
@@ -0,0 +1,163 @@
+package measurement_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+	dto "workout-tracker/internal/dto/measurement"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/measurement"
+	"workout-tracker/internal/model/units"
+	repo "workout-tracker/internal/repository/measurement"
+	"workout-tracker/internal/service/measurement"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// stubMeasurementRepo knows the built-in bodyweight metric, user 1's "calves" and their measurements.
+type stubMeasurementRepo struct {
+	repo.MeasurementRepositoryInterface
+	Measurements  []model.Measurement
+	Created       *model.Measurement
+	CreatedMetric *model.Metric
+	FetchedFrom   *time.Time
+}
+
+func (s *stubMeasurementRepo) GetMetricBySlug(ctx context.Context, userID int, slug string) (*model.Metric, error) {
+	switch {
+	case slug == model.Bodyweight:
+		return &model.Metric{ID: 1, Slug: slug, Kind: model.KindMass, Unit: model.UnitKilogram}, nil
+	case slug == "calves" && userID == 1:
+		return &model.Metric{ID: 9, UserID: &userID, Slug: slug, Kind: model.KindLength, Unit: model.UnitCentimetre}, nil
+	default:
+		return nil, erorrs.ErrNotFound
+	}
+}
+func (s *stubMeasurementRepo) CreateMetric(ctx context.Context, m model.Metric) (int, error) {
+	s.CreatedMetric = &m
+	return 10, nil
+}
+func (s *stubMeasurementRepo) CreateMeasurement(ctx context.Context, m model.Measurement) (int, error) {
+	s.Created = &m
+	return 20, nil
+}
+func (s *stubMeasurementRepo) GetMeasurements(ctx context.Context, userID, metricID int, from, to *time.Time) (
+	[]model.Measurement, error) {
+	s.FetchedFrom = from
+	return s.Measurements, nil
+}
+
+func newTestService(t *testing.T, r *stubMeasurementRepo) *measurement.MeasurementService {
+	t.Helper()
+	return measurement.NewMeasurementService(measurement.MeasurementServiceParams{
+		Repo: r,
+		Log:  zaptest.NewLogger(t).Sugar(),
+	})
+}
+
+func day(d int) time.Time {
+	return time.Date(2026, 3, d, 8, 0, 0, 0, time.UTC)
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestCreateMetric_DerivesSlug(t *testing.T) {
+	r := &stubMeasurementRepo{}
+	metric, err := newTestService(t, r).CreateMetric(t.Context(), 2, dto.MetricRequest{Name: " Left Calf ", Kind: "length"})
+	require.NoError(t, err)
+	assert.Equal(t, 10, metric.ID)
+	assert.Equal(t, "left-calf", r.CreatedMetric.Slug)
+	assert.Equal(t, "Left Calf", r.CreatedMetric.Name)
+	assert.Equal(t, 2, *r.CreatedMetric.UserID)
+	assert.Equal(t, model.UnitCentimetre, metric.Unit)
+}
+
+func TestCreateMetric_Taken(t *testing.T) {
+	service := newTestService(t, &stubMeasurementRepo{})
+
+	_, err := service.CreateMetric(t.Context(), 1, dto.MetricRequest{Name: "Bodyweight", Kind: "mass"})
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, appErr.Status)
+
+	_, err = service.CreateMetric(t.Context(), 1, dto.MetricRequest{Name: "Calves", Kind: "length"})
+	assert.ErrorIs(t, err, erorrs.ErrMetricAlreadyExists)
+}
+
+func TestAddMeasurement_ConvertsUnit(t *testing.T) {
+	r := &stubMeasurementRepo{}
+	measuredAt := day(2)
+	id, err := newTestService(t, r).AddMeasurement(t.Context(), 1, dto.MeasurementRequest{
+		Metric: model.Bodyweight, Value: floatPtr(176.37), Unit: "lb", MeasuredAt: &measuredAt,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 20, id)
+	assert.InDelta(t, 80, r.Created.Value, 0.001)
+	assert.Equal(t, day(2), r.Created.MeasuredAt)
+}
+
+func TestAddMeasurement_Invalid(t *testing.T) {
+	service := newTestService(t, &stubMeasurementRepo{})
+
+	_, err := service.AddMeasurement(t.Context(), 1, dto.MeasurementRequest{Metric: "calves", Value: floatPtr(40), Unit: "kg"})
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "unit", appErr.Fields[0].Field)
+
+	_, err = service.AddMeasurement(t.Context(), 2, dto.MeasurementRequest{Metric: "calves", Value: floatPtr(40)})
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, []erorrs.FieldError{{Field: "metric", Message: "does not exist"}}, appErr.Fields)
+}
+
+func TestAddMeasurement_PreferredUnit(t *testing.T) {
+	r := &stubMeasurementRepo{}
+	_, err := newTestService(t, r).AddMeasurement(t.Context(), 1, dto.MeasurementRequest{
+		Metric: "calves", Value: floatPtr(15), UnitSystem: units.Imperial,
+	})
+	require.NoError(t, err)
+	assert.InDelta(t, 38.1, r.Created.Value, 0.001)
+}
+
+func TestGetSeries_PreferredUnit(t *testing.T) {
+	r := &stubMeasurementRepo{Measurements: []model.Measurement{{ID: 1, Value: 80, MeasuredAt: day(1)}}}
+	series, err := newTestService(t, r).GetSeries(t.Context(), 1, model.Bodyweight, dto.SeriesQuery{UnitSystem: units.Imperial})
+	require.NoError(t, err)
+	assert.Equal(t, model.UnitPound, series.Unit)
+	assert.InDelta(t, 176.37, series.Points[0].Value, 0.01)
+}
+
+func TestGetSeries_FromIncludesWindow(t *testing.T) {
+	r := &stubMeasurementRepo{Measurements: []model.Measurement{
+		{ID: 1, Value: 82, MeasuredAt: day(1)},
+		{ID: 2, Value: 80, MeasuredAt: day(8)},
+		{ID: 3, Value: 81, MeasuredAt: day(9)},
+	}}
+	from := day(8)
+
+	series, err := newTestService(t, r).GetSeries(t.Context(), 1, model.Bodyweight, dto.SeriesQuery{From: &from, WindowDays: 14})
+	require.NoError(t, err)
+	assert.Equal(t, day(8).AddDate(0, 0, -14), *r.FetchedFrom)
+	require.Len(t, series.Points, 2, "points before from only feed the averages")
+	assert.Equal(t, 81.0, series.Points[0].MovingAverage)
+	assert.Equal(t, 81.0, *series.Latest)
+	assert.Equal(t, model.UnitKilogram, series.Unit)
+}
+
+func TestGetSeries_Errors(t *testing.T) {
+	service := newTestService(t, &stubMeasurementRepo{})
+
+	_, err := service.GetSeries(t.Context(), 1, model.Bodyweight, dto.SeriesQuery{WindowDays: 91})
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Status)
+
+	_, err = service.GetSeries(t.Context(), 1, model.Bodyweight, dto.SeriesQuery{Unit: "in"})
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Status)
+
+	_, err = service.GetSeries(t.Context(), 2, "calves", dto.SeriesQuery{})
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
@@ -0,0 +1,340 @@
+package program
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	dto "workout-tracker/internal/dto/program"
+	workoutDTO "workout-tracker/internal/dto/workout"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/program"
+	join "workout-tracker/internal/model/workoutexercisejoin"
+	programRepo "workout-tracker/internal/repository/program"
+	workoutRepo "workout-tracker/internal/repository/workout"
+	"workout-tracker/pkg/db"
+	"workout-tracker/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/dig"
+)
+
+const dateLayout = "2006-01-02"
+
+type ProgramServiceParams struct {
+	dig.In
+
+	Repo        programRepo.ProgramRepositoryInterface
+	WorkoutRepo workoutRepo.WorkoutRepositoryInterface
+	Tx          db.UnitOfWork
+	Log         logger.SugaredLoggerInterface
+}
+
+type ProgramService struct {
+	Repo        programRepo.ProgramRepositoryInterface
+	WorkoutRepo workoutRepo.WorkoutRepositoryInterface
+	Tx          db.UnitOfWork
+	Log         logger.SugaredLoggerInterface
+}
+
+func NewProgramService(params ProgramServiceParams) *ProgramService {
+	return &ProgramService{
+		Repo:        params.Repo,
+		WorkoutRepo: params.WorkoutRepo,
+		Tx:          params.Tx,
+		Log:         params.Log,
+	}
+}
+
+// CreateProgram stores the program with its days and progression rules in one transaction. Every
+// day must use one of the author's own workouts.
+func (s *ProgramService) CreateProgram(ctx context.Context, userID int, input dto.ProgramRequest) (int, error) {
+	days, rules := input.Days(), input.Rules()
+	if err := s.validateProgram(ctx, userID, input, days, rules); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	program := model.Program{
+		UserID:      userID,
+		Name:        strings.TrimSpace(input.Name),
+		Description: strings.TrimSpace(input.Description),
+		WeekCount:   len(input.Weeks),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	var id int
+	err := s.Tx.WithinTx(ctx, func(tx pgx.Tx) error {
+		repo := s.Repo.WithTx(tx)
+
+		var err error
+		id, err = repo.CreateProgram(ctx, program)
+		if err != nil {
+			return fmt.Errorf("create program: %w", err)
+		}
+		if err := repo.BulkInsertProgramDays(ctx, id, days); err != nil {
+			return fmt.Errorf("insert program days: %w", err)
+		}
+		if err := repo.BulkInsertProgressionRules(ctx, id, rules); err != nil {
+			return fmt.Errorf("insert progression rules: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// validateProgram reports every problem of a program at once: workouts that are not the author's,
+// rules for exercises no workout of the program contains, and programs without any workout.
+func (s *ProgramService) validateProgram(ctx context.Context, userID int, input dto.ProgramRequest,
+	days []model.Day, rules []model.ProgressionRule) error {
+	var fields []erorrs.FieldError
+
+	if strings.TrimSpace(input.Name) == "" {
+		fields = append(fields, erorrs.FieldError{Field: "name", Message: "must not be blank"})
+	}
+	if len(days) == 0 {
+		fields = append(fields, erorrs.FieldError{Field: "weeks", Message: "must schedule at least one workout"})
+	}
+
+	owned := make(map[int]bool)
+	for i, w := range input.Weeks {
+		for j, d := range w.Days {
+			known, checked := owned[d.WorkoutID]
+			if !checked {
+				var err error
+				if known, err = s.ownsWorkout(ctx, userID, d.WorkoutID); err != nil {
+					return err
+				}
+				owned[d.WorkoutID] = known
+			}
+			if !known {
+				fields = append(fields, erorrs.FieldError{
+					Field:   fmt.Sprintf("weeks[%d].days[%d].workout_id", i, j),
+					Message: "does not exist",
+				})
+			}
+		}
+	}
+
+	ids := make([]int, 0, len(owned))
+	for id, known := range owned {
+		if known {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	exercises, err := s.WorkoutRepo.GetExercisesByWorkoutIDs(ctx, ids)
+	if err != nil {
+		s.Log.Errorw("failed to load exercises of program workouts", "error", err)
+		return fmt.Errorf("load exercises of program workouts: %w", err)
+	}
+	used := make(map[int]bool)
+	for _, list := range exercises {
+		for _, e := range list {
+			used[e.ExerciseID] = true
+		}
+	}
+
+	for k, r := range rules {
+		if !r.Type.IsValid() {
+			fields = append(fields, erorrs.FieldError{
+				Field:   fmt.Sprintf("progression[%d].type", k),
+				Message: "must be one of weight, percent_1rm, reps",
+			})
+		}
+		if r.ExerciseID != nil && !used[*r.ExerciseID] {
+			fields = append(fields, erorrs.FieldError{
+				Field:   fmt.Sprintf("progression[%d].exercise_id", k),
+				Message: "is not part of any workout of the program",
+			})
+		}
+	}
+
+	if len(fields) > 0 {
+		return erorrs.Validation(fields...)
+	}
+	return nil
+}
+
+func (s *ProgramService) ownsWorkout(ctx context.Context, userID, workoutID int) (bool, error) {
+	ownerID, err := s.WorkoutRepo.GetWorkoutOwner(ctx, workoutID)
+	if err != nil {
+		if errors.Is(err, erorrs.ErrNotFound) {
+			return false, nil
+		}
+		s.Log.Errorw("failed to get workout owner", "workoutID", workoutID, "error", err)
+		return false, fmt.Errorf("get workout owner: %w", err)
+	}
+	return ownerID == userID, nil
+}
+
+// GetPrograms lists all programs; their weeks and rules are only returned by GetProgram.
+func (s *ProgramService) GetPrograms(ctx context.Context) ([]model.Program, error) {
+	programs, err := s.Repo.GetPrograms(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get programs: %w", err)
+	}
+	return programs, nil
+}
+
+func (s *ProgramService) GetProgram(ctx context.Context, id int) (*model.Program, error) {
+	program, err := s.Repo.GetProgramByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("program %d: %w", id, err)
+	}
+
+	days, err := s.Repo.GetProgramDays(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get program days: %w", err)
+	}
+	program.Weeks = model.GroupWeeks(program.WeekCount, days)
+
+	program.Progression, err = s.Repo.GetProgressionRules(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get progression rules: %w", err)
+	}
+	return program, nil
+}
+
+// DeleteProgram lets only the program's author delete it. Users enrolled in it are unenrolled.
+func (s *ProgramService) DeleteProgram(ctx context.Context, userID, id int) error {
+	program, err := s.Repo.GetProgramByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("program %d: %w", id, err)
+	}
+	if program.UserID != userID {
+		return fmt.Errorf("program %d: %w", id, erorrs.ErrForbidden)
+	}
+
+	if err := s.Repo.DeleteProgram(ctx, id); err != nil {
+		return fmt.Errorf("delete program %d: %w", id, err)
+	}
+	return nil
+}
+
+// Enroll puts the user on the program from startDate on, replacing any program they followed before.
+func (s *ProgramService) Enroll(ctx context.Context, userID, programID int, startDate time.Time) (*model.Enrollment, error) {
+	if _, err := s.Repo.GetProgramByID(ctx, programID); err != nil {
+		return nil, fmt.Errorf("program %d: %w", programID, err)
+	}
+
+	enrollment := model.Enrollment{
+		UserID:    userID,
+		ProgramID: programID,
+		StartDate: time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.UTC),
+		CreatedAt: time.Now(),
+	}
+	if err := s.Repo.UpsertEnrollment(ctx, enrollment); err != nil {
+		return nil, fmt.Errorf("enroll: %w", err)
+	}
+	return &enrollment, nil
+}
+
+func (s *ProgramService) Unenroll(ctx context.Context, userID int) error {
+	if err := s.Repo.DeleteEnrollment(ctx, userID); err != nil {
+		return fmt.Errorf("unenroll: %w", err)
+	}
+	return nil
+}
+
+// GetToday works out what the user's program plans for date. On a workout day the template is
+// returned with the progression rules applied for the current week.
+func (s *ProgramService) GetToday(ctx context.Context, userID int, date time.Time) (*dto.TodayResponse, error) {
+	enrollment, err := s.Repo.GetEnrollment(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get enrollment: %w", err)
+	}
+
+	program, err := s.Repo.GetProgramByID(ctx, enrollment.ProgramID)
+	if err != nil {
+		return nil, fmt.Errorf("program %d: %w", enrollment.ProgramID, err)
+	}
+
+	today := &dto.TodayResponse{
+		Date:        date.Format(dateLayout),
+		ProgramID:   program.ID,
+		ProgramName: program.Name,
+	}
+
+	week, day := enrollment.Position(date)
+	switch {
+	case week == 0:
+		today.Status = model.TodayNotStarted
+		return today, nil
+	case week > program.WeekCount:
+		today.Status = model.TodayFinished
+		return today, nil
+	}
+	today.Week, today.Day = week, day
+
+	days, err := s.Repo.GetProgramDays(ctx, program.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get program days: %w", err)
+	}
+
+	workoutID := 0
+	for _, d := range days {
+		if d.Week == week && d.Day == day {
+			workoutID = d.WorkoutID
+		}
+	}
+	if workoutID == 0 {
+		today.Status = model.TodayRest
+		return today, nil
+	}
+
+	workout, err := s.loadTemplate(ctx, program, workoutID, week)
+	if err != nil {
+		return nil, err
+	}
+	today.Status = model.TodayWorkout
+	today.Workout = workout
+	return today, nil
+}
+
+// loadTemplate reads one of the program's workouts, which belong to the program's author, and
+// progresses its targets to the given week. Workouts the author has deleted since are still found.
+func (s *ProgramService) loadTemplate(ctx context.Context, program *model.Program, workoutID, week int) (
+	*workoutDTO.WorkoutWithExercises, error) {
+	workout, err := s.WorkoutRepo.GetTemplateByID(ctx, workoutID, program.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("workout %d: %w", workoutID, err)
+	}
+
+	exercises, err := s.WorkoutRepo.GetWorkoutExercises(ctx, workoutID)
+	if err != nil {
+		return nil, fmt.Errorf("get exercises for workout: %w", err)
+	}
+	sets, err := s.WorkoutRepo.GetSetPrescriptions(ctx, workoutID)
+	if err != nil {
+		return nil, fmt.Errorf("get set prescriptions for workout: %w", err)
+	}
+	join.AttachSets(exercises, sets)
+
+	rules, err := s.Repo.GetProgressionRules(ctx, program.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get progression rules: %w", err)
+	}
+	for i := range exercises {
+		for _, r := range rules {
+			r.Apply(&exercises[i], week)
+		}
+	}
+
+	blocks, err := s.WorkoutRepo.GetBlocksByWorkoutIDs(ctx, []int{workoutID})
+	if err != nil {
+		return nil, fmt.Errorf("get blocks for workout: %w", err)
+	}
+
+	return &workoutDTO.WorkoutWithExercises{
+		Workout:   *workout,
+		Exercises: exercises,
+		Blocks:    join.Group(blocks[workoutID], exercises),
+	}, nil
+}
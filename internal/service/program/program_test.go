@@ -0,0 +1,328 @@
+package program_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+	dto "workout-tracker/internal/dto/program"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/program"
+	workoutModel "workout-tracker/internal/model/workout"
+	join "workout-tracker/internal/model/workoutexercisejoin"
+	programRepo "workout-tracker/internal/repository/program"
+	workoutRepo "workout-tracker/internal/repository/workout"
+	"workout-tracker/internal/service/program"
+	"workout-tracker/pkg/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type stubProgramRepo struct {
+	programRepo.ProgramRepositoryInterface
+	Program    *model.Program
+	Days       []model.Day
+	Rules      []model.ProgressionRule
+	Enrollment *model.Enrollment
+	CreatedID  int
+	Deleted    int
+	Enrolled   *model.Enrollment
+}
+
+func (s *stubProgramRepo) WithTx(tx pgx.Tx) programRepo.ProgramRepositoryInterface { return s }
+
+func (s *stubProgramRepo) CreateProgram(ctx context.Context, p model.Program) (int, error) {
+	s.Program = &p
+	return s.CreatedID, nil
+}
+func (s *stubProgramRepo) BulkInsertProgramDays(ctx context.Context, programID int, days []model.Day) error {
+	s.Days = days
+	return nil
+}
+func (s *stubProgramRepo) BulkInsertProgressionRules(ctx context.Context, programID int, rules []model.ProgressionRule) error {
+	s.Rules = rules
+	return nil
+}
+func (s *stubProgramRepo) GetProgramByID(ctx context.Context, id int) (*model.Program, error) {
+	if s.Program == nil || s.Program.ID != id {
+		return nil, erorrs.ErrNotFound
+	}
+	p := *s.Program
+	return &p, nil
+}
+func (s *stubProgramRepo) GetProgramDays(ctx context.Context, programID int) ([]model.Day, error) {
+	return s.Days, nil
+}
+func (s *stubProgramRepo) GetProgressionRules(ctx context.Context, programID int) ([]model.ProgressionRule, error) {
+	return s.Rules, nil
+}
+func (s *stubProgramRepo) DeleteProgram(ctx context.Context, id int) error {
+	s.Deleted = id
+	return nil
+}
+func (s *stubProgramRepo) UpsertEnrollment(ctx context.Context, e model.Enrollment) error {
+	s.Enrolled = &e
+	return nil
+}
+func (s *stubProgramRepo) GetEnrollment(ctx context.Context, userID int) (*model.Enrollment, error) {
+	if s.Enrollment == nil {
+		return nil, erorrs.ErrNotEnrolled
+	}
+	return s.Enrollment, nil
+}
+
+// stubWorkoutRepo knows the owners of a few workouts and the exercises of workout 10.
+type stubWorkoutRepo struct {
+	workoutRepo.WorkoutRepositoryInterface
+	Owners    map[int]int
+	Deleted   map[int]bool
+	Exercises []join.WorkoutExercise
+	Sets      []join.SetPrescription
+}
+
+func (s *stubWorkoutRepo) GetWorkoutOwner(ctx context.Context, workoutID int) (int, error) {
+	owner, ok := s.Owners[workoutID]
+	if !ok {
+		return 0, erorrs.ErrNotFound
+	}
+	return owner, nil
+}
+func (s *stubWorkoutRepo) GetExercisesByWorkoutIDs(ctx context.Context, workoutIDs []int) (map[int][]join.WorkoutExercise, error) {
+	return map[int][]join.WorkoutExercise{10: s.Exercises}, nil
+}
+func (s *stubWorkoutRepo) GetWorkoutByID(ctx context.Context, workoutID, userID int) (*workoutModel.Workout, error) {
+	if s.Deleted[workoutID] {
+		return nil, erorrs.ErrNotFound
+	}
+	return s.GetTemplateByID(ctx, workoutID, userID)
+}
+func (s *stubWorkoutRepo) GetTemplateByID(ctx context.Context, workoutID, userID int) (*workoutModel.Workout, error) {
+	if s.Owners[workoutID] != userID {
+		return nil, erorrs.ErrNotFound
+	}
+	return &workoutModel.Workout{ID: workoutID, UserID: userID, Name: "Squat day"}, nil
+}
+func (s *stubWorkoutRepo) GetWorkoutExercises(ctx context.Context, workoutID int) ([]join.WorkoutExercise, error) {
+	return append([]join.WorkoutExercise(nil), s.Exercises...), nil
+}
+func (s *stubWorkoutRepo) GetSetPrescriptions(ctx context.Context, workoutID int) ([]join.SetPrescription, error) {
+	return s.Sets, nil
+}
+func (s *stubWorkoutRepo) GetBlocksByWorkoutIDs(ctx context.Context, workoutIDs []int) (map[int][]join.Block, error) {
+	return map[int][]join.Block{10: {{WorkoutID: 10, Type: join.BlockStraight, Rounds: 1}}}, nil
+}
+
+func newTestService(t *testing.T, repo *stubProgramRepo, workouts *stubWorkoutRepo) (*program.ProgramService, *db.MockUnitOfWork) {
+	t.Helper()
+	tx := &db.MockUnitOfWork{}
+	return program.NewProgramService(program.ProgramServiceParams{
+		Repo:        repo,
+		WorkoutRepo: workouts,
+		Tx:          tx,
+		Log:         zaptest.NewLogger(t).Sugar(),
+	}), tx
+}
+
+func squatWorkouts() *stubWorkoutRepo {
+	return &stubWorkoutRepo{
+		Owners:    map[int]int{10: 1, 11: 1, 20: 2},
+		Exercises: []join.WorkoutExercise{{WorkoutID: 10, ExerciseID: 3, Position: 0, Sets: 3, Reps: 5}},
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestCreateProgram_Success(t *testing.T) {
+	repo := &stubProgramRepo{CreatedID: 5}
+	service, tx := newTestService(t, repo, squatWorkouts())
+
+	id, err := service.CreateProgram(t.Context(), 1, dto.ProgramRequest{
+		Name: " 5/3/1 ",
+		Weeks: []dto.WeekRequest{
+			{Days: []dto.DayRequest{{Day: 1, WorkoutID: 10}, {Day: 4, WorkoutID: 11}}},
+			{},
+			{Days: []dto.DayRequest{{Day: 2, WorkoutID: 10}}},
+		},
+		Progression: []dto.ProgressionRuleRequest{{ExerciseID: intPtr(3), Type: "weight", Amount: 2.5}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, id)
+	assert.Equal(t, 1, tx.Calls)
+	assert.Equal(t, "5/3/1", repo.Program.Name)
+	assert.Equal(t, 3, repo.Program.WeekCount)
+	assert.Equal(t, []model.Day{
+		{Week: 1, Day: 1, WorkoutID: 10},
+		{Week: 1, Day: 4, WorkoutID: 11},
+		{Week: 3, Day: 2, WorkoutID: 10},
+	}, repo.Days)
+	assert.Equal(t, []model.ProgressionRule{
+		{ExerciseID: intPtr(3), Type: model.ProgressWeight, Amount: 2.5, EveryWeeks: 1},
+	}, repo.Rules)
+}
+
+func TestCreateProgram_ReportsAllValidationErrors(t *testing.T) {
+	service, tx := newTestService(t, &stubProgramRepo{}, squatWorkouts())
+
+	_, err := service.CreateProgram(t.Context(), 1, dto.ProgramRequest{
+		Name: "Plan",
+		Weeks: []dto.WeekRequest{
+			{Days: []dto.DayRequest{{Day: 1, WorkoutID: 10}, {Day: 2, WorkoutID: 20}, {Day: 3, WorkoutID: 99}}},
+		},
+		Progression: []dto.ProgressionRuleRequest{{ExerciseID: intPtr(8), Type: "reps", Amount: 1}},
+	})
+
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, appErr.Status)
+	assert.Equal(t, []erorrs.FieldError{
+		{Field: "weeks[0].days[1].workout_id", Message: "does not exist"},
+		{Field: "weeks[0].days[2].workout_id", Message: "does not exist"},
+		{Field: "progression[0].exercise_id", Message: "is not part of any workout of the program"},
+	}, appErr.Fields)
+	assert.Zero(t, tx.Calls)
+}
+
+func TestCreateProgram_RequiresAWorkout(t *testing.T) {
+	service, _ := newTestService(t, &stubProgramRepo{}, squatWorkouts())
+
+	_, err := service.CreateProgram(t.Context(), 1, dto.ProgramRequest{Name: "Rest", Weeks: []dto.WeekRequest{{}}})
+
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, []erorrs.FieldError{{Field: "weeks", Message: "must schedule at least one workout"}}, appErr.Fields)
+}
+
+func TestGetProgram_GroupsDaysIntoWeeks(t *testing.T) {
+	repo := &stubProgramRepo{
+		Program: &model.Program{ID: 5, WeekCount: 2},
+		Days:    []model.Day{{Week: 2, Day: 3, WorkoutID: 10}},
+	}
+	service, _ := newTestService(t, repo, squatWorkouts())
+
+	p, err := service.GetProgram(t.Context(), 5)
+	require.NoError(t, err)
+	require.Len(t, p.Weeks, 2)
+	assert.Empty(t, p.Weeks[0].Days)
+	assert.Equal(t, []model.Day{{Week: 2, Day: 3, WorkoutID: 10}}, p.Weeks[1].Days)
+}
+
+func TestDeleteProgram_OnlyAuthor(t *testing.T) {
+	repo := &stubProgramRepo{Program: &model.Program{ID: 5, UserID: 1}}
+	service, _ := newTestService(t, repo, squatWorkouts())
+
+	err := service.DeleteProgram(t.Context(), 2, 5)
+	assert.ErrorIs(t, err, erorrs.ErrForbidden)
+	assert.Zero(t, repo.Deleted)
+
+	require.NoError(t, service.DeleteProgram(t.Context(), 1, 5))
+	assert.Equal(t, 5, repo.Deleted)
+}
+
+func TestEnroll_UnknownProgram(t *testing.T) {
+	service, _ := newTestService(t, &stubProgramRepo{}, squatWorkouts())
+	_, err := service.Enroll(t.Context(), 2, 5, time.Now())
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestEnroll_StoresStartDate(t *testing.T) {
+	repo := &stubProgramRepo{Program: &model.Program{ID: 5, UserID: 1}}
+	service, _ := newTestService(t, repo, squatWorkouts())
+
+	e, err := service.Enroll(t.Context(), 2, 5, time.Date(2026, 3, 2, 18, 30, 0, 0, time.Local))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), e.StartDate)
+	assert.Equal(t, repo.Enrolled, e)
+}
+
+func TestGetToday_NotEnrolled(t *testing.T) {
+	service, _ := newTestService(t, &stubProgramRepo{}, squatWorkouts())
+	_, err := service.GetToday(t.Context(), 2, time.Now())
+	assert.ErrorIs(t, err, erorrs.ErrNotEnrolled)
+}
+
+func enrolledRepo() *stubProgramRepo {
+	return &stubProgramRepo{
+		Program: &model.Program{ID: 5, UserID: 1, Name: "5/3/1", WeekCount: 3},
+		Days:    []model.Day{{Week: 1, Day: 1, WorkoutID: 10}, {Week: 3, Day: 1, WorkoutID: 10}},
+		Rules: []model.ProgressionRule{
+			{ExerciseID: intPtr(3), Type: model.ProgressWeight, Amount: 2.5, EveryWeeks: 1},
+			{Type: model.ProgressReps, Amount: 1, EveryWeeks: 2},
+		},
+		Enrollment: &model.Enrollment{UserID: 2, ProgramID: 5, StartDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+func TestGetToday_Status(t *testing.T) {
+	tests := []struct {
+		name   string
+		date   time.Time
+		status model.TodayStatus
+		week   int
+		day    int
+	}{
+		{"before start", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), model.TodayNotStarted, 0, 0},
+		{"rest day", time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), model.TodayRest, 1, 2},
+		{"empty week", time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), model.TodayRest, 2, 1},
+		{"after the last week", time.Date(2026, 3, 23, 0, 0, 0, 0, time.UTC), model.TodayFinished, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestService(t, enrolledRepo(), squatWorkouts())
+			today, err := service.GetToday(t.Context(), 2, tt.date)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, today.Status)
+			assert.Equal(t, tt.week, today.Week)
+			assert.Equal(t, tt.day, today.Day)
+			assert.Nil(t, today.Workout)
+			assert.Equal(t, "5/3/1", today.ProgramName)
+		})
+	}
+}
+
+func TestGetToday_AppliesProgression(t *testing.T) {
+	workouts := squatWorkouts()
+	weight := 100.0
+	workouts.Sets = []join.SetPrescription{{WorkoutID: 10, ExercisePosition: 0, SetNumber: 1,
+		Type: join.SetWorking, Reps: intPtr(5), Weight: &weight}}
+	service, _ := newTestService(t, enrolledRepo(), workouts)
+
+	today, err := service.GetToday(t.Context(), 2, time.Date(2026, 3, 16, 7, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, model.TodayWorkout, today.Status)
+	assert.Equal(t, "2026-03-16", today.Date)
+	assert.Equal(t, 3, today.Week)
+	assert.Equal(t, 1, today.Day)
+	require.NotNil(t, today.Workout)
+	assert.Equal(t, 10, today.Workout.ID)
+
+	e := today.Workout.Exercises[0]
+	assert.Equal(t, 6, e.Reps)
+	assert.InDelta(t, 105.0, *e.Prescription[0].Weight, 0.001)
+	assert.Equal(t, 6, *e.Prescription[0].Reps)
+	assert.Equal(t, 100.0, weight, "the template must not be modified")
+	assert.Len(t, today.Workout.Blocks[0].Exercises, 1)
+}
+
+func TestGetToday_DeletedTemplate(t *testing.T) {
+	workouts := squatWorkouts()
+	workouts.Deleted = map[int]bool{10: true}
+	service, _ := newTestService(t, enrolledRepo(), workouts)
+
+	today, err := service.GetToday(t.Context(), 2, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err, "enrolled users keep following a template its author deleted")
+	assert.Equal(t, model.TodayWorkout, today.Status)
+	assert.Equal(t, 10, today.Workout.ID)
+}
+
+func TestGetToday_MissingTemplate(t *testing.T) {
+	workouts := squatWorkouts()
+	delete(workouts.Owners, 10)
+	service, _ := newTestService(t, enrolledRepo(), workouts)
+
+	_, err := service.GetToday(t.Context(), 2, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+	assert.True(t, errors.Is(err, erorrs.ErrNotFound))
+}
@@ -0,0 +1,127 @@
+package progression
+
+import (
+	"context"
+	"fmt"
+	"time"
+	dto "workout-tracker/internal/dto/progression"
+	preferenceModel "workout-tracker/internal/model/preference"
+	model "workout-tracker/internal/model/progression"
+	"workout-tracker/internal/model/units"
+	join "workout-tracker/internal/model/workoutexercisejoin"
+	preferenceRepo "workout-tracker/internal/repository/preference"
+	sessionRepo "workout-tracker/internal/repository/session"
+	userRepo "workout-tracker/internal/repository/user"
+	workoutRepo "workout-tracker/internal/repository/workout"
+	"workout-tracker/pkg/logger"
+
+	"go.uber.org/dig"
+)
+
+type ProgressionServiceParams struct {
+	dig.In
+
+	Preferences preferenceRepo.PreferenceRepositoryInterface
+	WorkoutRepo workoutRepo.WorkoutRepositoryInterface
+	SessionRepo sessionRepo.SessionRepositoryInterface
+	Log         logger.SugaredLoggerInterface
+	UserCache   userRepo.UserCache `optional:"true"`
+}
+
+type ProgressionService struct {
+	Preferences preferenceRepo.PreferenceRepositoryInterface
+	WorkoutRepo workoutRepo.WorkoutRepositoryInterface
+	SessionRepo sessionRepo.SessionRepositoryInterface
+	Log         logger.SugaredLoggerInterface
+	UserCache   userRepo.UserCache
+}
+
+func NewProgressionService(params ProgressionServiceParams) *ProgressionService {
+	return &ProgressionService{
+		Preferences: params.Preferences,
+		WorkoutRepo: params.WorkoutRepo,
+		SessionRepo: params.SessionRepo,
+		Log:         params.Log,
+		UserCache:   params.UserCache,
+	}
+}
+
+// NextSession suggests targets for every exercise of the user's workout from the sessions they
+// logged, using the strategy from their preferences.
+func (s *ProgressionService) NextSession(ctx context.Context, userID, workoutID int) (*dto.NextSessionResponse, error) {
+	workout, err := s.WorkoutRepo.GetWorkoutByID(ctx, workoutID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("workout %d: %w", workoutID, err)
+	}
+
+	exercises, err := s.WorkoutRepo.GetWorkoutExercises(ctx, workoutID)
+	if err != nil {
+		return nil, fmt.Errorf("get exercises for workout: %w", err)
+	}
+	sets, err := s.WorkoutRepo.GetSetPrescriptions(ctx, workoutID)
+	if err != nil {
+		return nil, fmt.Errorf("get set prescriptions for workout: %w", err)
+	}
+	join.AttachSets(exercises, sets)
+
+	prefs, err := s.Preferences.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get preferences: %w", err)
+	}
+
+	ids := make([]int, len(exercises))
+	for i, e := range exercises {
+		ids[i] = e.ExerciseID
+	}
+	history, err := s.SessionRepo.GetExerciseHistory(ctx, userID, ids, model.DeloadAfter)
+	if err != nil {
+		s.Log.Errorw("failed to get exercise history", "workoutID", workoutID, "error", err)
+		return nil, fmt.Errorf("get exercise history: %w", err)
+	}
+
+	next := &dto.NextSessionResponse{
+		Workout:    *workout,
+		Strategy:   prefs.ProgressionStrategy,
+		UnitSystem: prefs.UnitSystem,
+		Exercises:  make([]dto.SuggestedExercise, len(exercises)),
+	}
+	for i, e := range exercises {
+		next.Exercises[i] = dto.SuggestedExercise{
+			WorkoutExercise: e,
+			Suggestion:      model.Suggest(prefs.ProgressionStrategy, prefs.UnitSystem, model.TargetOf(e), history[e.ExerciseID]),
+		}.InUnits(prefs.UnitSystem)
+	}
+	return next, nil
+}
+
+func (s *ProgressionService) GetPreferences(ctx context.Context, userID int) (*preferenceModel.Preferences, error) {
+	prefs, err := s.Preferences.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences saves the preferences given in input, keeping the saved value of the others.
+func (s *ProgressionService) UpdatePreferences(ctx context.Context, userID int, input dto.PreferencesRequest) (*preferenceModel.Preferences, error) {
+	prefs, err := s.Preferences.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get preferences: %w", err)
+	}
+	if input.ProgressionStrategy != "" {
+		prefs.ProgressionStrategy = model.Strategy(input.ProgressionStrategy)
+	}
+	if input.UnitSystem != "" {
+		prefs.UnitSystem = units.System(input.UnitSystem)
+	}
+	prefs.UpdatedAt = time.Now()
+
+	if err := s.Preferences.UpsertPreferences(ctx, *prefs); err != nil {
+		return nil, fmt.Errorf("save preferences: %w", err)
+	}
+	// The auth middleware reads the unit system from the cached user.
+	if s.UserCache != nil {
+		s.UserCache.Invalidate(ctx, userID)
+	}
+	return prefs, nil
+}
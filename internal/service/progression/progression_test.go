@@ -0,0 +1,188 @@
+package progression_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	dto "workout-tracker/internal/dto/progression"
+	"workout-tracker/internal/erorrs"
+	preferenceModel "workout-tracker/internal/model/preference"
+	model "workout-tracker/internal/model/progression"
+	sessionModel "workout-tracker/internal/model/session"
+	"workout-tracker/internal/model/units"
+	workoutModel "workout-tracker/internal/model/workout"
+	join "workout-tracker/internal/model/workoutexercisejoin"
+	preferenceRepo "workout-tracker/internal/repository/preference"
+	sessionRepo "workout-tracker/internal/repository/session"
+	userRepo "workout-tracker/internal/repository/user"
+	workoutRepo "workout-tracker/internal/repository/workout"
+	"workout-tracker/internal/service/progression"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type stubPreferenceRepo struct {
+	preferenceRepo.PreferenceRepositoryInterface
+	Strategy model.Strategy
+	Units    units.System
+	Saved    *preferenceModel.Preferences
+}
+
+func (s *stubPreferenceRepo) GetPreferences(ctx context.Context, userID int) (*preferenceModel.Preferences, error) {
+	p := preferenceModel.Default(userID)
+	if s.Strategy != "" {
+		p.ProgressionStrategy = s.Strategy
+	}
+	if s.Units != "" {
+		p.UnitSystem = s.Units
+	}
+	return &p, nil
+}
+func (s *stubPreferenceRepo) UpsertPreferences(ctx context.Context, p preferenceModel.Preferences) error {
+	s.Saved = &p
+	return nil
+}
+
+// stubWorkoutRepo holds workout 10 of user 1 with a squat and a pull-up.
+type stubWorkoutRepo struct {
+	workoutRepo.WorkoutRepositoryInterface
+}
+
+func (s *stubWorkoutRepo) GetWorkoutByID(ctx context.Context, workoutID, userID int) (*workoutModel.Workout, error) {
+	if workoutID != 10 || userID != 1 {
+		return nil, erorrs.ErrNotFound
+	}
+	return &workoutModel.Workout{ID: 10, UserID: 1, Name: "Legs"}, nil
+}
+func (s *stubWorkoutRepo) GetWorkoutExercises(ctx context.Context, workoutID int) ([]join.WorkoutExercise, error) {
+	return []join.WorkoutExercise{
+		{WorkoutID: 10, ExerciseID: 3, Position: 0, Sets: 3, Reps: 5},
+		{WorkoutID: 10, ExerciseID: 4, Position: 1, Sets: 2, Reps: 8},
+	}, nil
+}
+func (s *stubWorkoutRepo) GetSetPrescriptions(ctx context.Context, workoutID int) ([]join.SetPrescription, error) {
+	return nil, nil
+}
+
+type stubSessionRepo struct {
+	sessionRepo.SessionRepositoryInterface
+	History map[int][]sessionModel.Session
+	Err     error
+	LastIDs []int
+}
+
+func (s *stubSessionRepo) GetExerciseHistory(ctx context.Context, userID int, exerciseIDs []int, sessions int) (map[int][]sessionModel.Session, error) {
+	s.LastIDs = exerciseIDs
+	return s.History, s.Err
+}
+
+func newTestService(t *testing.T, prefs *stubPreferenceRepo, sessions *stubSessionRepo) *progression.ProgressionService {
+	t.Helper()
+	return progression.NewProgressionService(progression.ProgressionServiceParams{
+		Preferences: prefs,
+		WorkoutRepo: &stubWorkoutRepo{},
+		SessionRepo: sessions,
+		Log:         zaptest.NewLogger(t).Sugar(),
+	})
+}
+
+func squatSession(reps ...int) sessionModel.Session {
+	s := sessionModel.Session{ID: 1}
+	for i, r := range reps {
+		s.Sets = append(s.Sets, sessionModel.SessionSet{ExerciseID: 3, SetNumber: i + 1, Weight: 100, Reps: r})
+	}
+	return s
+}
+
+func TestNextSession_SuggestsWithUserStrategy(t *testing.T) {
+	sessions := &stubSessionRepo{History: map[int][]sessionModel.Session{3: {squatSession(5, 5, 5)}}}
+	service := newTestService(t, &stubPreferenceRepo{Strategy: model.Linear}, sessions)
+
+	next, err := service.NextSession(t.Context(), 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, model.Linear, next.Strategy)
+	assert.Equal(t, []int{3, 4}, sessions.LastIDs)
+	require.Len(t, next.Exercises, 2)
+	assert.Equal(t, 102.5, next.Exercises[0].Suggestion.Weight)
+	assert.Equal(t, 3, next.Exercises[0].Suggestion.Sets)
+	assert.Equal(t, 8, next.Exercises[1].Suggestion.Reps, "exercises without history keep the template")
+}
+
+func TestNextSession_Imperial(t *testing.T) {
+	sessions := &stubSessionRepo{History: map[int][]sessionModel.Session{3: {squatSession(5, 5, 5)}}}
+	service := newTestService(t, &stubPreferenceRepo{Strategy: model.Linear, Units: units.Imperial}, sessions)
+
+	next, err := service.NextSession(t.Context(), 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, units.Imperial, next.UnitSystem)
+	assert.Equal(t, 225.0, next.Exercises[0].Suggestion.Weight, "102.5 kg rounded to 5 lb plates")
+}
+
+func TestNextSession_Deload(t *testing.T) {
+	failed := squatSession(5, 3, 2)
+	sessions := &stubSessionRepo{History: map[int][]sessionModel.Session{3: {failed, failed, failed}}}
+	service := newTestService(t, &stubPreferenceRepo{Strategy: model.Double}, sessions)
+
+	next, err := service.NextSession(t.Context(), 1, 10)
+	require.NoError(t, err)
+	assert.True(t, next.Exercises[0].Suggestion.Deload)
+	assert.Equal(t, 90.0, next.Exercises[0].Suggestion.Weight)
+}
+
+func TestNextSession_OtherUsersWorkout(t *testing.T) {
+	service := newTestService(t, &stubPreferenceRepo{}, &stubSessionRepo{})
+
+	_, err := service.NextSession(t.Context(), 2, 10)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestNextSession_HistoryError(t *testing.T) {
+	service := newTestService(t, &stubPreferenceRepo{}, &stubSessionRepo{Err: errors.New("boom")})
+
+	_, err := service.NextSession(t.Context(), 1, 10)
+	assert.Error(t, err)
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	prefs := &stubPreferenceRepo{}
+	service := newTestService(t, prefs, &stubSessionRepo{})
+
+	saved, err := service.UpdatePreferences(t.Context(), 1, dto.PreferencesRequest{ProgressionStrategy: "rpe"})
+	require.NoError(t, err)
+	assert.Equal(t, model.RPE, saved.ProgressionStrategy)
+	require.NotNil(t, prefs.Saved)
+	assert.Equal(t, 1, prefs.Saved.UserID)
+	assert.Equal(t, units.Metric, prefs.Saved.UnitSystem)
+}
+
+func TestUpdatePreferences_KeepsOmittedValues(t *testing.T) {
+	prefs := &stubPreferenceRepo{Strategy: model.Double}
+	service := newTestService(t, prefs, &stubSessionRepo{})
+
+	_, err := service.UpdatePreferences(t.Context(), 1, dto.PreferencesRequest{UnitSystem: "imperial"})
+	require.NoError(t, err)
+	require.NotNil(t, prefs.Saved)
+	assert.Equal(t, model.Double, prefs.Saved.ProgressionStrategy)
+	assert.Equal(t, units.Imperial, prefs.Saved.UnitSystem)
+}
+
+type stubUserCache struct {
+	userRepo.UserCache
+	Invalidated []int
+}
+
+func (c *stubUserCache) Invalidate(ctx context.Context, userID int) {
+	c.Invalidated = append(c.Invalidated, userID)
+}
+
+func TestUpdatePreferences_InvalidatesUserCache(t *testing.T) {
+	users := &stubUserCache{}
+	service := newTestService(t, &stubPreferenceRepo{}, &stubSessionRepo{})
+	service.UserCache = users
+
+	_, err := service.UpdatePreferences(t.Context(), 1, dto.PreferencesRequest{UnitSystem: "imperial"})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, users.Invalidated)
+}
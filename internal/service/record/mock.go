@@ -0,0 +1,70 @@
+package record
+
+import (
+	"context"
+	"fmt"
+	model "workout-tracker/internal/model/record"
+	repo "workout-tracker/internal/repository/record"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecordRepo struct {
+	mock.Mock
+}
+
+func (m *MockRecordRepo) WithTx(tx pgx.Tx) repo.RecordRepositoryInterface {
+	return m
+}
+
+func (m *MockRecordRepo) LockExerciseRecords(ctx context.Context, userID, exerciseID int) error {
+	args := m.Called(ctx, userID, exerciseID)
+	return args.Error(0)
+}
+
+func (m *MockRecordRepo) CreateRecord(ctx context.Context, r model.PersonalRecord) (int, error) {
+	args := m.Called(ctx, r)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRecordRepo) UpdateRecord(ctx context.Context, r model.PersonalRecord) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *MockRecordRepo) GetBestRecord(
+	ctx context.Context, userID, exerciseID int, recordType model.RecordType, weight float64) (*model.PersonalRecord, error) {
+	args := m.Called(ctx, userID, exerciseID, recordType, weight)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	rec, ok := args.Get(0).(*model.PersonalRecord)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for *model.PersonalRecord: %w", args.Error(1))
+	}
+	return rec, args.Error(1)
+}
+
+func (m *MockRecordRepo) GetSessionVolume(ctx context.Context, sessionID, exerciseID int) (float64, error) {
+	args := m.Called(ctx, sessionID, exerciseID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockRecordRepo) GetRecordHistory(ctx context.Context, userID int, exerciseID *int) ([]model.PersonalRecord, error) {
+	args := m.Called(ctx, userID, exerciseID)
+	recs, ok := args.Get(0).([]model.PersonalRecord)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for []model.PersonalRecord: %w", args.Error(1))
+	}
+	return recs, args.Error(1)
+}
+
+func (m *MockRecordRepo) GetCurrentRecords(ctx context.Context, userID int, exerciseID *int) ([]model.PersonalRecord, error) {
+	args := m.Called(ctx, userID, exerciseID)
+	recs, ok := args.Get(0).([]model.PersonalRecord)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for []model.PersonalRecord: %w", args.Error(1))
+	}
+	return recs, args.Error(1)
+}
@@ -0,0 +1,179 @@
+package record
+
+import (
+	"context"
+	"fmt"
+	model "workout-tracker/internal/model/record"
+	sessionModel "workout-tracker/internal/model/session"
+	repo "workout-tracker/internal/repository/record"
+	"workout-tracker/pkg/db"
+	"workout-tracker/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/dig"
+)
+
+type RecordServiceParams struct {
+	dig.In
+
+	Repo repo.RecordRepositoryInterface
+	Tx   db.UnitOfWork
+	Log  logger.SugaredLoggerInterface
+}
+
+type RecordService struct {
+	Repo repo.RecordRepositoryInterface
+	Tx   db.UnitOfWork
+	Log  logger.SugaredLoggerInterface
+}
+
+func NewRecordService(params RecordServiceParams) *RecordService {
+	return &RecordService{
+		Repo: params.Repo,
+		Tx:   params.Tx,
+		Log:  params.Log,
+	}
+}
+
+// DetectRecords compares a freshly saved set with the user's previous bests and stores every record it beats.
+// The set must already be persisted so that it counts towards the session volume. Detection for one exercise
+// runs under a lock, so two sets logged at the same time cannot both beat the same previous best.
+func (s *RecordService) DetectRecords(ctx context.Context, userID int, set sessionModel.SessionSet) ([]model.PersonalRecord, error) {
+	base := model.PersonalRecord{
+		UserID:     userID,
+		ExerciseID: set.ExerciseID,
+		Weight:     set.Weight,
+		Reps:       set.Reps,
+		SessionID:  &set.SessionID,
+		SetID:      &set.ID,
+		AchievedAt: set.CompletedAt,
+	}
+
+	candidates := []model.PersonalRecord{}
+	if set.Reps > 0 {
+		candidates = append(candidates, withValue(base, model.RepsAtWeight, float64(set.Reps)))
+	}
+	if set.Weight > 0 && set.Reps > 0 {
+		candidates = append(candidates,
+			withValue(base, model.MaxWeight, set.Weight),
+			withValue(base, model.OneRepMax, model.EstimateOneRepMax(set.Weight, set.Reps)),
+		)
+	}
+
+	var result []model.PersonalRecord
+	err := s.Tx.WithinTx(ctx, func(tx pgx.Tx) error {
+		records := s.Repo.WithTx(tx)
+		if err := records.LockExerciseRecords(ctx, userID, set.ExerciseID); err != nil {
+			return fmt.Errorf("lock records: %w", err)
+		}
+
+		result = []model.PersonalRecord{}
+		for _, candidate := range candidates {
+			created, err := s.createIfBetter(ctx, records, candidate)
+			if err != nil {
+				return err
+			}
+			if created != nil {
+				result = append(result, *created)
+			}
+		}
+
+		if set.Weight > 0 && set.Reps > 0 {
+			volume, err := s.detectSessionVolume(ctx, records, base)
+			if err != nil {
+				return err
+			}
+			if volume != nil {
+				result = append(result, *volume)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *RecordService) GetRecords(ctx context.Context, userID int, exerciseID *int) (*model.Records, error) {
+	current, err := s.Repo.GetCurrentRecords(ctx, userID, exerciseID)
+	if err != nil {
+		s.Log.Errorw("failed to get current records", "userID", userID, "error", err)
+		return nil, fmt.Errorf("get current records: %w", err)
+	}
+
+	history, err := s.Repo.GetRecordHistory(ctx, userID, exerciseID)
+	if err != nil {
+		s.Log.Errorw("failed to get record history", "userID", userID, "error", err)
+		return nil, fmt.Errorf("get record history: %w", err)
+	}
+
+	return &model.Records{Current: current, History: history}, nil
+}
+
+func (s *RecordService) createIfBetter(
+	ctx context.Context, records repo.RecordRepositoryInterface, candidate model.PersonalRecord) (*model.PersonalRecord, error) {
+	best, err := records.GetBestRecord(ctx, candidate.UserID, candidate.ExerciseID, candidate.RecordType, candidate.Weight)
+	if err != nil {
+		s.Log.Errorw("failed to get best record", "type", candidate.RecordType, "error", err)
+		return nil, fmt.Errorf("get best record: %w", err)
+	}
+	if best != nil && candidate.Value <= best.Value {
+		return nil, nil
+	}
+
+	id, err := records.CreateRecord(ctx, candidate)
+	if err != nil {
+		s.Log.Errorw("failed to create record", "type", candidate.RecordType, "error", err)
+		return nil, fmt.Errorf("create record: %w", err)
+	}
+	candidate.ID = id
+
+	return &candidate, nil
+}
+
+// detectSessionVolume keeps a single volume record per session: once the session holds the best volume,
+// every further set raises that record instead of adding a new one.
+func (s *RecordService) detectSessionVolume(
+	ctx context.Context, records repo.RecordRepositoryInterface, base model.PersonalRecord) (*model.PersonalRecord, error) {
+	volume, err := records.GetSessionVolume(ctx, *base.SessionID, base.ExerciseID)
+	if err != nil {
+		s.Log.Errorw("failed to get session volume", "sessionID", *base.SessionID, "error", err)
+		return nil, fmt.Errorf("get session volume: %w", err)
+	}
+
+	best, err := records.GetBestRecord(ctx, base.UserID, base.ExerciseID, model.SessionVolume, 0)
+	if err != nil {
+		s.Log.Errorw("failed to get best volume record", "error", err)
+		return nil, fmt.Errorf("get best record: %w", err)
+	}
+	if best != nil && volume <= best.Value {
+		return nil, nil
+	}
+
+	candidate := withValue(base, model.SessionVolume, volume)
+	if best != nil && best.SessionID != nil && *best.SessionID == *base.SessionID {
+		candidate.ID = best.ID
+		if err := records.UpdateRecord(ctx, candidate); err != nil {
+			s.Log.Errorw("failed to update volume record", "recordID", best.ID, "error", err)
+			return nil, fmt.Errorf("update record: %w", err)
+		}
+		return &candidate, nil
+	}
+
+	id, err := records.CreateRecord(ctx, candidate)
+	if err != nil {
+		s.Log.Errorw("failed to create volume record", "error", err)
+		return nil, fmt.Errorf("create record: %w", err)
+	}
+	candidate.ID = id
+
+	return &candidate, nil
+}
+
+func withValue(base model.PersonalRecord, recordType model.RecordType, value float64) model.PersonalRecord {
+	base.RecordType = recordType
+	base.Value = value
+	return base
+}
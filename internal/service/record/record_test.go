@@ -0,0 +1,186 @@
+package record
+
+import (
+	"errors"
+	"testing"
+	"time"
+	model "workout-tracker/internal/model/record"
+	sessionModel "workout-tracker/internal/model/session"
+	"workout-tracker/internal/model/units"
+	"workout-tracker/pkg/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newService(repo *MockRecordRepo) *RecordService {
+	repo.On("LockExerciseRecords", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return NewRecordService(RecordServiceParams{Repo: repo, Tx: &db.MockUnitOfWork{}, Log: zap.NewNop().Sugar()})
+}
+
+func testSet() sessionModel.SessionSet {
+	return sessionModel.SessionSet{ID: 9, SessionID: 4, ExerciseID: 2, Reps: 5, Weight: 100, CompletedAt: time.Now()}
+}
+
+func recordOfType(recordType model.RecordType) interface{} {
+	return mock.MatchedBy(func(r model.PersonalRecord) bool { return r.RecordType == recordType })
+}
+
+func TestDetectRecords_FirstSetSetsAllRecords(t *testing.T) {
+	ctx := t.Context()
+	repo := new(MockRecordRepo)
+	repo.On("GetBestRecord", ctx, 1, 2, mock.Anything, mock.Anything).Return(nil, nil)
+	repo.On("GetSessionVolume", ctx, 4, 2).Return(500.0, nil)
+	repo.On("CreateRecord", ctx, mock.Anything).Return(1, nil)
+
+	recs, err := newService(repo).DetectRecords(ctx, 1, testSet())
+	require.NoError(t, err)
+	require.Len(t, recs, 4)
+
+	values := map[model.RecordType]float64{}
+	for _, r := range recs {
+		values[r.RecordType] = r.Value
+	}
+	assert.InDelta(t, 5.0, values[model.RepsAtWeight], 0.001)
+	assert.InDelta(t, 100.0, values[model.MaxWeight], 0.001)
+	assert.InDelta(t, 112.5, values[model.OneRepMax], 0.01)
+	assert.InDelta(t, 500.0, values[model.SessionVolume], 0.001)
+}
+
+func TestDetectRecords_LocksTheExerciseRecordsInATransaction(t *testing.T) {
+	ctx := t.Context()
+	repo := new(MockRecordRepo)
+	tx := &db.MockUnitOfWork{}
+	var calls []string
+	repo.On("LockExerciseRecords", ctx, 1, 2).Run(func(mock.Arguments) { calls = append(calls, "lock") }).Return(nil)
+	repo.On("GetBestRecord", ctx, 1, 2, mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { calls = append(calls, "best") }).Return(&model.PersonalRecord{Value: 1000}, nil)
+	repo.On("GetSessionVolume", ctx, 4, 2).Return(500.0, nil)
+
+	service := NewRecordService(RecordServiceParams{Repo: repo, Tx: tx, Log: zap.NewNop().Sugar()})
+	_, err := service.DetectRecords(ctx, 1, testSet())
+	require.NoError(t, err)
+	assert.Equal(t, 1, tx.Calls)
+	require.NotEmpty(t, calls)
+	assert.Equal(t, "lock", calls[0], "the previous bests are read under the lock")
+}
+
+func TestDetectRecords_LockError(t *testing.T) {
+	ctx := t.Context()
+	repo := new(MockRecordRepo)
+	repo.On("LockExerciseRecords", ctx, 1, 2).Return(errors.New("db"))
+
+	service := NewRecordService(RecordServiceParams{Repo: repo, Tx: &db.MockUnitOfWork{}, Log: zap.NewNop().Sugar()})
+	recs, err := service.DetectRecords(ctx, 1, testSet())
+	assert.Error(t, err)
+	assert.Nil(t, recs)
+	repo.AssertNotCalled(t, "GetBestRecord", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDetectRecords_NoImprovement(t *testing.T) {
+	ctx := t.Context()
+	repo := new(MockRecordRepo)
+	best := &model.PersonalRecord{ID: 3, Value: 1000}
+	repo.On("GetBestRecord", ctx, 1, 2, mock.Anything, mock.Anything).Return(best, nil)
+	repo.On("GetSessionVolume", ctx, 4, 2).Return(500.0, nil)
+
+	recs, err := newService(repo).DetectRecords(ctx, 1, testSet())
+	require.NoError(t, err)
+	assert.Empty(t, recs)
+	repo.AssertNotCalled(t, "CreateRecord", mock.Anything, mock.Anything)
+}
+
+func TestDetectRecords_RaisesVolumeOfSameSession(t *testing.T) {
+	ctx := t.Context()
+	repo := new(MockRecordRepo)
+	sessionID := 4
+	repo.On("GetBestRecord", ctx, 1, 2, model.SessionVolume, 0.0).
+		Return(&model.PersonalRecord{ID: 7, Value: 500, SessionID: &sessionID}, nil)
+	repo.On("GetBestRecord", ctx, 1, 2, mock.Anything, mock.Anything).Return(&model.PersonalRecord{Value: 1000}, nil)
+	repo.On("GetSessionVolume", ctx, 4, 2).Return(1000.0, nil)
+	repo.On("UpdateRecord", ctx, mock.MatchedBy(func(r model.PersonalRecord) bool {
+		return r.ID == 7 && r.Value == 1000
+	})).Return(nil)
+
+	recs, err := newService(repo).DetectRecords(ctx, 1, testSet())
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+	assert.Equal(t, model.SessionVolume, recs[0].RecordType)
+	repo.AssertNotCalled(t, "CreateRecord", mock.Anything, mock.Anything)
+}
+
+func TestDetectRecords_BodyweightOnlyTracksReps(t *testing.T) {
+	ctx := t.Context()
+	repo := new(MockRecordRepo)
+	set := testSet()
+	set.Weight = 0
+	repo.On("GetBestRecord", ctx, 1, 2, model.RepsAtWeight, 0.0).Return(nil, nil)
+	repo.On("CreateRecord", ctx, recordOfType(model.RepsAtWeight)).Return(5, nil)
+
+	recs, err := newService(repo).DetectRecords(ctx, 1, set)
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+	assert.Equal(t, 5, recs[0].ID)
+}
+
+func TestDetectRecords_RepoError(t *testing.T) {
+	ctx := t.Context()
+	repo := new(MockRecordRepo)
+	repo.On("GetBestRecord", ctx, 1, 2, mock.Anything, mock.Anything).Return(nil, errors.New("db"))
+
+	recs, err := newService(repo).DetectRecords(ctx, 1, testSet())
+	assert.Error(t, err)
+	assert.Nil(t, recs)
+}
+
+func TestGetRecords_Success(t *testing.T) {
+	ctx := t.Context()
+	repo := new(MockRecordRepo)
+	exerciseID := 2
+	current := []model.PersonalRecord{{ID: 2, RecordType: model.MaxWeight, Value: 120}}
+	history := []model.PersonalRecord{{ID: 2, Value: 120}, {ID: 1, Value: 100}}
+	repo.On("GetCurrentRecords", ctx, 1, &exerciseID).Return(current, nil)
+	repo.On("GetRecordHistory", ctx, 1, &exerciseID).Return(history, nil)
+
+	recs, err := newService(repo).GetRecords(ctx, 1, &exerciseID)
+	require.NoError(t, err)
+	assert.Equal(t, current, recs.Current)
+	assert.Equal(t, history, recs.History)
+}
+
+func TestGetRecords_Error(t *testing.T) {
+	ctx := t.Context()
+	repo := new(MockRecordRepo)
+	repo.On("GetCurrentRecords", ctx, 1, (*int)(nil)).Return(nil, errors.New("db"))
+
+	recs, err := newService(repo).GetRecords(ctx, 1, nil)
+	assert.Error(t, err)
+	assert.Nil(t, recs)
+}
+
+func TestDetectRecords_SameImperialWeightTwice(t *testing.T) {
+	ctx := t.Context()
+	repo := new(MockRecordRepo)
+	set := testSet()
+	set.Weight = units.Imperial.ToKilograms(225)
+
+	// The database compares against the stored NUMERIC(8,2) weight, 102.06 kg.
+	repo.On("GetSessionVolume", ctx, 4, 2).Return(0.0, nil)
+	repo.On("GetBestRecord", ctx, 1, 2, model.SessionVolume, 0.0).Return(&model.PersonalRecord{Value: 1000}, nil)
+	repo.On("GetBestRecord", ctx, 1, 2, model.RepsAtWeight, 102.06).Return(nil, nil).Once()
+	repo.On("GetBestRecord", ctx, 1, 2, model.RepsAtWeight, 102.06).Return(&model.PersonalRecord{Value: 5, Weight: 102.06}, nil)
+	repo.On("GetBestRecord", ctx, 1, 2, mock.Anything, mock.Anything).Return(&model.PersonalRecord{Value: 1000}, nil)
+	repo.On("CreateRecord", ctx, recordOfType(model.RepsAtWeight)).Return(1, nil).Once()
+
+	recs, err := newService(repo).DetectRecords(ctx, 1, set)
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+	assert.Equal(t, 102.06, recs[0].Weight)
+
+	recs, err = newService(repo).DetectRecords(ctx, 1, set)
+	require.NoError(t, err)
+	assert.Empty(t, recs, "the same weight logged again is not a new record")
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,274 @@
+package schedule
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+	dto "workout-tracker/internal/dto/schedule"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/schedule"
+	scheduleRepo "workout-tracker/internal/repository/schedule"
+	sessionRepo "workout-tracker/internal/repository/session"
+	userRepo "workout-tracker/internal/repository/user"
+	workoutRepo "workout-tracker/internal/repository/workout"
+	"workout-tracker/pkg/logger"
+
+	"go.uber.org/dig"
+)
+
+const (
+	dateLayout             = "2006-01-02"
+	defaultDurationMinutes = 60
+	// maxCalendarDays bounds the range GET /calendar expands recurrences over.
+	maxCalendarDays = 366
+	// The feed covers a window around the day it is fetched on.
+	feedDaysBack  = 90
+	feedDaysAhead = 365
+	// calendarTokenBytes is the amount of randomness in a feed token.
+	calendarTokenBytes = 32
+)
+
+type ScheduleServiceParams struct {
+	dig.In
+
+	Repo        scheduleRepo.ScheduleRepositoryInterface
+	WorkoutRepo workoutRepo.WorkoutRepositoryInterface
+	SessionRepo sessionRepo.SessionRepositoryInterface
+	UserRepo    userRepo.UserRepositoryInterface
+	Log         logger.SugaredLoggerInterface
+}
+
+type ScheduleService struct {
+	Repo        scheduleRepo.ScheduleRepositoryInterface
+	WorkoutRepo workoutRepo.WorkoutRepositoryInterface
+	SessionRepo sessionRepo.SessionRepositoryInterface
+	UserRepo    userRepo.UserRepositoryInterface
+	Log         logger.SugaredLoggerInterface
+}
+
+func NewScheduleService(params ScheduleServiceParams) *ScheduleService {
+	return &ScheduleService{
+		Repo:        params.Repo,
+		WorkoutRepo: params.WorkoutRepo,
+		SessionRepo: params.SessionRepo,
+		UserRepo:    params.UserRepo,
+		Log:         params.Log,
+	}
+}
+
+// CreateSchedule plans one of the user's own workouts. Without a timezone of its own the schedule
+// repeats in the timezone of the user's profile.
+func (s *ScheduleService) CreateSchedule(ctx context.Context, userID int, input dto.ScheduleRequest) (int, error) {
+	var fields []erorrs.FieldError
+
+	timezone := input.Timezone
+	if timezone == "" {
+		profile, err := s.UserRepo.GetProfile(ctx, userID)
+		if err != nil {
+			s.Log.Errorw("failed to get profile", "userID", userID, "error", err)
+			return 0, fmt.Errorf("get profile: %w", err)
+		}
+		timezone = profile.Timezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		fields = append(fields, erorrs.FieldError{Field: "timezone", Message: "must be an IANA timezone such as Europe/Berlin"})
+		loc = time.UTC
+	}
+
+	ownerID, err := s.WorkoutRepo.GetWorkoutOwner(ctx, input.WorkoutID)
+	switch {
+	case errors.Is(err, erorrs.ErrNotFound) || (err == nil && ownerID != userID):
+		fields = append(fields, erorrs.FieldError{Field: "workout_id", Message: "does not exist"})
+	case err != nil:
+		s.Log.Errorw("failed to get workout owner", "workoutID", input.WorkoutID, "error", err)
+		return 0, fmt.Errorf("get workout owner: %w", err)
+	}
+
+	weekdays, err := model.ParseWeekdays(input.Repeat)
+	if err != nil {
+		fields = append(fields, erorrs.FieldError{Field: "repeat", Message: err.Error()})
+	}
+
+	var until *time.Time
+	if input.Until != "" {
+		parsed, err := time.Parse(dateLayout, input.Until)
+		switch {
+		case err != nil:
+			fields = append(fields, erorrs.FieldError{Field: "until", Message: "must be a date such as " + dateLayout})
+		case weekdays == 0:
+			fields = append(fields, erorrs.FieldError{Field: "until", Message: "requires repeat"})
+		case parsed.Before(model.Date(input.StartsAt.In(loc))):
+			fields = append(fields, erorrs.FieldError{Field: "until", Message: "must not be before starts_at"})
+		default:
+			until = &parsed
+		}
+	}
+
+	if len(fields) > 0 {
+		return 0, erorrs.Validation(fields...)
+	}
+
+	duration := defaultDurationMinutes
+	if input.DurationMinutes != nil {
+		duration = *input.DurationMinutes
+	}
+
+	now := time.Now()
+	id, err := s.Repo.CreateSchedule(ctx, model.Schedule{
+		UserID:          userID,
+		WorkoutID:       input.WorkoutID,
+		StartsAt:        input.StartsAt.UTC(),
+		DurationMinutes: duration,
+		Weekdays:        weekdays,
+		Until:           until,
+		Timezone:        timezone,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("create schedule: %w", err)
+	}
+	return id, nil
+}
+
+func (s *ScheduleService) GetSchedules(ctx context.Context, userID int) ([]model.Schedule, error) {
+	schedules, err := s.Repo.GetSchedules(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+func (s *ScheduleService) DeleteSchedule(ctx context.Context, userID, id int) error {
+	if err := s.Repo.DeleteSchedule(ctx, id, userID); err != nil {
+		return fmt.Errorf("delete schedule %d: %w", id, err)
+	}
+	return nil
+}
+
+// MarkOccurrence marks the schedule's occurrence on date as completed or skipped, or resets it to
+// planned. A completed occurrence may name the session it was done in.
+func (s *ScheduleService) MarkOccurrence(ctx context.Context, userID, scheduleID int, date time.Time, input dto.MarkRequest) (
+	*model.Mark, error) {
+	schedule, err := s.Repo.GetScheduleByID(ctx, scheduleID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("schedule %d: %w", scheduleID, err)
+	}
+
+	var fields []erorrs.FieldError
+	if !schedule.OccursOn(date) {
+		fields = append(fields, erorrs.FieldError{Field: "date", Message: "is not an occurrence of the schedule"})
+	}
+	status := model.Status(input.Status)
+	if input.SessionID != nil {
+		if status != model.StatusCompleted {
+			fields = append(fields, erorrs.FieldError{Field: "session_id", Message: "is only allowed when completed"})
+		} else if _, err := s.SessionRepo.GetSessionByID(ctx, *input.SessionID, userID); err != nil {
+			if !errors.Is(err, erorrs.ErrNotFound) {
+				return nil, fmt.Errorf("get session: %w", err)
+			}
+			fields = append(fields, erorrs.FieldError{Field: "session_id", Message: "does not exist"})
+		}
+	}
+	if len(fields) > 0 {
+		return nil, erorrs.Validation(fields...)
+	}
+
+	mark := model.Mark{
+		ScheduleID: scheduleID,
+		Date:       model.Date(date),
+		Status:     status,
+		SessionID:  input.SessionID,
+		UpdatedAt:  time.Now(),
+	}
+	if status == model.StatusPlanned {
+		if err := s.Repo.DeleteMark(ctx, scheduleID, mark.Date); err != nil {
+			return nil, fmt.Errorf("unmark occurrence: %w", err)
+		}
+		return &mark, nil
+	}
+	if err := s.Repo.UpsertMark(ctx, mark); err != nil {
+		return nil, fmt.Errorf("mark occurrence: %w", err)
+	}
+	return &mark, nil
+}
+
+// GetCalendar returns the user's planned and completed workouts for the dates from through to.
+func (s *ScheduleService) GetCalendar(ctx context.Context, userID int, from, to time.Time) ([]model.Entry, error) {
+	from, end := model.Date(from), model.Date(to).AddDate(0, 0, 1)
+	if end.Before(from) || end.Equal(from) {
+		return nil, erorrs.ErrInvalidDateRange
+	}
+	if end.Sub(from) > maxCalendarDays*24*time.Hour {
+		return nil, erorrs.ErrCalendarRangeTooLong
+	}
+	return s.calendar(ctx, userID, from, end)
+}
+
+func (s *ScheduleService) calendar(ctx context.Context, userID int, from, to time.Time) ([]model.Entry, error) {
+	schedules, err := s.Repo.GetSchedules(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get schedules: %w", err)
+	}
+	marks, err := s.Repo.GetMarks(ctx, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("get occurrence marks: %w", err)
+	}
+	sessions, err := s.Repo.GetSessionEntries(ctx, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("get calendar sessions: %w", err)
+	}
+	return model.BuildCalendar(schedules, marks, sessions, from, to), nil
+}
+
+// CreateCalendarToken issues a new feed token, revoking the previous one. Only its hash is stored,
+// so the token cannot be shown again.
+func (s *ScheduleService) CreateCalendarToken(ctx context.Context, userID int) (string, error) {
+	raw := make([]byte, calendarTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate calendar token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err := s.Repo.SetCalendarToken(ctx, userID, hashToken(token)); err != nil {
+		return "", fmt.Errorf("store calendar token: %w", err)
+	}
+	return token, nil
+}
+
+func (s *ScheduleService) RevokeCalendarToken(ctx context.Context, userID int) error {
+	if err := s.Repo.DeleteCalendarToken(ctx, userID); err != nil {
+		return fmt.Errorf("revoke calendar token: %w", err)
+	}
+	return nil
+}
+
+// Feed renders the calendar of the token's owner as iCalendar, from feedDaysBack days before now
+// to feedDaysAhead days after it.
+func (s *ScheduleService) Feed(ctx context.Context, token string, now time.Time) (string, error) {
+	if token == "" {
+		return "", erorrs.ErrTokenNotFound
+	}
+	userID, err := s.Repo.GetCalendarTokenOwner(ctx, hashToken(token))
+	if err != nil {
+		return "", fmt.Errorf("calendar token: %w", err)
+	}
+
+	today := model.Date(now)
+	entries, err := s.calendar(ctx, userID, today.AddDate(0, 0, -feedDaysBack), today.AddDate(0, 0, feedDaysAhead))
+	if err != nil {
+		return "", err
+	}
+	return model.ICS(entries, now), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
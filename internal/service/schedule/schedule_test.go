@@ -0,0 +1,309 @@
+package schedule_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	dto "workout-tracker/internal/dto/schedule"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/schedule"
+	sessionModel "workout-tracker/internal/model/session"
+	userModel "workout-tracker/internal/model/user"
+	scheduleRepo "workout-tracker/internal/repository/schedule"
+	sessionRepo "workout-tracker/internal/repository/session"
+	userRepo "workout-tracker/internal/repository/user"
+	workoutRepo "workout-tracker/internal/repository/workout"
+	"workout-tracker/internal/service/schedule"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type stubScheduleRepo struct {
+	scheduleRepo.ScheduleRepositoryInterface
+	Schedule  *model.Schedule
+	Created   *model.Schedule
+	Marked    *model.Mark
+	Unmarked  time.Time
+	Sessions  []model.Entry
+	TokenHash string
+	MarksFrom time.Time
+	MarksTo   time.Time
+}
+
+func (s *stubScheduleRepo) CreateSchedule(ctx context.Context, sc model.Schedule) (int, error) {
+	s.Created = &sc
+	return 9, nil
+}
+func (s *stubScheduleRepo) GetScheduleByID(ctx context.Context, id, userID int) (*model.Schedule, error) {
+	if s.Schedule == nil || s.Schedule.ID != id || s.Schedule.UserID != userID {
+		return nil, erorrs.ErrNotFound
+	}
+	return s.Schedule, nil
+}
+func (s *stubScheduleRepo) GetSchedules(ctx context.Context, userID int) ([]model.Schedule, error) {
+	if s.Schedule == nil || s.Schedule.UserID != userID {
+		return nil, nil
+	}
+	return []model.Schedule{*s.Schedule}, nil
+}
+func (s *stubScheduleRepo) UpsertMark(ctx context.Context, m model.Mark) error {
+	s.Marked = &m
+	return nil
+}
+func (s *stubScheduleRepo) DeleteMark(ctx context.Context, scheduleID int, date time.Time) error {
+	s.Unmarked = date
+	return nil
+}
+func (s *stubScheduleRepo) GetMarks(ctx context.Context, userID int, from, to time.Time) ([]model.Mark, error) {
+	s.MarksFrom, s.MarksTo = from, to
+	if s.Marked == nil {
+		return nil, nil
+	}
+	return []model.Mark{*s.Marked}, nil
+}
+func (s *stubScheduleRepo) GetSessionEntries(ctx context.Context, userID int, from, to time.Time) ([]model.Entry, error) {
+	return s.Sessions, nil
+}
+func (s *stubScheduleRepo) SetCalendarToken(ctx context.Context, userID int, tokenHash string) error {
+	s.TokenHash = tokenHash
+	return nil
+}
+func (s *stubScheduleRepo) GetCalendarTokenOwner(ctx context.Context, tokenHash string) (int, error) {
+	if tokenHash != s.TokenHash {
+		return 0, erorrs.ErrTokenNotFound
+	}
+	return 1, nil
+}
+
+// stubWorkoutRepo knows that workout 5 belongs to user 1.
+type stubWorkoutRepo struct {
+	workoutRepo.WorkoutRepositoryInterface
+}
+
+func (s *stubWorkoutRepo) GetWorkoutOwner(ctx context.Context, workoutID int) (int, error) {
+	if workoutID != 5 {
+		return 0, erorrs.ErrNotFound
+	}
+	return 1, nil
+}
+
+// stubSessionRepo knows session 40 of user 1.
+type stubSessionRepo struct {
+	sessionRepo.SessionRepositoryInterface
+}
+
+func (s *stubSessionRepo) GetSessionByID(ctx context.Context, sessionID, userID int) (*sessionModel.Session, error) {
+	if sessionID != 40 || userID != 1 {
+		return nil, erorrs.ErrNotFound
+	}
+	return &sessionModel.Session{ID: 40, UserID: 1}, nil
+}
+
+// stubUserRepo gives every user the profile timezone Timezone, UTC by default.
+type stubUserRepo struct {
+	userRepo.UserRepositoryInterface
+	Timezone string
+}
+
+func (s *stubUserRepo) GetProfile(ctx context.Context, userID int) (*userModel.Profile, error) {
+	timezone := s.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	return &userModel.Profile{ID: userID, Timezone: timezone}, nil
+}
+
+func newTestService(t *testing.T, repo *stubScheduleRepo) *schedule.ScheduleService {
+	t.Helper()
+	return newTestServiceWithUsers(t, repo, &stubUserRepo{})
+}
+
+func newTestServiceWithUsers(t *testing.T, repo *stubScheduleRepo, users *stubUserRepo) *schedule.ScheduleService {
+	t.Helper()
+	return schedule.NewScheduleService(schedule.ScheduleServiceParams{
+		Repo:        repo,
+		WorkoutRepo: &stubWorkoutRepo{},
+		SessionRepo: &stubSessionRepo{},
+		UserRepo:    users,
+		Log:         zaptest.NewLogger(t).Sugar(),
+	})
+}
+
+func date(d, hour int) time.Time {
+	return time.Date(2026, 3, d, hour, 0, 0, 0, time.UTC)
+}
+
+// mondays schedules workout 5 of user 1 every Monday at 07:00 from March 2nd on.
+func mondays() *model.Schedule {
+	w, _ := model.ParseWeekdays([]string{"mon"})
+	return &model.Schedule{ID: 9, UserID: 1, WorkoutID: 5, WorkoutName: "Legs", StartsAt: date(2, 7), DurationMinutes: 60, Weekdays: w}
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestCreateSchedule_Success(t *testing.T) {
+	repo := &stubScheduleRepo{}
+	service := newTestService(t, repo)
+
+	id, err := service.CreateSchedule(t.Context(), 1, dto.ScheduleRequest{
+		WorkoutID: 5,
+		StartsAt:  time.Date(2026, 3, 2, 9, 0, 0, 0, time.FixedZone("CET", 3600)),
+		Repeat:    []string{"mon", "wed", "fri"},
+		Until:     "2026-06-30",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 9, id)
+	require.NotNil(t, repo.Created)
+	assert.Equal(t, date(2, 8), repo.Created.StartsAt)
+	assert.Equal(t, 60, repo.Created.DurationMinutes)
+	assert.Equal(t, []string{"mon", "wed", "fri"}, repo.Created.Weekdays.Names())
+	assert.Equal(t, time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC), *repo.Created.Until)
+}
+
+func TestCreateSchedule_Timezone(t *testing.T) {
+	repo := &stubScheduleRepo{}
+	service := newTestServiceWithUsers(t, repo, &stubUserRepo{Timezone: "Europe/Berlin"})
+
+	_, err := service.CreateSchedule(t.Context(), 1, dto.ScheduleRequest{WorkoutID: 5, StartsAt: date(2, 7)})
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", repo.Created.Timezone, "defaults to the profile timezone")
+
+	_, err = service.CreateSchedule(t.Context(), 1, dto.ScheduleRequest{WorkoutID: 5, StartsAt: date(2, 7), Timezone: "America/New_York"})
+	require.NoError(t, err)
+	assert.Equal(t, "America/New_York", repo.Created.Timezone)
+}
+
+func TestCreateSchedule_UntilIsALocalDate(t *testing.T) {
+	repo := &stubScheduleRepo{}
+	service := newTestServiceWithUsers(t, repo, &stubUserRepo{Timezone: "America/New_York"})
+
+	// Monday 21:00 in New York is already Tuesday in UTC, until may still be that Monday.
+	_, err := service.CreateSchedule(t.Context(), 1, dto.ScheduleRequest{
+		WorkoutID: 5,
+		StartsAt:  time.Date(2026, 3, 3, 2, 0, 0, 0, time.UTC),
+		Repeat:    []string{"mon"},
+		Until:     "2026-03-02",
+	})
+	require.NoError(t, err)
+}
+
+func TestCreateSchedule_ValidationErrors(t *testing.T) {
+	repo := &stubScheduleRepo{}
+	service := newTestService(t, repo)
+
+	_, err := service.CreateSchedule(t.Context(), 2, dto.ScheduleRequest{
+		WorkoutID: 5,
+		StartsAt:  date(2, 7),
+		Until:     "2026-06-30",
+	})
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, appErr.Status)
+	assert.Equal(t, []erorrs.FieldError{
+		{Field: "workout_id", Message: "does not exist"},
+		{Field: "until", Message: "requires repeat"},
+	}, appErr.Fields)
+	assert.Nil(t, repo.Created)
+}
+
+func TestMarkOccurrence_Completed(t *testing.T) {
+	repo := &stubScheduleRepo{Schedule: mondays()}
+	service := newTestService(t, repo)
+
+	mark, err := service.MarkOccurrence(t.Context(), 1, 9, date(9, 0), dto.MarkRequest{Status: "completed", SessionID: intPtr(40)})
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusCompleted, mark.Status)
+	require.NotNil(t, repo.Marked)
+	assert.Equal(t, date(9, 0), repo.Marked.Date)
+	assert.Equal(t, 40, *repo.Marked.SessionID)
+}
+
+func TestMarkOccurrence_NotAnOccurrence(t *testing.T) {
+	repo := &stubScheduleRepo{Schedule: mondays()}
+	service := newTestService(t, repo)
+
+	_, err := service.MarkOccurrence(t.Context(), 1, 9, date(10, 0), dto.MarkRequest{Status: "skipped", SessionID: intPtr(40)})
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, []erorrs.FieldError{
+		{Field: "date", Message: "is not an occurrence of the schedule"},
+		{Field: "session_id", Message: "is only allowed when completed"},
+	}, appErr.Fields)
+}
+
+func TestMarkOccurrence_OtherUsersSession(t *testing.T) {
+	repo := &stubScheduleRepo{Schedule: mondays()}
+	service := newTestService(t, repo)
+
+	_, err := service.MarkOccurrence(t.Context(), 1, 9, date(9, 0), dto.MarkRequest{Status: "completed", SessionID: intPtr(41)})
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "session_id", appErr.Fields[0].Field)
+}
+
+func TestMarkOccurrence_BackToPlanned(t *testing.T) {
+	repo := &stubScheduleRepo{Schedule: mondays()}
+	service := newTestService(t, repo)
+
+	_, err := service.MarkOccurrence(t.Context(), 1, 9, date(9, 12), dto.MarkRequest{Status: "planned"})
+	require.NoError(t, err)
+	assert.Equal(t, date(9, 0), repo.Unmarked)
+	assert.Nil(t, repo.Marked)
+}
+
+func TestMarkOccurrence_UnknownSchedule(t *testing.T) {
+	service := newTestService(t, &stubScheduleRepo{Schedule: mondays()})
+
+	_, err := service.MarkOccurrence(t.Context(), 2, 9, date(9, 0), dto.MarkRequest{Status: "skipped"})
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestGetCalendar(t *testing.T) {
+	repo := &stubScheduleRepo{
+		Schedule: mondays(),
+		Marked:   &model.Mark{ScheduleID: 9, Date: date(2, 0), Status: model.StatusSkipped},
+		Sessions: []model.Entry{{StartsAt: date(4, 18), SessionID: intPtr(40), Status: model.StatusCompleted, WorkoutName: "Run"}},
+	}
+	service := newTestService(t, repo)
+
+	entries, err := service.GetCalendar(t.Context(), 1, date(1, 0), date(9, 0))
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, model.StatusSkipped, entries[0].Status)
+	assert.Equal(t, "Run", entries[1].WorkoutName)
+	assert.Equal(t, date(9, 7), entries[2].StartsAt, "the to date is included")
+	assert.Equal(t, date(10, 0), repo.MarksTo)
+}
+
+func TestGetCalendar_InvalidRanges(t *testing.T) {
+	service := newTestService(t, &stubScheduleRepo{})
+
+	_, err := service.GetCalendar(t.Context(), 1, date(9, 0), date(1, 0))
+	assert.ErrorIs(t, err, erorrs.ErrInvalidDateRange)
+
+	_, err = service.GetCalendar(t.Context(), 1, date(1, 0), date(1, 0).AddDate(1, 1, 0))
+	assert.ErrorIs(t, err, erorrs.ErrCalendarRangeTooLong)
+}
+
+func TestFeed(t *testing.T) {
+	repo := &stubScheduleRepo{Schedule: mondays()}
+	service := newTestService(t, repo)
+
+	token, err := service.CreateCalendarToken(t.Context(), 1)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.NotEqual(t, token, repo.TokenHash, "only the hash is stored")
+
+	ics, err := service.Feed(t.Context(), token, date(3, 12))
+	require.NoError(t, err)
+	assert.Contains(t, ics, "UID:schedule-9-20260302@workout-tracker")
+	assert.Contains(t, ics, "SUMMARY:Legs")
+	assert.Equal(t, 1+365/7, strings.Count(ics, "BEGIN:VEVENT"), "the feed reaches a year ahead")
+
+	_, err = service.Feed(t.Context(), "wrong", date(3, 12))
+	assert.ErrorIs(t, err, erorrs.ErrTokenNotFound)
+}
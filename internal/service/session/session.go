@@ -0,0 +1,185 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"workout-tracker/internal/erorrs"
+	recordModel "workout-tracker/internal/model/record"
+	model "workout-tracker/internal/model/session"
+	exerciseRepo "workout-tracker/internal/repository/exercise"
+	sessionRepo "workout-tracker/internal/repository/session"
+	workoutRepo "workout-tracker/internal/repository/workout"
+	"workout-tracker/pkg/logger"
+
+	"go.uber.org/dig"
+)
+
+// RecordDetector checks a saved set for new personal records.
+type RecordDetector interface {
+	DetectRecords(ctx context.Context, userID int, set model.SessionSet) ([]recordModel.PersonalRecord, error)
+}
+
+type SessionServiceParams struct {
+	dig.In
+
+	Repo         sessionRepo.SessionRepositoryInterface
+	WorkoutRepo  workoutRepo.WorkoutRepositoryInterface
+	ExerciseRepo exerciseRepo.ExerciseRepositoryInterface
+	Records      RecordDetector
+	Log          logger.SugaredLoggerInterface
+}
+
+type SessionService struct {
+	Repo         sessionRepo.SessionRepositoryInterface
+	WorkoutRepo  workoutRepo.WorkoutRepositoryInterface
+	ExerciseRepo exerciseRepo.ExerciseRepositoryInterface
+	Records      RecordDetector
+	Log          logger.SugaredLoggerInterface
+}
+
+func NewSessionService(params SessionServiceParams) *SessionService {
+	return &SessionService{
+		Repo:         params.Repo,
+		WorkoutRepo:  params.WorkoutRepo,
+		ExerciseRepo: params.ExerciseRepo,
+		Records:      params.Records,
+		Log:          params.Log,
+	}
+}
+
+// StartSession opens a new session for the given workout template. The template itself is never modified.
+func (s *SessionService) StartSession(ctx context.Context, userID, workoutID int, notes string) (*model.Session, error) {
+	if _, err := s.WorkoutRepo.GetWorkoutByID(ctx, workoutID, userID); err != nil {
+		s.Log.Errorw("failed to get workout for session", "workoutID", workoutID, "error", err)
+		return nil, fmt.Errorf("get workout: %w", err)
+	}
+
+	now := time.Now()
+	session := model.Session{
+		UserID:    userID,
+		WorkoutID: workoutID,
+		Notes:     notes,
+		StartedAt: now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	id, err := s.Repo.CreateSession(ctx, session)
+	if err != nil {
+		s.Log.Errorw("failed to start session", "workoutID", workoutID, "error", err)
+		return nil, fmt.Errorf("start session: %w", err)
+	}
+	session.ID = id
+	session.Sets = []model.SessionSet{}
+
+	return &session, nil
+}
+
+func (s *SessionService) GetSessions(ctx context.Context, userID, workoutID int) ([]model.Session, error) {
+	sessions, err := s.Repo.GetSessionsByWorkout(ctx, workoutID, userID)
+	if err != nil {
+		s.Log.Errorw("failed to get sessions", "workoutID", workoutID, "error", err)
+		return nil, fmt.Errorf("get sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (s *SessionService) GetSession(ctx context.Context, userID, workoutID, sessionID int) (*model.Session, error) {
+	session, err := s.getSession(ctx, userID, workoutID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sets, err := s.Repo.GetSessionSets(ctx, sessionID)
+	if err != nil {
+		s.Log.Errorw("failed to get session sets", "sessionID", sessionID, "error", err)
+		return nil, fmt.Errorf("get session sets: %w", err)
+	}
+	session.Sets = sets
+
+	return session, nil
+}
+
+// LogSet records one performed set against an unfinished session. The exercise must exist and be
+// accessible to the user, and each set number can be logged once per exercise. Personal records
+// beaten by the set are returned with it; failing to detect them is logged but does not fail the set.
+func (s *SessionService) LogSet(ctx context.Context, userID, workoutID, sessionID int, set model.SessionSet) (*model.SessionSet, error) {
+	session, err := s.getSession(ctx, userID, workoutID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.IsFinished() {
+		return nil, erorrs.ErrSessionFinished
+	}
+	if err := s.checkExercise(ctx, userID, set.ExerciseID); err != nil {
+		return nil, err
+	}
+
+	set.SessionID = sessionID
+	if set.CompletedAt.IsZero() {
+		set.CompletedAt = time.Now()
+	}
+
+	id, err := s.Repo.AddSet(ctx, set)
+	if err != nil {
+		s.Log.Errorw("failed to log set", "sessionID", sessionID, "error", err)
+		return nil, fmt.Errorf("log set: %w", err)
+	}
+	set.ID = id
+
+	records, err := s.Records.DetectRecords(ctx, userID, set)
+	if err != nil {
+		s.Log.Errorw("failed to detect personal records", "setID", id, "error", err)
+	}
+	set.NewRecords = records
+
+	return &set, nil
+}
+
+func (s *SessionService) FinishSession(ctx context.Context, userID, workoutID, sessionID int, notes *string) error {
+	session, err := s.getSession(ctx, userID, workoutID, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.IsFinished() {
+		return erorrs.ErrSessionFinished
+	}
+
+	if err := s.Repo.FinishSession(ctx, sessionID, userID, time.Now(), notes); err != nil {
+		s.Log.Errorw("failed to finish session", "sessionID", sessionID, "error", err)
+		return fmt.Errorf("finish session: %w", err)
+	}
+
+	return nil
+}
+
+// checkExercise reports an exercise that does not exist or belongs to another user as a validation
+// error on exercise_id, so that private exercises are not revealed.
+func (s *SessionService) checkExercise(ctx context.Context, userID, exerciseID int) error {
+	found, err := s.ExerciseRepo.GetExercisesByIDs(ctx, []int{exerciseID})
+	if err != nil {
+		s.Log.Errorw("failed to load exercise", "exerciseID", exerciseID, "error", err)
+		return fmt.Errorf("load exercise: %w", err)
+	}
+	if len(found) == 0 || !found[0].IsAccessibleBy(userID) {
+		return erorrs.Validation(erorrs.FieldError{Field: "exercise_id", Message: "does not exist"}).
+			Wrap(fmt.Errorf("exercise %d: %w", exerciseID, erorrs.ErrUnknownExercise))
+	}
+
+	return nil
+}
+
+func (s *SessionService) getSession(ctx context.Context, userID, workoutID, sessionID int) (*model.Session, error) {
+	session, err := s.Repo.GetSessionByID(ctx, sessionID, userID)
+	if err != nil {
+		s.Log.Errorw("failed to get session", "sessionID", sessionID, "error", err)
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+	if session.WorkoutID != workoutID {
+		return nil, erorrs.ErrNotFound
+	}
+
+	return session, nil
+}
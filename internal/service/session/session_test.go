@@ -0,0 +1,345 @@
+package session_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+	"workout-tracker/internal/erorrs"
+	exerciseModel "workout-tracker/internal/model/exercise"
+	recordModel "workout-tracker/internal/model/record"
+	model "workout-tracker/internal/model/session"
+	workoutModel "workout-tracker/internal/model/workout"
+	exerciseRepo "workout-tracker/internal/repository/exercise"
+	workoutRepo "workout-tracker/internal/repository/workout"
+	"workout-tracker/internal/service/session"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type stubSessionRepo struct {
+	CreateSessionFn        func(ctx context.Context, s model.Session) (int, error)
+	GetSessionByIDFn       func(ctx context.Context, sessionID, userID int) (*model.Session, error)
+	GetSessionsByWorkoutFn func(ctx context.Context, workoutID, userID int) ([]model.Session, error)
+	FinishSessionFn        func(ctx context.Context, sessionID, userID int, finishedAt time.Time, notes *string) error
+	AddSetFn               func(ctx context.Context, set model.SessionSet) (int, error)
+	GetSessionSetsFn       func(ctx context.Context, sessionID int) ([]model.SessionSet, error)
+	GetExerciseHistoryFn   func(ctx context.Context, userID int, exerciseIDs []int, sessions int) (map[int][]model.Session, error)
+}
+
+func (s *stubSessionRepo) CreateSession(ctx context.Context, session model.Session) (int, error) {
+	return s.CreateSessionFn(ctx, session)
+}
+func (s *stubSessionRepo) GetSessionByID(ctx context.Context, sessionID, userID int) (*model.Session, error) {
+	return s.GetSessionByIDFn(ctx, sessionID, userID)
+}
+func (s *stubSessionRepo) GetSessionsByWorkout(ctx context.Context, workoutID, userID int) ([]model.Session, error) {
+	return s.GetSessionsByWorkoutFn(ctx, workoutID, userID)
+}
+func (s *stubSessionRepo) FinishSession(ctx context.Context, sessionID, userID int, finishedAt time.Time, notes *string) error {
+	return s.FinishSessionFn(ctx, sessionID, userID, finishedAt, notes)
+}
+func (s *stubSessionRepo) AddSet(ctx context.Context, set model.SessionSet) (int, error) {
+	return s.AddSetFn(ctx, set)
+}
+func (s *stubSessionRepo) GetSessionSets(ctx context.Context, sessionID int) ([]model.SessionSet, error) {
+	return s.GetSessionSetsFn(ctx, sessionID)
+}
+func (s *stubSessionRepo) GetExerciseHistory(ctx context.Context, userID int, exerciseIDs []int, sessions int) (map[int][]model.Session, error) {
+	return s.GetExerciseHistoryFn(ctx, userID, exerciseIDs, sessions)
+}
+
+type stubWorkoutRepo struct {
+	workoutRepo.WorkoutRepositoryInterface
+	GetWorkoutByIDFn func(ctx context.Context, workoutID, userID int) (*workoutModel.Workout, error)
+}
+
+func (s *stubWorkoutRepo) GetWorkoutByID(ctx context.Context, workoutID, userID int) (*workoutModel.Workout, error) {
+	return s.GetWorkoutByIDFn(ctx, workoutID, userID)
+}
+
+type stubExerciseRepo struct {
+	exerciseRepo.ExerciseRepositoryInterface
+	GetExercisesByIDsFn func(ctx context.Context, ids []int) ([]exerciseModel.Exercise, error)
+}
+
+// GetExercisesByIDs finds every exercise in the global catalogue unless GetExercisesByIDsFn is set.
+func (s *stubExerciseRepo) GetExercisesByIDs(ctx context.Context, ids []int) ([]exerciseModel.Exercise, error) {
+	if s.GetExercisesByIDsFn != nil {
+		return s.GetExercisesByIDsFn(ctx, ids)
+	}
+	found := make([]exerciseModel.Exercise, len(ids))
+	for i, id := range ids {
+		found[i] = exerciseModel.Exercise{ID: id}
+	}
+	return found, nil
+}
+
+type stubRecordDetector struct {
+	DetectRecordsFn func(ctx context.Context, userID int, set model.SessionSet) ([]recordModel.PersonalRecord, error)
+}
+
+func (s *stubRecordDetector) DetectRecords(ctx context.Context, userID int, set model.SessionSet) ([]recordModel.PersonalRecord, error) {
+	if s.DetectRecordsFn == nil {
+		return nil, nil
+	}
+	return s.DetectRecordsFn(ctx, userID, set)
+}
+
+func newTestService(t *testing.T, repo *stubSessionRepo, workouts *stubWorkoutRepo) *session.SessionService {
+	t.Helper()
+	return newTestServiceWithRecords(t, repo, workouts, &stubRecordDetector{})
+}
+
+func newTestServiceWithRecords(
+	t *testing.T, repo *stubSessionRepo, workouts *stubWorkoutRepo, records *stubRecordDetector) *session.SessionService {
+	t.Helper()
+	return newTestServiceWithExercises(t, repo, workouts, &stubExerciseRepo{}, records)
+}
+
+func newTestServiceWithExercises(t *testing.T, repo *stubSessionRepo, workouts *stubWorkoutRepo,
+	exercises *stubExerciseRepo, records *stubRecordDetector) *session.SessionService {
+	t.Helper()
+	return session.NewSessionService(session.SessionServiceParams{
+		Repo:         repo,
+		WorkoutRepo:  workouts,
+		ExerciseRepo: exercises,
+		Records:      records,
+		Log:          zaptest.NewLogger(t).Sugar(),
+	})
+}
+
+func TestStartSession_Success(t *testing.T) {
+	repo := &stubSessionRepo{
+		CreateSessionFn: func(ctx context.Context, s model.Session) (int, error) {
+			assert.Equal(t, 2, s.WorkoutID)
+			assert.Equal(t, 1, s.UserID)
+			return 10, nil
+		},
+	}
+	workouts := &stubWorkoutRepo{
+		GetWorkoutByIDFn: func(ctx context.Context, workoutID, userID int) (*workoutModel.Workout, error) {
+			return &workoutModel.Workout{ID: workoutID, UserID: userID}, nil
+		},
+	}
+
+	res, err := newTestService(t, repo, workouts).StartSession(t.Context(), 1, 2, "heavy day")
+	require.NoError(t, err)
+	assert.Equal(t, 10, res.ID)
+	assert.Equal(t, "heavy day", res.Notes)
+}
+
+func TestStartSession_WorkoutMissing(t *testing.T) {
+	workouts := &stubWorkoutRepo{
+		GetWorkoutByIDFn: func(ctx context.Context, workoutID, userID int) (*workoutModel.Workout, error) {
+			return nil, errors.New("no rows")
+		},
+	}
+
+	_, err := newTestService(t, &stubSessionRepo{}, workouts).StartSession(t.Context(), 1, 2, "")
+	assert.Error(t, err)
+}
+
+func TestLogSet_FinishedSession(t *testing.T) {
+	finished := time.Now()
+	repo := &stubSessionRepo{
+		GetSessionByIDFn: func(ctx context.Context, sessionID, userID int) (*model.Session, error) {
+			return &model.Session{ID: sessionID, WorkoutID: 2, FinishedAt: &finished}, nil
+		},
+	}
+
+	_, err := newTestService(t, repo, &stubWorkoutRepo{}).LogSet(t.Context(), 1, 2, 3, model.SessionSet{ExerciseID: 4})
+	assert.ErrorIs(t, err, erorrs.ErrSessionFinished)
+}
+
+func TestLogSet_WrongWorkout(t *testing.T) {
+	repo := &stubSessionRepo{
+		GetSessionByIDFn: func(ctx context.Context, sessionID, userID int) (*model.Session, error) {
+			return &model.Session{ID: sessionID, WorkoutID: 99}, nil
+		},
+	}
+
+	_, err := newTestService(t, repo, &stubWorkoutRepo{}).LogSet(t.Context(), 1, 2, 3, model.SessionSet{ExerciseID: 4})
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
+
+func TestLogSet_Success(t *testing.T) {
+	repo := &stubSessionRepo{
+		GetSessionByIDFn: func(ctx context.Context, sessionID, userID int) (*model.Session, error) {
+			return &model.Session{ID: sessionID, WorkoutID: 2}, nil
+		},
+		AddSetFn: func(ctx context.Context, set model.SessionSet) (int, error) {
+			assert.Equal(t, 3, set.SessionID)
+			assert.False(t, set.CompletedAt.IsZero())
+			return 7, nil
+		},
+	}
+
+	res, err := newTestService(t, repo, &stubWorkoutRepo{}).
+		LogSet(t.Context(), 1, 2, 3, model.SessionSet{ExerciseID: 4, SetNumber: 1, Reps: 5, Weight: 100})
+	require.NoError(t, err)
+	assert.Equal(t, 7, res.ID)
+}
+
+func TestLogSet_UnknownExercise(t *testing.T) {
+	repo := &stubSessionRepo{
+		GetSessionByIDFn: func(ctx context.Context, sessionID, userID int) (*model.Session, error) {
+			return &model.Session{ID: sessionID, WorkoutID: 2}, nil
+		},
+		AddSetFn: func(ctx context.Context, set model.SessionSet) (int, error) {
+			t.Fatal("a set of an unknown exercise must not be stored")
+			return 0, nil
+		},
+	}
+	exercises := &stubExerciseRepo{
+		GetExercisesByIDsFn: func(ctx context.Context, ids []int) ([]exerciseModel.Exercise, error) {
+			assert.Equal(t, []int{404}, ids)
+			return nil, nil
+		},
+	}
+
+	_, err := newTestServiceWithExercises(t, repo, &stubWorkoutRepo{}, exercises, &stubRecordDetector{}).
+		LogSet(t.Context(), 1, 2, 3, model.SessionSet{ExerciseID: 404, SetNumber: 1})
+
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, appErr.Status)
+	assert.Equal(t, []erorrs.FieldError{{Field: "exercise_id", Message: "does not exist"}}, appErr.Fields)
+	assert.ErrorIs(t, err, erorrs.ErrUnknownExercise)
+}
+
+func TestLogSet_ExerciseOfAnotherUser(t *testing.T) {
+	repo := &stubSessionRepo{
+		GetSessionByIDFn: func(ctx context.Context, sessionID, userID int) (*model.Session, error) {
+			return &model.Session{ID: sessionID, WorkoutID: 2}, nil
+		},
+	}
+	owner := 99
+	exercises := &stubExerciseRepo{
+		GetExercisesByIDsFn: func(ctx context.Context, ids []int) ([]exerciseModel.Exercise, error) {
+			return []exerciseModel.Exercise{{ID: ids[0], OwnerUserID: &owner}}, nil
+		},
+	}
+
+	_, err := newTestServiceWithExercises(t, repo, &stubWorkoutRepo{}, exercises, &stubRecordDetector{}).
+		LogSet(t.Context(), 1, 2, 3, model.SessionSet{ExerciseID: 4, SetNumber: 1})
+	assert.ErrorIs(t, err, erorrs.ErrUnknownExercise)
+	assert.Equal(t, http.StatusUnprocessableEntity, erorrs.FromError(err).Status)
+}
+
+func TestLogSet_OwnExercise(t *testing.T) {
+	repo := &stubSessionRepo{
+		GetSessionByIDFn: func(ctx context.Context, sessionID, userID int) (*model.Session, error) {
+			return &model.Session{ID: sessionID, WorkoutID: 2}, nil
+		},
+		AddSetFn: func(ctx context.Context, set model.SessionSet) (int, error) {
+			return 7, nil
+		},
+	}
+	owner := 1
+	exercises := &stubExerciseRepo{
+		GetExercisesByIDsFn: func(ctx context.Context, ids []int) ([]exerciseModel.Exercise, error) {
+			return []exerciseModel.Exercise{{ID: ids[0], OwnerUserID: &owner}}, nil
+		},
+	}
+
+	res, err := newTestServiceWithExercises(t, repo, &stubWorkoutRepo{}, exercises, &stubRecordDetector{}).
+		LogSet(t.Context(), 1, 2, 3, model.SessionSet{ExerciseID: 4, SetNumber: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 7, res.ID)
+}
+
+func TestLogSet_DuplicateSetNumber(t *testing.T) {
+	repo := &stubSessionRepo{
+		GetSessionByIDFn: func(ctx context.Context, sessionID, userID int) (*model.Session, error) {
+			return &model.Session{ID: sessionID, WorkoutID: 2}, nil
+		},
+		AddSetFn: func(ctx context.Context, set model.SessionSet) (int, error) {
+			return 0, erorrs.ErrSetAlreadyLogged
+		},
+	}
+
+	_, err := newTestService(t, repo, &stubWorkoutRepo{}).
+		LogSet(t.Context(), 1, 2, 3, model.SessionSet{ExerciseID: 4, SetNumber: 1})
+	assert.ErrorIs(t, err, erorrs.ErrSetAlreadyLogged)
+	assert.Equal(t, http.StatusConflict, erorrs.FromError(err).Status)
+}
+
+func TestLogSet_ReturnsNewRecords(t *testing.T) {
+	repo := &stubSessionRepo{
+		GetSessionByIDFn: func(ctx context.Context, sessionID, userID int) (*model.Session, error) {
+			return &model.Session{ID: sessionID, WorkoutID: 2}, nil
+		},
+		AddSetFn: func(ctx context.Context, set model.SessionSet) (int, error) {
+			return 7, nil
+		},
+	}
+	records := &stubRecordDetector{
+		DetectRecordsFn: func(ctx context.Context, userID int, set model.SessionSet) ([]recordModel.PersonalRecord, error) {
+			assert.Equal(t, 7, set.ID)
+			return []recordModel.PersonalRecord{{RecordType: recordModel.MaxWeight, Value: 100}}, nil
+		},
+	}
+
+	res, err := newTestServiceWithRecords(t, repo, &stubWorkoutRepo{}, records).
+		LogSet(t.Context(), 1, 2, 3, model.SessionSet{ExerciseID: 4, Reps: 5, Weight: 100})
+	require.NoError(t, err)
+	require.Len(t, res.NewRecords, 1)
+	assert.Equal(t, recordModel.MaxWeight, res.NewRecords[0].RecordType)
+}
+
+func TestLogSet_RecordDetectionErrorIgnored(t *testing.T) {
+	repo := &stubSessionRepo{
+		GetSessionByIDFn: func(ctx context.Context, sessionID, userID int) (*model.Session, error) {
+			return &model.Session{ID: sessionID, WorkoutID: 2}, nil
+		},
+		AddSetFn: func(ctx context.Context, set model.SessionSet) (int, error) {
+			return 7, nil
+		},
+	}
+	records := &stubRecordDetector{
+		DetectRecordsFn: func(ctx context.Context, userID int, set model.SessionSet) ([]recordModel.PersonalRecord, error) {
+			return nil, errors.New("db down")
+		},
+	}
+
+	res, err := newTestServiceWithRecords(t, repo, &stubWorkoutRepo{}, records).
+		LogSet(t.Context(), 1, 2, 3, model.SessionSet{ExerciseID: 4, Reps: 5, Weight: 100})
+	require.NoError(t, err)
+	assert.Equal(t, 7, res.ID)
+	assert.Empty(t, res.NewRecords)
+}
+
+func TestFinishSession_Success(t *testing.T) {
+	called := false
+	repo := &stubSessionRepo{
+		GetSessionByIDFn: func(ctx context.Context, sessionID, userID int) (*model.Session, error) {
+			return &model.Session{ID: sessionID, WorkoutID: 2}, nil
+		},
+		FinishSessionFn: func(ctx context.Context, sessionID, userID int, finishedAt time.Time, notes *string) error {
+			called = true
+			return nil
+		},
+	}
+
+	err := newTestService(t, repo, &stubWorkoutRepo{}).FinishSession(t.Context(), 1, 2, 3, nil)
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestGetSession_SetsError(t *testing.T) {
+	repo := &stubSessionRepo{
+		GetSessionByIDFn: func(ctx context.Context, sessionID, userID int) (*model.Session, error) {
+			return &model.Session{ID: sessionID, WorkoutID: 2}, nil
+		},
+		GetSessionSetsFn: func(ctx context.Context, sessionID int) ([]model.SessionSet, error) {
+			return nil, errors.New("db down")
+		},
+	}
+
+	_, err := newTestService(t, repo, &stubWorkoutRepo{}).GetSession(t.Context(), 1, 2, 3)
+	assert.Error(t, err)
+}
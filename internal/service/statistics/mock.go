@@ -0,0 +1,44 @@
+package statistics
+
+import (
+	"context"
+	"fmt"
+	dto "workout-tracker/internal/dto/statistics"
+	model "workout-tracker/internal/model/statistics"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStatisticsRepo struct {
+	mock.Mock
+}
+
+func (m *MockStatisticsRepo) GetCategoryStatistics(
+	ctx context.Context, userID int, filter dto.StatisticsFilter) ([]model.WorkoutStatistics, error) {
+	args := m.Called(ctx, userID, filter)
+	stats, ok := args.Get(0).([]model.WorkoutStatistics)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for []model.WorkoutStatistics: %w", args.Error(1))
+	}
+	return stats, args.Error(1)
+}
+
+func (m *MockStatisticsRepo) GetExerciseStatistics(
+	ctx context.Context, userID int, filter dto.StatisticsFilter) ([]model.ExerciseStatistics, error) {
+	args := m.Called(ctx, userID, filter)
+	stats, ok := args.Get(0).([]model.ExerciseStatistics)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for []model.ExerciseStatistics: %w", args.Error(1))
+	}
+	return stats, args.Error(1)
+}
+
+func (m *MockStatisticsRepo) GetPeriodStatistics(
+	ctx context.Context, userID int, bucket model.Bucket, filter dto.StatisticsFilter) ([]model.PeriodStatistics, error) {
+	args := m.Called(ctx, userID, bucket, filter)
+	stats, ok := args.Get(0).([]model.PeriodStatistics)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for []model.PeriodStatistics: %w", args.Error(1))
+	}
+	return stats, args.Error(1)
+}
@@ -0,0 +1,111 @@
+package statistics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+	dto "workout-tracker/internal/dto/statistics"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/statistics"
+	repo "workout-tracker/internal/repository/statistics"
+	"workout-tracker/pkg/logger"
+
+	"go.uber.org/dig"
+)
+
+// Bodyweights looks up the bodyweight relative strength is computed against.
+type Bodyweights interface {
+	LatestBodyweight(ctx context.Context, userID int, at time.Time) (*float64, error)
+}
+
+type StatisticsServiceParams struct {
+	dig.In
+
+	Repo        repo.StatisticsRepositoryInterface
+	Bodyweights Bodyweights
+	Log         logger.SugaredLoggerInterface
+}
+
+type StatisticsService struct {
+	Repo        repo.StatisticsRepositoryInterface
+	Bodyweights Bodyweights
+	Log         logger.SugaredLoggerInterface
+}
+
+func NewStatisticsService(params StatisticsServiceParams) *StatisticsService {
+	return &StatisticsService{
+		Repo:        params.Repo,
+		Bodyweights: params.Bodyweights,
+		Log:         params.Log,
+	}
+}
+
+func (s *StatisticsService) GetCategoryStatistics(
+	ctx context.Context, userID int, filter dto.StatisticsFilter) ([]model.WorkoutStatistics, error) {
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.Repo.GetCategoryStatistics(ctx, userID, filter)
+	if err != nil {
+		s.Log.Errorw("failed to get category statistics", "userID", userID, "error", err)
+		return nil, fmt.Errorf("get category statistics: %w", err)
+	}
+	return stats, nil
+}
+
+func (s *StatisticsService) GetExerciseStatistics(
+	ctx context.Context, userID int, filter dto.StatisticsFilter) ([]model.ExerciseStatistics, error) {
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.Repo.GetExerciseStatistics(ctx, userID, filter)
+	if err != nil {
+		s.Log.Errorw("failed to get exercise statistics", "userID", userID, "error", err)
+		return nil, fmt.Errorf("get exercise statistics: %w", err)
+	}
+
+	// Strength is compared with the bodyweight at the end of the period looked at.
+	at := time.Now()
+	if filter.To != nil {
+		at = *filter.To
+	}
+	bodyweight, err := s.Bodyweights.LatestBodyweight(ctx, userID, at)
+	if err != nil {
+		s.Log.Errorw("failed to get bodyweight", "userID", userID, "error", err)
+		return nil, fmt.Errorf("get exercise statistics: %w", err)
+	}
+	if bodyweight != nil && *bodyweight > 0 {
+		for i := range stats {
+			ratio := math.Round(stats[i].MaxWeight / *bodyweight * 100) / 100
+			stats[i].RelativeStrength = &ratio
+		}
+	}
+	return stats, nil
+}
+
+func (s *StatisticsService) GetPeriodStatistics(
+	ctx context.Context, userID int, bucket model.Bucket, filter dto.StatisticsFilter) ([]model.PeriodStatistics, error) {
+	if !bucket.IsValid() {
+		return nil, erorrs.ErrInvalidBucket
+	}
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.Repo.GetPeriodStatistics(ctx, userID, bucket, filter)
+	if err != nil {
+		s.Log.Errorw("failed to get period statistics", "userID", userID, "error", err)
+		return nil, fmt.Errorf("get period statistics: %w", err)
+	}
+	return stats, nil
+}
+
+func validateFilter(filter dto.StatisticsFilter) error {
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return erorrs.ErrInvalidDateRange
+	}
+	return nil
+}
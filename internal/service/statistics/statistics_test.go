@@ -0,0 +1,106 @@
+package statistics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	dto "workout-tracker/internal/dto/statistics"
+	"workout-tracker/internal/erorrs"
+	model "workout-tracker/internal/model/statistics"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type stubBodyweights struct {
+	weight *float64
+}
+
+func (s stubBodyweights) LatestBodyweight(ctx context.Context, userID int, at time.Time) (*float64, error) {
+	return s.weight, nil
+}
+
+func newService(repo *MockStatisticsRepo) *StatisticsService {
+	return newServiceWithBodyweight(repo, nil)
+}
+
+func newServiceWithBodyweight(repo *MockStatisticsRepo, bodyweight *float64) *StatisticsService {
+	return NewStatisticsService(StatisticsServiceParams{
+		Repo:        repo,
+		Bodyweights: stubBodyweights{weight: bodyweight},
+		Log:         zap.NewNop().Sugar(),
+	})
+}
+
+func TestGetCategoryStatistics_Success(t *testing.T) {
+	ctx := t.Context()
+	repo := new(MockStatisticsRepo)
+	expected := []model.WorkoutStatistics{{Category: "legs", TotalWeight: 100, UserID: 1}}
+	repo.On("GetCategoryStatistics", ctx, 1, dto.StatisticsFilter{}).Return(expected, nil)
+
+	res, err := newService(repo).GetCategoryStatistics(ctx, 1, dto.StatisticsFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, res)
+	repo.AssertExpectations(t)
+}
+
+func TestGetCategoryStatistics_InvalidRange(t *testing.T) {
+	from := time.Now()
+	to := from.Add(-time.Hour)
+	repo := new(MockStatisticsRepo)
+
+	_, err := newService(repo).GetCategoryStatistics(t.Context(), 1, dto.StatisticsFilter{From: &from, To: &to})
+	assert.ErrorIs(t, err, erorrs.ErrInvalidDateRange)
+	repo.AssertNotCalled(t, "GetCategoryStatistics")
+}
+
+func TestGetExerciseStatistics_Error(t *testing.T) {
+	ctx := t.Context()
+	repo := new(MockStatisticsRepo)
+	repo.On("GetExerciseStatistics", ctx, 1, dto.StatisticsFilter{}).Return([]model.ExerciseStatistics(nil), errors.New("db"))
+
+	_, err := newService(repo).GetExerciseStatistics(ctx, 1, dto.StatisticsFilter{})
+	assert.ErrorContains(t, err, "get exercise statistics")
+}
+
+func TestGetExerciseStatistics_RelativeStrength(t *testing.T) {
+	ctx := t.Context()
+	repo := new(MockStatisticsRepo)
+	repo.On("GetExerciseStatistics", ctx, 1, dto.StatisticsFilter{}).
+		Return([]model.ExerciseStatistics{{ExerciseID: 3, MaxWeight: 120}}, nil)
+	bodyweight := 80.0
+
+	res, err := newServiceWithBodyweight(repo, &bodyweight).GetExerciseStatistics(ctx, 1, dto.StatisticsFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, *res[0].RelativeStrength)
+}
+
+func TestGetExerciseStatistics_NoBodyweight(t *testing.T) {
+	ctx := t.Context()
+	repo := new(MockStatisticsRepo)
+	repo.On("GetExerciseStatistics", ctx, 1, dto.StatisticsFilter{}).
+		Return([]model.ExerciseStatistics{{ExerciseID: 3, MaxWeight: 120}}, nil)
+
+	res, err := newService(repo).GetExerciseStatistics(ctx, 1, dto.StatisticsFilter{})
+	assert.NoError(t, err)
+	assert.Nil(t, res[0].RelativeStrength)
+}
+
+func TestGetPeriodStatistics_InvalidBucket(t *testing.T) {
+	repo := new(MockStatisticsRepo)
+
+	_, err := newService(repo).GetPeriodStatistics(t.Context(), 1, model.Bucket("year"), dto.StatisticsFilter{})
+	assert.ErrorIs(t, err, erorrs.ErrInvalidBucket)
+}
+
+func TestGetPeriodStatistics_Success(t *testing.T) {
+	ctx := t.Context()
+	repo := new(MockStatisticsRepo)
+	expected := []model.PeriodStatistics{{TotalSets: 4}}
+	repo.On("GetPeriodStatistics", ctx, 1, model.BucketMonth, dto.StatisticsFilter{}).Return(expected, nil)
+
+	res, err := newService(repo).GetPeriodStatistics(ctx, 1, model.BucketMonth, dto.StatisticsFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, res)
+}
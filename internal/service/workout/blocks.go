@@ -0,0 +1,58 @@
+package workout
+
+import (
+	"context"
+	"fmt"
+	joinModel "workout-tracker/internal/model/workoutexercisejoin"
+	workoutInterface "workout-tracker/internal/repository/workout"
+)
+
+// insertBlocks stores the blocks of a workout followed by their exercises and set prescriptions.
+// Blocks are numbered in the given order and exercises are numbered across the whole workout.
+func (s *WorkoutService) insertBlocks(ctx context.Context, repo workoutInterface.WorkoutRepositoryInterface,
+	workoutID int, blocks []joinModel.Block) error {
+	rows, exercises, sets := flatten(workoutID, blocks)
+
+	if err := repo.BulkInsertWorkoutBlocks(ctx, rows); err != nil {
+		s.Log.Errorw("failed to insert blocks", "error", err)
+		return fmt.Errorf("insert blocks: %w", err)
+	}
+	if err := repo.BulkInsertWorkoutExercises(ctx, exercises); err != nil {
+		s.Log.Errorw("failed to insert exercises", "error", err)
+		return fmt.Errorf("insert exercises: %w", err)
+	}
+	if err := repo.BulkInsertSetPrescriptions(ctx, sets); err != nil {
+		s.Log.Errorw("failed to insert set prescriptions", "error", err)
+		return fmt.Errorf("insert set prescriptions: %w", err)
+	}
+	return nil
+}
+
+// flatten splits blocks into the rows of the three tables they are stored in. Sets are numbered
+// from one within their exercise.
+func flatten(workoutID int, blocks []joinModel.Block) (
+	[]joinModel.Block, []joinModel.WorkoutExercise, []joinModel.SetPrescription) {
+	rows := make([]joinModel.Block, 0, len(blocks))
+	var exercises []joinModel.WorkoutExercise
+	var sets []joinModel.SetPrescription
+	for i, b := range blocks {
+		for _, e := range b.Exercises {
+			e.WorkoutID = workoutID
+			e.BlockPosition = i
+			e.Position = len(exercises)
+			for k, set := range e.Prescription {
+				set.WorkoutID = workoutID
+				set.ExercisePosition = e.Position
+				set.SetNumber = k + 1
+				sets = append(sets, set)
+			}
+			e.Prescription = nil
+			exercises = append(exercises, e)
+		}
+		b.WorkoutID = workoutID
+		b.Position = i
+		b.Exercises = nil
+		rows = append(rows, b)
+	}
+	return rows, exercises, sets
+}
@@ -0,0 +1,91 @@
+package workout
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+	dto "workout-tracker/internal/dto/workout"
+	"workout-tracker/internal/erorrs"
+	categoryModel "workout-tracker/internal/model/category"
+	model "workout-tracker/internal/model/workout"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// normalizeFilter fills in the defaults, validates the filter and decodes its cursor.
+func normalizeFilter(filter dto.WorkoutFilter) (dto.WorkoutFilter, *dto.WorkoutCursor, error) {
+	filter.Category = categoryModel.Slugify(filter.Category)
+
+	if filter.SortBy == "" {
+		filter.SortBy = model.SortByCreatedAt
+	}
+	if !filter.SortBy.IsValid() {
+		return filter, nil, erorrs.ErrInvalidSort
+	}
+
+	if filter.Order == "" {
+		filter.Order = model.OrderDesc
+	}
+	if !filter.Order.IsValid() {
+		return filter, nil, erorrs.ErrInvalidOrder
+	}
+
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return filter, nil, erorrs.ErrInvalidDateRange
+	}
+
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = defaultPageSize
+	case filter.Limit > maxPageSize:
+		filter.Limit = maxPageSize
+	}
+
+	if filter.Cursor == "" {
+		return filter, nil, nil
+	}
+
+	cursor, err := decodeCursor(filter.Cursor)
+	if err != nil || cursor.SortBy != filter.SortBy {
+		return filter, nil, erorrs.ErrInvalidCursor
+	}
+
+	return filter, cursor, nil
+}
+
+func encodeCursor(sortBy model.SortField, last model.Workout) (string, error) {
+	cursor := dto.WorkoutCursor{SortBy: sortBy, ID: last.ID}
+	switch sortBy {
+	case model.SortByCreatedAt:
+		cursor.Value = last.CreatedAt.Format(time.RFC3339Nano)
+	case model.SortByUpdatedAt:
+		cursor.Value = last.UpdatedAt.Format(time.RFC3339Nano)
+	case model.SortByName:
+		cursor.Value = last.Name
+	case model.SortByTitle:
+		cursor.Value = last.Title
+	}
+
+	raw, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(encoded string) (*dto.WorkoutCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode cursor: %w", err)
+	}
+
+	var cursor dto.WorkoutCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, fmt.Errorf("decode cursor: %w", err)
+	}
+	return &cursor, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	dto "workout-tracker/internal/dto/workout"
 	"workout-tracker/internal/model/workout"
 	"workout-tracker/internal/model/workoutexercisejoin"
 
@@ -24,6 +25,31 @@ func (m *WorkoutRepoMock) BulkInsertWorkoutExercises(ctx context.Context, ex []w
 	return fmt.Errorf("error bulk insert workout: %w", args.Error(0))
 }
 
+func (m *WorkoutRepoMock) BulkInsertWorkoutBlocks(ctx context.Context, blocks []workoutexercisejoin.Block) error {
+	args := m.Called(ctx, blocks)
+	return args.Error(0)
+}
+
+func (m *WorkoutRepoMock) BulkInsertSetPrescriptions(ctx context.Context, sets []workoutexercisejoin.SetPrescription) error {
+	args := m.Called(ctx, sets)
+	return args.Error(0)
+}
+
+func (m *WorkoutRepoMock) GetSetPrescriptions(ctx context.Context, workoutID int) ([]workoutexercisejoin.SetPrescription, error) {
+	args := m.Called(ctx, workoutID)
+
+	sets, ok := args.Get(0).([]workoutexercisejoin.SetPrescription)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for []workoutexercisejoin.SetPrescription: %w", args.Error(1))
+	}
+
+	if err := args.Error(1); err != nil {
+		return nil, fmt.Errorf("error in GetSetPrescriptions: %w", err)
+	}
+
+	return sets, nil
+}
+
 func (m *WorkoutRepoMock) UpdateWorkout(ctx context.Context, w workout.Workout) error {
 	args := m.Called(ctx, w)
 	return fmt.Errorf("error: update workout%w", args.Error(0))
@@ -39,8 +65,9 @@ func (m *WorkoutRepoMock) DeleteWorkout(ctx context.Context, workoutID, userID i
 	return fmt.Errorf("error: delete workout%w", args.Error(0))
 }
 
-func (m *WorkoutRepoMock) GetAllWorkouts(ctx context.Context, userID int) ([]workout.Workout, error) {
-	args := m.Called(ctx, userID)
+func (m *WorkoutRepoMock) GetAllWorkouts(
+	ctx context.Context, userID int, filter dto.WorkoutFilter, after *dto.WorkoutCursor) ([]workout.Workout, error) {
+	args := m.Called(ctx, userID, filter, after)
 
 	workouts, ok := args.Get(0).([]workout.Workout)
 	if !ok {
@@ -69,6 +96,12 @@ func (m *WorkoutRepoMock) GetWorkoutByID(ctx context.Context, workoutID, userID
 	return workoutObj, nil
 }
 
+func (m *WorkoutRepoMock) GetTemplateByID(ctx context.Context, workoutID, userID int) (*workout.Workout, error) {
+	args := m.Called(ctx, workoutID, userID)
+	workoutObj, _ := args.Get(0).(*workout.Workout)
+	return workoutObj, args.Error(1)
+}
+
 func (m *WorkoutRepoMock) GetWorkoutExercises(ctx context.Context, workoutID int) ([]workoutexercisejoin.WorkoutExercise, error) {
 	args := m.Called(ctx, workoutID)
 
@@ -83,3 +116,35 @@ func (m *WorkoutRepoMock) GetWorkoutExercises(ctx context.Context, workoutID int
 
 	return exercises, nil
 }
+
+func (m *WorkoutRepoMock) GetExercisesByWorkoutIDs(
+	ctx context.Context, workoutIDs []int) (map[int][]workoutexercisejoin.WorkoutExercise, error) {
+	args := m.Called(ctx, workoutIDs)
+
+	exercises, ok := args.Get(0).(map[int][]workoutexercisejoin.WorkoutExercise)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for map[int][]workoutexercisejoin.WorkoutExercise: %w", args.Error(0))
+	}
+
+	if err := args.Error(1); err != nil {
+		return nil, fmt.Errorf("error in GetExercisesByWorkoutIDs: %w", err)
+	}
+
+	return exercises, nil
+}
+
+func (m *WorkoutRepoMock) GetBlocksByWorkoutIDs(
+	ctx context.Context, workoutIDs []int) (map[int][]workoutexercisejoin.Block, error) {
+	args := m.Called(ctx, workoutIDs)
+
+	blocks, ok := args.Get(0).(map[int][]workoutexercisejoin.Block)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for map[int][]workoutexercisejoin.Block: %w", args.Error(0))
+	}
+
+	if err := args.Error(1); err != nil {
+		return nil, fmt.Errorf("error in GetBlocksByWorkoutIDs: %w", err)
+	}
+
+	return blocks, nil
+}
@@ -0,0 +1,268 @@
+package workout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"workout-tracker/internal/erorrs"
+	categoryModel "workout-tracker/internal/model/category"
+	joinModel "workout-tracker/internal/model/workoutexercisejoin"
+)
+
+// maxExercises caps the number of exercises across all blocks of a workout.
+const maxExercises = 50
+
+// validateWorkout checks the parts of a workout the request binding cannot: it reports every
+// problem at once, including unknown categories and exercises that do not exist or belong to
+// another user. It returns the category's slug, or "" when the workout has no category, and the
+// blocks with their defaults filled in.
+func (s *WorkoutService) validateWorkout(ctx context.Context, userID int, name, category string,
+	blocks []joinModel.Block) (string, []joinModel.Block, error) {
+	var fields []erorrs.FieldError
+
+	if strings.TrimSpace(name) == "" {
+		fields = append(fields, erorrs.FieldError{Field: "name", Message: "must not be blank"})
+	}
+
+	slug := categoryModel.Slugify(category)
+	if strings.TrimSpace(category) != "" {
+		known, err := s.categoryExists(ctx, slug)
+		if err != nil {
+			return "", nil, err
+		}
+		if !known {
+			fields = append(fields, erorrs.FieldError{Field: "category", Message: "must be a known workout category"})
+		}
+	}
+
+	blocks = normalizeBlocks(blocks)
+	var exercises []joinModel.WorkoutExercise
+	var paths []string
+	for i, b := range blocks {
+		fields = append(fields, checkBlock(i, b)...)
+		for j, e := range b.Exercises {
+			exercises = append(exercises, e)
+			paths = append(paths, fmt.Sprintf("%s[%d].exercise_id", exercisesPath(i, b), j))
+		}
+	}
+
+	switch {
+	case len(exercises) == 0:
+		fields = append(fields, erorrs.FieldError{Field: "exercises", Message: "must contain at least one exercise"})
+	case len(exercises) > maxExercises:
+		field := "blocks"
+		if len(blocks) == 1 && blocks[0].Implicit {
+			field = "exercises"
+		}
+		fields = append(fields, erorrs.FieldError{
+			Field:   field,
+			Message: fmt.Sprintf("must contain at most %d exercises in total", maxExercises),
+		})
+	}
+
+	unknown, err := s.unknownExercises(ctx, userID, exercises)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, i := range unknown {
+		fields = append(fields, erorrs.FieldError{Field: paths[i], Message: "does not exist"})
+	}
+
+	if len(fields) == 0 {
+		return slug, blocks, nil
+	}
+
+	appErr := erorrs.Validation(fields...)
+	if len(unknown) > 0 {
+		appErr = appErr.Wrap(fmt.Errorf("exercise %d: %w", exercises[unknown[0]].ExerciseID, erorrs.ErrUnknownExercise))
+	}
+	return "", nil, appErr
+}
+
+// normalizeBlocks makes blocks without a type straight sets and blocks without rounds run once.
+func normalizeBlocks(blocks []joinModel.Block) []joinModel.Block {
+	result := make([]joinModel.Block, len(blocks))
+	for i, b := range blocks {
+		if b.Type == "" {
+			b.Type = joinModel.BlockStraight
+		}
+		if b.Rounds == 0 {
+			b.Rounds = 1
+		}
+		exercises := make([]joinModel.WorkoutExercise, len(b.Exercises))
+		for j, e := range b.Exercises {
+			exercises[j] = normalizeExercise(e)
+		}
+		b.Exercises = exercises
+		result[i] = b
+	}
+	return result
+}
+
+// normalizeExercise makes sets without a type working sets and, when the exercise is prescribed set
+// by set, fills in the Sets and Reps summary that is missing.
+func normalizeExercise(e joinModel.WorkoutExercise) joinModel.WorkoutExercise {
+	if len(e.Prescription) == 0 {
+		return e
+	}
+
+	sets := make([]joinModel.SetPrescription, len(e.Prescription))
+	for k, set := range e.Prescription {
+		if set.Type == "" {
+			set.Type = joinModel.SetWorking
+		}
+		sets[k] = set
+	}
+	e.Prescription = sets
+
+	if e.Sets == 0 {
+		e.Sets = len(sets)
+	}
+	if e.Reps == 0 {
+		e.Reps = summaryReps(sets)
+	}
+	return e
+}
+
+// summaryReps is the rep target of the first working set, or of the first set with one when no
+// working set has a target.
+func summaryReps(sets []joinModel.SetPrescription) int {
+	reps := 0
+	for _, set := range sets {
+		if set.Reps == nil {
+			continue
+		}
+		if set.Type == joinModel.SetWorking {
+			return *set.Reps
+		}
+		if reps == 0 {
+			reps = *set.Reps
+		}
+	}
+	return reps
+}
+
+// exercisesPath names the exercises of block i the way the client sent them: as blocks[i].exercises,
+// or as the plain exercises list an implicit block wraps.
+func exercisesPath(i int, b joinModel.Block) string {
+	if b.Implicit {
+		return "exercises"
+	}
+	return fmt.Sprintf("blocks[%d].exercises", i)
+}
+
+// checkBlock validates a single block: its type, its size for that type, and that no exercise
+// appears in it twice. An empty implicit block is left to the check of the whole workout.
+func checkBlock(i int, b joinModel.Block) []erorrs.FieldError {
+	var fields []erorrs.FieldError
+	path := fmt.Sprintf("blocks[%d]", i)
+	exercises := exercisesPath(i, b)
+
+	if !b.Type.IsValid() {
+		fields = append(fields, erorrs.FieldError{Field: path + ".type", Message: "must be one of straight, superset, circuit, emom, amrap"})
+	} else if minimum := b.Type.MinExercises(); len(b.Exercises) < minimum && !b.Implicit {
+		message := "must contain at least one exercise"
+		if minimum > 1 {
+			message = fmt.Sprintf("a %s block needs at least %d exercises", b.Type, minimum)
+		}
+		fields = append(fields, erorrs.FieldError{Field: exercises, Message: message})
+	}
+	if b.Rounds < 0 {
+		fields = append(fields, erorrs.FieldError{Field: path + ".rounds", Message: "must not be negative"})
+	}
+	if b.RestSeconds < 0 {
+		fields = append(fields, erorrs.FieldError{Field: path + ".rest_seconds", Message: "must not be negative"})
+	}
+
+	seen := make(map[int]bool, len(b.Exercises))
+	for j, e := range b.Exercises {
+		if seen[e.ExerciseID] {
+			fields = append(fields, erorrs.FieldError{
+				Field:   fmt.Sprintf("%s[%d].exercise_id", exercises, j),
+				Message: "is listed more than once",
+			})
+		}
+		seen[e.ExerciseID] = true
+		fields = append(fields, checkPrescription(fmt.Sprintf("%s[%d]", exercises, j), e)...)
+	}
+	return fields
+}
+
+// checkPrescription validates the set prescription of an exercise: the summary must agree with it,
+// a rep range needs both ends in order, and a set is loaded by weight or by percentage, not both.
+func checkPrescription(path string, e joinModel.WorkoutExercise) []erorrs.FieldError {
+	var fields []erorrs.FieldError
+
+	if len(e.Prescription) > 0 && e.Sets != len(e.Prescription) {
+		fields = append(fields, erorrs.FieldError{
+			Field:   path + ".sets",
+			Message: fmt.Sprintf("must match the %d prescribed sets", len(e.Prescription)),
+		})
+	}
+
+	for k, set := range e.Prescription {
+		setPath := fmt.Sprintf("%s.prescription[%d]", path, k)
+		if !set.Type.IsValid() {
+			fields = append(fields, erorrs.FieldError{Field: setPath + ".type", Message: "must be one of warmup, working, drop, failure"})
+		}
+		if set.RepsMax != nil {
+			switch {
+			case set.Reps == nil:
+				fields = append(fields, erorrs.FieldError{Field: setPath + ".reps", Message: "is required with reps_max"})
+			case *set.RepsMax < *set.Reps:
+				fields = append(fields, erorrs.FieldError{Field: setPath + ".reps_max", Message: "must be at least reps"})
+			}
+		}
+		if set.Weight != nil && set.PercentOneRM != nil {
+			fields = append(fields, erorrs.FieldError{Field: setPath + ".percent_1rm", Message: "must not be combined with weight"})
+		}
+	}
+	return fields
+}
+
+func (s *WorkoutService) categoryExists(ctx context.Context, slug string) (bool, error) {
+	if slug == "" {
+		return false, nil
+	}
+	if _, err := s.CategoryRepo.GetCategoryBySlug(ctx, slug); err != nil {
+		if errors.Is(err, erorrs.ErrNotFound) {
+			return false, nil
+		}
+		s.Log.Errorw("failed to load category", "slug", slug, "error", err)
+		return false, fmt.Errorf("load category: %w", err)
+	}
+	return true, nil
+}
+
+// unknownExercises returns the positions of exercises that either do not exist or are private to
+// another user.
+func (s *WorkoutService) unknownExercises(ctx context.Context, userID int, exercises []joinModel.WorkoutExercise) ([]int, error) {
+	if len(exercises) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int, 0, len(exercises))
+	for _, e := range exercises {
+		ids = append(ids, e.ExerciseID)
+	}
+
+	found, err := s.ExerciseRepo.GetExercisesByIDs(ctx, ids)
+	if err != nil {
+		s.Log.Errorw("failed to load exercises", "error", err)
+		return nil, fmt.Errorf("load exercises: %w", err)
+	}
+
+	accessible := make(map[int]bool, len(found))
+	for i := range found {
+		accessible[found[i].ID] = found[i].IsAccessibleBy(userID)
+	}
+
+	var unknown []int
+	for i, id := range ids {
+		if !accessible[id] {
+			unknown = append(unknown, i)
+		}
+	}
+	return unknown, nil
+}
@@ -2,37 +2,58 @@ package workout
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 	dto "workout-tracker/internal/dto/workout"
+	"workout-tracker/internal/erorrs"
 	model "workout-tracker/internal/model/workout"
 	joinModel "workout-tracker/internal/model/workoutexercisejoin"
+	categoryRepo "workout-tracker/internal/repository/category"
+	exerciseRepo "workout-tracker/internal/repository/exercise"
 	workoutInterface "workout-tracker/internal/repository/workout"
+	"workout-tracker/pkg/db"
 	"workout-tracker/pkg/logger"
 
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/dig"
 )
 
 type WorkoutServiceParams struct {
 	dig.In
 
-	Repo workoutInterface.WorkoutRepositoryInterface
-	Log  logger.SugaredLoggerInterface
+	Repo         workoutInterface.WorkoutRepositoryInterface
+	ExerciseRepo exerciseRepo.ExerciseRepositoryInterface
+	CategoryRepo categoryRepo.CategoryRepositoryInterface
+	Tx           db.UnitOfWork
+	Log          logger.SugaredLoggerInterface
 }
 
 type WorkoutService struct {
-	Repo workoutInterface.WorkoutRepositoryInterface
-	Log  logger.SugaredLoggerInterface
+	Repo         workoutInterface.WorkoutRepositoryInterface
+	ExerciseRepo exerciseRepo.ExerciseRepositoryInterface
+	CategoryRepo categoryRepo.CategoryRepositoryInterface
+	Tx           db.UnitOfWork
+	Log          logger.SugaredLoggerInterface
 }
 
 func NewWorkoutService(params WorkoutServiceParams) *WorkoutService {
 	return &WorkoutService{
-		Repo: params.Repo,
-		Log:  params.Log,
+		Repo:         params.Repo,
+		ExerciseRepo: params.ExerciseRepo,
+		CategoryRepo: params.CategoryRepo,
+		Tx:           params.Tx,
+		Log:          params.Log,
 	}
 }
 
-func (s *WorkoutService) CreateWorkout(ctx context.Context, userID int, name, title, category string, exercises []joinModel.WorkoutExercise) error {
+// CreateWorkout stores the workout and its blocks of exercises in one transaction.
+func (s *WorkoutService) CreateWorkout(ctx context.Context, userID int, name, title, category string, blocks []joinModel.Block) error {
+	category, blocks, err := s.validateWorkout(ctx, userID, name, category, blocks)
+	if err != nil {
+		return err
+	}
+
 	now := time.Now()
 	workout := model.Workout{
 		UserID:    userID,
@@ -43,27 +64,31 @@ func (s *WorkoutService) CreateWorkout(ctx context.Context, userID int, name, ti
 		UpdatedAt: now,
 	}
 
-	id, err := s.Repo.CreateWorkout(ctx, workout)
-	if err != nil {
-		s.Log.Errorw("failed to create workout", "error", err)
-		return fmt.Errorf("create workout: %w", err)
-	}
-
-	for i := range exercises {
-		exercises[i].WorkoutID = id
-	}
+	return s.Tx.WithinTx(ctx, func(tx pgx.Tx) error {
+		repo := s.Repo.WithTx(tx)
 
-	err = s.Repo.BulkInsertWorkoutExercises(ctx, exercises)
-	if err != nil {
-		s.Log.Errorw("failed to insert exercises", "error", err)
-		return fmt.Errorf("insert exercises: %w", err)
-	}
+		id, err := repo.CreateWorkout(ctx, workout)
+		if err != nil {
+			s.Log.Errorw("failed to create workout", "error", err)
+			return fmt.Errorf("create workout: %w", err)
+		}
 
-	return nil
+		return s.insertBlocks(ctx, repo, id, blocks)
+	})
 }
 
+// UpdateWorkout replaces the workout's fields and blocks in one transaction, so a failure never
+// leaves the workout without its exercises.
 func (s *WorkoutService) UpdateWorkout(ctx context.Context, userID, workoutID int,
-	name, title, category string, exercises []joinModel.WorkoutExercise) error {
+	name, title, category string, blocks []joinModel.Block) error {
+	if err := s.authorize(ctx, userID, workoutID); err != nil {
+		return err
+	}
+	category, blocks, err := s.validateWorkout(ctx, userID, name, category, blocks)
+	if err != nil {
+		return err
+	}
+
 	workout := model.Workout{
 		ID:        workoutID,
 		Name:      name,
@@ -73,28 +98,43 @@ func (s *WorkoutService) UpdateWorkout(ctx context.Context, userID, workoutID in
 		UpdatedAt: time.Now(),
 	}
 
-	if err := s.Repo.UpdateWorkout(ctx, workout); err != nil {
-		return fmt.Errorf("update workout: %w", err)
-	}
+	return s.Tx.WithinTx(ctx, func(tx pgx.Tx) error {
+		repo := s.Repo.WithTx(tx)
 
-	if err := s.Repo.DeleteWorkoutExercises(ctx, workoutID); err != nil {
-		return fmt.Errorf("delete workout exercises: %w", err)
-	}
+		if err := repo.UpdateWorkout(ctx, workout); err != nil {
+			return fmt.Errorf("update workout: %w", err)
+		}
 
-	for i := range exercises {
-		exercises[i].WorkoutID = workoutID
-	}
+		if err := repo.DeleteWorkoutExercises(ctx, workoutID); err != nil {
+			return fmt.Errorf("delete workout exercises: %w", err)
+		}
+
+		return s.insertBlocks(ctx, repo, workoutID, blocks)
+	})
+}
 
-	err := s.Repo.BulkInsertWorkoutExercises(ctx, exercises)
+// authorize returns ErrNotFound when the workout does not exist and ErrForbidden when it belongs to
+// another user.
+func (s *WorkoutService) authorize(ctx context.Context, userID, workoutID int) error {
+	ownerID, err := s.Repo.GetWorkoutOwner(ctx, workoutID)
 	if err != nil {
-		s.Log.Errorw("failed to insert exercises", "error", err)
-		return fmt.Errorf("insert exercises: %w", err)
+		if errors.Is(err, erorrs.ErrNotFound) {
+			return fmt.Errorf("workout %d: %w", workoutID, err)
+		}
+		s.Log.Errorw("failed to get workout owner", "workoutID", workoutID, "error", err)
+		return fmt.Errorf("get workout owner: %w", err)
+	}
+	if ownerID != userID {
+		return fmt.Errorf("workout %d: %w", workoutID, erorrs.ErrForbidden)
 	}
-
 	return nil
 }
 
 func (s *WorkoutService) DeleteWorkout(ctx context.Context, userID, workoutID int) error {
+	if err := s.authorize(ctx, userID, workoutID); err != nil {
+		return err
+	}
+
 	err := s.Repo.DeleteWorkout(ctx, workoutID, userID)
 	if err != nil {
 		s.Log.Errorw("failed to delete workout", "error", err)
@@ -104,30 +144,69 @@ func (s *WorkoutService) DeleteWorkout(ctx context.Context, userID, workoutID in
 	return nil
 }
 
-func (s *WorkoutService) GetAllWorkoutsWithExercises(ctx context.Context, userID int) ([]dto.WorkoutWithExercises, error) {
-	workouts, err := s.Repo.GetAllWorkouts(ctx, userID)
+// GetAllWorkoutsWithExercises returns one page of the user's workouts. The exercises of the whole page
+// are loaded with a single query.
+func (s *WorkoutService) GetAllWorkoutsWithExercises(ctx context.Context, userID int, filter dto.WorkoutFilter) (*dto.WorkoutPage, error) {
+	filter, after, err := normalizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra row to find out whether there is a next page.
+	query := filter
+	query.Limit++
+	workouts, err := s.Repo.GetAllWorkouts(ctx, userID, query, after)
 	if err != nil {
 		return nil, fmt.Errorf("get all workouts: %w", err)
 	}
 
-	var result []dto.WorkoutWithExercises
+	hasMore := len(workouts) > filter.Limit
+	if hasMore {
+		workouts = workouts[:filter.Limit]
+	}
+
+	ids := make([]int, 0, len(workouts))
 	for _, w := range workouts {
-		exercises, err := s.Repo.GetWorkoutExercises(ctx, w.ID)
-		if err != nil {
-			s.Log.Errorw("failed to fetch exercises for workout", "workout_id", w.ID, "error", err)
-			return nil, fmt.Errorf("fetch exercises for workout: %w", err)
-		}
+		ids = append(ids, w.ID)
+	}
 
-		result = append(result, dto.WorkoutWithExercises{
+	exercises, err := s.Repo.GetExercisesByWorkoutIDs(ctx, ids)
+	if err != nil {
+		s.Log.Errorw("failed to fetch exercises for workouts", "error", err)
+		return nil, fmt.Errorf("fetch exercises for workouts: %w", err)
+	}
+
+	blocks, err := s.Repo.GetBlocksByWorkoutIDs(ctx, ids)
+	if err != nil {
+		s.Log.Errorw("failed to fetch blocks for workouts", "error", err)
+		return nil, fmt.Errorf("fetch blocks for workouts: %w", err)
+	}
+
+	page := &dto.WorkoutPage{Items: make([]dto.WorkoutWithExercises, 0, len(workouts))}
+	for _, w := range workouts {
+		page.Items = append(page.Items, dto.WorkoutWithExercises{
 			Workout:   w,
-			Exercises: exercises,
+			Exercises: exercises[w.ID],
+			Blocks:    joinModel.Group(blocks[w.ID], exercises[w.ID]),
 		})
 	}
 
-	return result, nil
+	if hasMore {
+		page.NextCursor, err = encodeCursor(filter.SortBy, workouts[len(workouts)-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
 }
 
+// GetWorkoutByID returns the workout with its exercises, their set prescriptions and its blocks.
 func (s *WorkoutService) GetWorkoutByID(ctx context.Context, userID int, workoutID int) (*dto.WorkoutWithExercises, error) {
+	if err := s.authorize(ctx, userID, workoutID); err != nil {
+		return nil, err
+	}
+
 	workout, err := s.Repo.GetWorkoutByID(ctx, workoutID, userID)
 	if err != nil {
 		s.Log.Errorw("failed to get workout", "workoutID", workoutID, "error", err)
@@ -140,14 +219,32 @@ func (s *WorkoutService) GetWorkoutByID(ctx context.Context, userID int, workout
 		return nil, fmt.Errorf("get exercises for workout: %w", err)
 	}
 
+	sets, err := s.Repo.GetSetPrescriptions(ctx, workoutID)
+	if err != nil {
+		s.Log.Errorw("failed to get set prescriptions for workout", "workoutID", workoutID, "error", err)
+		return nil, fmt.Errorf("get set prescriptions for workout: %w", err)
+	}
+	joinModel.AttachSets(exercises, sets)
+
+	blocks, err := s.Repo.GetBlocksByWorkoutIDs(ctx, []int{workoutID})
+	if err != nil {
+		s.Log.Errorw("failed to get blocks for workout", "workoutID", workoutID, "error", err)
+		return nil, fmt.Errorf("get blocks for workout: %w", err)
+	}
+
 	return &dto.WorkoutWithExercises{
 		Workout:   *workout,
 		Exercises: exercises,
+		Blocks:    joinModel.Group(blocks[workoutID], exercises),
 	}, nil
 }
 
-func (s *WorkoutService) UpdateWorkoutPhoto(ctx context.Context, workoutID int, path string) error {
-	err := s.Repo.UpdateWorkoutPhoto(ctx, workoutID, path)
+func (s *WorkoutService) UpdateWorkoutPhoto(ctx context.Context, userID, workoutID int, path string) error {
+	if err := s.authorize(ctx, userID, workoutID); err != nil {
+		return err
+	}
+
+	err := s.Repo.UpdateWorkoutPhoto(ctx, workoutID, userID, path)
 	if err != nil {
 		return fmt.Errorf("update workout photo: %w", err)
 	}
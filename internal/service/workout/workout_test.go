@@ -3,34 +3,97 @@ package workout_test
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
+	"time"
+	dto "workout-tracker/internal/dto/workout"
+	"workout-tracker/internal/erorrs"
+	categoryModel "workout-tracker/internal/model/category"
+	exerciseModel "workout-tracker/internal/model/exercise"
 	model "workout-tracker/internal/model/workout"
 	joinModel "workout-tracker/internal/model/workoutexercisejoin"
+	workoutRepo "workout-tracker/internal/repository/workout"
+	"workout-tracker/internal/service/admin"
+	"workout-tracker/internal/service/category"
 	"workout-tracker/internal/service/workout"
+	"workout-tracker/pkg/db"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
 )
 
 type stubRepo struct {
+	WithTxFn                     func(tx pgx.Tx) workoutRepo.WorkoutRepositoryInterface
 	CreateWorkoutFn              func(ctx context.Context, w model.Workout) (int, error)
+	BulkInsertWorkoutBlocksFn    func(ctx context.Context, blocks []joinModel.Block) error
 	BulkInsertWorkoutExercisesFn func(ctx context.Context, ex []joinModel.WorkoutExercise) error
 	UpdateWorkoutFn              func(ctx context.Context, w model.Workout) error
 	DeleteWorkoutExercisesFn     func(ctx context.Context, workoutID int) error
 	DeleteWorkoutFn              func(ctx context.Context, workoutID int, userID int) error
-	GetAllWorkoutsFn             func(ctx context.Context, userID int) ([]model.Workout, error)
+	GetAllWorkoutsFn             func(ctx context.Context, userID int, filter dto.WorkoutFilter, after *dto.WorkoutCursor) ([]model.Workout, error)
+	GetExercisesByWorkoutIDsFn   func(ctx context.Context, workoutIDs []int) (map[int][]joinModel.WorkoutExercise, error)
+	GetBlocksByWorkoutIDsFn      func(ctx context.Context, workoutIDs []int) (map[int][]joinModel.Block, error)
+	BulkInsertSetPrescriptionsFn func(ctx context.Context, sets []joinModel.SetPrescription) error
+	GetSetPrescriptionsFn        func(ctx context.Context, workoutID int) ([]joinModel.SetPrescription, error)
 	GetWorkoutExercisesFn        func(ctx context.Context, workoutID int) ([]joinModel.WorkoutExercise, error)
 	GetWorkoutByIDFn             func(ctx context.Context, workoutID int, userID int) (*model.Workout, error)
-	UpdateWorkoutPhotoFn         func(ctx context.Context, workoutID int, path string) error
+	GetWorkoutOwnerFn            func(ctx context.Context, workoutID int) (int, error)
+	UpdateWorkoutPhotoFn         func(ctx context.Context, workoutID, userID int, path string) error
 }
 
-func (s *stubRepo) UpdateWorkoutPhoto(ctx context.Context, workoutID int, path string) error {
-	return s.UpdateWorkoutPhotoFn(ctx, workoutID, path)
+func (s *stubRepo) WithTx(tx pgx.Tx) workoutRepo.WorkoutRepositoryInterface {
+	if s.WithTxFn != nil {
+		return s.WithTxFn(tx)
+	}
+	return s
+}
+
+func (s *stubRepo) UpdateWorkoutPhoto(ctx context.Context, workoutID, userID int, path string) error {
+	return s.UpdateWorkoutPhotoFn(ctx, workoutID, userID, path)
+}
+
+// GetWorkoutOwner defaults to user 1, the user the tests act as.
+func (s *stubRepo) GetWorkoutOwner(ctx context.Context, workoutID int) (int, error) {
+	if s.GetWorkoutOwnerFn != nil {
+		return s.GetWorkoutOwnerFn(ctx, workoutID)
+	}
+	return 1, nil
 }
 
 func (s *stubRepo) CreateWorkout(ctx context.Context, w model.Workout) (int, error) {
 	return s.CreateWorkoutFn(ctx, w)
 }
+
+// BulkInsertWorkoutBlocks and GetBlocksByWorkoutIDs succeed unless a test says otherwise.
+func (s *stubRepo) BulkInsertWorkoutBlocks(ctx context.Context, blocks []joinModel.Block) error {
+	if s.BulkInsertWorkoutBlocksFn != nil {
+		return s.BulkInsertWorkoutBlocksFn(ctx, blocks)
+	}
+	return nil
+}
+func (s *stubRepo) GetBlocksByWorkoutIDs(ctx context.Context, workoutIDs []int) (map[int][]joinModel.Block, error) {
+	if s.GetBlocksByWorkoutIDsFn != nil {
+		return s.GetBlocksByWorkoutIDsFn(ctx, workoutIDs)
+	}
+	return map[int][]joinModel.Block{}, nil
+}
+
+// BulkInsertSetPrescriptions and GetSetPrescriptions succeed unless a test says otherwise.
+func (s *stubRepo) BulkInsertSetPrescriptions(ctx context.Context, sets []joinModel.SetPrescription) error {
+	if s.BulkInsertSetPrescriptionsFn != nil {
+		return s.BulkInsertSetPrescriptionsFn(ctx, sets)
+	}
+	return nil
+}
+func (s *stubRepo) GetSetPrescriptions(ctx context.Context, workoutID int) ([]joinModel.SetPrescription, error) {
+	if s.GetSetPrescriptionsFn != nil {
+		return s.GetSetPrescriptionsFn(ctx, workoutID)
+	}
+	return nil, nil
+}
 func (s *stubRepo) BulkInsertWorkoutExercises(ctx context.Context, ex []joinModel.WorkoutExercise) error {
 	return s.BulkInsertWorkoutExercisesFn(ctx, ex)
 }
@@ -43,8 +106,12 @@ func (s *stubRepo) DeleteWorkoutExercises(ctx context.Context, workoutID int) er
 func (s *stubRepo) DeleteWorkout(ctx context.Context, workoutID int, userID int) error {
 	return s.DeleteWorkoutFn(ctx, workoutID, userID)
 }
-func (s *stubRepo) GetAllWorkouts(ctx context.Context, userID int) ([]model.Workout, error) {
-	return s.GetAllWorkoutsFn(ctx, userID)
+func (s *stubRepo) GetAllWorkouts(
+	ctx context.Context, userID int, filter dto.WorkoutFilter, after *dto.WorkoutCursor) ([]model.Workout, error) {
+	return s.GetAllWorkoutsFn(ctx, userID, filter, after)
+}
+func (s *stubRepo) GetExercisesByWorkoutIDs(ctx context.Context, workoutIDs []int) (map[int][]joinModel.WorkoutExercise, error) {
+	return s.GetExercisesByWorkoutIDsFn(ctx, workoutIDs)
 }
 func (s *stubRepo) GetWorkoutExercises(ctx context.Context, workoutID int) ([]joinModel.WorkoutExercise, error) {
 	return s.GetWorkoutExercisesFn(ctx, workoutID)
@@ -52,11 +119,43 @@ func (s *stubRepo) GetWorkoutExercises(ctx context.Context, workoutID int) ([]jo
 func (s *stubRepo) GetWorkoutByID(ctx context.Context, workoutID int, userID int) (*model.Workout, error) {
 	return s.GetWorkoutByIDFn(ctx, workoutID, userID)
 }
+func (s *stubRepo) GetTemplateByID(ctx context.Context, workoutID int, userID int) (*model.Workout, error) {
+	return s.GetWorkoutByIDFn(ctx, workoutID, userID)
+}
+
+// straight wraps exercises into a single block of straight sets, the way a plain exercise list is
+// sent.
+func straight(exercises ...joinModel.WorkoutExercise) []joinModel.Block {
+	return []joinModel.Block{{Exercises: exercises, Implicit: true}}
+}
+
+// newCategoryRepo knows exactly the given category slugs.
+func newCategoryRepo(slugs ...string) *category.MockCategoryRepo {
+	categories := new(category.MockCategoryRepo)
+	for _, slug := range slugs {
+		categories.On("GetCategoryBySlug", mock.Anything, slug).Return(&categoryModel.Category{Slug: slug}, nil)
+	}
+	categories.On("GetCategoryBySlug", mock.Anything, mock.Anything).Return(nil, erorrs.ErrNotFound)
+	return categories
+}
 
 func newTestService(t *testing.T, repo *stubRepo) *workout.WorkoutService {
+	t.Helper()
+	exercises := new(admin.MockExerciseRepo)
+	exercises.On("GetExercisesByIDs", mock.Anything, mock.Anything).Return([]exerciseModel.Exercise{{ID: 1}}, nil)
+	return newTestServiceWithExercises(t, repo, exercises)
+}
+
+func newTestServiceWithExercises(t *testing.T, repo *stubRepo, exercises *admin.MockExerciseRepo) *workout.WorkoutService {
 	t.Helper()
 	logger := zaptest.NewLogger(t).Sugar()
-	return workout.NewWorkoutService(workout.WorkoutServiceParams{Repo: repo, Log: logger})
+	return workout.NewWorkoutService(workout.WorkoutServiceParams{
+		Repo:         repo,
+		ExerciseRepo: exercises,
+		CategoryRepo: newCategoryRepo("strength", "hiit"),
+		Tx:           &db.MockUnitOfWork{},
+		Log:          logger,
+	})
 }
 
 func TestCreateWorkout_Success(t *testing.T) {
@@ -69,8 +168,7 @@ func TestCreateWorkout_Success(t *testing.T) {
 		},
 	}
 	service := newTestService(t, repo)
-	exercises := []joinModel.WorkoutExercise{{ExerciseID: 1, Sets: 3}}
-	err := service.CreateWorkout(t.Context(), 1, "Test", "Title", "Strength", exercises)
+	err := service.CreateWorkout(t.Context(), 1, "Test", "Title", "Strength", straight(joinModel.WorkoutExercise{ExerciseID: 1, Sets: 3}))
 	assert.NoError(t, err)
 }
 
@@ -84,10 +182,276 @@ func TestCreateWorkout_BulkInsertFails(t *testing.T) {
 		},
 	}
 	service := newTestService(t, repo)
-	err := service.CreateWorkout(t.Context(), 1, "Test", "Title", "Strength", nil)
+	err := service.CreateWorkout(t.Context(), 1, "Test", "Title", "Strength", straight(joinModel.WorkoutExercise{ExerciseID: 1}))
 	assert.Error(t, err)
 }
 
+func TestCreateWorkout_NormalizesCategory(t *testing.T) {
+	var stored model.Workout
+	repo := &stubRepo{
+		CreateWorkoutFn: func(ctx context.Context, w model.Workout) (int, error) {
+			stored = w
+			return 1, nil
+		},
+		BulkInsertWorkoutExercisesFn: func(ctx context.Context, ex []joinModel.WorkoutExercise) error {
+			return nil
+		},
+	}
+	service := newTestService(t, repo)
+
+	err := service.CreateWorkout(t.Context(), 1, "Test", "Title", " HIIT ", straight(joinModel.WorkoutExercise{ExerciseID: 1}))
+	require.NoError(t, err)
+	assert.Equal(t, "hiit", stored.Category)
+}
+
+func TestCreateWorkout_ReportsAllValidationErrors(t *testing.T) {
+	exercises := new(admin.MockExerciseRepo)
+	exercises.On("GetExercisesByIDs", mock.Anything, []int{1, 1, 9}).Return([]exerciseModel.Exercise{{ID: 1}}, nil)
+	service := newTestServiceWithExercises(t, &stubRepo{}, exercises)
+
+	err := service.CreateWorkout(t.Context(), 1, " ", "Title", "knitting",
+		straight(joinModel.WorkoutExercise{ExerciseID: 1}, joinModel.WorkoutExercise{ExerciseID: 1}, joinModel.WorkoutExercise{ExerciseID: 9}))
+
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, appErr.Status)
+	assert.ErrorIs(t, err, erorrs.ErrUnknownExercise)
+	assert.Equal(t, []erorrs.FieldError{
+		{Field: "name", Message: "must not be blank"},
+		{Field: "category", Message: "must be a known workout category"},
+		{Field: "exercises[1].exercise_id", Message: "is listed more than once"},
+		{Field: "exercises[2].exercise_id", Message: "does not exist"},
+	}, appErr.Fields)
+}
+
+func TestCreateWorkout_StoresBlocksInOrder(t *testing.T) {
+	var storedBlocks []joinModel.Block
+	var storedExercises []joinModel.WorkoutExercise
+	repo := &stubRepo{
+		CreateWorkoutFn: func(ctx context.Context, w model.Workout) (int, error) {
+			return 7, nil
+		},
+		BulkInsertWorkoutBlocksFn: func(ctx context.Context, blocks []joinModel.Block) error {
+			storedBlocks = blocks
+			return nil
+		},
+		BulkInsertWorkoutExercisesFn: func(ctx context.Context, ex []joinModel.WorkoutExercise) error {
+			storedExercises = ex
+			return nil
+		},
+	}
+	exercises := new(admin.MockExerciseRepo)
+	exercises.On("GetExercisesByIDs", mock.Anything, []int{1, 2, 3}).
+		Return([]exerciseModel.Exercise{{ID: 1}, {ID: 2}, {ID: 3}}, nil)
+	service := newTestServiceWithExercises(t, repo, exercises)
+
+	err := service.CreateWorkout(t.Context(), 1, "Test", "Title", "", []joinModel.Block{
+		{Exercises: []joinModel.WorkoutExercise{{ExerciseID: 1, Sets: 5, Reps: 5}}},
+		{Type: joinModel.BlockSuperset, Rounds: 3, RestSeconds: 60, Exercises: []joinModel.WorkoutExercise{
+			{ExerciseID: 2, Sets: 1, Reps: 10},
+			{ExerciseID: 3, Sets: 1, Reps: 12},
+		}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []joinModel.Block{
+		{WorkoutID: 7, Position: 0, Type: joinModel.BlockStraight, Rounds: 1},
+		{WorkoutID: 7, Position: 1, Type: joinModel.BlockSuperset, Rounds: 3, RestSeconds: 60},
+	}, storedBlocks)
+	assert.Equal(t, []joinModel.WorkoutExercise{
+		{WorkoutID: 7, ExerciseID: 1, Position: 0, BlockPosition: 0, Sets: 5, Reps: 5},
+		{WorkoutID: 7, ExerciseID: 2, Position: 1, BlockPosition: 1, Sets: 1, Reps: 10},
+		{WorkoutID: 7, ExerciseID: 3, Position: 2, BlockPosition: 1, Sets: 1, Reps: 12},
+	}, storedExercises)
+}
+
+func TestCreateWorkout_SupersetNeedsTwoExercises(t *testing.T) {
+	service := newTestService(t, &stubRepo{})
+	err := service.CreateWorkout(t.Context(), 1, "Test", "Title", "", []joinModel.Block{
+		{Type: joinModel.BlockSuperset, Exercises: []joinModel.WorkoutExercise{{ExerciseID: 1}}},
+	})
+
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, appErr.Status)
+	assert.Equal(t, []erorrs.FieldError{
+		{Field: "blocks[0].exercises", Message: "a superset block needs at least 2 exercises"},
+	}, appErr.Fields)
+}
+
+func TestCreateWorkout_BlockErrorsNameTheBlock(t *testing.T) {
+	exercises := new(admin.MockExerciseRepo)
+	exercises.On("GetExercisesByIDs", mock.Anything, []int{1, 9}).Return([]exerciseModel.Exercise{{ID: 1}}, nil)
+	service := newTestServiceWithExercises(t, &stubRepo{}, exercises)
+
+	err := service.CreateWorkout(t.Context(), 1, "Test", "Title", "", []joinModel.Block{
+		{Exercises: []joinModel.WorkoutExercise{{ExerciseID: 1}}},
+		{Exercises: []joinModel.WorkoutExercise{{ExerciseID: 9, Sets: 2, Prescription: []joinModel.SetPrescription{{}}}}},
+	})
+
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, []erorrs.FieldError{
+		{Field: "blocks[1].exercises[0].sets", Message: "must match the 1 prescribed sets"},
+		{Field: "blocks[1].exercises[0].exercise_id", Message: "does not exist"},
+	}, appErr.Fields)
+}
+
+func TestCreateWorkout_StoresSetPrescription(t *testing.T) {
+	var storedExercises []joinModel.WorkoutExercise
+	var storedSets []joinModel.SetPrescription
+	repo := &stubRepo{
+		CreateWorkoutFn: func(ctx context.Context, w model.Workout) (int, error) {
+			return 7, nil
+		},
+		BulkInsertWorkoutExercisesFn: func(ctx context.Context, ex []joinModel.WorkoutExercise) error {
+			storedExercises = ex
+			return nil
+		},
+		BulkInsertSetPrescriptionsFn: func(ctx context.Context, sets []joinModel.SetPrescription) error {
+			storedSets = sets
+			return nil
+		},
+	}
+	service := newTestService(t, repo)
+	warmupReps, workingReps, workingMax := 10, 6, 8
+	percent := 80.0
+
+	err := service.CreateWorkout(t.Context(), 1, "Test", "Title", "", straight(joinModel.WorkoutExercise{
+		ExerciseID: 1,
+		Prescription: []joinModel.SetPrescription{
+			{Type: joinModel.SetWarmup, Reps: &warmupReps},
+			{Reps: &workingReps, RepsMax: &workingMax, PercentOneRM: &percent},
+		},
+	}))
+	require.NoError(t, err)
+
+	require.Len(t, storedExercises, 1)
+	assert.Equal(t, 2, storedExercises[0].Sets)
+	assert.Equal(t, 6, storedExercises[0].Reps)
+	assert.Nil(t, storedExercises[0].Prescription)
+	assert.Equal(t, []joinModel.SetPrescription{
+		{WorkoutID: 7, ExercisePosition: 0, SetNumber: 1, Type: joinModel.SetWarmup, Reps: &warmupReps},
+		{WorkoutID: 7, ExercisePosition: 0, SetNumber: 2, Type: joinModel.SetWorking,
+			Reps: &workingReps, RepsMax: &workingMax, PercentOneRM: &percent},
+	}, storedSets)
+}
+
+func TestCreateWorkout_InvalidSetPrescription(t *testing.T) {
+	service := newTestService(t, &stubRepo{})
+	reps, repsMax := 10, 8
+	weight, percent := 100.0, 70.0
+
+	err := service.CreateWorkout(t.Context(), 1, "Test", "Title", "", straight(joinModel.WorkoutExercise{
+		ExerciseID: 1,
+		Sets:       3,
+		Prescription: []joinModel.SetPrescription{
+			{Type: "cluster", RepsMax: &repsMax},
+			{Reps: &reps, RepsMax: &repsMax, Weight: &weight, PercentOneRM: &percent},
+		},
+	}))
+
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, []erorrs.FieldError{
+		{Field: "exercises[0].sets", Message: "must match the 2 prescribed sets"},
+		{Field: "exercises[0].prescription[0].type", Message: "must be one of warmup, working, drop, failure"},
+		{Field: "exercises[0].prescription[0].reps", Message: "is required with reps_max"},
+		{Field: "exercises[0].prescription[1].reps_max", Message: "must be at least reps"},
+		{Field: "exercises[0].prescription[1].percent_1rm", Message: "must not be combined with weight"},
+	}, appErr.Fields)
+}
+
+func TestCreateWorkout_RequiresExercises(t *testing.T) {
+	service := newTestService(t, &stubRepo{})
+	err := service.CreateWorkout(t.Context(), 1, "Test", "Title", "", nil)
+
+	var appErr *erorrs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, []erorrs.FieldError{{Field: "exercises", Message: "must contain at least one exercise"}}, appErr.Fields)
+}
+
+func TestCreateWorkout_OtherUsersExercise(t *testing.T) {
+	owner := 2
+	exercises := new(admin.MockExerciseRepo)
+	exercises.On("GetExercisesByIDs", mock.Anything, []int{1, 5}).
+		Return([]exerciseModel.Exercise{{ID: 1}, {ID: 5, OwnerUserID: &owner}}, nil)
+	service := newTestServiceWithExercises(t, &stubRepo{}, exercises)
+
+	err := service.CreateWorkout(t.Context(), 1, "Test", "Title", "Strength",
+		straight(joinModel.WorkoutExercise{ExerciseID: 1}, joinModel.WorkoutExercise{ExerciseID: 5}))
+	assert.ErrorIs(t, err, erorrs.ErrUnknownExercise)
+}
+
+func TestUpdateWorkout_MissingExercise(t *testing.T) {
+	exercises := new(admin.MockExerciseRepo)
+	exercises.On("GetExercisesByIDs", mock.Anything, []int{9}).Return([]exerciseModel.Exercise{}, nil)
+	service := newTestServiceWithExercises(t, &stubRepo{}, exercises)
+
+	err := service.UpdateWorkout(t.Context(), 1, 3, "Test", "Title", "Strength", straight(joinModel.WorkoutExercise{ExerciseID: 9}))
+	assert.ErrorIs(t, err, erorrs.ErrUnknownExercise)
+}
+
+func TestCreateWorkout_OwnExercise(t *testing.T) {
+	owner := 1
+	exercises := new(admin.MockExerciseRepo)
+	exercises.On("GetExercisesByIDs", mock.Anything, []int{5}).
+		Return([]exerciseModel.Exercise{{ID: 5, OwnerUserID: &owner}}, nil)
+	repo := &stubRepo{
+		CreateWorkoutFn: func(ctx context.Context, w model.Workout) (int, error) {
+			return 1, nil
+		},
+		BulkInsertWorkoutExercisesFn: func(ctx context.Context, ex []joinModel.WorkoutExercise) error {
+			return nil
+		},
+	}
+	service := newTestServiceWithExercises(t, repo, exercises)
+
+	err := service.CreateWorkout(t.Context(), 1, "Test", "Title", "Strength", straight(joinModel.WorkoutExercise{ExerciseID: 5}))
+	assert.NoError(t, err)
+}
+
+func TestUpdateWorkout_RunsInTransaction(t *testing.T) {
+	tx := new(db.MockTx)
+	var steps []string
+	txRepo := &stubRepo{
+		UpdateWorkoutFn: func(ctx context.Context, w model.Workout) error {
+			steps = append(steps, "update")
+			return nil
+		},
+		DeleteWorkoutExercisesFn: func(ctx context.Context, workoutID int) error {
+			steps = append(steps, "delete")
+			return nil
+		},
+		BulkInsertWorkoutExercisesFn: func(ctx context.Context, ex []joinModel.WorkoutExercise) error {
+			steps = append(steps, "insert")
+			assert.Equal(t, 3, ex[0].WorkoutID)
+			return errors.New("copy failed")
+		},
+	}
+	repo := &stubRepo{
+		WithTxFn: func(got pgx.Tx) workoutRepo.WorkoutRepositoryInterface {
+			assert.Same(t, tx, got)
+			return txRepo
+		},
+	}
+	exercises := new(admin.MockExerciseRepo)
+	exercises.On("GetExercisesByIDs", mock.Anything, []int{1}).Return([]exerciseModel.Exercise{{ID: 1}}, nil)
+	uow := &db.MockUnitOfWork{Tx: tx}
+	service := workout.NewWorkoutService(workout.WorkoutServiceParams{
+		Repo:         repo,
+		ExerciseRepo: exercises,
+		CategoryRepo: newCategoryRepo("strength"),
+		Tx:           uow,
+		Log:          zaptest.NewLogger(t).Sugar(),
+	})
+
+	err := service.UpdateWorkout(t.Context(), 1, 3, "Test", "Title", "Strength", straight(joinModel.WorkoutExercise{ExerciseID: 1}))
+	assert.Error(t, err)
+	assert.Equal(t, 1, uow.Calls)
+	assert.Equal(t, []string{"update", "delete", "insert"}, steps)
+}
+
 func TestDeleteWorkout_Success(t *testing.T) {
 	repo := &stubRepo{
 		DeleteWorkoutFn: func(ctx context.Context, workoutID int, userID int) error {
@@ -114,38 +478,219 @@ func TestGetWorkoutByID_Success(t *testing.T) {
 	assert.Equal(t, 1, res.Workout.ID)
 }
 
+func TestGetWorkoutByID_AttachesSetPrescriptions(t *testing.T) {
+	reps := 5
+	repo := &stubRepo{
+		GetWorkoutByIDFn: func(ctx context.Context, workoutID int, userID int) (*model.Workout, error) {
+			return &model.Workout{ID: 1, Name: "Test"}, nil
+		},
+		GetWorkoutExercisesFn: func(ctx context.Context, workoutID int) ([]joinModel.WorkoutExercise, error) {
+			return []joinModel.WorkoutExercise{
+				{WorkoutID: 1, ExerciseID: 4, Position: 0},
+				{WorkoutID: 1, ExerciseID: 5, Position: 1},
+			}, nil
+		},
+		GetSetPrescriptionsFn: func(ctx context.Context, workoutID int) ([]joinModel.SetPrescription, error) {
+			return []joinModel.SetPrescription{
+				{WorkoutID: 1, ExercisePosition: 1, SetNumber: 1, Type: joinModel.SetWorking, Reps: &reps},
+				{WorkoutID: 1, ExercisePosition: 1, SetNumber: 2, Type: joinModel.SetDrop},
+			}, nil
+		},
+		GetBlocksByWorkoutIDsFn: func(ctx context.Context, workoutIDs []int) (map[int][]joinModel.Block, error) {
+			return map[int][]joinModel.Block{1: {{WorkoutID: 1, Type: joinModel.BlockStraight, Rounds: 1}}}, nil
+		},
+	}
+	service := newTestService(t, repo)
+	res, err := service.GetWorkoutByID(t.Context(), 1, 1)
+	require.NoError(t, err)
+	assert.Empty(t, res.Exercises[0].Prescription)
+	require.Len(t, res.Exercises[1].Prescription, 2)
+	assert.Equal(t, joinModel.SetDrop, res.Exercises[1].Prescription[1].Type)
+	assert.Len(t, res.Blocks[0].Exercises[1].Prescription, 2)
+}
+
+func TestGetWorkoutByID_SetPrescriptionsFail(t *testing.T) {
+	repo := &stubRepo{
+		GetWorkoutByIDFn: func(ctx context.Context, workoutID int, userID int) (*model.Workout, error) {
+			return &model.Workout{ID: 1}, nil
+		},
+		GetWorkoutExercisesFn: func(ctx context.Context, workoutID int) ([]joinModel.WorkoutExercise, error) {
+			return nil, nil
+		},
+		GetSetPrescriptionsFn: func(ctx context.Context, workoutID int) ([]joinModel.SetPrescription, error) {
+			return nil, errors.New("db down")
+		},
+	}
+	service := newTestService(t, repo)
+	_, err := service.GetWorkoutByID(t.Context(), 1, 1)
+	assert.Error(t, err)
+}
+
+func TestGetWorkoutByID_GroupsExercisesIntoBlocks(t *testing.T) {
+	repo := &stubRepo{
+		GetWorkoutByIDFn: func(ctx context.Context, workoutID int, userID int) (*model.Workout, error) {
+			return &model.Workout{ID: 1, Name: "Test"}, nil
+		},
+		GetWorkoutExercisesFn: func(ctx context.Context, workoutID int) ([]joinModel.WorkoutExercise, error) {
+			return []joinModel.WorkoutExercise{
+				{WorkoutID: 1, ExerciseID: 4, Position: 0, BlockPosition: 0},
+				{WorkoutID: 1, ExerciseID: 5, Position: 1, BlockPosition: 1},
+				{WorkoutID: 1, ExerciseID: 6, Position: 2, BlockPosition: 1},
+			}, nil
+		},
+		GetBlocksByWorkoutIDsFn: func(ctx context.Context, workoutIDs []int) (map[int][]joinModel.Block, error) {
+			return map[int][]joinModel.Block{1: {
+				{WorkoutID: 1, Position: 0, Type: joinModel.BlockStraight, Rounds: 1},
+				{WorkoutID: 1, Position: 1, Type: joinModel.BlockCircuit, Rounds: 4},
+			}}, nil
+		},
+	}
+	service := newTestService(t, repo)
+	res, err := service.GetWorkoutByID(t.Context(), 1, 1)
+	require.NoError(t, err)
+	require.Len(t, res.Blocks, 2)
+	assert.Len(t, res.Blocks[0].Exercises, 1)
+	assert.Equal(t, joinModel.BlockCircuit, res.Blocks[1].Type)
+	assert.Equal(t, []int{5, 6}, []int{res.Blocks[1].Exercises[0].ExerciseID, res.Blocks[1].Exercises[1].ExerciseID})
+}
+
 func TestGetAllWorkoutsWithExercises_FetchFails(t *testing.T) {
 	repo := &stubRepo{
-		GetAllWorkoutsFn: func(ctx context.Context, userID int) ([]model.Workout, error) {
+		GetAllWorkoutsFn: func(ctx context.Context, userID int, filter dto.WorkoutFilter, after *dto.WorkoutCursor) ([]model.Workout, error) {
 			return []model.Workout{{ID: 1}}, nil
 		},
-		GetWorkoutExercisesFn: func(ctx context.Context, workoutID int) ([]joinModel.WorkoutExercise, error) {
+		GetExercisesByWorkoutIDsFn: func(ctx context.Context, workoutIDs []int) (map[int][]joinModel.WorkoutExercise, error) {
 			return nil, errors.New("fetch error")
 		},
 	}
 	service := newTestService(t, repo)
-	_, err := service.GetAllWorkoutsWithExercises(t.Context(), 1)
+	_, err := service.GetAllWorkoutsWithExercises(t.Context(), 1, dto.WorkoutFilter{})
 	assert.Error(t, err)
 }
 
+func TestGetAllWorkoutsWithExercises_Paginates(t *testing.T) {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 600000000, time.UTC)
+	var gotAfter *dto.WorkoutCursor
+	repo := &stubRepo{
+		GetAllWorkoutsFn: func(ctx context.Context, userID int, filter dto.WorkoutFilter, after *dto.WorkoutCursor) ([]model.Workout, error) {
+			assert.Equal(t, 3, filter.Limit)
+			assert.Equal(t, model.SortByCreatedAt, filter.SortBy)
+			assert.Equal(t, model.OrderDesc, filter.Order)
+			gotAfter = after
+			return []model.Workout{{ID: 9}, {ID: 8, CreatedAt: created}, {ID: 7}}, nil
+		},
+		GetExercisesByWorkoutIDsFn: func(ctx context.Context, workoutIDs []int) (map[int][]joinModel.WorkoutExercise, error) {
+			assert.Equal(t, []int{9, 8}, workoutIDs)
+			return map[int][]joinModel.WorkoutExercise{9: {{WorkoutID: 9, ExerciseID: 1}}}, nil
+		},
+	}
+	service := newTestService(t, repo)
+
+	page, err := service.GetAllWorkoutsWithExercises(t.Context(), 1, dto.WorkoutFilter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.Len(t, page.Items[0].Exercises, 1)
+	assert.Nil(t, gotAfter)
+	require.NotEmpty(t, page.NextCursor)
+
+	_, err = service.GetAllWorkoutsWithExercises(t.Context(), 1, dto.WorkoutFilter{Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.NotNil(t, gotAfter)
+	assert.Equal(t, 8, gotAfter.ID)
+	assert.Equal(t, created.Format(time.RFC3339Nano), gotAfter.Value)
+}
+
+func TestGetAllWorkoutsWithExercises_LastPageHasNoCursor(t *testing.T) {
+	repo := &stubRepo{
+		GetAllWorkoutsFn: func(ctx context.Context, userID int, filter dto.WorkoutFilter, after *dto.WorkoutCursor) ([]model.Workout, error) {
+			return []model.Workout{{ID: 1}}, nil
+		},
+		GetExercisesByWorkoutIDsFn: func(ctx context.Context, workoutIDs []int) (map[int][]joinModel.WorkoutExercise, error) {
+			return map[int][]joinModel.WorkoutExercise{}, nil
+		},
+	}
+
+	page, err := newTestService(t, repo).GetAllWorkoutsWithExercises(t.Context(), 1, dto.WorkoutFilter{})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestGetAllWorkoutsWithExercises_InvalidFilter(t *testing.T) {
+	service := newTestService(t, &stubRepo{})
+	ctx := t.Context()
+
+	_, err := service.GetAllWorkoutsWithExercises(ctx, 1, dto.WorkoutFilter{SortBy: "rating"})
+	assert.ErrorIs(t, err, erorrs.ErrInvalidSort)
+
+	_, err = service.GetAllWorkoutsWithExercises(ctx, 1, dto.WorkoutFilter{Order: "up"})
+	assert.ErrorIs(t, err, erorrs.ErrInvalidOrder)
+
+	_, err = service.GetAllWorkoutsWithExercises(ctx, 1, dto.WorkoutFilter{Cursor: "not-a-cursor"})
+	assert.ErrorIs(t, err, erorrs.ErrInvalidCursor)
+}
+
 func TestUpdateWorkoutPhoto_Success(t *testing.T) {
 	repo := &stubRepo{
-		UpdateWorkoutPhotoFn: func(ctx context.Context, workoutID int, path string) error {
+		UpdateWorkoutPhotoFn: func(ctx context.Context, workoutID, userID int, path string) error {
+			assert.Equal(t, 1, userID)
 			return nil
 		},
 	}
 	service := newTestService(t, repo)
-	err := service.UpdateWorkoutPhoto(t.Context(), 1, "Test")
+	err := service.UpdateWorkoutPhoto(t.Context(), 1, 1, "Test")
 	assert.NoError(t, err)
 }
 
 func TestUpdateWorkoutPhoto_Error(t *testing.T) {
 	repo := &stubRepo{
-		UpdateWorkoutPhotoFn: func(ctx context.Context, workoutID int, path string) error {
+		UpdateWorkoutPhotoFn: func(ctx context.Context, workoutID, userID int, path string) error {
 			return errors.New("update error")
 		},
 	}
 	service := newTestService(t, repo)
-	err := service.UpdateWorkoutPhoto(t.Context(), 1, "Test")
+	err := service.UpdateWorkoutPhoto(t.Context(), 1, 1, "Test")
 	assert.Error(t, err)
 }
+
+func otherUsersWorkout(ctx context.Context, workoutID int) (int, error) {
+	return 2, nil
+}
+
+func TestCrossUserAccess_Forbidden(t *testing.T) {
+	repo := &stubRepo{GetWorkoutOwnerFn: otherUsersWorkout}
+	service := newTestService(t, repo)
+	ctx := t.Context()
+
+	_, err := service.GetWorkoutByID(ctx, 1, 5)
+	assert.ErrorIs(t, err, erorrs.ErrForbidden)
+
+	err = service.UpdateWorkout(ctx, 1, 5, "n", "t", "c", nil)
+	assert.ErrorIs(t, err, erorrs.ErrForbidden)
+
+	err = service.DeleteWorkout(ctx, 1, 5)
+	assert.ErrorIs(t, err, erorrs.ErrForbidden)
+
+	err = service.UpdateWorkoutPhoto(ctx, 1, 5, "uploads/workouts/5/photo.jpg")
+	assert.ErrorIs(t, err, erorrs.ErrForbidden)
+}
+
+func TestMissingWorkout_NotFound(t *testing.T) {
+	repo := &stubRepo{GetWorkoutOwnerFn: func(ctx context.Context, workoutID int) (int, error) {
+		return 0, erorrs.ErrNotFound
+	}}
+	service := newTestService(t, repo)
+	ctx := t.Context()
+
+	_, err := service.GetWorkoutByID(ctx, 1, 5)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+
+	err = service.UpdateWorkout(ctx, 1, 5, "n", "t", "c", nil)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+
+	err = service.DeleteWorkout(ctx, 1, 5)
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+
+	err = service.UpdateWorkoutPhoto(ctx, 1, 5, "uploads/workouts/5/photo.jpg")
+	assert.ErrorIs(t, err, erorrs.ErrNotFound)
+}
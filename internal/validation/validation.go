@@ -0,0 +1,70 @@
+// Package validation registers the project's custom rules on gin's validator.
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"workout-tracker/internal/model/category"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	once        sync.Once
+	registerErr error
+)
+
+// Register installs the custom rules and makes validation errors report JSON field names.
+// It is safe to call more than once.
+func Register() error {
+	once.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			registerErr = fmt.Errorf("unexpected validator engine %T", binding.Validator.Engine())
+			return
+		}
+		registerErr = register(v)
+	})
+	return registerErr
+}
+
+func register(v *validator.Validate) error {
+	v.RegisterTagNameFunc(jsonName)
+
+	rules := map[string]validator.Func{
+		"notblank": notBlank,
+		"slug":     slug,
+	}
+	for tag, fn := range rules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return fmt.Errorf("register %s: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	default:
+		return name
+	}
+}
+
+// notBlank rejects strings that are empty once surrounding whitespace is removed.
+func notBlank(fl validator.FieldLevel) bool {
+	return strings.TrimSpace(fl.Field().String()) != ""
+}
+
+// slug accepts values that are already in the form category.Slugify produces.
+func slug(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	return value != "" && category.Slugify(value) == value
+}
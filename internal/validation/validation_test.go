@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sample struct {
+	Name  string `json:"name" binding:"required,notblank"`
+	Slug  string `json:"slug" binding:"omitempty,slug"`
+	Items []struct {
+		ID int `json:"id" binding:"gt=0"`
+	} `json:"items" binding:"dive"`
+}
+
+func validate(t *testing.T, s sample) validator.ValidationErrors {
+	t.Helper()
+	require.NoError(t, Register())
+	err := binding.Validator.ValidateStruct(s)
+	if err == nil {
+		return nil
+	}
+	var errs validator.ValidationErrors
+	require.True(t, errors.As(err, &errs))
+	return errs
+}
+
+func TestRegister_Idempotent(t *testing.T) {
+	require.NoError(t, Register())
+	require.NoError(t, Register())
+}
+
+func TestNotBlank(t *testing.T) {
+	errs := validate(t, sample{Name: "   "})
+	require.Len(t, errs, 1)
+	assert.Equal(t, "notblank", errs[0].Tag())
+	assert.Equal(t, "name", errs[0].Field())
+}
+
+func TestSlug(t *testing.T) {
+	assert.Empty(t, validate(t, sample{Name: "Push", Slug: "leg-day"}))
+
+	for _, bad := range []string{"Leg Day", "leg--day", "-legs", "legs_"} {
+		errs := validate(t, sample{Name: "Push", Slug: bad})
+		require.Len(t, errs, 1, bad)
+		assert.Equal(t, "slug", errs[0].Tag())
+	}
+}
+
+func TestJSONNamesInNamespace(t *testing.T) {
+	s := sample{Name: "Push"}
+	s.Items = append(s.Items, struct {
+		ID int `json:"id" binding:"gt=0"`
+	}{ID: 0})
+
+	errs := validate(t, s)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "sample.items[0].id", errs[0].Namespace())
+}
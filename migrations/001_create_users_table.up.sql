@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS users (
+    id            SERIAL PRIMARY KEY,
+    username      VARCHAR(255) NOT NULL UNIQUE,
+    password      VARCHAR(255) NOT NULL,
+    role          VARCHAR(32)  NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin')),
+    token_version INTEGER      NOT NULL DEFAULT 0,
+    createdat     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updatedat     TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS exercises (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT         NOT NULL DEFAULT '',
+    createdat   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updatedat   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    deletedat   TIMESTAMPTZ
+);
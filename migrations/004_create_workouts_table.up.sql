@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS workouts (
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name       VARCHAR(255) NOT NULL,
+    title      VARCHAR(255) NOT NULL DEFAULT '',
+    category   VARCHAR(255) NOT NULL DEFAULT '',
+    photo_path TEXT,
+    createdat  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updatedat  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    deletedat  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_workouts_user_id ON workouts (user_id) WHERE deletedat IS NULL;
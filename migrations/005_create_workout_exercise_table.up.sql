@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS workout_exercise (
+    workout_id  INTEGER NOT NULL REFERENCES workouts (id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises (id),
+    reps        INTEGER NOT NULL DEFAULT 0,
+    sets        INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_workout_exercise_workout_id ON workout_exercise (workout_id);
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS workout_sessions (
+    id          SERIAL PRIMARY KEY,
+    user_id     INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    workout_id  INTEGER     NOT NULL REFERENCES workouts (id) ON DELETE CASCADE,
+    notes       TEXT        NOT NULL DEFAULT '',
+    started_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+    createdat   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updatedat   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_workout_sessions_user_workout ON workout_sessions (user_id, workout_id);
+
+CREATE TABLE IF NOT EXISTS session_sets (
+    id           SERIAL PRIMARY KEY,
+    session_id   INTEGER       NOT NULL REFERENCES workout_sessions (id) ON DELETE CASCADE,
+    exercise_id  INTEGER       NOT NULL REFERENCES exercises (id),
+    set_number   INTEGER       NOT NULL CHECK (set_number > 0),
+    reps         INTEGER       NOT NULL CHECK (reps >= 0),
+    weight       NUMERIC(8, 2) NOT NULL DEFAULT 0 CHECK (weight >= 0),
+    rpe          NUMERIC(3, 1) CHECK (rpe BETWEEN 1 AND 10),
+    rest_seconds INTEGER CHECK (rest_seconds >= 0),
+    completed_at TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    UNIQUE (session_id, exercise_id, set_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_sets_exercise_id ON session_sets (exercise_id);
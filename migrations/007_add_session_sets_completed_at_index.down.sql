@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_session_sets_completed_at;
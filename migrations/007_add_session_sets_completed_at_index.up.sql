@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_session_sets_completed_at ON session_sets (completed_at);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS personal_records (
+    id          SERIAL PRIMARY KEY,
+    user_id     INTEGER       NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    exercise_id INTEGER       NOT NULL REFERENCES exercises (id),
+    record_type VARCHAR(32)   NOT NULL
+        CHECK (record_type IN ('max_weight', 'reps_at_weight', 'estimated_1rm', 'session_volume')),
+    value       NUMERIC(10, 2) NOT NULL,
+    weight      NUMERIC(8, 2)  NOT NULL DEFAULT 0,
+    reps        INTEGER        NOT NULL DEFAULT 0,
+    session_id  INTEGER        REFERENCES workout_sessions (id) ON DELETE SET NULL,
+    set_id      INTEGER        REFERENCES session_sets (id) ON DELETE SET NULL,
+    achieved_at TIMESTAMPTZ    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_personal_records_lookup
+    ON personal_records (user_id, exercise_id, record_type, weight, value DESC);
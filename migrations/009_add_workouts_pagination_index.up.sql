@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_workouts_user_created
+    ON workouts (user_id, createdat DESC, id DESC) WHERE deletedat IS NULL;
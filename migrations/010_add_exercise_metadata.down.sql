@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_exercises_equipment;
+DROP INDEX IF EXISTS idx_exercises_primary_muscles;
+DROP INDEX IF EXISTS idx_exercises_search_vector;
+
+ALTER TABLE exercises
+    DROP COLUMN IF EXISTS search_vector,
+    DROP COLUMN IF EXISTS measurement_kind,
+    DROP COLUMN IF EXISTS movement_type,
+    DROP COLUMN IF EXISTS equipment,
+    DROP COLUMN IF EXISTS secondary_muscles,
+    DROP COLUMN IF EXISTS primary_muscles;
@@ -0,0 +1,17 @@
+ALTER TABLE exercises
+    ADD COLUMN IF NOT EXISTS primary_muscles   TEXT[]      NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS secondary_muscles TEXT[]      NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS equipment         TEXT[]      NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS movement_type     VARCHAR(16) NOT NULL DEFAULT ''
+        CHECK (movement_type IN ('', 'compound', 'isolation')),
+    ADD COLUMN IF NOT EXISTS measurement_kind  VARCHAR(16) NOT NULL DEFAULT 'weight_reps'
+        CHECK (measurement_kind IN ('weight_reps', 'time', 'distance')),
+    ADD COLUMN IF NOT EXISTS search_vector     TSVECTOR
+        GENERATED ALWAYS AS (
+            setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+            setweight(to_tsvector('english', coalesce(description, '')), 'B')
+        ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_exercises_search_vector ON exercises USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_exercises_primary_muscles ON exercises USING GIN (primary_muscles);
+CREATE INDEX IF NOT EXISTS idx_exercises_equipment ON exercises USING GIN (equipment);
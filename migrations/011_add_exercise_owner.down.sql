@@ -0,0 +1,12 @@
+-- Private exercises cannot outlive the owner column, so they go together with the workout entries,
+-- sets and records that use them.
+DELETE FROM personal_records WHERE exercise_id IN (SELECT id FROM exercises WHERE owner_user_id IS NOT NULL);
+DELETE FROM session_sets WHERE exercise_id IN (SELECT id FROM exercises WHERE owner_user_id IS NOT NULL);
+DELETE FROM workout_exercise WHERE exercise_id IN (SELECT id FROM exercises WHERE owner_user_id IS NOT NULL);
+DELETE FROM exercises WHERE owner_user_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_exercises_owner_name;
+DROP INDEX IF EXISTS idx_exercises_global_name;
+ALTER TABLE exercises ADD CONSTRAINT exercises_name_key UNIQUE (name);
+
+ALTER TABLE exercises DROP COLUMN IF EXISTS owner_user_id;
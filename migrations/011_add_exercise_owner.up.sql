@@ -0,0 +1,7 @@
+ALTER TABLE exercises
+    ADD COLUMN IF NOT EXISTS owner_user_id INTEGER REFERENCES users (id) ON DELETE CASCADE;
+
+-- Names stay unique within the global catalogue and within each user's private exercises.
+ALTER TABLE exercises DROP CONSTRAINT IF EXISTS exercises_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exercises_global_name ON exercises (name) WHERE owner_user_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exercises_owner_name ON exercises (owner_user_id, name) WHERE owner_user_id IS NOT NULL;
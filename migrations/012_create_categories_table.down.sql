@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_workouts_category;
+ALTER TABLE workouts DROP CONSTRAINT IF EXISTS fk_workouts_category;
+
+UPDATE workouts SET category = '' WHERE category IS NULL;
+ALTER TABLE workouts
+    ALTER COLUMN category TYPE VARCHAR(255),
+    ALTER COLUMN category SET DEFAULT '',
+    ALTER COLUMN category SET NOT NULL;
+
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,61 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id        SERIAL PRIMARY KEY,
+    slug      VARCHAR(64) NOT NULL UNIQUE CHECK (slug ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+    name      VARCHAR(64) NOT NULL,
+    icon      VARCHAR(64) NOT NULL DEFAULT '',
+    color     VARCHAR(7)  NOT NULL DEFAULT '' CHECK (color = '' OR color ~ '^#[0-9a-fA-F]{6}$'),
+    position  INTEGER     NOT NULL DEFAULT 0,
+    createdat TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updatedat TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_position ON categories (position, name);
+
+INSERT INTO categories (slug, name, position)
+VALUES ('strength', 'Strength', 0),
+       ('hypertrophy', 'Hypertrophy', 1),
+       ('cardio', 'Cardio', 2),
+       ('hiit', 'HIIT', 3),
+       ('endurance', 'Endurance', 4),
+       ('mobility', 'Mobility', 5)
+ON CONFLICT (slug) DO NOTHING;
+
+-- Slugify keeps only ASCII letters and digits, which would erase categories written in other scripts
+-- such as "Ноги" and cut "Ноги day" down to "day". Any value that would lose characters that way gets
+-- a slug derived from its text instead and keeps the text as its name.
+INSERT INTO categories (slug, name, position)
+SELECT DISTINCT ON (slug) slug, name, 100
+  FROM (SELECT 'category-' || left(md5(lower(trim(category))), 8) AS slug, left(trim(category), 64) AS name
+          FROM workouts
+         WHERE trim(category) <> ''
+           AND (category ~ '[^\x01-\x7f]' OR lower(category) !~ '[a-z0-9]')) AS unslugged
+ ORDER BY slug, name
+ON CONFLICT (slug) DO NOTHING;
+
+-- Normalise the free-text categories the same way model/category.Slugify does, so "Legs", "legs "
+-- and "LEGS" end up as one category, then make every remaining value a category of its own.
+UPDATE workouts
+   SET category = CASE
+           WHEN trim(category) <> '' AND (category ~ '[^\x01-\x7f]' OR lower(category) !~ '[a-z0-9]')
+               THEN 'category-' || left(md5(lower(trim(category))), 8)
+           ELSE trim(BOTH '-' FROM left(trim(BOTH '-' FROM regexp_replace(lower(category), '[^a-z0-9]+', '-', 'g')), 64))
+       END;
+
+INSERT INTO categories (slug, name, position)
+SELECT DISTINCT category, initcap(replace(category, '-', ' ')), 100
+  FROM workouts
+ WHERE category <> ''
+ON CONFLICT (slug) DO NOTHING;
+
+ALTER TABLE workouts
+    ALTER COLUMN category DROP NOT NULL,
+    ALTER COLUMN category DROP DEFAULT,
+    ALTER COLUMN category TYPE VARCHAR(64);
+
+UPDATE workouts SET category = NULL WHERE category = '';
+
+ALTER TABLE workouts
+    ADD CONSTRAINT fk_workouts_category FOREIGN KEY (category)
+        REFERENCES categories (slug) ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_workouts_category ON workouts (category);
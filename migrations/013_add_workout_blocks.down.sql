@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_workout_exercise_position;
+CREATE INDEX IF NOT EXISTS idx_workout_exercise_workout_id ON workout_exercise (workout_id);
+
+ALTER TABLE workout_exercise
+    DROP CONSTRAINT IF EXISTS fk_workout_exercise_block,
+    DROP COLUMN IF EXISTS block_position,
+    DROP COLUMN IF EXISTS position;
+
+DROP TABLE IF EXISTS workout_blocks;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS workout_blocks (
+    workout_id   INTEGER     NOT NULL REFERENCES workouts (id) ON DELETE CASCADE,
+    position     INTEGER     NOT NULL CHECK (position >= 0),
+    block_type   VARCHAR(16) NOT NULL DEFAULT 'straight'
+        CHECK (block_type IN ('straight', 'superset', 'circuit', 'emom', 'amrap')),
+    rounds       INTEGER     NOT NULL DEFAULT 1 CHECK (rounds >= 1),
+    rest_seconds INTEGER     NOT NULL DEFAULT 0 CHECK (rest_seconds >= 0),
+    PRIMARY KEY (workout_id, position)
+);
+
+ALTER TABLE workout_exercise
+    ADD COLUMN IF NOT EXISTS position       INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS block_position INTEGER NOT NULL DEFAULT 0;
+
+-- Existing workouts become a single block of straight sets, keeping the order the rows were stored in.
+UPDATE workout_exercise we
+   SET position = numbered.position
+  FROM (SELECT ctid, row_number() OVER (PARTITION BY workout_id ORDER BY ctid) - 1 AS position
+          FROM workout_exercise) AS numbered
+ WHERE we.ctid = numbered.ctid;
+
+INSERT INTO workout_blocks (workout_id, position)
+SELECT DISTINCT workout_id, 0
+  FROM workout_exercise
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE workout_exercise
+    ALTER COLUMN position DROP DEFAULT,
+    ALTER COLUMN block_position DROP DEFAULT,
+    ADD CONSTRAINT fk_workout_exercise_block FOREIGN KEY (workout_id, block_position)
+        REFERENCES workout_blocks (workout_id, position) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS idx_workout_exercise_workout_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workout_exercise_position ON workout_exercise (workout_id, position);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS workout_exercise_sets (
+    workout_id        INTEGER       NOT NULL,
+    exercise_position INTEGER       NOT NULL,
+    set_number        INTEGER       NOT NULL CHECK (set_number > 0),
+    set_type          VARCHAR(16)   NOT NULL DEFAULT 'working'
+        CHECK (set_type IN ('warmup', 'working', 'drop', 'failure')),
+    reps              INTEGER CHECK (reps > 0),
+    reps_max          INTEGER,
+    weight            NUMERIC(8, 2) CHECK (weight >= 0),
+    percent_1rm       NUMERIC(5, 2) CHECK (percent_1rm > 0),
+    rpe               NUMERIC(3, 1) CHECK (rpe BETWEEN 1 AND 10),
+    rest_seconds      INTEGER CHECK (rest_seconds >= 0),
+    PRIMARY KEY (workout_id, exercise_position, set_number),
+    CONSTRAINT fk_workout_exercise_sets_exercise FOREIGN KEY (workout_id, exercise_position)
+        REFERENCES workout_exercise (workout_id, position) ON DELETE CASCADE,
+    CONSTRAINT chk_workout_exercise_sets_rep_range CHECK (reps_max IS NULL OR (reps IS NOT NULL AND reps_max >= reps)),
+    CONSTRAINT chk_workout_exercise_sets_load CHECK (weight IS NULL OR percent_1rm IS NULL)
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS program_enrollments;
+DROP TABLE IF EXISTS program_progressions;
+DROP TABLE IF EXISTS program_days;
+DROP TABLE IF EXISTS programs;
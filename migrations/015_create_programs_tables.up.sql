@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS programs (
+    id          SERIAL PRIMARY KEY,
+    user_id     INTEGER      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name        VARCHAR(100) NOT NULL,
+    description TEXT         NOT NULL DEFAULT '',
+    weeks       INTEGER      NOT NULL CHECK (weeks BETWEEN 1 AND 52),
+    createdat   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updatedat   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_programs_user_id ON programs (user_id);
+
+CREATE TABLE IF NOT EXISTS program_days (
+    program_id INTEGER NOT NULL REFERENCES programs (id) ON DELETE CASCADE,
+    week       INTEGER NOT NULL CHECK (week >= 1),
+    day        INTEGER NOT NULL CHECK (day BETWEEN 1 AND 7),
+    workout_id INTEGER NOT NULL REFERENCES workouts (id) ON DELETE CASCADE,
+    PRIMARY KEY (program_id, week, day)
+);
+
+CREATE TABLE IF NOT EXISTS program_progressions (
+    program_id  INTEGER       NOT NULL REFERENCES programs (id) ON DELETE CASCADE,
+    position    INTEGER       NOT NULL CHECK (position >= 0),
+    exercise_id INTEGER REFERENCES exercises (id) ON DELETE CASCADE,
+    rule_type   VARCHAR(16)   NOT NULL CHECK (rule_type IN ('weight', 'percent_1rm', 'reps')),
+    amount      NUMERIC(6, 2) NOT NULL,
+    every_weeks INTEGER       NOT NULL DEFAULT 1 CHECK (every_weeks >= 1),
+    PRIMARY KEY (program_id, position)
+);
+
+-- A user follows at most one program at a time; enrolling again replaces the enrollment.
+CREATE TABLE IF NOT EXISTS program_enrollments (
+    user_id    INTEGER     PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    program_id INTEGER     NOT NULL REFERENCES programs (id) ON DELETE CASCADE,
+    start_date DATE        NOT NULL,
+    createdat  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
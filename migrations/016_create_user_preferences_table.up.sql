@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS user_preferences (
+    user_id              INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    progression_strategy VARCHAR(32) NOT NULL DEFAULT 'linear' CHECK (progression_strategy IN ('linear', 'double', 'rpe')),
+    updatedat            TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
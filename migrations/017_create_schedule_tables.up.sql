@@ -0,0 +1,32 @@
+-- weekdays is a bit set of the days a schedule repeats on, bit 0 being Sunday; 0 means the
+-- workout is planned once, at starts_at.
+CREATE TABLE IF NOT EXISTS schedules (
+    id               SERIAL PRIMARY KEY,
+    user_id          INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    workout_id       INTEGER     NOT NULL REFERENCES workouts (id) ON DELETE CASCADE,
+    starts_at        TIMESTAMPTZ NOT NULL,
+    duration_minutes INTEGER     NOT NULL DEFAULT 60 CHECK (duration_minutes BETWEEN 0 AND 1440),
+    weekdays         SMALLINT    NOT NULL DEFAULT 0 CHECK (weekdays BETWEEN 0 AND 127),
+    until            DATE,
+    createdat        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updatedat        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedules_user_id ON schedules (user_id);
+
+-- Occurrences are only stored once they were marked completed or skipped.
+CREATE TABLE IF NOT EXISTS schedule_occurrences (
+    schedule_id INTEGER     NOT NULL REFERENCES schedules (id) ON DELETE CASCADE,
+    occurs_on   DATE        NOT NULL,
+    status      VARCHAR(16) NOT NULL CHECK (status IN ('completed', 'skipped')),
+    session_id  INTEGER REFERENCES workout_sessions (id) ON DELETE SET NULL,
+    updatedat   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (schedule_id, occurs_on)
+);
+
+-- Only a hash of the feed token is kept; the token itself is shown once when it is created.
+CREATE TABLE IF NOT EXISTS calendar_tokens (
+    user_id    INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    createdat  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
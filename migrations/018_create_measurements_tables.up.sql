@@ -0,0 +1,36 @@
+-- Metrics without a user are built in and shared by everyone; users may add their own. Values are
+-- stored in the canonical unit of the metric's kind: kilograms, centimetres or percent.
+CREATE TABLE IF NOT EXISTS measurement_metrics (
+    id        SERIAL PRIMARY KEY,
+    user_id   INTEGER REFERENCES users (id) ON DELETE CASCADE,
+    slug      VARCHAR(64) NOT NULL CHECK (slug ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+    name      VARCHAR(64) NOT NULL,
+    kind      VARCHAR(16) NOT NULL CHECK (kind IN ('mass', 'length', 'percent')),
+    createdat TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_measurement_metrics_owner_slug ON measurement_metrics (COALESCE(user_id, 0), slug);
+
+INSERT INTO measurement_metrics (slug, name, kind)
+SELECT v.slug, v.name, v.kind
+  FROM (VALUES ('bodyweight', 'Bodyweight', 'mass'),
+               ('body-fat', 'Body fat', 'percent'),
+               ('waist', 'Waist', 'length'),
+               ('chest', 'Chest', 'length'),
+               ('hips', 'Hips', 'length'),
+               ('arm', 'Arm', 'length'),
+               ('thigh', 'Thigh', 'length'),
+               ('neck', 'Neck', 'length')) AS v (slug, name, kind)
+ WHERE NOT EXISTS (SELECT 1 FROM measurement_metrics m WHERE m.user_id IS NULL AND m.slug = v.slug);
+
+CREATE TABLE IF NOT EXISTS measurements (
+    id          SERIAL PRIMARY KEY,
+    user_id     INTEGER       NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    metric_id   INTEGER       NOT NULL REFERENCES measurement_metrics (id) ON DELETE CASCADE,
+    value       NUMERIC(8, 2) NOT NULL CHECK (value >= 0),
+    note        TEXT          NOT NULL DEFAULT '',
+    measured_at TIMESTAMPTZ   NOT NULL,
+    createdat   TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_measurements_user_metric_time ON measurements (user_id, metric_id, measured_at);
@@ -0,0 +1,3 @@
+ALTER TABLE user_preferences
+    ADD COLUMN IF NOT EXISTS unit_system VARCHAR(16) NOT NULL DEFAULT 'metric'
+        CHECK (unit_system IN ('metric', 'imperial'));
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_users_email;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deletedat,
+    DROP COLUMN IF EXISTS avatar_url,
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS sex,
+    DROP COLUMN IF EXISTS height_cm,
+    DROP COLUMN IF EXISTS birth_date,
+    DROP COLUMN IF EXISTS email,
+    DROP COLUMN IF EXISTS display_name;
@@ -0,0 +1,12 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS display_name VARCHAR(64)  NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS email        VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS birth_date   DATE,
+    ADD COLUMN IF NOT EXISTS height_cm    NUMERIC(5, 1),
+    ADD COLUMN IF NOT EXISTS sex          VARCHAR(16) CHECK (sex IN ('female', 'male', 'other')),
+    ADD COLUMN IF NOT EXISTS timezone     VARCHAR(64)  NOT NULL DEFAULT 'UTC',
+    ADD COLUMN IF NOT EXISTS avatar_url   TEXT,
+    ADD COLUMN IF NOT EXISTS deletedat    TIMESTAMPTZ;
+
+-- Deleted accounts give their email address free for a new account.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (LOWER(email)) WHERE deletedat IS NULL;
@@ -0,0 +1,12 @@
+-- The plain tokens cannot be recovered from their hashes, so everyone has to sign in again.
+DELETE FROM refresh_tokens;
+
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+DROP INDEX IF EXISTS idx_refresh_tokens_token_hash;
+
+ALTER TABLE refresh_tokens
+    ADD COLUMN IF NOT EXISTS token VARCHAR(255) NOT NULL UNIQUE,
+    DROP COLUMN IF EXISTS revoked_at,
+    DROP COLUMN IF EXISTS used_at,
+    DROP COLUMN IF EXISTS token_hash,
+    DROP COLUMN IF EXISTS family_id;
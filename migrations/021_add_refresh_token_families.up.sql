@@ -0,0 +1,20 @@
+-- Refresh tokens are kept as SHA-256 hashes. A token is marked used when it is rotated; all tokens
+-- rotated from one login share a family, which is revoked as a whole when a used token comes back.
+ALTER TABLE refresh_tokens
+    ADD COLUMN IF NOT EXISTS family_id  UUID,
+    ADD COLUMN IF NOT EXISTS token_hash VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS used_at    TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;
+
+UPDATE refresh_tokens
+SET family_id  = id,
+    token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex')
+WHERE token_hash IS NULL;
+
+ALTER TABLE refresh_tokens
+    ALTER COLUMN family_id SET NOT NULL,
+    ALTER COLUMN token_hash SET NOT NULL,
+    DROP COLUMN IF EXISTS token;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
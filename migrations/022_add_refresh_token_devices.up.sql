@@ -0,0 +1,6 @@
+-- Every refresh token records the device it was issued to. A token family is one signed-in session,
+-- and the family's newest token tells when and from where the session was used last.
+ALTER TABLE refresh_tokens
+    ADD COLUMN IF NOT EXISTS device_name VARCHAR(64)  NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS user_agent  VARCHAR(512) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ip          VARCHAR(45)  NOT NULL DEFAULT '';
@@ -0,0 +1,9 @@
+-- Recurrences are worked out in the schedule's timezone. Existing schedules take their owner's, as
+-- the days they repeat on were chosen in local time.
+ALTER TABLE schedules
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+UPDATE schedules s
+   SET timezone = u.timezone
+  FROM users u
+ WHERE u.id = s.user_id;
@@ -0,0 +1,110 @@
+package migrations_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+	"workout-tracker/migrations"
+	"workout-tracker/pkg/migrate"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// testPool connects to the database named by TEST_DATABASE_URL and gives the test a schema of its own,
+// dropped again afterwards. Tests that need it are skipped when the variable is unset.
+func testPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := t.Context()
+	schema := fmt.Sprintf("migrations_test_%d", time.Now().UnixNano())
+
+	admin, err := pgxpool.New(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(admin.Close)
+
+	_, err = admin.Exec(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = admin.Exec(ctx, "DROP SCHEMA "+schema+" CASCADE")
+	})
+
+	config, err := pgxpool.ParseConfig(dsn)
+	require.NoError(t, err)
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+// migrator returns a migrator that knows the migrations up to and including version.
+func migrator(t *testing.T, pool *pgxpool.Pool, version int64) *migrate.Migrator {
+	t.Helper()
+
+	m, err := migrate.New(pool, zap.NewNop().Sugar(), migrations.FS)
+	require.NoError(t, err)
+
+	for i, migration := range m.Migrations {
+		if migration.Version > version {
+			m.Migrations = m.Migrations[:i]
+			break
+		}
+	}
+	return m
+}
+
+func TestCreateCategories_KeepsMixedScriptNames(t *testing.T) {
+	ctx := t.Context()
+	pool := testPool(t)
+
+	require.NoError(t, migrator(t, pool, 11).Up(ctx))
+
+	var userID int
+	err := pool.QueryRow(ctx, `INSERT INTO users (username, password) VALUES ('alice', 'x') RETURNING id`).
+		Scan(&userID)
+	require.NoError(t, err)
+
+	for _, category := range []string{"Ноги day", "Ноги", "Day", " Legs "} {
+		_, err := pool.Exec(ctx, `INSERT INTO workouts (user_id, name, category) VALUES ($1, $2, $2)`, userID, category)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, migrator(t, pool, 12).Up(ctx))
+
+	rows, err := pool.Query(ctx, `
+		SELECT w.name, w.category, c.name
+		  FROM workouts w
+		  JOIN categories c ON c.slug = w.category`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	slugs := make(map[string]string)
+	names := make(map[string]string)
+	for rows.Next() {
+		var workout, slug, name string
+		require.NoError(t, rows.Scan(&workout, &slug, &name))
+		slugs[workout] = slug
+		names[workout] = name
+	}
+	require.NoError(t, rows.Err())
+	require.Len(t, slugs, 4)
+
+	assert.Regexp(t, `^category-[0-9a-f]{8}$`, slugs["Ноги day"])
+	assert.Equal(t, "Ноги day", names["Ноги day"])
+	assert.Regexp(t, `^category-[0-9a-f]{8}$`, slugs["Ноги"])
+	assert.Equal(t, "Ноги", names["Ноги"])
+	assert.NotEqual(t, slugs["Ноги"], slugs["Ноги day"])
+	assert.Equal(t, "day", slugs["Day"])
+	assert.Equal(t, "legs", slugs[" Legs "])
+}
@@ -0,0 +1,74 @@
+package cache_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+	"workout-tracker/pkg/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := cache.NewLRU[int, string](2, time.Minute)
+	c.Set(1, "one")
+	c.Set(2, "two")
+
+	_, ok := c.Get(1)
+	assert.True(t, ok)
+	c.Set(3, "three")
+
+	_, ok = c.Get(2)
+	assert.False(t, ok, "2 was used least recently")
+	v, ok := c.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "one", v)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRU_Expires(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	c := cache.NewLRU[int, string](2, time.Minute).WithClock(func() time.Time { return now })
+	c.Set(1, "one")
+
+	now = now.Add(59 * time.Second)
+	_, ok := c.Get(1)
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok = c.Get(1)
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len(), "expired entries are dropped on lookup")
+}
+
+func TestLRU_SetRefreshesAndDelete(t *testing.T) {
+	c := cache.NewLRU[int, string](2, time.Minute)
+	c.Set(1, "one")
+	c.Set(1, "uno")
+	v, _ := c.Get(1)
+	assert.Equal(t, "uno", v)
+	assert.Equal(t, 1, c.Len())
+
+	c.Delete(1)
+	c.Delete(2)
+	_, ok := c.Get(1)
+	assert.False(t, ok)
+}
+
+func TestLRU_Concurrent(t *testing.T) {
+	c := cache.NewLRU[int, int](16, time.Minute)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				c.Set(j%32, i)
+				c.Get(j % 32)
+				c.Delete((j + i) % 32)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, c.Len(), 16)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -44,6 +45,11 @@ func (m *MockPool) Begin(ctx context.Context) (pgx.Tx, error) {
 	return ret.Get(0).(pgx.Tx), ret.Error(1)
 }
 
+func (m *MockPool) Acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	ret := m.Called(ctx)
+	return ret.Get(0).(*pgxpool.Conn), ret.Error(1)
+}
+
 // --- MockRow ---
 
 type MockRow struct {
@@ -181,3 +187,16 @@ func (m *MockBatchResults) QueryRow() pgx.Row {
 	args := m.Called()
 	return args.Get(0).(pgx.Row)
 }
+
+// --- MockUnitOfWork ---
+
+// MockUnitOfWork runs fn straight away with Tx and counts how often it was used.
+type MockUnitOfWork struct {
+	Tx    pgx.Tx
+	Calls int
+}
+
+func (m *MockUnitOfWork) WithinTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	m.Calls++
+	return fn(m.Tx)
+}
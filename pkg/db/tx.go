@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"workout-tracker/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/dig"
+)
+
+// TxBeginner is implemented by *pgxpool.Pool.
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// UnitOfWork runs a group of repository calls atomically. Repositories take part by being bound to
+// the transaction passed to fn.
+type UnitOfWork interface {
+	WithinTx(ctx context.Context, fn func(tx pgx.Tx) error) error
+}
+
+type TxManagerParams struct {
+	dig.In
+
+	DB  *DB
+	Log logger.SugaredLoggerInterface
+}
+
+type TxManager struct {
+	Pool TxBeginner
+	Log  logger.SugaredLoggerInterface
+}
+
+func NewTxManager(params TxManagerParams) *TxManager {
+	return &TxManager{
+		Pool: params.DB.Pool,
+		Log:  params.Log,
+	}
+}
+
+// WithinTx commits the transaction when fn succeeds and rolls it back when fn fails or panics.
+// The error returned by fn is passed through unchanged. Functions registered with AfterCommit run
+// once the commit succeeded.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	begun, err := m.Pool.Begin(ctx)
+	if err != nil {
+		m.Log.Errorw("failed to begin transaction", "error", err)
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	tx := &hookedTx{Tx: begun}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			m.Log.Errorw("failed to roll back transaction", "error", err)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		m.Log.Errorw("failed to commit transaction", "error", err)
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	for _, hook := range tx.afterCommit {
+		hook()
+	}
+	return nil
+}
+
+// hookedTx is the transaction WithinTx passes on, it collects the AfterCommit functions.
+type hookedTx struct {
+	pgx.Tx
+	afterCommit []func()
+}
+
+// AfterCommit runs fn once tx is committed and not at all when it is rolled back, for side effects
+// such as cache invalidation that must not be seen before the writes are. Outside a transaction of
+// WithinTx, including a nil tx, fn runs at once.
+func AfterCommit(tx pgx.Tx, fn func()) {
+	if hooked, ok := tx.(*hookedTx); ok {
+		hooked.afterCommit = append(hooked.afterCommit, fn)
+		return
+	}
+	fn()
+}
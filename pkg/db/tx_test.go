@@ -0,0 +1,129 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTxManager(pool *MockPool) *TxManager {
+	return &TxManager{Pool: pool, Log: zap.NewNop().Sugar()}
+}
+
+func TestWithinTx_Commits(t *testing.T) {
+	ctx := t.Context()
+	pool := new(MockPool)
+	tx := new(MockTx)
+	pool.On("Begin", ctx).Return(tx, nil)
+	tx.On("Commit", ctx).Return(nil)
+	tx.On("Rollback", ctx).Return(pgx.ErrTxClosed)
+
+	var got pgx.Tx
+	err := newTxManager(pool).WithinTx(ctx, func(inner pgx.Tx) error {
+		got = inner
+		return nil
+	})
+	require.NoError(t, err)
+	require.IsType(t, &hookedTx{}, got)
+	assert.Same(t, tx, got.(*hookedTx).Tx)
+	tx.AssertExpectations(t)
+}
+
+func TestWithinTx_RollsBackOnError(t *testing.T) {
+	ctx := t.Context()
+	pool := new(MockPool)
+	tx := new(MockTx)
+	pool.On("Begin", ctx).Return(tx, nil)
+	tx.On("Rollback", ctx).Return(nil)
+
+	fnErr := errors.New("insert failed")
+	err := newTxManager(pool).WithinTx(ctx, func(pgx.Tx) error { return fnErr })
+	assert.Same(t, fnErr, err)
+	tx.AssertNotCalled(t, "Commit", ctx)
+	tx.AssertExpectations(t)
+}
+
+func TestAfterCommit(t *testing.T) {
+	ctx := t.Context()
+	pool := new(MockPool)
+	tx := new(MockTx)
+	pool.On("Begin", ctx).Return(tx, nil)
+	tx.On("Commit", ctx).Return(nil)
+	tx.On("Rollback", ctx).Return(pgx.ErrTxClosed)
+
+	var events []string
+	err := newTxManager(pool).WithinTx(ctx, func(inner pgx.Tx) error {
+		AfterCommit(inner, func() { events = append(events, "hook") })
+		events = append(events, "fn")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fn", "hook"}, events)
+
+	ran := false
+	AfterCommit(nil, func() { ran = true })
+	assert.True(t, ran, "runs at once outside a transaction")
+}
+
+func TestAfterCommit_NotOnRollback(t *testing.T) {
+	ctx := t.Context()
+	pool := new(MockPool)
+	tx := new(MockTx)
+	pool.On("Begin", ctx).Return(tx, nil)
+	tx.On("Rollback", ctx).Return(nil)
+
+	ran := false
+	_ = newTxManager(pool).WithinTx(ctx, func(inner pgx.Tx) error {
+		AfterCommit(inner, func() { ran = true })
+		return errors.New("insert failed")
+	})
+	assert.False(t, ran)
+}
+
+func TestWithinTx_RollsBackOnPanic(t *testing.T) {
+	ctx := t.Context()
+	pool := new(MockPool)
+	tx := new(MockTx)
+	pool.On("Begin", ctx).Return(tx, nil)
+	tx.On("Rollback", ctx).Return(nil)
+
+	assert.Panics(t, func() {
+		_ = newTxManager(pool).WithinTx(ctx, func(pgx.Tx) error { panic("boom") })
+	})
+	tx.AssertExpectations(t)
+}
+
+func TestWithinTx_BeginFails(t *testing.T) {
+	ctx := t.Context()
+	pool := new(MockPool)
+	pool.On("Begin", ctx).Return((*MockTx)(nil), errors.New("no connection"))
+
+	called := false
+	err := newTxManager(pool).WithinTx(ctx, func(pgx.Tx) error {
+		called = true
+		return nil
+	})
+	assert.Error(t, err)
+	assert.False(t, called)
+}
+
+func TestWithinTx_CommitFails(t *testing.T) {
+	ctx := t.Context()
+	pool := new(MockPool)
+	tx := new(MockTx)
+	pool.On("Begin", ctx).Return(tx, nil)
+	tx.On("Commit", ctx).Return(errors.New("serialization failure"))
+	tx.On("Rollback", ctx).Return(pgx.ErrTxClosed)
+
+	ran := false
+	err := newTxManager(pool).WithinTx(ctx, func(inner pgx.Tx) error {
+		AfterCommit(inner, func() { ran = true })
+		return nil
+	})
+	assert.ErrorContains(t, err, "commit transaction")
+	assert.False(t, ran)
+}
@@ -0,0 +1,271 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	minRSABits = 2048
+	keyFileExt = ".pem"
+)
+
+var (
+	ErrMissingSecret        = errors.New("JWT_SECRET environment variable is not set")
+	ErrMissingKeysDir       = errors.New("JWT_KEYS_DIR environment variable is not set")
+	ErrMissingSigningKey    = errors.New("JWT_SIGNING_KEY_ID environment variable is not set")
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrUnsupportedKey       = errors.New("unsupported key type")
+	ErrUnknownKey           = errors.New("unknown key id")
+)
+
+// Key is a verification key, published in the JWKS under its id.
+type Key struct {
+	Public crypto.PublicKey
+	Method jwt.SigningMethod
+	ID     string
+}
+
+// KeySet signs access tokens with a single key and verifies them against every key it holds, so
+// that a retired signing key keeps verifying the tokens it issued until they expire.
+type KeySet struct {
+	signer  any
+	method  jwt.SigningMethod
+	keys    map[string]Key
+	signKey string
+}
+
+// NewHMAC returns a key set that signs and verifies with a shared HS256 secret.
+func NewHMAC(secret string) (*KeySet, error) {
+	if secret == "" {
+		return nil, ErrMissingSecret
+	}
+
+	return &KeySet{signer: []byte(secret), method: jwt.SigningMethodHS256}, nil
+}
+
+// FromEnv builds the key set from JWT_ALGORITHM, which defaults to HS256 with JWT_SECRET.
+// RS256 and EdDSA read every *.pem file in JWT_KEYS_DIR, named <kid>.pem, and sign with the
+// private key JWT_SIGNING_KEY_ID.
+func FromEnv() (*KeySet, error) {
+	algorithm := os.Getenv("JWT_ALGORITHM")
+	if algorithm == "" || algorithm == AlgorithmHS256 {
+		return NewHMAC(os.Getenv("JWT_SECRET"))
+	}
+
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir == "" {
+		return nil, ErrMissingKeysDir
+	}
+	signingKeyID := os.Getenv("JWT_SIGNING_KEY_ID")
+	if signingKeyID == "" {
+		return nil, ErrMissingSigningKey
+	}
+
+	return Load(algorithm, dir, signingKeyID)
+}
+
+// Load reads the keys in dir. Files may hold a private key, which can also sign, or only a public
+// key, which is how a retired key is kept around for verification.
+func Load(algorithm, dir, signingKeyID string) (*KeySet, error) {
+	if algorithm != AlgorithmRS256 && algorithm != AlgorithmEdDSA {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, algorithm)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+keyFileExt))
+	if err != nil {
+		return nil, fmt.Errorf("error listing keys: %w", err)
+	}
+
+	set := &KeySet{keys: make(map[string]Key, len(paths)), signKey: signingKeyID}
+	for _, path := range paths {
+		id := strings.TrimSuffix(filepath.Base(path), keyFileExt)
+
+		private, public, err := readKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading key %q: %w", id, err)
+		}
+		method, err := methodFor(public)
+		if err != nil {
+			return nil, fmt.Errorf("error reading key %q: %w", id, err)
+		}
+
+		set.keys[id] = Key{ID: id, Public: public, Method: method}
+		if id == signingKeyID {
+			set.signer = private
+			set.method = method
+		}
+	}
+
+	signing, ok := set.keys[signingKeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: signing key %q not found in %s", ErrUnknownKey, signingKeyID, dir)
+	}
+	if set.signer == nil {
+		return nil, fmt.Errorf("signing key %q holds no private key", signingKeyID)
+	}
+	if signing.Method.Alg() != algorithm {
+		return nil, fmt.Errorf("signing key %q is a %s key, not %s", signingKeyID, signing.Method.Alg(), algorithm)
+	}
+
+	return set, nil
+}
+
+// Sign signs the claims with the current signing key and names it in the kid header.
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+	if k.signKey != "" {
+		token.Header["kid"] = k.signKey
+	}
+
+	return token.SignedString(k.signer)
+}
+
+// Keyfunc resolves the key a token was signed with, for jwt.Parse.
+func (k *KeySet) Keyfunc(token *jwt.Token) (any, error) {
+	if k.keys == nil {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v: %w", token.Header["alg"], jwt.ErrTokenSignatureInvalid)
+		}
+		return k.signer, nil
+	}
+
+	id, _ := token.Header["kid"].(string)
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, id)
+	}
+	// The algorithm is pinned by the key, never taken from the token.
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %v for key %q: %w", token.Header["alg"], id, jwt.ErrTokenSignatureInvalid)
+	}
+
+	return key.Public, nil
+}
+
+// ValidMethods lists the algorithms of the verification keys, for jwt.WithValidMethods.
+func (k *KeySet) ValidMethods() []string {
+	if k.keys == nil {
+		return []string{k.method.Alg()}
+	}
+
+	seen := make(map[string]bool)
+	var methods []string
+	for _, key := range k.keys {
+		if alg := key.Method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	sort.Strings(methods)
+
+	return methods
+}
+
+// JWK is a public key in RFC 7517 form. RSA keys fill N and E, Ed25519 keys Crv and X.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public verification keys. A shared HS256 secret is never published, so the set
+// is empty in that mode.
+func (k *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(k.keys))}
+	for _, key := range k.keys {
+		jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+		switch public := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+
+	return set
+}
+
+func readKey(path string) (crypto.Signer, crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, nil, ErrUnsupportedKey
+		}
+		return signer, signer.Public(), nil
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		return key, key.Public(), nil
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		return nil, key, err
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		return nil, key, err
+	default:
+		return nil, nil, fmt.Errorf("%w: PEM block %q", ErrUnsupportedKey, block.Type)
+	}
+}
+
+func methodFor(public crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("%w: RSA keys need at least %d bits", ErrUnsupportedKey, minRSABits)
+		}
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, public)
+	}
+}
@@ -0,0 +1,186 @@
+package jwtkeys_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"workout-tracker/pkg/jwtkeys"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePrivateKey(t *testing.T, dir, id string, key any) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	writePEM(t, dir, id, "PRIVATE KEY", der)
+}
+
+func writePublicKey(t *testing.T, dir, id string, key any) {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	writePEM(t, dir, id, "PUBLIC KEY", der)
+}
+
+func writePEM(t *testing.T, dir, id, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, id+".pem"), data, 0o600))
+}
+
+func claims() jwt.MapClaims {
+	return jwt.MapClaims{"user_id": 1, "exp": time.Now().Add(time.Minute).Unix()}
+}
+
+func parse(set *jwtkeys.KeySet, token string) error {
+	_, err := jwt.Parse(token, set.Keyfunc, jwt.WithValidMethods(set.ValidMethods()))
+	return err
+}
+
+func TestHMAC(t *testing.T) {
+	set, err := jwtkeys.NewHMAC("secret")
+	require.NoError(t, err)
+
+	token, err := set.Sign(claims())
+	require.NoError(t, err)
+	assert.NoError(t, parse(set, token))
+	assert.Empty(t, set.JWKS().Keys)
+
+	_, err = jwtkeys.NewHMAC("")
+	assert.ErrorIs(t, err, jwtkeys.ErrMissingSecret)
+}
+
+func TestLoad_EdDSA(t *testing.T) {
+	dir := t.TempDir()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writePrivateKey(t, dir, "2026-10", private)
+
+	set, err := jwtkeys.Load(jwtkeys.AlgorithmEdDSA, dir, "2026-10")
+	require.NoError(t, err)
+
+	token, err := set.Sign(claims())
+	require.NoError(t, err)
+	assert.NoError(t, parse(set, token))
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10", parsed.Header["kid"])
+	assert.Equal(t, "EdDSA", parsed.Header["alg"])
+
+	jwks := set.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, jwtkeys.JWK{Kty: "OKP", Kid: "2026-10", Use: "sig", Alg: "EdDSA", Crv: "Ed25519",
+		X: base64.RawURLEncoding.EncodeToString(public)}, jwks.Keys[0])
+}
+
+func TestLoad_RotationKeepsOldKeysVerifying(t *testing.T) {
+	dir := t.TempDir()
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	writePrivateKey(t, dir, "old", oldKey)
+	writePrivateKey(t, dir, "new", newKey)
+
+	before, err := jwtkeys.Load(jwtkeys.AlgorithmRS256, dir, "old")
+	require.NoError(t, err)
+	issued, err := before.Sign(claims())
+	require.NoError(t, err)
+
+	// The old private key is retired, only its public half stays for verification.
+	writePublicKey(t, dir, "old", &oldKey.PublicKey)
+	after, err := jwtkeys.Load(jwtkeys.AlgorithmRS256, dir, "new")
+	require.NoError(t, err)
+
+	assert.NoError(t, parse(after, issued))
+	token, err := after.Sign(claims())
+	require.NoError(t, err)
+	assert.NoError(t, parse(after, token))
+
+	jwks := after.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "new", jwks.Keys[0].Kid)
+	assert.Equal(t, "RSA", jwks.Keys[0].Kty)
+	assert.Equal(t, "AQAB", jwks.Keys[0].E)
+	assert.Equal(t, "old", jwks.Keys[1].Kid)
+}
+
+func TestKeyfunc_Rejects(t *testing.T) {
+	dir := t.TempDir()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writePrivateKey(t, dir, "current", private)
+	set, err := jwtkeys.Load(jwtkeys.AlgorithmEdDSA, dir, "current")
+	require.NoError(t, err)
+
+	hmac, err := jwtkeys.NewHMAC("secret")
+	require.NoError(t, err)
+	hmacToken, err := hmac.Sign(claims())
+	require.NoError(t, err)
+	assert.Error(t, parse(set, hmacToken), "HS256 tokens are not accepted by an asymmetric key set")
+
+	unknown := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims())
+	unknown.Header["kid"] = "missing"
+	unknownToken, err := unknown.SignedString(private)
+	require.NoError(t, err)
+	assert.ErrorIs(t, parse(set, unknownToken), jwtkeys.ErrUnknownKey)
+
+	setToken, err := set.Sign(claims())
+	require.NoError(t, err)
+	assert.Error(t, parse(hmac, setToken), "an HS256 key set only accepts HMAC tokens")
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writePrivateKey(t, dir, "ed", private)
+	writePublicKey(t, dir, "public", private.Public())
+
+	_, err = jwtkeys.Load("none", dir, "ed")
+	assert.ErrorIs(t, err, jwtkeys.ErrUnsupportedAlgorithm)
+
+	_, err = jwtkeys.Load(jwtkeys.AlgorithmEdDSA, dir, "missing")
+	assert.ErrorIs(t, err, jwtkeys.ErrUnknownKey)
+
+	_, err = jwtkeys.Load(jwtkeys.AlgorithmEdDSA, dir, "public")
+	assert.ErrorContains(t, err, "no private key")
+
+	_, err = jwtkeys.Load(jwtkeys.AlgorithmRS256, dir, "ed")
+	assert.ErrorContains(t, err, "not RS256")
+
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	writePrivateKey(t, dir, "weak", weak)
+	_, err = jwtkeys.Load(jwtkeys.AlgorithmEdDSA, dir, "ed")
+	assert.ErrorIs(t, err, jwtkeys.ErrUnsupportedKey)
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("JWT_ALGORITHM", "")
+	t.Setenv("JWT_SECRET", "secret")
+	set, err := jwtkeys.FromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"HS256"}, set.ValidMethods())
+
+	t.Setenv("JWT_ALGORITHM", jwtkeys.AlgorithmEdDSA)
+	t.Setenv("JWT_KEYS_DIR", "")
+	_, err = jwtkeys.FromEnv()
+	assert.ErrorIs(t, err, jwtkeys.ErrMissingKeysDir)
+
+	t.Setenv("JWT_KEYS_DIR", t.TempDir())
+	t.Setenv("JWT_SIGNING_KEY_ID", "")
+	_, err = jwtkeys.FromEnv()
+	assert.ErrorIs(t, err, jwtkeys.ErrMissingSigningKey)
+}
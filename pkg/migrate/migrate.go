@@ -0,0 +1,385 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+	"workout-tracker/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const schemaTable = "schema_migrations"
+
+// lockKey names the advisory lock a run holds, so that migrators started at the same time, such as
+// several replicas booting together, take turns instead of applying the same migration twice.
+const lockKey int64 = 7_302_418_551
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+var (
+	ErrMissingDownFile  = errors.New("migration has no down file")
+	ErrMissingUpFile    = errors.New("migration has no up file")
+	ErrDuplicateVersion = errors.New("duplicate migration version")
+	ErrUnknownVersion   = errors.New("unknown migration version")
+)
+
+type DBPool interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Acquire(ctx context.Context) (*pgxpool.Conn, error)
+}
+
+// Conn is the connection a run holds the migration lock on until it is released.
+type Conn interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Release()
+}
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	AppliedAt *time.Time
+	Name      string
+	Version   int64
+	Applied   bool
+}
+
+type Migrator struct {
+	Pool DBPool
+	// Acquire hands out the connection for the migration lock. New takes it from Pool.
+	Acquire    func(ctx context.Context) (Conn, error)
+	Log        logger.SugaredLoggerInterface
+	Migrations []Migration
+}
+
+func New(pool DBPool, log logger.SugaredLoggerInterface, source fs.FS) (*Migrator, error) {
+	migrations, err := Load(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		Pool: pool,
+		Acquire: func(ctx context.Context) (Conn, error) {
+			conn, err := pool.Acquire(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return conn, nil
+		},
+		Log:        log,
+		Migrations: migrations,
+	}, nil
+}
+
+// Load reads NNN_name.up.sql / NNN_name.down.sql pairs from source and returns them ordered by version.
+func Load(source fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse migration version %q: %w", entry.Name(), err)
+		}
+
+		body, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %q: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateVersion, version)
+		}
+
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMissingUpFile, m.Version, m.Name)
+		}
+		if m.Down == "" {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMissingDownFile, m.Version, m.Name)
+		}
+		result = append(result, *m)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result, nil
+}
+
+func (m *Migrator) ensureSchemaTable(ctx context.Context) error {
+	_, err := m.Pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS `+schemaTable+` (
+			version    BIGINT PRIMARY KEY,
+			name       VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		m.Log.Errorw("failed to create schema table", "error", err)
+		return fmt.Errorf("create schema table: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	rows, err := m.Pool.Query(ctx, `SELECT version, applied_at FROM `+schemaTable)
+	if err != nil {
+		m.Log.Errorw("failed to read applied migrations", "error", err)
+		return nil, fmt.Errorf("read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("scan applied migration: %w", err)
+		}
+		result[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read applied migrations: %w", err)
+	}
+
+	return result, nil
+}
+
+// Up applies every pending migration in version order, each one in its own transaction.
+func (m *Migrator) Up(ctx context.Context) error {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := m.ensureSchemaTable(ctx); err != nil {
+		return err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range m.Migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := m.run(ctx, migration.Up,
+			`INSERT INTO `+schemaTable+` (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+		if err != nil {
+			m.Log.Errorw("failed to apply migration", "version", migration.Version, "name", migration.Name, "error", err)
+			return fmt.Errorf("apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		m.Log.Infof("applied migration %d_%s", migration.Version, migration.Name)
+	}
+
+	return nil
+}
+
+// Down rolls back the last steps applied migrations, newest first.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := m.ensureSchemaTable(ctx); err != nil {
+		return err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := len(m.Migrations) - 1; i >= 0 && steps > 0; i-- {
+		migration := m.Migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err := m.run(ctx, migration.Down, `DELETE FROM `+schemaTable+` WHERE version = $1`, migration.Version)
+		if err != nil {
+			m.Log.Errorw("failed to revert migration", "version", migration.Version, "name", migration.Name, "error", err)
+			return fmt.Errorf("revert migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		m.Log.Infof("reverted migration %d_%s", migration.Version, migration.Name)
+		steps--
+	}
+
+	return nil
+}
+
+// Status reports every known migration together with whether and when it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.ensureSchemaTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Status, 0, len(m.Migrations))
+	for _, migration := range m.Migrations {
+		s := Status{Version: migration.Version, Name: migration.Name}
+		if at, ok := applied[migration.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = &at
+		}
+		result = append(result, s)
+	}
+
+	return result, nil
+}
+
+// Force records migrations up to and including version as applied and everything newer as pending,
+// without running any SQL. It is meant for repairing the schema table after a manual intervention.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && !m.known(version) {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := m.ensureSchemaTable(ctx); err != nil {
+		return err
+	}
+
+	tx, err := m.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM `+schemaTable+` WHERE version > $1`, version); err != nil {
+		return fmt.Errorf("force version: %w", err)
+	}
+
+	for _, migration := range m.Migrations {
+		if migration.Version > version {
+			break
+		}
+		_, err := tx.Exec(ctx,
+			`INSERT INTO `+schemaTable+` (version, name) VALUES ($1, $2) ON CONFLICT (version) DO NOTHING`,
+			migration.Version, migration.Name)
+		if err != nil {
+			return fmt.Errorf("force version: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	m.Log.Infof("forced schema version to %d", version)
+	return nil
+}
+
+// lock waits for the migration lock on a connection of its own and returns the func that releases
+// both. The lock belongs to the session, so it outlives the transactions of the run.
+func (m *Migrator) lock(ctx context.Context) (func(), error) {
+	conn, err := m.Acquire(ctx)
+	if err != nil {
+		m.Log.Errorw("failed to acquire connection", "error", err)
+		return nil, fmt.Errorf("acquire connection: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		conn.Release()
+		m.Log.Errorw("failed to take migration lock", "error", err)
+		return nil, fmt.Errorf("take migration lock: %w", err)
+	}
+
+	return func() {
+		if _, err := conn.Exec(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, lockKey); err != nil {
+			m.Log.Errorw("failed to release migration lock", "error", err)
+		}
+		conn.Release()
+	}, nil
+}
+
+func (m *Migrator) known(version int64) bool {
+	for _, migration := range m.Migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Migrator) run(ctx context.Context, body, record string, args ...interface{}) error {
+	tx, err := m.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err := tx.Exec(ctx, body); err != nil {
+		return fmt.Errorf("execute migration: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, record, args...); err != nil {
+		return fmt.Errorf("record migration: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+	"workout-tracker/pkg/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"002_create_b.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+		"002_create_b.down.sql": {Data: []byte("DROP TABLE b;")},
+		"001_create_a.up.sql":   {Data: []byte("CREATE TABLE a ();")},
+		"001_create_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"README.md":             {Data: []byte("ignored")},
+	}
+}
+
+type mockConn struct {
+	mock.Mock
+}
+
+func (c *mockConn) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	ret := c.Called(append([]interface{}{ctx, sql}, args...)...)
+	return ret.Get(0).(pgconn.CommandTag), ret.Error(1)
+}
+
+func (c *mockConn) Release() {
+	c.Called()
+}
+
+// lockConn returns a connection that grants the migration lock and expects it back.
+func lockConn(ctx context.Context) *mockConn {
+	conn := new(mockConn)
+	conn.On("Exec", ctx, "SELECT pg_advisory_lock($1)", lockKey).Return(pgconn.NewCommandTag("SELECT 1"), nil).Once()
+	conn.On("Exec", mock.Anything, "SELECT pg_advisory_unlock($1)", lockKey).
+		Return(pgconn.NewCommandTag("SELECT 1"), nil).Once()
+	conn.On("Release").Return().Once()
+	return conn
+}
+
+func newMigrator(t *testing.T, pool *db.MockPool, conn *mockConn) *Migrator {
+	t.Helper()
+
+	m, err := New(pool, zap.NewNop().Sugar(), testFS())
+	require.NoError(t, err)
+	m.Acquire = func(context.Context) (Conn, error) {
+		return conn, nil
+	}
+	return m
+}
+
+func TestLoad_SortsByVersion(t *testing.T) {
+	list, err := Load(testFS())
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, int64(1), list[0].Version)
+	assert.Equal(t, "create_a", list[0].Name)
+	assert.Equal(t, "CREATE TABLE a ();", list[0].Up)
+	assert.Equal(t, "DROP TABLE a;", list[0].Down)
+	assert.Equal(t, int64(2), list[1].Version)
+}
+
+func TestLoad_MissingDown(t *testing.T) {
+	_, err := Load(fstest.MapFS{
+		"001_create_a.up.sql": {Data: []byte("CREATE TABLE a ();")},
+	})
+	assert.ErrorIs(t, err, ErrMissingDownFile)
+}
+
+func TestLoad_DuplicateVersion(t *testing.T) {
+	_, err := Load(fstest.MapFS{
+		"001_create_a.up.sql": {Data: []byte("CREATE TABLE a ();")},
+		"001_create_b.up.sql": {Data: []byte("CREATE TABLE b ();")},
+	})
+	assert.ErrorIs(t, err, ErrDuplicateVersion)
+}
+
+func TestUp_AppliesOnlyPending(t *testing.T) {
+	ctx := t.Context()
+	pool := new(db.MockPool)
+	rows := new(db.MockRow)
+	tx := new(db.MockTx)
+
+	pool.On("Exec", ctx, mock.Anything).Return(pgconn.NewCommandTag("CREATE TABLE"), nil)
+	pool.On("Query", ctx, mock.Anything).Return(rows, nil)
+	rows.On("Next").Return(true).Once()
+	rows.On("Scan", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		version, ok := args.Get(0).(*int64)
+		require.True(t, ok)
+		*version = 1
+		appliedAt, ok := args.Get(1).(*time.Time)
+		require.True(t, ok)
+		*appliedAt = time.Now()
+	}).Return(nil).Once()
+	rows.On("Next").Return(false).Once()
+	rows.On("Err").Return(nil)
+	rows.On("Close").Return()
+
+	pool.On("Begin", ctx).Return(pgx.Tx(tx), nil).Once()
+	tx.On("Exec", ctx, "CREATE TABLE b ();").Return(pgconn.NewCommandTag("CREATE TABLE"), nil).Once()
+	tx.On("Exec", ctx, mock.Anything, int64(2), "create_b").Return(pgconn.NewCommandTag("INSERT 0 1"), nil).Once()
+	tx.On("Commit", ctx).Return(nil).Once()
+	tx.On("Rollback", ctx).Return(nil).Once()
+
+	conn := lockConn(ctx)
+	m := newMigrator(t, pool, conn)
+
+	require.NoError(t, m.Up(ctx))
+	pool.AssertExpectations(t)
+	tx.AssertExpectations(t)
+	conn.AssertExpectations(t)
+}
+
+func TestUp_MigrationError(t *testing.T) {
+	ctx := t.Context()
+	pool := new(db.MockPool)
+	rows := new(db.MockRow)
+	tx := new(db.MockTx)
+
+	pool.On("Exec", ctx, mock.Anything).Return(pgconn.NewCommandTag("CREATE TABLE"), nil)
+	pool.On("Query", ctx, mock.Anything).Return(rows, nil)
+	rows.On("Next").Return(false)
+	rows.On("Err").Return(nil)
+	rows.On("Close").Return()
+
+	pool.On("Begin", ctx).Return(pgx.Tx(tx), nil).Once()
+	tx.On("Exec", ctx, "CREATE TABLE a ();").Return(pgconn.NewCommandTag(""), errors.New("syntax error")).Once()
+	tx.On("Rollback", ctx).Return(nil).Once()
+
+	conn := lockConn(ctx)
+	m := newMigrator(t, pool, conn)
+
+	err := m.Up(ctx)
+	assert.ErrorContains(t, err, "apply migration 1_create_a")
+	tx.AssertNotCalled(t, "Commit", ctx)
+	conn.AssertExpectations(t)
+}
+
+func TestUp_HoldsTheLockForTheWholeRun(t *testing.T) {
+	ctx := t.Context()
+	pool := new(db.MockPool)
+	rows := new(db.MockRow)
+	tx := new(db.MockTx)
+	conn := new(mockConn)
+
+	var steps []string
+	step := func(name string) func(mock.Arguments) {
+		return func(mock.Arguments) { steps = append(steps, name) }
+	}
+
+	conn.On("Exec", ctx, "SELECT pg_advisory_lock($1)", lockKey).Run(step("lock")).
+		Return(pgconn.NewCommandTag("SELECT 1"), nil).Once()
+	conn.On("Exec", mock.Anything, "SELECT pg_advisory_unlock($1)", lockKey).Run(step("unlock")).
+		Return(pgconn.NewCommandTag("SELECT 1"), nil).Once()
+	conn.On("Release").Run(step("release")).Return().Once()
+
+	pool.On("Exec", ctx, mock.Anything).Run(step("schema")).Return(pgconn.NewCommandTag("CREATE TABLE"), nil)
+	pool.On("Query", ctx, mock.Anything).Return(rows, nil)
+	rows.On("Next").Return(false)
+	rows.On("Err").Return(nil)
+	rows.On("Close").Return()
+
+	pool.On("Begin", ctx).Return(pgx.Tx(tx), nil)
+	tx.On("Exec", ctx, mock.Anything, mock.Anything, mock.Anything).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+	tx.On("Exec", ctx, mock.Anything).Return(pgconn.NewCommandTag("CREATE TABLE"), nil)
+	tx.On("Commit", ctx).Run(step("commit")).Return(nil)
+	tx.On("Rollback", ctx).Return(nil)
+
+	m := newMigrator(t, pool, conn)
+
+	require.NoError(t, m.Up(ctx))
+	assert.Equal(t, []string{"lock", "schema", "commit", "commit", "unlock", "release"}, steps)
+}
+
+func TestUp_LockError(t *testing.T) {
+	ctx := t.Context()
+	pool := new(db.MockPool)
+	conn := new(mockConn)
+
+	conn.On("Exec", ctx, "SELECT pg_advisory_lock($1)", lockKey).
+		Return(pgconn.NewCommandTag(""), errors.New("connection reset")).Once()
+	conn.On("Release").Return().Once()
+
+	m := newMigrator(t, pool, conn)
+
+	err := m.Up(ctx)
+	assert.ErrorContains(t, err, "take migration lock")
+	pool.AssertNotCalled(t, "Exec", ctx, mock.Anything)
+	conn.AssertExpectations(t)
+}
+
+func TestForce_UnknownVersion(t *testing.T) {
+	m, err := New(new(db.MockPool), zap.NewNop().Sugar(), testFS())
+	require.NoError(t, err)
+
+	err = m.Force(t.Context(), 42)
+	assert.ErrorIs(t, err, ErrUnknownVersion)
+}